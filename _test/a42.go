@@ -0,0 +1,27 @@
+package main
+
+import "fmt"
+
+const N = 4
+
+const M = N * 2
+
+type T [N + 1]int
+
+func main() {
+	var a [N]int
+	var b [N * 2]float64
+	var c [len("key")]byte
+	var d [M]int
+	var e T
+	f := [...]int{5: 1, 2}
+	g := [...]string{2: "a", 0: "b"}
+	const K = 3
+	h := [...]int{K: 1}
+	var i [1 << 3]bool
+	var j [N / 2]int
+	fmt.Println(len(a), len(b), len(c), len(d), len(e), len(f), f, len(g), len(h), len(i), len(j))
+}
+
+// Output:
+// 4 8 3 8 5 7 [0 0 0 0 0 1 2] 3 4 8 2
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/containous/yaegi/_test/ct1"
+)
+
+var a [ct1.AL + 1]string
+
+var b [N * L]int
+
+const N = 2
+
+const L = len("abcd")
+
+func main() {
+	const K = 3
+	var c [K * 2]int
+	var d [len(c) + 1]int
+	e := [...]int{K: 1, N: 2}
+	fmt.Println(len(a), len(b), len(c), len(d), len(e), e)
+}
+
+// Output:
+// 3 8 6 7 4 [0 0 2 1]
@@ -0,0 +1,24 @@
+package main
+
+import "fmt"
+
+const Sat = 6
+
+type Day uint8
+
+const Mon Day = 1
+
+func main() {
+	weekdays := []string{0: "Sun", Sat: "Sat"}
+	a := [...]int{9: 1}
+	b := []int{1, 5: 2, 3, 1: 4}
+	c := [5]int{3: 1, 2}
+	const K = 2
+	d := []string{K: "k", "l"}
+	e := [...]string{K + 1: "x"}
+	f := []string{Mon: "Mon", Sat - 1: "Fri"}
+	fmt.Println(len(weekdays), weekdays, len(a), a, b, c, d, len(d), len(e), len(f), f[1], f[5])
+}
+
+// Output:
+// 7 [Sun      Sat] 10 [0 0 0 0 0 0 0 0 0 1] [1 4 0 0 0 2 3] [0 0 0 1 2] [  k l] 4 4 6 Mon Fri
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+type Root struct{ Name string }
+
+type One = Root
+
+func (o One) Hello() string { return "hello " + o.Name }
+
+type D = time.Duration
+
+func main() {
+	var r Root = One{"one"}
+	var o One = Root{"root"}
+	var d D = time.Second
+	var td time.Duration = 2 * d
+	type L = int
+	var l L = 3
+	var n int = l
+	fmt.Println(r.Hello(), o.Hello(), d, td, n)
+}
+
+// Output:
+// hello one hello root 1s 2s 3
@@ -0,0 +1,43 @@
+package main
+
+import "fmt"
+
+type P struct{ x, y int }
+
+func main() {
+	a, b := 1, 2
+	a, b = b, a
+	fmt.Println(a, b)
+
+	s := []int{1, 2, 3}
+	s[0], s[2] = s[2], s[0]
+	fmt.Println(s)
+
+	i := 0
+	x := []int{0, 0, 0}
+	i, x[i] = 1, 2
+	fmt.Println(i, x)
+
+	m := map[int]int{1: 10, 10: 100}
+	k := 1
+	k, m[k] = m[k], k
+	fmt.Println(k, m)
+
+	j := 0
+	n := map[int]string{}
+	j, n[j] = 1, "a"
+	fmt.Println(j, n)
+
+	q := &P{3, 4}
+	r := q
+	q, q.x = &P{5, 6}, 7
+	fmt.Println(*q, *r)
+}
+
+// Output:
+// 2 1
+// [3 2 1]
+// 1 [2 0 0]
+// 10 map[1:1 10:100]
+// 1 map[0:a]
+// {5 6} {7 4}
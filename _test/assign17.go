@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+)
+
+type point struct{ x, y int }
+
+func main() {
+	i := 3
+	var a, b int
+	var s string
+	a, b, s = i, 2*i, strconv.Itoa(i)
+	fmt.Println(a, b, s)
+
+	var p point
+	p.x, p.y = i, len(fmt.Sprint(i*10))
+	fmt.Println(p)
+
+	a, p = i+1, point{x: 5}
+	fmt.Println(a, p)
+
+	c := make(chan int, 1)
+	c <- 9
+	a, b = i, <-c
+	fmt.Println(a, b)
+
+	a, b = b, a+10
+	fmt.Println(a, b)
+	a, b = b, -a
+	fmt.Println(a, b)
+}
+
+// Output:
+// 3 6 3
+// {3 2}
+// 4 {5 0}
+// 3 9
+// 9 13
+// 13 -9
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+func pow() (float64, float64) { return 2, 10 }
+
+func repeat() (string, int) { return "ab", 2 }
+
+const (
+	c = 3
+	f = 1.5
+)
+
+func main() {
+	// Untyped constants of every kind, converted to the parameter types.
+	fmt.Println(math.Pow(2, 10), math.Sqrt(c), math.Max(c, f), math.Abs(1<<70))
+	fmt.Println(math.Ldexp(1, 2.0), math.Float32bits(f), cmplx.Abs(3+4i), cmplx.Abs(1))
+	fmt.Println(strings.Repeat("x", c), strings.IndexByte("abc", 'b'), unicode.IsUpper('A'))
+	fmt.Println(strconv.FormatFloat(c, 'g', -1, 32), strconv.FormatUint(1<<63, 10), strconv.FormatBool(true))
+
+	// Defined parameter types.
+	fmt.Println(time.Duration(90e9).Truncate(60e9), time.Unix(0, 0).Add(1e9).UTC().Second())
+	fmt.Println(os.FileMode(0755).Perm()|0100, os.ModeDir|0644)
+
+	// Binary constants.
+	p := math.Pi
+	_, _ = 1, math.MaxInt8
+	fmt.Println(p, math.Cos(math.Pi), time.Duration(math.MaxInt8))
+
+	// Variadic and multi-value arguments.
+	fmt.Println(strings.NewReplacer("a", "b").Replace("aaa"), fmt.Sprint(1, 2.5, 'c', 1i))
+	fmt.Println(math.Pow(pow()), strings.Repeat(repeat()))
+}
+
+// Output:
+// 1024 1.7320508075688772 3 1.1805916207174113e+21
+// 4 1069547520 5 1
+// xxx 1 true
+// 3 9223372036854775808 true
+// 1m0s 1
+// -rwxr-xr-x drw-r--r--
+// 3.141592653589793 -1 127ns
+// bbb 1 2.5 99 (0+1i)
+// 1024 abab
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	_ "strings"
+)
+
+type T struct {
+	_ int
+	A int
+	_ string
+}
+
+func (T) _() {}
+
+func (T) _() {}
+
+func _() {}
+
+func _() {}
+
+var n int
+
+func h() int { n++; return n }
+
+func f(_ int, a int) (_ int, b int) { return a, a + 1 }
+
+var _ = h()
+
+func main() {
+	_ = h()
+	_, _ = h(), h()
+	var _ int = h()
+	for _ = range []int{1, 2} {
+	}
+	_, b := f(1, 2)
+	t := T{A: 3}
+	fmt.Println(n, b, t.A, t)
+}
+
+// Output:
+// 5 3 3 {0 3 }
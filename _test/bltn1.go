@@ -0,0 +1,57 @@
+package main
+
+import "fmt"
+
+func copy(dst, src string) string { return dst + src }
+
+type len int
+
+func outer() int { return cap([]int{1, 2}) }
+
+var new = "new"
+
+func inner() {
+	cap := func(s []int) int { return 42 }
+	fmt.Println(cap([]int{1}), outer())
+	append := 3
+	fmt.Println(append)
+	{
+		true := false
+		fmt.Println(true)
+	}
+	fmt.Println(true)
+	const iota = 7
+	fmt.Println(iota)
+	nil := 1
+	fmt.Println(nil)
+	make := map[string]int{"a": 1}
+	fmt.Println(make["a"])
+}
+
+const (
+	a = iota
+	b
+)
+
+func main() {
+	fmt.Println(copy("a", "b"), new)
+	var l len = 3
+	fmt.Println(l)
+	inner()
+	fmt.Println(a, b)
+	x := []int{1}
+	fmt.Println(cap(x), string(append([]byte("x"), 'y')))
+}
+
+// Output:
+// ab new
+// 3
+// 42 2
+// 3
+// false
+// true
+// 7
+// 1
+// 1
+// 0 1
+// 1 xy
@@ -0,0 +1,58 @@
+//go:build go1.21
+// +build go1.21
+
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func outer() (int, float64, string) { return max(1, 5, 3), max(2.5, 1), max("a", "b") }
+
+func clearAll(m map[string]int) {
+	clear := func(m map[string]int) { m["cleared"] = 1 }
+	clear(m)
+}
+
+func main() {
+	fmt.Println(min(3, 2))
+	fmt.Println(outer())
+	x, y := 3, 7
+	var f32 float32 = 1.5
+	fmt.Println(max(x, y, 5), max(f32, 2), max(x, 2.0))
+	fmt.Println(max(1, math.NaN()), math.IsNaN(max(math.NaN(), 1)))
+	const c = max(1, 2.5, 'a')
+	fmt.Printf("%T %v\n", c, c)
+	var arr [max(2, 3)]int
+	fmt.Println(len(arr))
+	m := map[string]int{"a": 1, "b": 2}
+	clearAll(m)
+	fmt.Println(len(m))
+	clear(m)
+	fmt.Println(len(m))
+	s := []int{1, 2, 3}
+	clear(s)
+	fmt.Println(s, len(s))
+	var u uint8 = 200
+	fmt.Println(max(u, 100), max("x"))
+}
+
+// Output:
+// 2
+// 5 2.5 b
+// 7 2 3
+// NaN true
+// float64 97
+// 3
+// 3
+// 0
+// [0 0 0] 3
+// 200 x
@@ -0,0 +1,34 @@
+package main
+
+import "fmt"
+
+func main() {
+	m := [][]int{{1, 2, 3}, {4, 5, 6}}
+outer:
+	for _, row := range m {
+		for _, v := range row {
+			if v == 5 {
+				break outer
+			}
+			fmt.Println(v)
+		}
+	}
+
+	k := 0
+sw:
+	switch {
+	case k == 0:
+		for {
+			break sw
+		}
+		fmt.Println("not reached")
+	}
+	fmt.Println("done")
+}
+
+// Output:
+// 1
+// 2
+// 3
+// 4
+// done
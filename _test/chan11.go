@@ -0,0 +1,37 @@
+package main
+
+import "fmt"
+
+type B bool
+
+func main() {
+	ch := make(chan int, 3)
+	ch <- 1
+	ch <- 2
+	ch <- 3
+
+	var ok B
+	v := 5
+	v, ok = <-ch
+	fmt.Println(v, ok)
+
+	if v, ok := <-ch; ok {
+		fmt.Println("if", v, ok)
+	}
+	close(ch)
+	for v, ok := <-ch; ok; v, ok = <-ch {
+		fmt.Println("for", v, ok)
+	}
+	fmt.Println(v, ok)
+
+	_, ok = <-ch
+	_, ok1 := <-ch
+	fmt.Println(ok, ok1, <-ch)
+}
+
+// Output:
+// 1 true
+// if 2 true
+// for 3 true
+// 1 true
+// false false 0
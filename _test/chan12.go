@@ -0,0 +1,78 @@
+package main
+
+import "fmt"
+
+type point struct {
+	x, y int
+	name string
+	tags []string
+}
+
+type pipe struct {
+	in  <-chan point
+	out chan<- point
+	ctl chan chan int
+}
+
+func one() int { return 1 }
+
+func get(c <-chan int) int { return <-c }
+
+func main() {
+	c := make(chan point, 10)
+	done := make(chan int)
+	go func() {
+		bad := 0
+		for p := range c {
+			if p.y != 2*p.x || p.name != fmt.Sprint(p.x) || len(p.tags) != 1 {
+				bad++
+			}
+		}
+		done <- bad
+	}()
+	p := point{tags: []string{"t"}}
+	for i := 0; i < 100; i++ {
+		p.x, p.y, p.name = i, 2*i, fmt.Sprint(i)
+		c <- p
+	}
+	close(c)
+	fmt.Println(<-done)
+
+	ch := make(chan point, 1)
+	r := make(chan int, 1)
+	pp := pipe{in: ch, out: ch, ctl: make(chan chan int, 1)}
+	pp.out <- point{x: 5}
+	fmt.Println((<-pp.in).x)
+	pp.ctl <- r
+	(<-pp.ctl) <- 9
+	fmt.Println(<-r)
+
+	cs := make(chan []point, 1)
+	cs <- []point{{x: 1}, {x: 2}}
+	fmt.Println(len(<-cs))
+
+	cf := make(chan func() int, 2)
+	cf <- one
+	select {
+	case cf <- func() int { return 2 }:
+	}
+	f, g := <-cf, <-cf
+	fmt.Println(f(), g())
+
+	cf <- one
+	close(cf)
+	for f := range cf {
+		fmt.Println(f())
+	}
+	r <- 3
+	fmt.Println(get(r))
+}
+
+// Output:
+// 0
+// 5
+// 9
+// 2
+// 1 2
+// 1
+// 3
@@ -0,0 +1,40 @@
+package main
+
+import "fmt"
+
+const c = 2 + 3i
+const r = real(c)
+const im = imag(c)
+const k = complex(1, 2)
+
+func main() {
+	fmt.Println(c, r, im, k)
+	var a [int(r)]int
+	fmt.Println(len(a))
+	var x complex64 = 1 + 2i
+	y := x * 2
+	z := x + 1i
+	fmt.Println(x, y, z, x == 1+2i, x != z)
+	fmt.Printf("%T %T %T %T\n", x, y, k, real(x))
+	w := complex(float32(1), 2)
+	fmt.Printf("%T %v\n", w, w)
+	d := complex(3.0, 4.0)
+	e := d / (1 + 2i)
+	fmt.Println(e, real(e), imag(e))
+	var f float64 = real(c)
+	fmt.Println(f, imag(d))
+	var cc complex128 = 1
+	fmt.Println(cc/0, c*c, c/2)
+	fmt.Printf("%.2f %v\n", x, complex(0, 1)*complex(0, 1))
+}
+
+// Output:
+// (2+3i) 2 3 (1+2i)
+// 2
+// (1+2i) (2+4i) (1+3i) true true
+// complex64 complex64 complex128 float32
+// complex64 (1+2i)
+// (2.2-0.4i) 2.2 -0.4
+// 2 4
+// (+Inf+NaNi) (-5+12i) (1+1.5i)
+// (1.00+2.00i) (-1+0i)
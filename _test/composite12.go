@@ -0,0 +1,35 @@
+package main
+
+import "fmt"
+
+type Point struct{ X, Y int }
+
+type T struct{ X int }
+
+type PS []Point
+
+type PS2 PS
+
+func main() {
+	a := [][]int{{1, 2}, {3}}
+	b := map[string][]Point{"a": {{1, 2}}}
+	c := []*T{{X: 1}, {2}}
+	d := map[Point]string{{1, 2}: "p"}
+	e := [...][2]int{{1, 2}, {3, 4}}
+	f := map[string]*Point{"x": {3, 4}}
+	g := [][]*T{{{X: 5}}}
+	h := []*[]int{{1}}
+	i := PS2{{6, 7}}
+	j := [2]*Point{1: {1, 1}}
+	fmt.Println(a, b, *c[0], *c[1], d, e, *f["x"], *g[0][0], *h[0], i, j[0], *j[1])
+
+	var ps []*T
+	for i := 0; i < 2; i++ {
+		ps = append(ps, []*T{{X: i}}[0])
+	}
+	fmt.Println(*ps[0], *ps[1])
+}
+
+// Output:
+// [[1 2] [3]] map[a:[{1 2}]] {1} {2} map[{1 2}:p] [[1 2] [3 4]] {3 4} {5} [1] [{6 7}] <nil> {1 1}
+// {0} {1}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"net/url"
+	"sort"
+)
+
+func main() {
+	a := []image.Point{{1, 2}}
+	b := []*image.Point{{3, 4}}
+	c := map[string]*image.Point{"a": {5, 6}}
+	d := url.Values{"k": {"v"}}
+	e := sort.IntSlice{3, 1, 2: 5}
+	e.Sort()
+	fmt.Println(a, *b[0], *c["a"], d.Get("k"), e)
+}
+
+// Output:
+// [(1,2)] (3,4) (5,6) v [1 3 5]
@@ -0,0 +1,23 @@
+package main
+
+import "fmt"
+
+const big = 1_000_000_000_000_000_000_000
+
+func main() {
+	fmt.Println(1_000_000, 0b1010, 0o755, 0755, 0x1p-2, 0x_FF, 0B11, 0O17)
+	fmt.Println(0b101i, 0o17i, 0x1p4i, 1_0.5_0, 1e1_0)
+	var f float32 = 0x1.8p1
+	var u uint8 = 0b1111_1111
+	fmt.Println(f, u, big/1e21, 'a', '\x41')
+	const c = 0x1p-1074
+	fmt.Println(c > 0)
+	fmt.Printf("%T %T %T\n", 0b1, 0x1p1, 0o1i)
+}
+
+// Output:
+// 1000000 10 493 493 0.25 255 3 15
+// (0+5i) (0+15i) (0+16i) 10.5 1e+10
+// 3 255 1 97 65
+// true
+// int float64 complex128
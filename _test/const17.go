@@ -0,0 +1,98 @@
+package main
+
+import "fmt"
+
+const (
+	F1 = 1 << iota
+	F2
+	F3
+	F4
+	F5
+	F6
+	F7
+	F8
+	F9
+	F10
+	F11
+	F12
+	F13
+	F14
+	F15
+	F16
+	F17
+	F18
+	F19
+	F20
+	F21
+	F22
+	F23
+	F24
+	F25
+	F26
+	F27
+	F28
+	F29
+	F30
+	F31
+	F32
+	F33
+	F34
+	F35
+	F36
+	F37
+	F38
+	F39
+	F40
+	F41
+	F42
+	F43
+	F44
+	F45
+	F46
+	F47
+	F48
+	F49
+	F50
+	F51
+	F52
+	F53
+	F54
+	F55
+	F56
+	F57
+	F58
+	F59
+	F60
+	F61
+	F62
+	F63
+	F64
+	F65
+	F66
+	F67
+	F68
+	F69
+	F70
+)
+
+const (
+	mask = (1<<128 - 1) >> 100
+	low  = F70&F1 | F64>>60
+	bits = 1<<70 - 1
+)
+
+func main() {
+	fmt.Println(F1, F63>>60, F70>>68, F70/F68, low)
+	fmt.Println(mask, bits&0xf0, ^bits>>69, bits&^(1<<69-1)>>66)
+	var u uint64 = F64 - 1
+	var f float64 = F70
+	fmt.Println(u, f, F70 > F69, F70 == F69<<1)
+	x := 3
+	fmt.Println(F70 < F69 || x == 3, !(F70 > F69) || x > 5, F70 > F69 && x == 3)
+}
+
+// Output:
+// 1 4 2 4 8
+// 268435455 240 -2 8
+// 9223372036854775807 5.902958103587057e+20 true true
+// true false true
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+type Level int
+
+const (
+	Debug Level = iota
+	Info
+	Warn
+	Error = Warn + 1
+	Count = int(Error) + 1
+)
+
+func (l Level) String() string {
+	switch l {
+	case Debug:
+		return "debug"
+	case Info:
+		return "info"
+	case Warn:
+		return "warn"
+	}
+	return fmt.Sprintf("level(%d)", int(l))
+}
+
+var names = map[Level]string{Debug: "D", Info: "I", Warn: "W"}
+
+const (
+	Jan time.Month = iota + 1
+	Feb
+	Mar
+)
+
+func main() {
+	var levels [Count]Level
+	for i := range levels {
+		levels[i] = Level(i)
+	}
+	for _, l := range levels {
+		fmt.Print(l.String(), " ")
+	}
+	fmt.Println(len(levels), names[Info], names[Warn])
+	var flags [Warn]bool
+	fmt.Println(len(flags), Error.String(), (Error + 1).String())
+	t := time.Date(2020, Mar, 1, 0, 0, 0, 0, time.UTC)
+	fmt.Println(t.Month(), t.Month() == Mar, []time.Month{Jan, Feb})
+	fmt.Printf("%T %v\n", Feb+1, Feb+1)
+}
+
+// Output:
+// debug info warn level(3) 4 I W
+// 2 level(3) level(4)
+// March true [January February]
+// time.Month March
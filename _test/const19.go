@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	timeout = time.Duration(10) * time.Millisecond
+	retry   = 2 * time.Duration(3) * time.Second
+	half    = time.Duration(1.5 * float64(time.Second))
+	perm    = os.FileMode(0644)
+	dirPerm = os.FileMode(0755) | os.ModeDir
+	private = perm &^ 0044
+	ms      = int64(timeout / time.Millisecond)
+	third   = float32(1) / 3
+)
+
+var buf [int(timeout / time.Millisecond)]byte
+
+func main() {
+	fmt.Println(timeout, retry, half, ms, third, len(buf))
+	fmt.Println(perm, dirPerm, private)
+	fmt.Printf("%T %T %T\n", timeout, dirPerm, ms)
+	const wait = time.Duration(5) * time.Minute
+	fmt.Println(wait, wait.Minutes())
+}
+
+// Output:
+// 10ms 6s 1.5s 10 0.33333334 10
+// -rw-r--r-- drwxr-xr-x -rw-------
+// time.Duration fs.FileMode int64
+// 5m0s 5
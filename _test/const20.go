@@ -0,0 +1,14 @@
+package main
+
+import "time"
+
+var n = 3
+
+const d = time.Duration(n) * time.Second
+
+func main() {
+	println(d)
+}
+
+// Error:
+// 7:11: time.Duration(n) * time.Second (value of type time.Duration) is not constant
@@ -0,0 +1,14 @@
+package main
+
+const a = b
+
+const b = a
+
+func main() {
+	println(a)
+}
+
+// Error:
+// ../_test/const21.go:3:7: constant definition loop
+//	../_test/const21.go:3:7: a refers to b
+//	../_test/const21.go:5:7: b refers to a
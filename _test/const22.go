@@ -0,0 +1,11 @@
+package main
+
+const n = n + 1
+
+func main() {
+	println(n)
+}
+
+// Error:
+// ../_test/const22.go:3:7: constant definition loop
+//	../_test/const22.go:3:7: n refers to itself
@@ -0,0 +1,14 @@
+package main
+
+const n = len(arr)
+
+var arr [n]int
+
+func main() {
+	println(n)
+}
+
+// Error:
+// ../_test/const23.go:3:7: variable definition loop
+//	../_test/const23.go:3:7: n refers to arr
+//	../_test/const23.go:5:5: arr refers to n
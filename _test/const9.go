@@ -15,3 +15,7 @@ func main() {
 
 // Error:
 // 5:2: constant definition loop
+//	../_test/const9.go:5:2: b refers to c
+//	../_test/const9.go:6:2: c refers to d
+//	../_test/const9.go:7:2: d refers to e
+//	../_test/const9.go:8:2: e refers to b
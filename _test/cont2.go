@@ -0,0 +1,24 @@
+package main
+
+import "fmt"
+
+func main() {
+loop:
+	for i := 0; i < 5; i++ {
+		for j := range "ab" {
+			switch {
+			case i == 1:
+				continue loop
+			case i == 3:
+				break loop
+			}
+			fmt.Println(i, j)
+		}
+	}
+}
+
+// Output:
+// 0 0
+// 0 1
+// 2 0
+// 2 1
@@ -0,0 +1,37 @@
+package main
+
+import "fmt"
+
+type (
+	MyString string
+	MyBytes  []byte
+	MyInt    int
+)
+
+type A struct{ X int }
+
+type B struct {
+	X int `json:"x"`
+}
+
+func main() {
+	bs := []byte("abc")
+	s := MyString(bs)
+	b := MyBytes(s)
+	r := []rune(s)
+	fmt.Println(s, b, r, string(r), string(MyInt(0x263a)), string(rune(65)))
+
+	x := MyInt(2.0)
+	f := float32(x) / 4
+	fmt.Println(x, f, int(f*10), uint8(x+255))
+
+	pb := &B{3}
+	pa := (*A)(pb)
+	pa.X++
+	fmt.Println(A(*pb), pb.X)
+}
+
+// Output:
+// abc [97 98 99] [97 98 99] abc ☺ A
+// 2 0.5 5 1
+// {4} 4
@@ -0,0 +1,19 @@
+package main
+
+import "fmt"
+
+func main() {
+	s := []int{1, 2, 3}
+	a := [2]int(s)
+	p := (*[3]int)(s)
+	p[0] = 10
+	a[1] = 20
+	fmt.Println(a, *p, s)
+
+	defer func() { fmt.Println("recovered:", recover()) }()
+	_ = (*[4]int)(s)
+}
+
+// Output:
+// [1 20] [10 2 3] [10 2 3]
+// recovered: runtime error: cannot convert slice with length 3 to array or pointer to array with length 4
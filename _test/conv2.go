@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"os"
+	"strings"
+)
+
+type point struct {
+	X, Y int `json:"x"`
+}
+
+type rect struct {
+	Min, Max image.Point
+}
+
+type readWriter struct {
+	*bufio.Reader
+	*bufio.Writer
+}
+
+func main() {
+	fmt.Println(image.Point(point{1, 2}), point(image.Pt(3, 4)))
+	fmt.Println(image.Rectangle(rect{image.Pt(0, 0), image.Pt(5, 6)}))
+
+	rw := bufio.ReadWriter(readWriter{bufio.NewReader(strings.NewReader("hello\n")), bufio.NewWriter(os.Stdout)})
+	s, _ := rw.ReadString('\n')
+	rw.WriteString(strings.ToUpper(s))
+	rw.Flush()
+
+	r := readWriter(rw)
+	fmt.Println(r.Reader == rw.Reader, r.Writer == rw.Writer)
+}
+
+// Output:
+// (1,2) {3 4}
+// (0,0)-(5,6)
+// HELLO
+// true true
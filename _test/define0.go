@@ -0,0 +1,27 @@
+package main
+
+import "strconv"
+
+func main() {
+	a := 1
+	p := &a
+	a, b := 2, 3
+	println(*p, a, b)
+
+	n, err := strconv.Atoi("x")
+	q := &err
+	m, err := strconv.Atoi("4")
+	println(n, m, err == nil, *q == nil)
+
+	{
+		a := "inner"
+		println(a)
+	}
+	println(a)
+}
+
+// Output:
+// 2 2 3
+// 0 4 true true
+// inner
+// 2
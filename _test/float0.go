@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+func main() {
+	// Naive summation, each operation is rounded to float32 precision.
+	var s, x float32
+	for i := 0; i < 1000000; i++ {
+		s += x
+		x += 0.001
+	}
+	fmt.Println(s, math.Float32bits(s))
+
+	var a, b float32 = 1.1, 2.3
+	fmt.Println(float64(a*b), float64(a+b), float64(a/b), float64(a-b))
+	fmt.Println(float64(a*b*a) == float64(float32(a*b)*a))
+
+	// Conversions round to nearest even.
+	d := math.Float64frombits(0x3FF0000010000000)
+	e := math.Float64frombits(0x3FF0000030000000)
+	fmt.Println(math.Float32bits(float32(d)), math.Float32bits(float32(e)))
+
+	var n int64 = 1<<24 + 1
+	fmt.Println(float64(float32(n)))
+
+	const k float32 = 0.1
+	fmt.Println(float64(k), float64(k*3))
+}
+
+// Output:
+// 4.989923e+08 1307439141
+// 2.5299999713897705 3.4000000953674316 0.47826090455055237 -1.1999999284744263
+// true
+// 1065353216 1065353218
+// 1.6777216e+07
+// 0.10000000149011612 0.30000001192092896
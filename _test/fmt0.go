@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+type T struct {
+	A int
+	b string
+	C []int
+	M map[string]int
+	I interface{}
+	E error
+	P *T
+	_ int
+}
+
+type U struct {
+	T
+	S string
+	F func()
+}
+
+func main() {
+	t := T{A: 1, b: "x", C: []int{2}, M: map[string]int{"k": 3}, I: 4, E: errors.New("e")}
+	fmt.Printf("%v\n", t)
+	fmt.Printf("%+v\n", t)
+	fmt.Printf("%+v\n", &t)
+	fmt.Printf("%#v\n", T{A: 1, b: "x", C: []int{2}, M: map[string]int{"k": 3}, I: 4})
+	fmt.Printf("%#v\n", []T{{A: 1}})
+	fmt.Printf("%#v\n", map[string]*T{"a": nil})
+	fmt.Printf("%+v\n", U{T: T{A: 5}, S: "s"})
+	fmt.Printf("%#v\n", struct {
+		T
+		q int
+	}{q: 3})
+
+	var i interface{} = t
+	fmt.Printf("%+v\n", i)
+	fmt.Printf("%#v\n", []interface{}{1, "a", T{}, nil})
+}
+
+// Output:
+// {1 x [2] map[k:3] 4 e <nil> 0}
+// {A:1 b:x C:[2] M:map[k:3] I:4 E:e P:<nil> _:0}
+// &{A:1 b:x C:[2] M:map[k:3] I:4 E:e P:<nil> _:0}
+// main.T{A:1, b:"x", C:[]int{2}, M:map[string]int{"k":3}, I:4, E:error(nil), P:(*main.T)(nil), _:0}
+// []main.T{main.T{A:1, b:"", C:[]int(nil), M:map[string]int(nil), I:interface {}(nil), E:error(nil), P:(*main.T)(nil), _:0}}
+// map[string]*main.T{"a":(*main.T)(nil)}
+// {T:{A:5 b: C:[] M:map[] I:<nil> E:<nil> P:<nil> _:0} S:s F:<nil>}
+// struct { main.T; q int }{T:main.T{A:0, b:"", C:[]int(nil), M:map[string]int(nil), I:interface {}(nil), E:error(nil), P:(*main.T)(nil), _:0}, q:3}
+// {A:1 b:x C:[2] M:map[k:3] I:4 E:e P:<nil> _:0}
+// []interface {}{1, "a", main.T{A:0, b:"", C:[]int(nil), M:map[string]int(nil), I:interface {}(nil), E:error(nil), P:(*main.T)(nil), _:0}, interface {}(nil)}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+)
+
+type L []int
+
+type M map[string]L
+
+type P struct{ X, Y int }
+
+type N struct {
+	p P
+	L L
+	A [2]P
+}
+
+func main() {
+	fmt.Printf("%v %#v\n", L{1}, L{1})
+	fmt.Printf("%v %#v\n", M{"b": nil, "a": {1}}, M{"a": {1}})
+	fmt.Printf("%d %x %s %5v|\n", P{1, 2}, P{10, 11}, P{1, 2}, P{1, 2})
+	fmt.Printf("%+v\n", N{p: P{1, 2}, A: [2]P{{3, 4}}})
+	fmt.Printf("%#v\n", N{})
+	fmt.Println(P{1, 2}, &P{3, 4}, []P{{5, 6}}, map[P]int{{1, 1}: 1})
+	fmt.Print(P{1, 2}, "\n")
+	fmt.Println(fmt.Sprintf("%+v", []*P{nil}))
+	fmt.Println(fmt.Errorf("err %+v", P{1, 2}))
+	fmt.Fprintf(os.Stdout, "%+v\n", struct{ p P }{P{1, 2}})
+	fmt.Printf("%T %#v %*d %+v %[1]T\n", 1, P{1, 2}, 3, 4, P{5, 6})
+
+	var i interface{} = M{"x": nil}
+	fmt.Printf("%#v %v\n", i, []interface{}{P{}, nil, L{}})
+
+	log.SetOutput(os.Stdout)
+	log.SetFlags(0)
+	log.Printf("%+v", P{7, 8})
+}
+
+// Output:
+// [1] main.L{1}
+// map[a:[1] b:[]] main.M{"a":main.L{1}}
+// {1 2} {a b} {%!s(int=1) %!s(int=2)} {    1     2}|
+// {p:{X:1 Y:2} L:[] A:[{X:3 Y:4} {X:0 Y:0}]}
+// main.N{p:main.P{X:0, Y:0}, L:main.L(nil), A:[2]main.P{main.P{X:0, Y:0}, main.P{X:0, Y:0}}}
+// {1 2} &{3 4} [{5 6}] map[{1 1}:1]
+// {1 2}
+// [<nil>]
+// err {X:1 Y:2}
+// {p:{X:1 Y:2}}
+// int main.P{X:1, Y:2}   4 {X:5 Y:6} int
+// main.M{"x":main.L(nil)} [{0 0} <nil> []]
+// {X:7 Y:8}
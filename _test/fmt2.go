@@ -0,0 +1,43 @@
+package main
+
+import "fmt"
+
+type Level int
+
+func (l Level) String() string { return [...]string{"D", "I", "W"}[l] }
+
+type P struct{ X, Y int }
+
+func (p *P) String() string { return fmt.Sprintf("P(%d,%d)", p.X, p.Y) }
+
+type E struct{ msg string }
+
+func (e E) Error() string { return "E: " + e.msg }
+
+type G struct{ v int }
+
+func (g G) GoString() string { return "G!" }
+
+type S struct {
+	L   Level
+	Err E
+	P   *P
+	l   Level
+}
+
+func main() {
+	fmt.Println([]Level{1, 2})
+	fmt.Printf("%v %d %q\n", []Level{0, 2}, []Level{0, 2}, [1]Level{1})
+	fmt.Println(map[Level]string{1: "one", 2: "two"})
+	fmt.Println([]*P{{1, 2}, {3, 4}}, []P{{5, 6}})
+	fmt.Printf("%v %+v\n", S{2, E{"x"}, &P{7, 8}, 1}, S{L: 1, Err: E{"y"}, P: &P{}, l: 2})
+	fmt.Printf("%#v %v\n", []G{{1}}, []G{{2}})
+}
+
+// Output:
+// [I W]
+// [D W] [0 2] ["I"]
+// map[I:one W:two]
+// [P(1,2) P(3,4)] [{5 6}]
+// {W E: x P(7,8) 1} {L:I Err:E: y P:P(0,0) l:2}
+// []main.G{G!} [{2}]
@@ -0,0 +1,37 @@
+package main
+
+import "fmt"
+
+func main() {
+	var fs []func() string
+	var ps []*int
+	for i := 0; i < 3; i++ {
+		x := i
+		var y = i * 10
+		fs = append(fs, func() string { return fmt.Sprint(x, y) })
+		ps = append(ps, &x)
+	}
+	for _, s := range []string{"a", "b"} {
+		for j := 0; j < 2; j++ {
+			t := s
+			fs = append(fs, func() string { return t })
+		}
+	}
+	for _, f := range fs {
+		fmt.Print(f(), " ")
+	}
+	fmt.Println(*ps[0], *ps[1], *ps[2])
+
+	for i := 0; i < 2; i++ {
+		n := i
+		defer func() {
+			fmt.Println("recover", n, recover())
+		}()
+	}
+	panic("boom")
+}
+
+// Output:
+// 0 0 1 10 2 20 a a b b 0 1 2
+// recover 1 boom
+// recover 0 <nil>
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+)
+
+type Op func(ctx context.Context) error
+
+type Handler func(w http.ResponseWriter, r *http.Request)
+
+func run(op Op) error { return op(context.Background()) }
+
+func apply(f func(a, b int) int, x, y int) int { return f(x, y) }
+
+func main() {
+	var f func(c context.Context) error = func(ctx context.Context) error { fmt.Println("f called"); return nil }
+	var op Op = f
+	fmt.Println(run(op), run(f))
+	g := (func(context.Context) error)(op)
+	fmt.Println(g(context.Background()))
+	op = Op(g)
+	fmt.Println(op(context.Background()))
+
+	add := func(x, y int) int { return x + y }
+	fmt.Println(apply(add, 1, 2))
+	var h func(int, int) int = add
+	fmt.Println(h(3, 4))
+
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { fmt.Fprint(w, "hello") })
+	rec := httptest.NewRecorder()
+	hf.ServeHTTP(rec, nil)
+	fmt.Println(rec.Body.String())
+
+	var hd Handler = func(w http.ResponseWriter, r *http.Request) { fmt.Fprint(w, "handler") }
+	hf2 := http.HandlerFunc(hd)
+	rec = httptest.NewRecorder()
+	hf2.ServeHTTP(rec, nil)
+	fmt.Println(rec.Body.String())
+
+	var raw func(http.ResponseWriter, *http.Request) = hf
+	rec = httptest.NewRecorder()
+	raw(rec, nil)
+	fmt.Println(rec.Body.String())
+
+	var v func(...int) int = func(a ...int) int { return len(a) }
+	fmt.Println(v(1, 2, 3))
+}
+
+// Output:
+// f called
+// f called
+// <nil> <nil>
+// f called
+// <nil>
+// f called
+// <nil>
+// 3
+// 7
+// hello
+// handler
+// hello
+// 3
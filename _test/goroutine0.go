@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+var c = make(chan int, 10)
+
+func send(i int) int {
+	c <- i
+	return i
+}
+
+func main() {
+	for i := 0; i < 10; i++ {
+		go send(i)
+	}
+	var r []int
+	for i := 0; i < 10; i++ {
+		r = append(r, <-c)
+	}
+	sort.Ints(r)
+	fmt.Println(r)
+}
+
+// Output:
+// [0 1 2 3 4 5 6 7 8 9]
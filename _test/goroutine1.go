@@ -0,0 +1,31 @@
+package main
+
+import "fmt"
+
+type T struct{ n int }
+
+func (t T) process(x int, c chan int) { c <- t.n*100 + x }
+
+func (t *T) pprocess(x int, c chan int) { c <- t.n*100 + x }
+
+func main() {
+	c := make(chan int)
+	t := T{1}
+	x := 2
+	go t.process(x, c)
+	t.n, x = 3, 4
+	fmt.Println(<-c)
+
+	t.n = 5
+	go t.pprocess(x, c)
+	fmt.Println(<-c)
+
+	go func(v int) { c <- v }(x)
+	x = 6
+	fmt.Println(<-c)
+}
+
+// Output:
+// 102
+// 504
+// 4
@@ -0,0 +1,27 @@
+package main
+
+import "fmt"
+
+type N int
+
+type F float32
+
+type T struct{ A [2]N }
+
+func main() {
+	var n N = 1
+	n++
+	var f F = 1.5
+	f--
+	c := complex64(1i)
+	c++
+	p := &n
+	*p++
+	(*p)++
+	t := &T{}
+	t.A[1]--
+	fmt.Println(n, f, c, t.A)
+}
+
+// Output:
+// 4 0.5 (1+1i) [0 -1]
@@ -2,6 +2,8 @@ package main
 
 import (
 	"log"
+	"os"
+	"reflect"
 
 	"github.com/containous/yaegi/interp"
 )
@@ -10,10 +12,11 @@ func main() {
 	log.SetFlags(log.Lshortfile)
 	i := interp.New(interp.Options{})
 	i.Use(interp.Symbols)
-	if _, err := i.Eval(`import "github.com/containous/yaegi/interp"`); err != nil {
+	i.Use(map[string]map[string]reflect.Value{"os": {"Stdout": reflect.ValueOf(&os.Stdout).Elem()}})
+	if _, err := i.Eval(`import ("github.com/containous/yaegi/interp"; "os")`); err != nil {
 		log.Fatal(err)
 	}
-	if _, err := i.Eval(`i := interp.New(interp.Options{})`); err != nil {
+	if _, err := i.Eval(`i := interp.New(interp.Options{Stderr: os.Stdout})`); err != nil {
 		log.Fatal(err)
 	}
 	if _, err := i.Eval(`i.Eval("println(42)")`); err != nil {
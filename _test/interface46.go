@@ -0,0 +1,39 @@
+package main
+
+import "fmt"
+
+type B bool
+
+type I interface{ M() }
+
+type T int
+
+func (T) M() {}
+
+func main() {
+	var x interface{} = T(2)
+
+	var ok B = true
+	var s = "s"
+	s, ok = x.(string)
+	fmt.Printf("%q %v\n", s, ok)
+
+	if v, ok := x.(T); ok {
+		fmt.Println("if", v, ok)
+	}
+	for i, ok := x.(I); ok; ok = false {
+		fmt.Println("for", i, ok)
+	}
+	fmt.Printf("%q %v\n", s, ok)
+
+	_, ok = x.(I)
+	_, ok1 := x.(fmt.Stringer)
+	fmt.Println(ok, ok1)
+}
+
+// Output:
+// "" false
+// if 2 true
+// for 2 true
+// "" false
+// true false
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+type ReadWriteCloser interface {
+	io.ReadCloser
+	io.WriteCloser
+}
+
+type Namer interface{ Name() string }
+
+type Both interface {
+	Namer
+	Name() string
+	fmt.Stringer
+}
+
+type file struct{ buf bytes.Buffer }
+
+func (f *file) Read(p []byte) (int, error)  { return f.buf.Read(p) }
+func (f *file) Write(p []byte) (int, error) { return f.buf.Write(p) }
+func (f *file) Close() error                { return nil }
+
+type T struct{}
+
+func (T) Name() string   { return "T" }
+func (T) String() string { return "t" }
+
+type nopCloser struct{ *bytes.Buffer }
+
+func (nopCloser) Close() error { return nil }
+
+func describe(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return "nil"
+	case any:
+		return fmt.Sprintf("any %v", x)
+	}
+	return ""
+}
+
+func main() {
+	var rwc ReadWriteCloser = &file{}
+	fmt.Fprint(rwc, "hello")
+	b, _ := ioutil.ReadAll(rwc)
+	fmt.Println(string(b), rwc.Close())
+
+	var bin ReadWriteCloser = nopCloser{new(bytes.Buffer)}
+	bin.Write([]byte("bin"))
+	b, _ = ioutil.ReadAll(bin)
+	fmt.Println(string(b))
+
+	var bo Both = T{}
+	fmt.Println(bo.Name(), bo.String())
+
+	m := map[string]any{"a": 1, "b": "two"}
+	fmt.Println(m["a"], m["b"])
+	fmt.Println(describe(nil), describe(3))
+	var a any = rwc
+	_, ok := a.(ReadWriteCloser)
+	_, ok2 := a.(io.ReadWriteCloser)
+	fmt.Println(ok, ok2)
+	s := []any{1, "x"}
+	fmt.Println(len(s))
+}
+
+// Output:
+// hello <nil>
+// bin
+// T t
+// 1 two
+// nil any 3
+// true true
+// 2
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"container/list"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type scriptReader struct{ name string }
+
+func (r *scriptReader) Read(b []byte) (int, error) { return 0, io.EOF }
+
+func (r *scriptReader) Name() string { return r.name }
+
+type valueReader struct{ n int }
+
+func (r valueReader) Read(b []byte) (int, error) { return 0, io.EOF }
+
+type namer interface{ Name() string }
+
+func describe(v interface{}) string {
+	switch r := v.(type) {
+	case *scriptReader:
+		return "script " + r.name
+	case valueReader:
+		return fmt.Sprint("value ", r.n)
+	case *strings.Reader:
+		return fmt.Sprint("strings ", r.Len())
+	default:
+		return "other"
+	}
+}
+
+func main() {
+	// Map storage.
+	m := map[string]io.Reader{}
+	m["a"] = &scriptReader{"a"}
+	m["b"] = strings.NewReader("bb")
+	m["c"] = valueReader{3}
+	for _, k := range []string{"a", "b", "c"} {
+		fmt.Println(k, describe(m[k]))
+	}
+	if r, ok := m["a"].(*scriptReader); ok {
+		fmt.Println("assert", r.name)
+	}
+	if n, ok := m["a"].(namer); ok {
+		fmt.Println("namer", n.Name())
+	}
+	_, ok := m["c"].(namer)
+	fmt.Println("namer", ok)
+
+	// Channel transport.
+	ch := make(chan io.Reader, 1)
+	ch <- &scriptReader{"ch"}
+	fmt.Println(describe(<-ch))
+
+	// Binary containers and function returns.
+	l := list.New()
+	l.PushBack(io.Reader(&scriptReader{"list"}))
+	fmt.Println(describe(l.Front().Value))
+	lr := io.LimitReader(&scriptReader{"limit"}, 3).(*io.LimitedReader)
+	switch r := lr.R.(type) {
+	case *scriptReader:
+		fmt.Println("limit", r.Name())
+	}
+	var get func() io.Reader = func() io.Reader { return valueReader{7} }
+	fmt.Println(describe(get()))
+}
+
+// Output:
+// a script a
+// b strings 2
+// c value 3
+// assert a
+// namer a
+// namer false
+// script ch
+// script list
+// limit limit
+// value 7
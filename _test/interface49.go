@@ -0,0 +1,21 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+)
+
+type Node struct{ pos, end token.Pos }
+
+func (n Node) Pos() token.Pos { return n.pos }
+func (n Node) End() token.Pos { return n.end }
+
+func main() {
+	var e ast.Expr = Node{2, 5}
+	var s ast.Stmt = &ast.ExprStmt{X: e}
+	fmt.Println(s.Pos(), s.End())
+}
+
+// Output:
+// 2 5
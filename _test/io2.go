@@ -0,0 +1,37 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+type chunkReader struct {
+	s     string
+	chunk int
+}
+
+// Read returns the last bytes along with io.EOF.
+func (r *chunkReader) Read(p []byte) (int, error) {
+	if len(r.s) == 0 {
+		return 0, io.EOF
+	}
+	if len(p) > r.chunk {
+		p = p[:r.chunk]
+	}
+	n := copy(p, r.s)
+	r.s = r.s[n:]
+	if len(r.s) == 0 {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func main() {
+	var b bytes.Buffer
+	n, err := io.Copy(&b, &chunkReader{s: "hello world", chunk: 4})
+	fmt.Println(n, err, b.String())
+}
+
+// Output:
+// 11 <nil> hello world
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+type byteReader struct{ b []byte }
+
+func (r *byteReader) Read(p []byte) (n int, err error) {
+	for n < len(p) && n < 3 && len(r.b) > 0 {
+		p[n] = r.b[0]
+		r.b = r.b[1:]
+		n++
+	}
+	if len(r.b) == 0 {
+		err = io.EOF
+	}
+	return
+}
+
+func main() {
+	s := bufio.NewScanner(&byteReader{[]byte("one\ntwo three\nfour")})
+	for s.Scan() {
+		fmt.Printf("%q\n", s.Text())
+	}
+	fmt.Println(s.Err())
+}
+
+// Output:
+// "one"
+// "two three"
+// "four"
+// <nil>
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"fmt"
+)
+
+type lineWriter struct{ lines *[]string }
+
+func (w lineWriter) Write(p []byte) (int, error) {
+	*w.lines = append(*w.lines, string(p))
+	return len(p), nil
+}
+
+func main() {
+	var lines []string
+	w := lineWriter{&lines}
+	fmt.Fprintf(w, "%d-%s", 1, "a")
+	fmt.Fprintln(w, "b", 2)
+	fmt.Printf("%q\n", lines)
+}
+
+// Output:
+// ["1-a" "b 2\n"]
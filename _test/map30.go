@@ -0,0 +1,33 @@
+package main
+
+import "fmt"
+
+type B bool
+
+func main() {
+	m := map[string]int{"a": 1}
+
+	var ok B = true
+	v := 5
+	v, ok = m["b"]
+	fmt.Println(v, ok)
+
+	if v, ok := m["a"]; ok {
+		fmt.Println("if", v, ok)
+	}
+	for v, ok := m["a"]; ok; ok = false {
+		fmt.Println("for", v, ok)
+	}
+	fmt.Println(v, ok)
+
+	_, ok = m["a"]
+	_, ok1 := m["b"]
+	fmt.Println(ok, ok1)
+}
+
+// Output:
+// 0 false
+// if 1 true
+// for 1 true
+// 0 false
+// true false
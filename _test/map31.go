@@ -0,0 +1,36 @@
+package main
+
+import "fmt"
+
+type T struct{ X int }
+
+var n int
+
+func key() string {
+	n++
+	return "k"
+}
+
+func main() {
+	m := map[string]int{}
+	m[key()] += 10
+	m[key()]++
+	m[key()] <<= 2
+	m["a"]--
+	fmt.Println(m, n)
+
+	s := map[int]string{}
+	s[1] += "hello"
+	s[1] += " world"
+	fmt.Println(s)
+
+	p := map[string]*T{"a": {}}
+	p["a"].X = 2
+	p["a"].X++
+	fmt.Println(p["a"].X)
+}
+
+// Output:
+// map[a:-1 k:44] 3
+// map[1:hello world]
+// 3
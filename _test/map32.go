@@ -0,0 +1,43 @@
+package main
+
+import "fmt"
+
+func main() {
+	m := map[int]int{}
+	for i := 0; i < 100; i++ {
+		m[i] = i
+	}
+	for k := range m {
+		delete(m, k)
+	}
+	fmt.Println(len(m))
+
+	// Entries deleted before being reached are not produced.
+	m = map[int]int{1: 1, 2: 2, 3: 3}
+	n := 0
+	for k := range m {
+		n++
+		for j := range m {
+			if j != k {
+				delete(m, j)
+			}
+		}
+	}
+	fmt.Println(n, len(m))
+
+	// Entries inserted during iteration may or may not be produced.
+	m = map[int]int{0: 0}
+	n = 0
+	for k := range m {
+		if n < 10 {
+			m[k+1] = k
+		}
+		n++
+	}
+	fmt.Println(n >= 1 && n <= 11)
+}
+
+// Output:
+// 0
+// 1 1
+// true
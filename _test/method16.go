@@ -17,4 +17,4 @@ func main() {
 }
 
 // Output:
-// &{Xproperty:value} param
+// &{property:value} param
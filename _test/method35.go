@@ -0,0 +1,46 @@
+package main
+
+import "fmt"
+
+type T struct{ n int }
+
+func (t T) Get() int { return t.n }
+
+func (t *T) Inc() { t.n++ }
+
+type U struct{ T }
+
+func apply(f func() int) int { return f() }
+
+func main() {
+	t := T{1}
+	f := t.Get
+	t = T{2}
+	fmt.Println(f(), t.Get())
+
+	g := t.Inc
+	g()
+	g()
+	fmt.Println(t.n)
+
+	p := &T{5}
+	h := p.Get
+	p.n = 6
+	fmt.Println(h())
+
+	u := U{T{7}}
+	k := u.Get
+	u.n = 8
+	fmt.Println(k(), apply(u.Get))
+
+	fs := []func() int{t.Get}
+	t.n = 100
+	fmt.Println(fs[0]())
+}
+
+// Output:
+// 1 2
+// 4
+// 5
+// 7 8
+// 4
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type T struct{ n int }
+
+func (t T) Shift(r rune) rune { return r + rune(t.n) }
+
+func main() {
+	t := T{1}
+	m := t.Shift
+	t.n = 2
+	fmt.Println(strings.Map(m, "abc"), strings.Map(t.Shift, "abc"))
+
+	var b strings.Builder
+	w := b.WriteString
+	w("hello")
+	fmt.Println(b.String())
+
+	fmt.Println(strings.Map(unicode.TurkishCase.ToUpper, "iii"))
+}
+
+// Output:
+// bcd cde
+// hello
+// İİİ
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+)
+
+type T struct{ n int }
+
+func (t *T) Inc()    { t.n++ }
+func (t T) Get() int { return t.n }
+
+type E struct{ T }
+
+type U struct {
+	t  T
+	pt *T
+}
+
+type P struct{ u *U }
+
+func main() {
+	var v T
+	v.Inc()
+	fmt.Println(v.n, v.Get())
+
+	s := []T{{}, {}}
+	for i := range s {
+		s[i].Inc()
+	}
+	fmt.Println(s[0].n, s[1].n)
+
+	var e E
+	e.Inc()
+	(&e).Inc()
+	fmt.Println(e.n)
+
+	p := &U{pt: &T{n: 2}}
+	pp := &p
+	(*pp).t.Inc()
+	(*pp).pt.Inc()
+	fmt.Println(p.t.n, (**pp).pt.Get())
+
+	m := map[string]*T{"a": {n: 5}}
+	m["a"].Inc()
+	fmt.Println(m["a"].n, m["a"].Get())
+
+	q := &P{u: p}
+	q.u.pt.Inc()
+	q.u.t.Inc()
+	fmt.Println(q.u.pt.n, q.u.t.Get())
+
+	bs := make([]bytes.Buffer, 1)
+	bs[0].WriteString("hi")
+	fmt.Println(bs[0].String())
+}
+
+// Output:
+// 1 1
+// 1 1
+// 2
+// 1 3
+// 6 6
+// 4 2
+// hi
@@ -0,0 +1,35 @@
+package main
+
+import "fmt"
+
+type Color int
+
+const (
+	Red Color = iota
+	Green
+	Blue
+)
+
+func (c Color) String() string { return [...]string{"red", "green", "blue"}[c] }
+
+type Celsius float64
+
+type Fahrenheit float64
+
+func (c Celsius) F() Fahrenheit { return Fahrenheit(c*9/5 + 32) }
+
+func (f Fahrenheit) String() string { return fmt.Sprintf("%.1fF", float64(f)) }
+
+func main() {
+	fmt.Println(Blue, Green)
+	fmt.Printf("%v %s %d %q\n", Red, Blue, Blue, Green)
+	var s fmt.Stringer = Green
+	fmt.Println(s)
+	fmt.Println(Celsius(100).F(), []Celsius{0, 100}[1].F())
+}
+
+// Output:
+// blue green
+// red blue 2 "green"
+// green
+// 212.0F 212.0F
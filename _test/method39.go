@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+type List []int
+
+func (l *List) Push(v int) { *l = append(*l, v) }
+
+func (l List) Len() int { return len(l) }
+
+type Set map[string]bool
+
+func (s Set) Add(k string) { s[k] = true }
+
+type Op func(int) int
+
+func (o Op) Twice(x int) int { return o(o(x)) }
+
+type Buf []byte
+
+func (b *Buf) Write(p []byte) (int, error) { *b = append(*b, p...); return len(p), nil }
+
+type Pusher interface{ Push(int) }
+
+type Lener interface{ Len() int }
+
+type Adder interface{ Add(string) }
+
+type Wrap struct {
+	List
+	n int
+}
+
+func main() {
+	var l List
+	var p Pusher = &l
+	p.Push(1)
+	p.Push(2)
+	var n Lener = l
+	fmt.Println(l, n.Len())
+
+	var a Adder = Set{}
+	a.Add("x")
+	fmt.Println(a)
+
+	fmt.Println(Op(func(x int) int { return x * 3 }).Twice(2))
+
+	var b Buf
+	var w io.Writer = &b
+	fmt.Fprintf(w, "x=%d", 3)
+	fmt.Println(string(b))
+
+	var wr Wrap
+	wr.Push(3)
+	var wp Pusher = &wr
+	wp.Push(4)
+	fmt.Println(wr.List, wr.Len())
+
+	ls := []List{{1}, {2, 3}}
+	ls[1].Push(4)
+	fmt.Println(ls, ls[1].Len())
+}
+
+// Output:
+// [1 2] 2
+// map[x:true]
+// 18
+// x=3
+// [3 4] 2
+// [[1] [2 3 4]] 3
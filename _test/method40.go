@@ -0,0 +1,32 @@
+package main
+
+import "fmt"
+
+type Named interface{ Name() string }
+
+type Key string
+
+func (k Key) Name() string { return "key:" + string(k) }
+
+type Color int
+
+func (c Color) String() string { return "color" }
+
+type Holder struct {
+	Named
+	Color
+}
+
+func main() {
+	h := Holder{Named: Key("a")}
+	fmt.Println(h.Name(), h.String())
+	var n Named = h
+	fmt.Println(n.Name())
+	var s fmt.Stringer = h
+	fmt.Println(s.String())
+}
+
+// Output:
+// key:a color
+// key:a
+// color
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+var trace []string
+
+func tr(s string) { trace = append(trace, s) }
+
+type T struct {
+	F int
+	S []int
+}
+
+func (t T) Get() T   { tr("Get"); return T{F: t.F + 1, S: t.S} }
+func (t *T) P() *T   { tr("P"); return t }
+func (t T) Val() int { return t.F }
+
+var (
+	gs = []int{1, 2}
+	gm = map[string]int{"k": 1}
+	gt = &T{F: 1, S: []int{1}}
+)
+
+func mk() T              { tr("mk"); return T{F: 1, S: []int{5, 6}} }
+func sl() []int          { tr("sl"); return gs }
+func mp() map[string]int { tr("mp"); return gm }
+func pk() *T             { tr("pk"); return gt }
+func it() interface{}    { tr("it"); return T{F: 3} }
+func idx() int           { tr("idx"); return 1 }
+func key() string        { tr("key"); return "k" }
+
+func main() {
+	fmt.Println(strings.Split("a,b", ",")[1], mk().Get().Get().F, mk().S[idx()])
+	fmt.Println(pk().P().Get().Val(), it().(T).Get().F, []T{mk()}[0].Get().F)
+	sl()[idx()] += 10
+	sl()[idx()]++
+	mp()[key()] += 10
+	pk().P().F *= 2
+	pk().S[0]++
+	fmt.Println(gs, gm, gt.F, gt.S)
+	fmt.Println(strings.Join(trace, " "))
+}
+
+// Output:
+// b 3 6
+// 2 4 2
+// [1 13] map[k:11] 2 [2]
+// mk Get Get mk idx pk P Get it Get mk Get sl idx sl idx mp key pk P pk
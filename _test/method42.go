@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"time"
+)
+
+type Enum int
+
+const (
+	A Enum = iota
+	B
+)
+
+func (e Enum) String() string { return fmt.Sprint("enum", int(e)) }
+func (e Enum) Next() Enum     { return e + 1 }
+
+type Point struct{ X, Y float64 }
+
+func (p Point) Norm() float64     { return math.Hypot(p.X, p.Y) }
+func (p Point) Add(q Point) Point { return Point{p.X + q.X, p.Y + q.Y} }
+
+type List []int
+
+func (l List) Len() int { return len(l) }
+
+func main() {
+	fmt.Println(time.Minute.String(), (2 * time.Second).String(), time.January.String(), os.FileMode(0755).String())
+	fmt.Println(Enum(3).String(), B.String(), B.Next().Next().String(), A.Next())
+	fmt.Println(Point{3, 4}.Norm(), Point{1, 2}.Add(Point{2, 2}).Norm(), List{1, 2}.Len())
+
+	const d = time.Hour
+	f, g := Enum(5).String, d.String
+	fmt.Println(f(), g(), (d + time.Minute).Minutes())
+}
+
+// Output:
+// 1m0s 2s January -rwxr-xr-x
+// enum3 enum1 enum3 enum1
+// 5 5 2
+// enum5 1h0m0s 61
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+type T struct{ X int }
+
+func (t *T) Inc() { t.X++ }
+
+type N int
+
+func (n N) Double() N { return n * 2 }
+
+func main() {
+	a := new([8]byte)
+	a[2] = 3
+	fmt.Println(a, len(a))
+
+	b := new(map[string]int)
+	fmt.Println(*b == nil, len(*b))
+
+	c := new(time.Duration)
+	*c = 3 * time.Second
+	fmt.Println(c.String())
+
+	d := new(T)
+	d.Inc()
+	fmt.Println(d.X)
+
+	e := new(N)
+	*e = 4
+	fmt.Println(e.Double())
+
+	f1, f2 := new(int), new(int)
+	fmt.Println(f1 != f2, *f1 == *f2)
+
+	g := new(interface{})
+	fmt.Println(*g == nil)
+}
+
+// Output:
+// &[0 0 3 0 0 0 0 0] 8
+// true 0
+// 3s
+// 1
+// 8
+// true true
+// true
@@ -0,0 +1,9 @@
+package main
+
+func main() {
+	x := 1
+	_ = new(x)
+}
+
+// Error:
+// _test/new4.go:5:10: x is not a type
@@ -0,0 +1,56 @@
+package main
+
+import "fmt"
+
+type T struct{ A int }
+
+type S struct {
+	M map[string]int
+	L []int
+	F func()
+	P *T
+	I interface{}
+	C chan int
+}
+
+var gm map[string]int
+var gs []T
+var gi interface{}
+var gf func()
+var gp *T
+var gc chan int
+
+func main() {
+	var m map[string]int
+	var s []T
+	var i interface{}
+	var f func()
+	var p *T
+	var c chan int
+	var e error
+	fmt.Println(m == nil, s == nil, i == nil, f == nil, p == nil, c == nil, e == nil, len(m), len(s), m["a"])
+	fmt.Println(gm == nil, gs == nil, gi == nil, gf == nil, gp == nil, gc == nil)
+	var st S
+	fmt.Println(st.M == nil, st.L == nil, st.F == nil, st.P == nil, st.I == nil, st.C == nil)
+	if m == nil {
+		m = make(map[string]int)
+	}
+	m["x"] = 1
+	fmt.Println(m, m == nil)
+	var arr [2][]int
+	fmt.Println(arr[0] == nil)
+	var mm map[string][]int
+	fmt.Println(mm["a"] == nil)
+	defer func() { fmt.Println("recovered:", recover()) }()
+	var w map[string]int
+	w["a"] = 1
+}
+
+// Output:
+// true true true true true true true 0 0 0
+// true true true true true true
+// true true true true true true
+// map[x:1] false
+// true
+// true
+// recovered: assignment to entry in nil map
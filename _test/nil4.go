@@ -0,0 +1,61 @@
+package main
+
+import "fmt"
+
+type S struct{ I interface{} }
+
+type E struct{ Err error }
+
+func ret() interface{} { return nil }
+
+func isNil(v interface{}) bool { return v == nil }
+
+func main() {
+	var st S
+	var es E
+	fmt.Println(st.I == nil, st.I != nil, es.Err == nil)
+	if st.I == nil {
+		fmt.Println("nil field")
+	}
+	st.I = 1
+	fmt.Println(st.I == nil, st.I)
+	st.I = nil
+	fmt.Println(st.I == nil)
+	var i interface{} = nil
+	fmt.Println(i == nil, ret() == nil)
+	m := map[string]interface{}{}
+	fmt.Println(m["x"] == nil)
+	l := make([]interface{}, 1)
+	fmt.Println(l[0] == nil)
+	ps := &S{}
+	fmt.Println(ps.I == nil)
+	var a [2]interface{}
+	fmt.Println(a[1] == nil)
+	var ms map[string]S
+	fmt.Println(ms["a"].I == nil, len(ms), ms == nil)
+	var sl []int
+	sl2 := sl[:0]
+	fmt.Println(sl2 == nil, sl == nil)
+	var fn func() int
+	fmt.Println(fn == nil)
+	var ch chan int
+	fmt.Println(ch == nil, len(ch), cap(ch))
+	x := st.I
+	fmt.Println(x == nil, isNil(st.I), isNil(es.Err))
+}
+
+// Output:
+// true false true
+// nil field
+// false 1
+// true
+// true true
+// true
+// true
+// true
+// true
+// true 0 true
+// true true
+// true
+// true 0 0
+// true true true
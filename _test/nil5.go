@@ -0,0 +1,60 @@
+package main
+
+import "fmt"
+
+type MyError struct{}
+
+func (e *MyError) Error() string { return "my error" }
+
+func find() *MyError { return nil }
+
+func get() error {
+	var p *MyError
+	return p
+}
+
+func none() error { return nil }
+
+func pair() (int, error) { return 1, find() }
+
+func isNil(err error) bool { return err == nil }
+
+func main() {
+	// A nil pointer boxed in an interface is a non-nil interface.
+	var err error = find()
+	fmt.Println(err == nil, err != nil)
+	err = get()
+	fmt.Println(err == nil, err)
+	_, err = pair()
+	fmt.Println(err == nil)
+	fmt.Println(isNil(find()), isNil(none()), isNil(nil))
+
+	// Untyped nil assigned to an interface is the nil interface.
+	err = none()
+	fmt.Println(err == nil)
+	err = nil
+	fmt.Println(err == nil)
+
+	es := []error{nil, find(), none()}
+	fmt.Println(es[0] == nil, es[1] == nil, es[2] == nil)
+	m := map[string]error{"a": find(), "b": nil}
+	fmt.Println(m["a"] == nil, m["b"] == nil, m["c"] == nil)
+	ch := make(chan error, 2)
+	ch <- find()
+	ch <- nil
+	fmt.Println(<-ch == nil, <-ch == nil)
+	s := struct{ E error }{E: find()}
+	fmt.Println(s.E == nil)
+}
+
+// Output:
+// false true
+// false my error
+// false
+// false true true
+// true
+// true
+// true false true
+// false true true
+// false true
+// false
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type W struct{}
+
+func (w *W) Write(b []byte) (int, error) { return len(b), nil }
+
+func writer() io.Writer {
+	var w *W
+	return w
+}
+
+func atoi(s string) error {
+	_, err := strconv.Atoi(s)
+	return err
+}
+
+func value() interface{} { return strconv.Itoa(1) }
+
+func values() (interface{}, interface{}) { return strconv.Atoi("2") }
+
+func main() {
+	// Nil pointers boxed in binary interfaces are non-nil interfaces.
+	var w io.Writer = (*W)(nil)
+	fmt.Println(w == nil, writer() == nil)
+	n, err := w.Write([]byte("abc"))
+	fmt.Println(n, err)
+	var sr *strings.Reader
+	var r io.Reader = sr
+	fmt.Println(r == nil)
+
+	// Nil errors returned by binary functions are nil interfaces.
+	var e interface{}
+	_, e = strconv.Atoi("1")
+	fmt.Println(e == nil, atoi("1") == nil, atoi("x") == nil)
+	a, b := values()
+	fmt.Println(value(), a, b == nil)
+}
+
+// Output:
+// false false
+// 3 <nil>
+// false
+// true true false
+// 1 2 true
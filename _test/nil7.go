@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type T struct {
+	cb func(string) string
+	m  map[string][]string
+	s  []string
+}
+
+func redirect(req *http.Request, via []*http.Request) error { return nil }
+
+func main() {
+	var t T
+	fmt.Println(t.cb == nil, nil == t.m, t.s == nil)
+
+	// Values set from binary packages.
+	t.cb = strings.ToUpper
+	t.m = http.Header{}
+	t.s = strings.Fields("a b")
+	fmt.Println(t.cb == nil, nil == t.cb, nil != t.m, t.s != nil)
+
+	// Binary func fields holding nil or interpreted functions.
+	var c http.Client
+	var f func(*http.Request, []*http.Request) error
+	fmt.Println(c.CheckRedirect == nil, nil == f)
+	c.CheckRedirect = f
+	fmt.Println(c.CheckRedirect == nil, nil != c.CheckRedirect)
+	c.CheckRedirect = redirect
+	fmt.Println(c.CheckRedirect == nil, nil != c.CheckRedirect)
+
+	// Nil functions in slices.
+	var fns []func()
+	fns = append(fns, nil, func() {})
+	fmt.Println(fns[0] == nil, nil == fns[1])
+}
+
+// Output:
+// true true true
+// false false true true
+// true true
+// true false
+// false true
+// true false
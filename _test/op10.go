@@ -0,0 +1,466 @@
+package main
+
+import (
+	"fmt"
+	"hash/crc32"
+	"math"
+)
+
+// Integer operations wrap around at the exact width of operands. The results
+// over boundary values are summarized by a checksum for each integer type.
+
+const maxInt = int(^uint(0) >> 1)
+
+func sum(name string, r interface{}) {
+	fmt.Println(name, crc32.ChecksumIEEE([]byte(fmt.Sprint(r))))
+}
+
+func checkInt8() {
+	vals := []int8{math.MinInt8, math.MinInt8 + 1, -2, -1, 0, 1, 2, math.MaxInt8 - 1, math.MaxInt8}
+	var r []int8
+	for _, a := range vals {
+		for _, b := range vals {
+			r = append(r, a+b, a-b, a*b, a&b, a|b, a^b, a&^b)
+			c := a
+			c += b
+			c *= b
+			c -= a
+			c ^= b
+			r = append(r, c)
+			if b != 0 {
+				r = append(r, a/b, a%b)
+				c = a
+				c /= b
+				r = append(r, c)
+				c = a
+				c %= b
+				r = append(r, c)
+			}
+		}
+		for _, s := range []uint{0, 1, 7, 8, 15, 16, 31, 32, 63, 64, 100} {
+			c := a
+			c <<= s
+			r = append(r, a<<s, a>>s, c)
+		}
+		r = append(r, -a, ^a, +a, a+1, a-1, 1-a, 2*a, a*3, a/2, a%3, a<<1, a>>1, a<<7, a>>7)
+		r = append(r, a*-1, a/-1, a%-1, -1-a)
+		c := a
+		c++
+		r = append(r, c)
+		c = a
+		c--
+		r = append(r, c)
+	}
+	sum("int8", r)
+}
+
+func checkInt16() {
+	vals := []int16{math.MinInt16, math.MinInt16 + 1, -2, -1, 0, 1, 2, math.MaxInt16 - 1, math.MaxInt16}
+	var r []int16
+	for _, a := range vals {
+		for _, b := range vals {
+			r = append(r, a+b, a-b, a*b, a&b, a|b, a^b, a&^b)
+			c := a
+			c += b
+			c *= b
+			c -= a
+			c ^= b
+			r = append(r, c)
+			if b != 0 {
+				r = append(r, a/b, a%b)
+				c = a
+				c /= b
+				r = append(r, c)
+				c = a
+				c %= b
+				r = append(r, c)
+			}
+		}
+		for _, s := range []uint{0, 1, 7, 8, 15, 16, 31, 32, 63, 64, 100} {
+			c := a
+			c <<= s
+			r = append(r, a<<s, a>>s, c)
+		}
+		r = append(r, -a, ^a, +a, a+1, a-1, 1-a, 2*a, a*3, a/2, a%3, a<<1, a>>1, a<<7, a>>7)
+		r = append(r, a*-1, a/-1, a%-1, -1-a)
+		c := a
+		c++
+		r = append(r, c)
+		c = a
+		c--
+		r = append(r, c)
+	}
+	sum("int16", r)
+}
+
+func checkInt32() {
+	vals := []int32{math.MinInt32, math.MinInt32 + 1, -2, -1, 0, 1, 2, math.MaxInt32 - 1, math.MaxInt32}
+	var r []int32
+	for _, a := range vals {
+		for _, b := range vals {
+			r = append(r, a+b, a-b, a*b, a&b, a|b, a^b, a&^b)
+			c := a
+			c += b
+			c *= b
+			c -= a
+			c ^= b
+			r = append(r, c)
+			if b != 0 {
+				r = append(r, a/b, a%b)
+				c = a
+				c /= b
+				r = append(r, c)
+				c = a
+				c %= b
+				r = append(r, c)
+			}
+		}
+		for _, s := range []uint{0, 1, 7, 8, 15, 16, 31, 32, 63, 64, 100} {
+			c := a
+			c <<= s
+			r = append(r, a<<s, a>>s, c)
+		}
+		r = append(r, -a, ^a, +a, a+1, a-1, 1-a, 2*a, a*3, a/2, a%3, a<<1, a>>1, a<<7, a>>7)
+		r = append(r, a*-1, a/-1, a%-1, -1-a)
+		c := a
+		c++
+		r = append(r, c)
+		c = a
+		c--
+		r = append(r, c)
+	}
+	sum("int32", r)
+}
+
+func checkInt64() {
+	vals := []int64{math.MinInt64, math.MinInt64 + 1, -2, -1, 0, 1, 2, math.MaxInt64 - 1, math.MaxInt64}
+	var r []int64
+	for _, a := range vals {
+		for _, b := range vals {
+			r = append(r, a+b, a-b, a*b, a&b, a|b, a^b, a&^b)
+			c := a
+			c += b
+			c *= b
+			c -= a
+			c ^= b
+			r = append(r, c)
+			if b != 0 {
+				r = append(r, a/b, a%b)
+				c = a
+				c /= b
+				r = append(r, c)
+				c = a
+				c %= b
+				r = append(r, c)
+			}
+		}
+		for _, s := range []uint{0, 1, 7, 8, 15, 16, 31, 32, 63, 64, 100} {
+			c := a
+			c <<= s
+			r = append(r, a<<s, a>>s, c)
+		}
+		r = append(r, -a, ^a, +a, a+1, a-1, 1-a, 2*a, a*3, a/2, a%3, a<<1, a>>1, a<<7, a>>7)
+		r = append(r, a*-1, a/-1, a%-1, -1-a)
+		c := a
+		c++
+		r = append(r, c)
+		c = a
+		c--
+		r = append(r, c)
+	}
+	sum("int64", r)
+}
+
+func checkInt() {
+	vals := []int{-maxInt - 1, -maxInt - 1 + 1, -2, -1, 0, 1, 2, maxInt - 1, maxInt}
+	var r []int
+	for _, a := range vals {
+		for _, b := range vals {
+			r = append(r, a+b, a-b, a*b, a&b, a|b, a^b, a&^b)
+			c := a
+			c += b
+			c *= b
+			c -= a
+			c ^= b
+			r = append(r, c)
+			if b != 0 {
+				r = append(r, a/b, a%b)
+				c = a
+				c /= b
+				r = append(r, c)
+				c = a
+				c %= b
+				r = append(r, c)
+			}
+		}
+		for _, s := range []uint{0, 1, 7, 8, 15, 16, 31, 32, 63, 64, 100} {
+			c := a
+			c <<= s
+			r = append(r, a<<s, a>>s, c)
+		}
+		r = append(r, -a, ^a, +a, a+1, a-1, 1-a, 2*a, a*3, a/2, a%3, a<<1, a>>1, a<<7, a>>7)
+		r = append(r, a*-1, a/-1, a%-1, -1-a)
+		c := a
+		c++
+		r = append(r, c)
+		c = a
+		c--
+		r = append(r, c)
+	}
+	sum("int", r)
+}
+
+func checkUint8() {
+	vals := []uint8{0, 1, 2, 3, math.MaxUint8 / 2, math.MaxUint8/2 + 1, math.MaxUint8 - 1, math.MaxUint8}
+	var r []uint8
+	for _, a := range vals {
+		for _, b := range vals {
+			r = append(r, a+b, a-b, a*b, a&b, a|b, a^b, a&^b)
+			c := a
+			c += b
+			c *= b
+			c -= a
+			c ^= b
+			r = append(r, c)
+			if b != 0 {
+				r = append(r, a/b, a%b)
+				c = a
+				c /= b
+				r = append(r, c)
+				c = a
+				c %= b
+				r = append(r, c)
+			}
+		}
+		for _, s := range []uint{0, 1, 7, 8, 15, 16, 31, 32, 63, 64, 100} {
+			c := a
+			c <<= s
+			r = append(r, a<<s, a>>s, c)
+		}
+		r = append(r, -a, ^a, +a, a+1, a-1, 1-a, 2*a, a*3, a/2, a%3, a<<1, a>>1, a<<7, a>>7)
+		c := a
+		c++
+		r = append(r, c)
+		c = a
+		c--
+		r = append(r, c)
+	}
+	sum("uint8", r)
+}
+
+func checkUint16() {
+	vals := []uint16{0, 1, 2, 3, math.MaxUint16 / 2, math.MaxUint16/2 + 1, math.MaxUint16 - 1, math.MaxUint16}
+	var r []uint16
+	for _, a := range vals {
+		for _, b := range vals {
+			r = append(r, a+b, a-b, a*b, a&b, a|b, a^b, a&^b)
+			c := a
+			c += b
+			c *= b
+			c -= a
+			c ^= b
+			r = append(r, c)
+			if b != 0 {
+				r = append(r, a/b, a%b)
+				c = a
+				c /= b
+				r = append(r, c)
+				c = a
+				c %= b
+				r = append(r, c)
+			}
+		}
+		for _, s := range []uint{0, 1, 7, 8, 15, 16, 31, 32, 63, 64, 100} {
+			c := a
+			c <<= s
+			r = append(r, a<<s, a>>s, c)
+		}
+		r = append(r, -a, ^a, +a, a+1, a-1, 1-a, 2*a, a*3, a/2, a%3, a<<1, a>>1, a<<7, a>>7)
+		c := a
+		c++
+		r = append(r, c)
+		c = a
+		c--
+		r = append(r, c)
+	}
+	sum("uint16", r)
+}
+
+func checkUint32() {
+	vals := []uint32{0, 1, 2, 3, math.MaxUint32 / 2, math.MaxUint32/2 + 1, math.MaxUint32 - 1, math.MaxUint32}
+	var r []uint32
+	for _, a := range vals {
+		for _, b := range vals {
+			r = append(r, a+b, a-b, a*b, a&b, a|b, a^b, a&^b)
+			c := a
+			c += b
+			c *= b
+			c -= a
+			c ^= b
+			r = append(r, c)
+			if b != 0 {
+				r = append(r, a/b, a%b)
+				c = a
+				c /= b
+				r = append(r, c)
+				c = a
+				c %= b
+				r = append(r, c)
+			}
+		}
+		for _, s := range []uint{0, 1, 7, 8, 15, 16, 31, 32, 63, 64, 100} {
+			c := a
+			c <<= s
+			r = append(r, a<<s, a>>s, c)
+		}
+		r = append(r, -a, ^a, +a, a+1, a-1, 1-a, 2*a, a*3, a/2, a%3, a<<1, a>>1, a<<7, a>>7)
+		c := a
+		c++
+		r = append(r, c)
+		c = a
+		c--
+		r = append(r, c)
+	}
+	sum("uint32", r)
+}
+
+func checkUint64() {
+	vals := []uint64{0, 1, 2, 3, math.MaxUint64 / 2, math.MaxUint64/2 + 1, math.MaxUint64 - 1, math.MaxUint64}
+	var r []uint64
+	for _, a := range vals {
+		for _, b := range vals {
+			r = append(r, a+b, a-b, a*b, a&b, a|b, a^b, a&^b)
+			c := a
+			c += b
+			c *= b
+			c -= a
+			c ^= b
+			r = append(r, c)
+			if b != 0 {
+				r = append(r, a/b, a%b)
+				c = a
+				c /= b
+				r = append(r, c)
+				c = a
+				c %= b
+				r = append(r, c)
+			}
+		}
+		for _, s := range []uint{0, 1, 7, 8, 15, 16, 31, 32, 63, 64, 100} {
+			c := a
+			c <<= s
+			r = append(r, a<<s, a>>s, c)
+		}
+		r = append(r, -a, ^a, +a, a+1, a-1, 1-a, 2*a, a*3, a/2, a%3, a<<1, a>>1, a<<7, a>>7)
+		c := a
+		c++
+		r = append(r, c)
+		c = a
+		c--
+		r = append(r, c)
+	}
+	sum("uint64", r)
+}
+
+func checkUint() {
+	vals := []uint{0, 1, 2, 3, ^uint(0) / 2, ^uint(0)/2 + 1, ^uint(0) - 1, ^uint(0)}
+	var r []uint
+	for _, a := range vals {
+		for _, b := range vals {
+			r = append(r, a+b, a-b, a*b, a&b, a|b, a^b, a&^b)
+			c := a
+			c += b
+			c *= b
+			c -= a
+			c ^= b
+			r = append(r, c)
+			if b != 0 {
+				r = append(r, a/b, a%b)
+				c = a
+				c /= b
+				r = append(r, c)
+				c = a
+				c %= b
+				r = append(r, c)
+			}
+		}
+		for _, s := range []uint{0, 1, 7, 8, 15, 16, 31, 32, 63, 64, 100} {
+			c := a
+			c <<= s
+			r = append(r, a<<s, a>>s, c)
+		}
+		r = append(r, -a, ^a, +a, a+1, a-1, 1-a, 2*a, a*3, a/2, a%3, a<<1, a>>1, a<<7, a>>7)
+		c := a
+		c++
+		r = append(r, c)
+		c = a
+		c--
+		r = append(r, c)
+	}
+	sum("uint", r)
+}
+
+func checkUintptr() {
+	vals := []uintptr{0, 1, 2, 3, ^uintptr(0) / 2, ^uintptr(0)/2 + 1, ^uintptr(0) - 1, ^uintptr(0)}
+	var r []uintptr
+	for _, a := range vals {
+		for _, b := range vals {
+			r = append(r, a+b, a-b, a*b, a&b, a|b, a^b, a&^b)
+			c := a
+			c += b
+			c *= b
+			c -= a
+			c ^= b
+			r = append(r, c)
+			if b != 0 {
+				r = append(r, a/b, a%b)
+				c = a
+				c /= b
+				r = append(r, c)
+				c = a
+				c %= b
+				r = append(r, c)
+			}
+		}
+		for _, s := range []uint{0, 1, 7, 8, 15, 16, 31, 32, 63, 64, 100} {
+			c := a
+			c <<= s
+			r = append(r, a<<s, a>>s, c)
+		}
+		r = append(r, -a, ^a, +a, a+1, a-1, 1-a, 2*a, a*3, a/2, a%3, a<<1, a>>1, a<<7, a>>7)
+		c := a
+		c++
+		r = append(r, c)
+		c = a
+		c--
+		r = append(r, c)
+	}
+	sum("uintptr", r)
+}
+
+func main() {
+	checkInt8()
+	checkInt16()
+	checkInt32()
+	checkInt64()
+	checkInt()
+	checkUint8()
+	checkUint16()
+	checkUint32()
+	checkUint64()
+	checkUint()
+	checkUintptr()
+}
+
+// Output:
+// int8 1150770540
+// int16 225107748
+// int32 3925322893
+// int64 2107419846
+// int 2107419846
+// uint8 2871497361
+// uint16 2346744382
+// uint32 3941220508
+// uint64 3844050630
+// uint 3844050630
+// uintptr 3844050630
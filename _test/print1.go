@@ -0,0 +1,12 @@
+package main
+
+func main() {
+	x := 1.5
+	var e error
+	print("a", 1, x, true, "\n")
+	println("a", -1, x, uint8(3), false, 2+3i, float32(0.1), e)
+}
+
+// Output:
+// a11.5true
+// a -1 1.5 3 false (2+3i) 0.1 (0x0,0x0)
@@ -0,0 +1,10 @@
+package main
+
+type T struct{ X int }
+
+func main() {
+	println(T{1})
+}
+
+// Error:
+// _test/print2.go:6:10: illegal types for operand: println
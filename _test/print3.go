@@ -0,0 +1,14 @@
+package main
+
+import "fmt"
+
+func print(a ...interface{}) { fmt.Println("print:", a) }
+
+func main() {
+	print(1, 2)
+	println(3, 4)
+}
+
+// Output:
+// print: [1 2]
+// 3 4
@@ -0,0 +1,38 @@
+package main
+
+import "fmt"
+
+func main() {
+	// Emoji, combining characters and invalid UTF-8 bytes.
+	s := "a😀e\u0301\xffz\xe2\x82"
+	var runes []string
+	for i, r := range s {
+		runes = append(runes, fmt.Sprintf("%d:%q", i, r))
+	}
+	fmt.Println(runes)
+
+	var index []int
+	for i := range s {
+		index = append(index, i)
+		i += 10
+	}
+	fmt.Println(index)
+
+	n := 0
+	for range s {
+		n++
+	}
+	fmt.Println(n, len(s))
+
+	var r rune
+	for _, r = range "日本" {
+		fmt.Printf("%c", r)
+	}
+	fmt.Println()
+}
+
+// Output:
+// [0:'a' 1:'😀' 5:'e' 6:'́' 8:'�' 9:'z' 10:'�' 11:'�']
+// [0 1 5 6 8 9 10 11]
+// 8 12
+// 日本
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+var errBase = errors.New("base")
+
+type E struct{ msg string }
+
+func (e E) Error() string { return "E: " + e.msg }
+
+type S int
+
+func (s S) String() string { return fmt.Sprint("S", int(s)) }
+
+func check() {
+	r := recover()
+	fmt.Printf("%v\n", r)
+	if err, ok := r.(error); ok {
+		fmt.Println(errors.Is(err, errBase))
+	}
+	if e, ok := r.(E); ok {
+		fmt.Println(e.msg)
+	}
+	if s, ok := r.(S); ok {
+		fmt.Println(int(s))
+	}
+}
+
+func panicWrapped() {
+	defer check()
+	panic(fmt.Errorf("boom: %w", errBase))
+}
+
+func panicError() {
+	defer check()
+	panic(E{"x"})
+}
+
+func panicStringer() {
+	defer check()
+	panic(S(3))
+}
+
+func main() {
+	panicWrapped()
+	panicError()
+	panicStringer()
+}
+
+// Output:
+// boom: base
+// true
+// E: x
+// false
+// x
+// S3
+// 3
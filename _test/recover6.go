@@ -0,0 +1,44 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+func compile(pattern string) (re *regexp.Regexp, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("recovered: %v", r)
+		}
+	}()
+	return regexp.MustCompile(pattern), nil
+}
+
+func marshal(v interface{}) (s string) {
+	defer func() {
+		if r := recover(); r != nil {
+			s = fmt.Sprintf("recovered %T: %v", r, r)
+		}
+	}()
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	return string(b)
+}
+
+func main() {
+	_, err := compile("a(")
+	fmt.Println(err)
+
+	fmt.Println(marshal(map[string]int{"a": 1}))
+	fmt.Println(marshal(make(chan int)))
+}
+
+// Output:
+// recovered: regexp: Compile(`a(`): error parsing regexp: missing closing ): `a(`
+// {
+//   "a": 1
+// }
+// recovered *json.UnsupportedTypeError: json: unsupported type: chan int
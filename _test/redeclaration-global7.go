@@ -0,0 +1,13 @@
+package main
+
+var foo = 1
+
+var foo = 2
+
+func main() {
+	println(foo)
+}
+
+// Error:
+// ../_test/redeclaration-global7.go:5:5: foo redeclared in this block
+//	previous declaration at ../_test/redeclaration-global7.go:3:5
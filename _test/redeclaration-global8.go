@@ -0,0 +1,13 @@
+package main
+
+func foo() {}
+
+var foo int
+
+func main() {
+	println(foo)
+}
+
+// Error:
+// ../_test/redeclaration-global8.go:5:5: foo redeclared in this block
+//	previous declaration at ../_test/redeclaration-global8.go:3:6
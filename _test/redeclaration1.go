@@ -10,3 +10,4 @@ func main() {
 
 // Error:
 // ../_test/redeclaration1.go:6:6: foo redeclared in this block
+//	previous declaration at ../_test/redeclaration1.go:4:6
@@ -11,3 +11,4 @@ func main() {
 
 // Error:
 // ../_test/redeclaration3.go:7:7: foo redeclared in this block
+//	previous declaration at ../_test/redeclaration3.go:4:6
@@ -12,3 +12,4 @@ func main() {
 
 // Error:
 // ../_test/redeclaration4.go:8:7: foo redeclared in this block
+//	previous declaration at ../_test/redeclaration4.go:4:6
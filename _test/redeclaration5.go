@@ -12,3 +12,4 @@ func main() {
 
 // Error:
 // ../_test/redeclaration5.go:8:7: foo redeclared in this block
+//	previous declaration at ../_test/redeclaration5.go:4:7
@@ -0,0 +1,10 @@
+package main
+
+func main() {
+	foo := 1
+	foo := 2
+	println(foo)
+}
+
+// Error:
+// ../_test/redeclaration6.go:5:2: no new variables on left side of :=
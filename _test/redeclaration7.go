@@ -0,0 +1,11 @@
+package main
+
+func foo(a int) (a int) { return 1 }
+
+func main() {
+	println(foo(1))
+}
+
+// Error:
+// ../_test/redeclaration7.go:3:18: a redeclared in this block
+//	previous declaration at ../_test/redeclaration7.go:3:10
@@ -0,0 +1,14 @@
+package main
+
+func foo(a int) {
+	var a string
+	println(a)
+}
+
+func main() {
+	foo(1)
+}
+
+// Error:
+// ../_test/redeclaration8.go:4:6: a redeclared in this block
+//	previous declaration at ../_test/redeclaration8.go:3:10
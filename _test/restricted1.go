@@ -14,5 +14,5 @@ func main() {
 	println("not printed")
 }
 
-// Output:
-// recover: os.Exit(1)
+// Error:
+// exit status 1
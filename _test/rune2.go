@@ -0,0 +1,27 @@
+package main
+
+import "fmt"
+
+type MyRunes []rune
+
+func main() {
+	var s []string
+	for _, i := range []int{65, 0x263a, 0xD800, 0xDFFF, 0x10FFFF, 0x110000, -1, 1<<32 + 65} {
+		s = append(s, string(i), string(rune(i)))
+	}
+	fmt.Printf("%q\n", s)
+
+	var u uint64 = 1<<64 - 1
+	var v uint8 = 66
+	var r rune = 'é'
+	fmt.Printf("%q %q %q\n", string(u), string(v), string(r))
+
+	fmt.Printf("%q %q %q\n", string(MyRunes{'a', 0xD800, 'b'}), string([]byte{0xff, 'c'}), string([]rune("日本")))
+	fmt.Printf("%q %q %q %q\n", string(65), string(0xD800), string(0x110000), string(-1))
+}
+
+// Output:
+// ["A" "A" "☺" "☺" "�" "�" "�" "�" "\U0010ffff" "\U0010ffff" "�" "�" "�" "�" "�" "A"]
+// "�" "B" "é"
+// "a�b" "\xffc" "日本"
+// "A" "�" "�" "�"
@@ -0,0 +1,37 @@
+package main
+
+import "fmt"
+
+func main() {
+	c := make(chan int, 10)
+	for i := 0; i < 10; i++ {
+		c <- i
+	}
+
+	n := 0
+sel:
+	for {
+		select {
+		case v := <-c:
+			if v == 3 {
+				break sel
+			}
+			n++
+		}
+	}
+	fmt.Println("n", n)
+
+	j := 0
+	for j < 3 {
+		select {
+		case <-c:
+			j++
+			break
+		}
+	}
+	fmt.Println("j", j, len(c))
+}
+
+// Output:
+// n 3
+// j 3 3
@@ -0,0 +1,51 @@
+package main
+
+import "fmt"
+
+type req struct {
+	n     int
+	reply chan<- int
+}
+
+func serve(reqs <-chan req, quit chan chan struct{}) {
+	for {
+		select {
+		case r := <-reqs:
+			x := r.n * 2
+			r.reply <- x
+		case q := <-quit:
+			close(q)
+			return
+		}
+	}
+}
+
+func main() {
+	c := make(chan int, 1)
+	select {
+	case c <- 1:
+	}
+	var v int
+	select {
+	case v = <-c:
+	}
+	fmt.Println(v)
+
+	reqs := make(chan req)
+	quit := make(chan chan struct{})
+	go serve(reqs, quit)
+	reply := make(chan int)
+	sum := 0
+	for i := 0; i < 100; i++ {
+		reqs <- req{n: i, reply: reply}
+		sum += <-reply
+	}
+	q := make(chan struct{})
+	quit <- q
+	<-q
+	fmt.Println(sum)
+}
+
+// Output:
+// 1
+// 9900
@@ -0,0 +1,20 @@
+package main
+
+import "fmt"
+
+const c = "hello"
+
+func next(b byte) byte { return b + 1 }
+
+func main() {
+	s := "hello"
+	b := s[0]
+	fmt.Printf("%T %c\n", b, next(b))
+	fmt.Printf("%T %c\n", c[1], next(c[1]))
+	fmt.Printf("%T %s %s\n", s[1:3], s[1:3], c[3:])
+}
+
+// Output:
+// uint8 i
+// uint8 f
+// string el lo
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// A is embedded twice at depth 2 in D, through B and C.
+type A struct{ X, Y int }
+
+func (A) Hello() string { return "A" }
+
+type B struct {
+	A
+	Y int
+}
+
+func (B) Name() string { return "B" }
+
+type C struct {
+	A
+	Z int
+}
+
+func (C) Name() string { return "C" }
+
+type D struct {
+	B
+	C
+	Z int
+}
+
+// The name of D shadows the ones of B and C.
+func (D) Name() string { return "D" }
+
+// E embeds an interpreted type and a binary one.
+type E struct {
+	B
+	*bytes.Buffer
+}
+
+func (E) Len() int { return -1 }
+
+type F struct {
+	E
+	Len int
+}
+
+func main() {
+	var d D
+	d.B.X, d.C.X = 1, 2
+	d.Y, d.Z, d.C.Z = 3, 4, 5
+	fmt.Println(d.B.X, d.C.X, d.Y, d.B.A.Y, d.Z, d.C.Z)
+	fmt.Println(d.Name(), d.B.Name(), d.C.Name(), d.B.Hello())
+
+	e := E{Buffer: bytes.NewBufferString("hello")}
+	fmt.Println(e.Len(), e.Buffer.Len(), e.String(), e.Name())
+
+	f := F{E: e, Len: 2}
+	fmt.Println(f.Len, f.E.Len(), f.Y)
+}
+
+// Output:
+// 1 2 3 0 4 5
+// D B C A
+// -1 5 hello B
+// 2 -1 0
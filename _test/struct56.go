@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type Node struct {
+	Val      int
+	Next     *Node  `json:",omitempty"`
+	Children []Node `json:",omitempty"`
+}
+
+func (n *Node) Len() int {
+	if n == nil {
+		return 0
+	}
+	return 1 + n.Next.Len()
+}
+
+type A struct {
+	Name string
+	B    *B
+}
+
+type B struct {
+	Name string
+	A    *A
+	M    map[string]*B
+	F    func(*A) *B
+}
+
+func (a *A) Other() string { return a.B.Name }
+
+type T struct {
+	Kids []T
+	F    func(T) int
+	V    int
+}
+
+func sum(t T) int {
+	s := t.V
+	for _, k := range t.Kids {
+		s += sum(k)
+	}
+	return s
+}
+
+func leaf(v int) T { return T{V: v} }
+
+func main() {
+	l := &Node{Val: 1, Next: &Node{Val: 2, Next: &Node{Val: 3}}}
+	fmt.Println(l.Len())
+	l.Children = []Node{{Val: 4}}
+	b, err := json.Marshal(l)
+	fmt.Println(string(b), err)
+
+	a := &A{Name: "a"}
+	bb := &B{Name: "b", A: a}
+	a.B = bb
+	bb.M = map[string]*B{"self": bb}
+	bb.F = func(x *A) *B { return x.B }
+	fmt.Println(a.Other(), a.B.A.Name, bb.M["self"].Name, bb.F(a).Name)
+
+	t := T{V: 1, Kids: []T{leaf(2), {V: 3, Kids: []T{leaf(4)}}}}
+	t.F = func(x T) int { return 10 * sum(x) }
+	fmt.Println(sum(t), t.F(t), t.F(t.Kids[1]))
+	k := t.Kids[0]
+	k.V = 5
+	fmt.Println(k.V, t.Kids[0].V)
+}
+
+// Output:
+// 3
+// {"Val":1,"Next":{"Val":2,"Next":{"Val":3}},"Children":[{"Val":4}]} <nil>
+// b a b b
+// 10 100 70
+// 5 2
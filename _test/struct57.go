@@ -0,0 +1,33 @@
+package main
+
+import "fmt"
+
+type T struct{ A [4]int }
+
+func change(t T) T {
+	t.A[1] = 10
+	return t
+}
+
+func main() {
+	ts := []T{{A: [4]int{1, 2, 3, 4}}, {A: [4]int{5, 6, 7, 8}}}
+	for _, v := range ts {
+		v.A[0] = 0
+	}
+	fmt.Println(ts)
+
+	a := ts[0]
+	a.A[2] = 0
+	b := change(a)
+	b.A[3] = 0
+	fmt.Println(ts[0], a, b)
+
+	var i interface{} = ts[1]
+	ts[1].A[0] = 0
+	fmt.Println(i, ts[1])
+}
+
+// Output:
+// [{[1 2 3 4]} {[5 6 7 8]}]
+// {[1 2 3 4]} {[1 2 0 4]} {[1 10 0 0]}
+// {[5 6 7 8]} {[0 6 7 8]}
@@ -48,4 +48,4 @@ func main() {
 }
 
 // Error:
-// 37:2: duplicate case Bir in type switch
+// 37:7: duplicate case Bir in type switch
@@ -0,0 +1,40 @@
+package main
+
+import "fmt"
+
+func main() {
+	x := 10
+	switch x := 2; x {
+	case 1:
+		fmt.Println("one")
+	case 2:
+		fmt.Println("two", x)
+		fallthrough
+	case 3:
+		fmt.Println("three")
+		fallthrough
+	default:
+		fmt.Println("default")
+	case 4:
+		fmt.Println("four")
+	}
+	fmt.Println(x)
+
+	y := 5
+	switch y {
+	default:
+		fmt.Println("default")
+		fallthrough
+	case 1:
+		fmt.Println("one")
+	case 5:
+		fmt.Println("five")
+	}
+}
+
+// Output:
+// two 2
+// three
+// default
+// 10
+// five
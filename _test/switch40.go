@@ -0,0 +1,45 @@
+package main
+
+import "fmt"
+
+func f(s string, b bool) bool {
+	fmt.Println("eval", s)
+	return b
+}
+
+func g(i int) int {
+	fmt.Println("eval", i)
+	return i
+}
+
+func main() {
+	switch z := 7; {
+	default:
+		fmt.Println("default", z)
+	case f("a", z > 100):
+		fmt.Println("gt 100")
+	case f("b", z > 5):
+		fmt.Println("gt 5")
+		fallthrough
+	case f("c", z > 6):
+		fmt.Println("gt 6")
+	}
+
+	switch 2 {
+	case g(1):
+		fmt.Println("1")
+	default:
+		fmt.Println("default")
+	case g(2), g(3):
+		fmt.Println("2")
+	}
+}
+
+// Output:
+// eval a
+// eval b
+// gt 5
+// gt 6
+// eval 1
+// eval 2
+// 2
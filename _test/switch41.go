@@ -0,0 +1,42 @@
+package main
+
+import "fmt"
+
+func kind(r rune) string {
+	switch r {
+	case '\U0001F600':
+		return "smile"
+	case '\x41':
+		return "A"
+	case '\101' + 1:
+		return "B"
+	case 'é':
+		return "e-acute"
+	case '\'':
+		return "quote"
+	case '\\':
+		return "backslash"
+	}
+	return "other"
+}
+
+func main() {
+	fmt.Println(kind('😀'), kind('A'), kind('B'), kind('é'), kind('\''), kind('\\'), kind('z'))
+	m := map[string]int{"é": 1, "\xff": 2, "\101": 3, "\U0001F600": 4}
+	fmt.Println(m["é"], m[string([]byte{0xff})], m["A"], m["😀"], len("\xff"), len("é"))
+	s := `a` + "\t" + `\n`
+	fmt.Println(s, len(s))
+	const c = '\U0001F600'
+	fmt.Printf("%T %v %q\n", c, c, "\a\b\f\v\x00")
+	switch "é" {
+	case "é":
+		fmt.Println("match")
+	}
+}
+
+// Output:
+// smile A B e-acute quote backslash other
+// 1 2 3 4 1 2
+// a	\n 4
+// int32 128512 "\a\b\f\v\x00"
+// match
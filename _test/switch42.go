@@ -0,0 +1,39 @@
+package main
+
+import "fmt"
+
+type T int
+
+const (
+	A T = iota
+	B
+)
+
+func main() {
+	x := 2
+	switch t := T(1); t {
+	case A:
+		fmt.Println("A")
+	case B, 2:
+		fmt.Println("B")
+	}
+	switch x {
+	case 1, x, 2:
+		fmt.Println(x)
+	}
+	const c = 2
+	var u uint8 = 2
+	switch u {
+	case 1, c:
+		fmt.Println("c")
+	case 3.0:
+		fmt.Println("3")
+	case c + 2:
+		fmt.Println("4")
+	}
+}
+
+// Output:
+// B
+// 2
+// c
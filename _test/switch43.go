@@ -0,0 +1,15 @@
+package main
+
+func main() {
+	const c = 2
+	var u uint8
+	switch u {
+	case 1, c:
+		println("c")
+	case 3, 2.0:
+		println("2")
+	}
+}
+
+// Error:
+// 9:10: duplicate case 2.0 in expression switch
@@ -0,0 +1,33 @@
+package main
+
+import "fmt"
+
+func main() {
+	m := map[string]int{"a": 1}
+	switch x := 2; m["a"] + x {
+	case 3:
+		fmt.Println("three")
+	default:
+		fmt.Println("other")
+	}
+
+	var s []interface{}
+	s = append(s, "s", 2)
+	switch y := 2; s[y-1].(type) {
+	case int:
+		fmt.Println("int")
+	default:
+		fmt.Println("other")
+	}
+	switch z := s[0].(type) {
+	case string:
+		fmt.Println("string", z)
+	default:
+		fmt.Println("other")
+	}
+}
+
+// Output:
+// three
+// int
+// string s
@@ -0,0 +1,14 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+func main() {
+	t := &time.Time{}
+	fmt.Println(t.Year(), t.IsZero())
+}
+
+// Output:
+// 1 true
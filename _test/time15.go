@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+const timeout time.Duration = 5 * time.Second
+
+type job struct {
+	name  string
+	delay time.Duration
+}
+
+func half(d time.Duration) time.Duration { return d / 2 }
+
+func stringer() fmt.Stringer { return 3 * time.Second }
+
+func main() {
+	d := 3 * time.Second
+	e := time.Second * 3
+	fmt.Println(d, e, d == e, d > time.Second, time.Millisecond < d)
+	fmt.Println(d/time.Millisecond, int64(d/time.Millisecond), d%time.Second == 0)
+
+	d += time.Second
+	d *= 2
+	fmt.Println(d, -d, half(d), half(2*d))
+
+	n := 4
+	fmt.Println(time.Duration(n)*time.Millisecond, timeout/time.Duration(n))
+
+	j := job{"a", 90 * time.Second}
+	fmt.Println(j.delay*2, j.delay+time.Minute > j.delay, j.delay.Minutes())
+
+	var s fmt.Stringer = 2 * time.Minute
+	fmt.Println(s, stringer())
+
+	t0 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	t1 := t0.Add(36 * time.Hour)
+	fmt.Println(t1.Sub(t0) == 36*time.Hour, t1.Sub(t0).Hours(), t1.Format("2006-01-02 15:04"))
+	time.Sleep(d / 8000)
+}
+
+// Output:
+// 3s 3s true true true
+// 3µs 3000 true
+// 8s -8s 4s 8s
+// 4ms 1.25s
+// 3m0s true 1.5
+// 2m0s 3s
+// true 36 2020-01-02 12:00
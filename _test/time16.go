@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"runtime"
+	"time"
+)
+
+func main() {
+	var d time.Duration
+	var s float64
+	rate := runtime.MemProfileRate
+	for i := 1; i <= 3; i++ {
+		d += time.Nanosecond
+		s += math.Pi
+		ns := time.Nanosecond
+		var v interface{} = time.Nanosecond * 2
+		fmt.Printf("%T %v %T %v %v\n", ns, ns, v, v, http.StatusOK)
+
+		// A binary variable is read at each iteration.
+		runtime.MemProfileRate = i * 1000
+		fmt.Println(runtime.MemProfileRate/1000, runtime.MemProfileRate > 1500)
+	}
+	runtime.MemProfileRate = rate
+	fmt.Printf("%T %v %.2f\n", d, d, s)
+}
+
+// Output:
+// time.Duration 1ns time.Duration 2ns 200
+// 1 false
+// time.Duration 1ns time.Duration 2ns 200
+// 2 true
+// time.Duration 1ns time.Duration 2ns 200
+// 3 true
+// time.Duration 3ns 9.42
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+)
+
+type W struct{ s string }
+
+func (w *W) WriteString(s string) (int, error) { w.s += s; return len(s), nil }
+
+type Stringer interface{ String() string }
+
+type T int
+
+func (t T) String() string { return fmt.Sprint("T", int(t)) }
+
+func main() {
+	var w interface{} = &W{}
+	sw, ok := w.(io.StringWriter)
+	fmt.Println(ok)
+	sw.WriteString("hello")
+	fmt.Println(w.(*W).s)
+
+	var v interface{} = T(2)
+	b, ok := v.(*bytes.Buffer)
+	fmt.Println(b, ok)
+	s, ok := v.(fmt.Stringer)
+	fmt.Println(s, ok)
+
+	var x interface{} = bytes.NewBufferString("buf")
+	b, ok = x.(*bytes.Buffer)
+	fmt.Println(b, ok)
+	s2, ok := x.(Stringer)
+	fmt.Println(s2.String(), ok)
+	_, ok = x.(*W)
+	fmt.Println(ok)
+
+	var e error
+	_, ok = e.(fmt.Stringer)
+	fmt.Println(ok)
+	var n interface{}
+	_, ok = n.(Stringer)
+	fmt.Println(ok)
+	_, ok = n.(int)
+	fmt.Println(ok)
+
+	var st fmt.Stringer = T(3)
+	s2, ok = st.(Stringer)
+	fmt.Println(s2.String(), ok)
+	_, ok = st.(io.Reader)
+	fmt.Println(ok)
+
+	var r interface{} = bytes.NewBufferString("copied\n")
+	rd := r.(io.Reader)
+	fmt.Printf("%T\n", rd)
+	_, ok = rd.(io.WriterTo)
+	fmt.Println(ok)
+	switch rd := r.(type) {
+	case io.Reader:
+		fmt.Printf("%T\n", rd)
+		io.Copy(os.Stdout, rd)
+	}
+}
+
+// Output:
+// true
+// hello
+// <nil> false
+// T2 true
+// buf true
+// buf true
+// false
+// false
+// false
+// false
+// T3 true
+// false
+// *bytes.Buffer
+// true
+// *bytes.Buffer
+// copied
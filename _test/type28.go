@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+type Stringer interface{ String() string }
+
+type T int
+
+func (t T) String() string { return fmt.Sprint("T", int(t)) }
+
+var (
+	v interface{} = T(2)
+	n interface{}
+	x interface{} = bytes.NewBufferString("buf")
+)
+
+func main() {
+	assertBuffer()
+	assertNil()
+	assertNilInt()
+	assertReader()
+	assertInt()
+	assertStringer()
+}
+
+func assertBuffer() {
+	defer func() { fmt.Println(recover()) }()
+	_ = v.(*bytes.Buffer)
+}
+
+func assertNil() {
+	defer func() { fmt.Println(recover()) }()
+	_ = n.(Stringer)
+}
+
+func assertNilInt() {
+	defer func() { fmt.Println(recover()) }()
+	_ = n.(int)
+}
+
+func assertReader() {
+	defer func() { fmt.Println(recover()) }()
+	_ = v.(io.Reader)
+}
+
+func assertInt() {
+	defer func() { fmt.Println(recover()) }()
+	_ = x.(int)
+}
+
+func assertStringer() {
+	defer func() { fmt.Println(recover()) }()
+	fmt.Println(v.(fmt.Stringer).String(), x.(Stringer).String())
+}
+
+// Output:
+// interface conversion: interface {} is main.T, not *bytes.Buffer
+// interface conversion: interface is nil, not main.Stringer
+// interface conversion: interface {} is nil, not int
+// interface conversion: main.T is not io.Reader: missing method Read
+// interface conversion: interface {} is *bytes.Buffer, not int
+// T2 buf
+// <nil>
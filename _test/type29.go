@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+type P struct {
+	X, Y int
+}
+
+type Tagged struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+func sum(p struct{ X, Y int }) int { return p.X + p.Y }
+
+func closeIt(c interface{ Close() error }) error { return c.Close() }
+
+type myCloser struct{ name string }
+
+func (m *myCloser) Close() error { fmt.Println("close", m.name); return nil }
+
+func main() {
+	a := struct{ X, Y int }{1, 2}
+	var b struct{ X, Y int }
+	b = a
+	fmt.Println(b, sum(a), sum(b))
+
+	var p P = a
+	a = p
+	fmt.Println(p, a)
+
+	t := Tagged(p)
+	fmt.Println(t, P(t))
+
+	var v interface{} = &myCloser{"mine"}
+	if c, ok := v.(interface{ Close() error }); ok {
+		c.Close()
+	}
+	_, ok := v.(interface{ Read([]byte) (int, error) })
+	fmt.Println(ok)
+
+	var r interface{} = ioutil.NopCloser(strings.NewReader("x"))
+	if c, ok := r.(interface{ Close() error }); ok {
+		fmt.Println(c.Close())
+	}
+
+	switch x := v.(type) {
+	case interface{ Read([]byte) (int, error) }:
+		fmt.Println("reader")
+	case interface{ Close() error }:
+		fmt.Println("closer", x.Close())
+	}
+
+	fmt.Println(closeIt(&myCloser{"arg"}))
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		panic(err)
+	}
+	defer pr.Close()
+	fmt.Println(closeIt(pw) == nil)
+	assertCloser(1)
+}
+
+func assertCloser(v interface{}) {
+	defer func() { fmt.Println(recover()) }()
+	_ = v.(interface{ Close() error })
+}
+
+// Output:
+// {1 2} 3 3
+// {1 2} {1 2}
+// {1 2} {1 2}
+// close mine
+// false
+// <nil>
+// close mine
+// closer <nil>
+// close arg
+// <nil>
+// true
+// interface conversion: int is not interface { Close() error }: missing method Close
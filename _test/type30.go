@@ -0,0 +1,16 @@
+package main
+
+import "unsafe"
+
+var t T
+
+type T [unsafe.Sizeof(t)]byte
+
+func main() {
+	println(len(t))
+}
+
+// Error:
+// ../_test/type30.go:5:5: invalid cycle in declaration of t
+//	../_test/type30.go:5:5: t refers to T
+//	../_test/type30.go:7:6: T refers to t
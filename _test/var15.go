@@ -0,0 +1,14 @@
+package main
+
+var a = b
+
+var b = a
+
+func main() {
+	println(a)
+}
+
+// Error:
+// ../_test/var15.go:3:5: variable definition loop
+//	../_test/var15.go:3:5: a refers to b
+//	../_test/var15.go:5:5: b refers to a
@@ -0,0 +1,27 @@
+package main
+
+type T struct{ A int }
+
+var (
+	i int
+	s []int
+	t T
+	p *T
+)
+
+func one() int { return 1 }
+
+func set() {
+	i = one()
+	s = append(s, 2)
+	t = T{3}
+	p = &T{4}
+}
+
+func main() {
+	set()
+	println(i, s[0], t.A, p.A)
+}
+
+// Output:
+// 1 2 3 4
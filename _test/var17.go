@@ -0,0 +1,17 @@
+package main
+
+var a = b
+
+var b = f()
+
+func f() int { return a }
+
+func main() {
+	println(a, b)
+}
+
+// Error:
+// ../_test/var17.go:3:5: initialization cycle for a
+//	../_test/var17.go:3:5: a refers to b
+//	../_test/var17.go:5:5: b refers to f
+//	../_test/var17.go:7:6: f refers to a
@@ -0,0 +1,20 @@
+package main
+
+type T struct{ n int }
+
+func (t T) get() int { return y + t.n }
+
+var x = get()
+
+var y = 2
+
+var z = T{1}.get()
+
+func get() int { return y }
+
+func main() {
+	println(x, y, z)
+}
+
+// Output:
+// 2 2 3
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"log"
+	"os"
+)
+
+func main() {
+	l := log.New(os.Stdout, "", 0)
+	x := 3
+	l.Printf("%d %d %d", 1, 2, x)
+	l.Printf("%d", x)
+	l.Printf("%d %d", x, 4)
+	l.Println()
+}
+
+// Output:
+// 1 2 3
+// 3
+// 3 4
+//
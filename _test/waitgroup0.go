@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+)
+
+type counter struct {
+	sync.WaitGroup
+	sync.Mutex
+	n int
+}
+
+func (c *counter) inc() {
+	defer c.Done()
+	c.Lock()
+	c.n++
+	c.Unlock()
+}
+
+type waiter interface {
+	Add(int)
+	Done()
+	Wait()
+}
+
+type pool struct {
+	wg  sync.WaitGroup
+	mu  sync.Mutex
+	sum int
+}
+
+func (p *pool) run(i int) {
+	defer p.wg.Done()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sum += i
+}
+
+func main() {
+	var c counter
+	for i := 0; i < 100; i++ {
+		c.Add(1)
+		go c.inc()
+	}
+	c.Wait()
+	fmt.Println(c.n)
+
+	var w waiter = &c
+	for i := 0; i < 100; i++ {
+		w.Add(1)
+		go w.Done()
+	}
+	w.Wait()
+
+	pools := make([]pool, 2)
+	for j := range pools {
+		for i := 0; i < 100; i++ {
+			pools[j].wg.Add(1)
+			go pools[j].run(i)
+		}
+	}
+	for j := range pools {
+		pools[j].wg.Wait()
+		fmt.Println(pools[j].sum)
+	}
+}
+
+// Output:
+// 100
+// 4950
+// 4950
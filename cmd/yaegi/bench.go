@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// maxBenchLines is the maximum number of log lines displayed for a benchmark.
+const maxBenchLines = 10
+
+// bench stores the state of an interpreted benchmark, shared by all its runs.
+type bench struct {
+	runner *runner
+	name   string // full name, including parents
+	level  int    // sub-benchmark level, 0 for a top level benchmark
+
+	mutex  sync.Mutex
+	output []string // log lines
+	allocs bool     // true if ReportAllocs was called
+	hasSub bool     // true if Run was called
+	failed bool     // true if a sub-benchmark failed
+}
+
+// B is the implementation of testing.B for interpreted benchmarks. The loop
+// on b.N and the timers are handled by the host testing package.
+type B struct {
+	*testing.B
+	bench   *bench
+	running bool // true if run by testing.Benchmark
+}
+
+// runBench runs the benchmark f called name, and reports its result in a
+// format compatible with "go test -bench". It returns false if the
+// benchmark failed.
+func (r *runner) runBench(name string, level int, f func(*B)) bool {
+	s := &bench{runner: r, name: name, level: level}
+
+	// Run once with b.N = 1 to find sub-benchmarks, as the testing package does.
+	// Sub-benchmarks are run and reported from Run, outside of testing.Benchmark
+	// which does not support nested calls.
+	tb := &testing.B{N: 1}
+	s.run(func() { f(&B{B: tb, bench: s}) })
+	if s.hasSub {
+		if tb.Failed() || s.failed {
+			s.report("FAIL")
+			return false
+		}
+		s.report("BENCH")
+		return true
+	}
+	switch {
+	case tb.Failed():
+		s.report("FAIL")
+		return false
+	case tb.Skipped():
+		s.report("SKIP")
+		return true
+	}
+
+	s.output = nil
+	res := testing.Benchmark(func(tb2 *testing.B) {
+		tb = tb2
+		f(&B{B: tb2, bench: s, running: true})
+	})
+	if tb.Failed() {
+		s.report("FAIL")
+		return false
+	}
+	if procs := runtime.GOMAXPROCS(-1); procs != 1 {
+		name = fmt.Sprintf("%s-%d", name, procs)
+	}
+	line := name + "\t" + res.String()
+	if s.allocs || r.benchmem {
+		line += "\t" + res.MemString()
+	}
+	r.printf("%s\n", line)
+	s.report("BENCH")
+	return true
+}
+
+// run executes f in a new goroutine, to allow FailNow, and recovers panics
+// as failures.
+func (s *bench) run(f func()) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer func() {
+			if r := recover(); r != nil {
+				s.log(fmt.Sprintf("panic: %v", r))
+				s.failed = true
+			}
+		}()
+		f()
+	}()
+	<-done
+}
+
+// report displays the log lines of the benchmark, if any, or if it failed.
+func (s *bench) report(status string) {
+	if status != "FAIL" && len(s.output) == 0 {
+		return
+	}
+	output := s.output
+	if len(output) > maxBenchLines {
+		output = append(output[:maxBenchLines:maxBenchLines], strings.Repeat("    ", s.level+1)+"... [output truncated]\n")
+	}
+	s.runner.printf("%s--- %s: %s\n%s", strings.Repeat("    ", s.level), status, s.name, strings.Join(output, ""))
+}
+
+func (s *bench) log(msg string) {
+	indent := strings.Repeat("    ", s.level+1)
+	msg = indent + strings.Replace(strings.TrimSuffix(msg, "\n"), "\n", "\n"+indent+"    ", -1) + "\n"
+	s.mutex.Lock()
+	s.output = append(s.output, msg)
+	s.mutex.Unlock()
+}
+
+// ReportAllocs enables malloc statistics for this benchmark.
+func (b *B) ReportAllocs() {
+	b.bench.mutex.Lock()
+	b.bench.allocs = true
+	b.bench.mutex.Unlock()
+	b.B.ReportAllocs()
+}
+
+// Log formats its arguments as fmt.Sprintln, and records the text in the benchmark log.
+func (b *B) Log(args ...interface{}) { b.bench.log(fmt.Sprintln(args...)) }
+
+// Logf formats its arguments as fmt.Sprintf, and records the text in the benchmark log.
+func (b *B) Logf(format string, args ...interface{}) { b.bench.log(fmt.Sprintf(format, args...)) }
+
+// Error is equivalent to Log followed by Fail.
+func (b *B) Error(args ...interface{}) { b.Log(args...); b.Fail() }
+
+// Errorf is equivalent to Logf followed by Fail.
+func (b *B) Errorf(format string, args ...interface{}) { b.Logf(format, args...); b.Fail() }
+
+// Fatal is equivalent to Log followed by FailNow.
+func (b *B) Fatal(args ...interface{}) { b.Log(args...); b.FailNow() }
+
+// Fatalf is equivalent to Logf followed by FailNow.
+func (b *B) Fatalf(format string, args ...interface{}) { b.Logf(format, args...); b.FailNow() }
+
+// Skip is equivalent to Log followed by SkipNow.
+func (b *B) Skip(args ...interface{}) { b.Log(args...); b.SkipNow() }
+
+// Skipf is equivalent to Logf followed by SkipNow.
+func (b *B) Skipf(format string, args ...interface{}) { b.Logf(format, args...); b.SkipNow() }
+
+// Run benchmarks f as a sub-benchmark of b called name, and reports its result.
+// It reports whether f succeeded.
+func (b *B) Run(name string, f func(b *B)) bool {
+	s := b.bench
+	if b.running {
+		// Sub-benchmarks not found in the first run are aggregated in the
+		// result of their parent by the testing package.
+		return b.B.Run(name, func(tb *testing.B) { f(&B{B: tb, bench: s, running: true}) })
+	}
+	s.hasSub = true
+	subName := s.name + "/" + rewrite(name)
+	if !matches(s.runner.bench, subName, s.level+1) {
+		return true
+	}
+	ok := s.runner.runBench(subName, s.level+1, f)
+	if !ok {
+		s.failed = true
+	}
+	return ok
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/containous/yaegi/interp"
+)
+
+// check parses and type checks the source files, without running them, and
+// prints the diagnostics on out, one JSON object per line. Each file is checked
+// in a new interpreter. It returns the exit status: 1 if any error was found,
+// the warnings being ignored.
+func check(newInterp func() *interp.Interpreter, files []string, out io.Writer) int {
+	status := 0
+	enc := json.NewEncoder(out)
+	for _, file := range files {
+		for _, d := range newInterp().Check(file) {
+			if err := enc.Encode(d); err != nil {
+				fmt.Fprintln(out, err)
+			}
+			if d.Severity == "error" {
+				status = 1
+			}
+		}
+	}
+	return status
+}
@@ -0,0 +1,369 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/containous/yaegi/interp"
+)
+
+// test interprets the source package in the directory given in args, including
+// its test files, and runs its test functions. It returns the exit status.
+func test(newInterp func() *interp.Interpreter, args []string, out io.Writer) int {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(out)
+	verbose := fs.Bool("v", false, "verbose: print all tests as they are run")
+	run := fs.String("run", "", "run only the tests matching the regular expression")
+	short := fs.Bool("short", false, "tell long running tests to shorten their run time")
+	bench := fs.String("bench", "", "run only the benchmarks matching the regular expression")
+	benchmem := fs.Bool("benchmem", false, "print memory allocations for benchmarks")
+	benchtime := fs.String("benchtime", "", "run enough iterations of each benchmark to take the given duration, or Nx")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	dir := "."
+	if fs.NArg() > 0 {
+		dir = fs.Arg(0)
+	}
+
+	r := &runner{out: out, verbose: *verbose, short: *short, benchmem: *benchmem}
+	var err error
+	if r.match, err = splitRegexp(*run); err != nil {
+		fmt.Fprintf(out, "invalid regexp for -run: %v\n", err)
+		return 2
+	}
+	if r.bench, err = splitRegexp(*bench); err != nil {
+		fmt.Fprintf(out, "invalid regexp for -bench: %v\n", err)
+		return 2
+	}
+	if *benchtime != "" {
+		testing.Init()
+		if err := flag.Set("test.benchtime", *benchtime); err != nil {
+			fmt.Fprintf(out, "invalid value for -benchtime: %v\n", err)
+			return 2
+		}
+	}
+
+	path := dir
+	if !strings.HasPrefix(path, "./") && !strings.HasPrefix(path, "../") && !strings.HasPrefix(path, "/") {
+		if _, err := os.Stat(path); err == nil {
+			// Directories are imported relatively to the current one.
+			path = "./" + filepath.ToSlash(filepath.Clean(path))
+		}
+	}
+
+	start := time.Now()
+	i := newInterp()
+	i.Use(interp.Exports{"testing": r.symbols()})
+	funcs, err := i.EvalTest(path)
+	if err != nil {
+		fmt.Fprintln(out, err)
+		if p, ok := err.(interp.Panic); ok {
+			fmt.Fprintln(out, string(p.Stack))
+		}
+		fmt.Fprintf(out, "FAIL\t%s [setup failed]\n", dir)
+		return 1
+	}
+
+	names := make([]string, 0, len(funcs))
+	for name := range funcs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	failed, ran := false, false
+	for _, name := range names {
+		f, ok := funcs[name].Interface().(func(*T))
+		if !ok || !isTest(name, "Test") {
+			continue
+		}
+		t := &T{runner: r, name: name}
+		if !matches(r.match, t.name, t.level) {
+			continue
+		}
+		ran = true
+		if !t.run(f) {
+			failed = true
+		}
+	}
+	if !ran {
+		fmt.Fprintln(out, "testing: warning: no tests to run")
+	}
+
+	if r.bench != nil {
+		for _, name := range names {
+			f, ok := funcs[name].Interface().(func(*B))
+			if !ok || !isTest(name, "Benchmark") || !matches(r.bench, name, 0) {
+				continue
+			}
+			if !r.header {
+				fmt.Fprintf(out, "goos: %s\ngoarch: %s\npkg: %s\n", runtime.GOOS, runtime.GOARCH, dir)
+				r.header = true
+			}
+			if !r.runBench(name, 0, f) {
+				failed = true
+			}
+		}
+	}
+
+	elapsed := time.Since(start).Seconds()
+	if failed {
+		fmt.Fprintln(out, "FAIL")
+		fmt.Fprintf(out, "FAIL\t%s\t%.3fs\n", dir, elapsed)
+		return 1
+	}
+	fmt.Fprintln(out, "PASS")
+	fmt.Fprintf(out, "ok  \t%s\t%.3fs\n", dir, elapsed)
+	return 0
+}
+
+// isTest returns true if name is the name of a test function with the given
+// prefix, i.e. "TestFoo" or "Test" for the "Test" prefix, but not "Testing".
+func isTest(name, prefix string) bool {
+	if !strings.HasPrefix(name, prefix) {
+		return false
+	}
+	if len(name) == len(prefix) {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(name[len(prefix):])
+	return !unicode.IsLower(r)
+}
+
+// runner stores the settings of a test run.
+type runner struct {
+	mutex   sync.Mutex // serializes outputs
+	out     io.Writer
+	verbose bool
+	short   bool
+	match   []*regexp.Regexp // -run patterns, per subtest level
+
+	bench    []*regexp.Regexp // -bench patterns, per sub-benchmark level
+	benchmem bool
+	header   bool // true if the benchmark header is displayed
+}
+
+// symbols returns the symbols of the testing package for interpreted tests.
+func (r *runner) symbols() map[string]reflect.Value {
+	return map[string]reflect.Value{
+		"Short":   reflect.ValueOf(func() bool { return r.short }),
+		"Verbose": reflect.ValueOf(func() bool { return r.verbose }),
+
+		"B": reflect.ValueOf((*B)(nil)),
+		"T": reflect.ValueOf((*T)(nil)),
+	}
+}
+
+// splitRegexp compiles the slash separated regular expressions of a -run or
+// -bench flag, one per subtest level.
+func splitRegexp(s string) ([]*regexp.Regexp, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var res []*regexp.Regexp
+	for _, e := range strings.Split(s, "/") {
+		re, err := regexp.Compile(e)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+// matches returns true if the test or benchmark name at the given subtest level
+// is selected by patterns. Each element of the name is matched by the pattern
+// of the same level.
+func matches(patterns []*regexp.Regexp, name string, level int) bool {
+	return level >= len(patterns) || patterns[level].MatchString(name[strings.LastIndex(name, "/")+1:])
+}
+
+func (r *runner) printf(format string, a ...interface{}) {
+	r.mutex.Lock()
+	fmt.Fprintf(r.out, format, a...)
+	r.mutex.Unlock()
+}
+
+// T is the implementation of testing.T for interpreted tests. Parallel tests
+// are run sequentially.
+type T struct {
+	runner *runner
+	parent *T
+	name   string // full name, including parents
+	level  int    // subtest level, 0 for a top level test
+
+	mutex    sync.Mutex
+	output   []string // logs, displayed at end of test if not verbose
+	failed   bool
+	skipped  bool
+	cleanups []func()
+}
+
+// run executes f in a new goroutine, to allow FailNow, then reports the
+// result. It returns false if the test failed.
+func (t *T) run(f func(*T)) bool {
+	if t.runner.verbose {
+		t.runner.printf("=== RUN   %s\n", t.name)
+	}
+
+	start := time.Now()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer func() {
+			for i := len(t.cleanups) - 1; i >= 0; i-- {
+				t.cleanups[i]()
+			}
+		}()
+		defer func() {
+			if r := recover(); r != nil {
+				t.Errorf("panic: %v", r)
+			}
+		}()
+		f(t)
+	}()
+	<-done
+
+	status := "PASS"
+	switch {
+	case t.Failed():
+		status = "FAIL"
+	case t.Skipped():
+		status = "SKIP"
+	}
+	if status != "FAIL" && !t.runner.verbose {
+		return true
+	}
+	report := fmt.Sprintf("%s--- %s: %s (%.2fs)\n", strings.Repeat("    ", t.level), status, t.name, time.Since(start).Seconds())
+	report += strings.Join(t.output, "")
+	if t.parent != nil && !t.runner.verbose {
+		// Reports of subtests are displayed after the one of their parent.
+		t.parent.mutex.Lock()
+		t.parent.output = append(t.parent.output, report)
+		t.parent.mutex.Unlock()
+	} else {
+		t.runner.printf("%s", report)
+	}
+	return status != "FAIL"
+}
+
+// log formats and records a test message, indented according to the test level.
+func (t *T) log(s string) {
+	indent := strings.Repeat("    ", t.level+1)
+	s = indent + strings.Replace(strings.TrimSuffix(s, "\n"), "\n", "\n"+indent+"    ", -1) + "\n"
+	if t.runner.verbose {
+		t.runner.printf("%s", s)
+		return
+	}
+	t.mutex.Lock()
+	t.output = append(t.output, s)
+	t.mutex.Unlock()
+}
+
+// Name returns the name of the running test.
+func (t *T) Name() string { return t.name }
+
+// Log formats its arguments as fmt.Sprintln, and records the text in the test log.
+func (t *T) Log(args ...interface{}) { t.log(fmt.Sprintln(args...)) }
+
+// Logf formats its arguments as fmt.Sprintf, and records the text in the test log.
+func (t *T) Logf(format string, args ...interface{}) { t.log(fmt.Sprintf(format, args...)) }
+
+// Fail marks the test as having failed, but continues execution.
+func (t *T) Fail() {
+	t.mutex.Lock()
+	t.failed = true
+	t.mutex.Unlock()
+	if t.parent != nil {
+		t.parent.Fail()
+	}
+}
+
+// Failed reports whether the test has failed.
+func (t *T) Failed() bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.failed
+}
+
+// FailNow marks the test as having failed and stops its execution.
+func (t *T) FailNow() {
+	t.Fail()
+	runtime.Goexit()
+}
+
+// Error is equivalent to Log followed by Fail.
+func (t *T) Error(args ...interface{}) { t.Log(args...); t.Fail() }
+
+// Errorf is equivalent to Logf followed by Fail.
+func (t *T) Errorf(format string, args ...interface{}) { t.Logf(format, args...); t.Fail() }
+
+// Fatal is equivalent to Log followed by FailNow.
+func (t *T) Fatal(args ...interface{}) { t.Log(args...); t.FailNow() }
+
+// Fatalf is equivalent to Logf followed by FailNow.
+func (t *T) Fatalf(format string, args ...interface{}) { t.Logf(format, args...); t.FailNow() }
+
+// SkipNow marks the test as having been skipped and stops its execution.
+func (t *T) SkipNow() {
+	t.mutex.Lock()
+	t.skipped = true
+	t.mutex.Unlock()
+	runtime.Goexit()
+}
+
+// Skipped reports whether the test was skipped.
+func (t *T) Skipped() bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.skipped
+}
+
+// Skip is equivalent to Log followed by SkipNow.
+func (t *T) Skip(args ...interface{}) { t.Log(args...); t.SkipNow() }
+
+// Skipf is equivalent to Logf followed by SkipNow.
+func (t *T) Skipf(format string, args ...interface{}) { t.Logf(format, args...); t.SkipNow() }
+
+// Helper has no effect, as source locations are not reported.
+func (t *T) Helper() {}
+
+// Parallel has no effect, tests are run sequentially.
+func (t *T) Parallel() {}
+
+// Cleanup registers a function to be called when the test completes.
+func (t *T) Cleanup(f func()) { t.cleanups = append(t.cleanups, f) }
+
+// Run runs f as a subtest of t called name, and waits for its completion.
+// It reports whether f succeeded.
+func (t *T) Run(name string, f func(t *T)) bool {
+	sub := &T{runner: t.runner, parent: t, name: t.name + "/" + rewrite(name), level: t.level + 1}
+	if !matches(t.runner.match, sub.name, sub.level) {
+		return true
+	}
+	return sub.run(f)
+}
+
+// rewrite returns a subtest name where spaces are replaced by underscores, as
+// in the testing package.
+func rewrite(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return '_'
+		}
+		return r
+	}, s)
+}
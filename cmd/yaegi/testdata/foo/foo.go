@@ -0,0 +1,3 @@
+package foo
+
+func Add(a, b int) int { return a + b }
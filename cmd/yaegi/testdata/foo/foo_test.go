@@ -0,0 +1,54 @@
+package foo
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestAdd(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		a, b, r int
+	}{
+		{"one", 1, 1, 2},
+		{"two", 2, 2, 4},
+		{"bad", 2, 2, 5},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if r := Add(tc.a, tc.b); r != tc.r {
+				t.Errorf("got %d, want %d", r, tc.r)
+			}
+		})
+	}
+}
+
+func TestFatal(t *testing.T) {
+	t.Fatal("fatal")
+	t.Error("not reached")
+}
+
+func TestSkip(t *testing.T) { t.Skip("skipped") }
+
+func TestLog(t *testing.T) { t.Log("hello") }
+
+func BenchmarkAdd(b *testing.B) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Add(i, i)
+	}
+}
+
+func BenchmarkSizes(b *testing.B) {
+	for _, n := range []int{1, 10} {
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for j := 0; j < n; j++ {
+					Add(i, j)
+				}
+			}
+		})
+	}
+}
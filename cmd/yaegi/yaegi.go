@@ -21,6 +21,9 @@ starts with "#!" characters (the shebang pattern to allow executable
 scripts), for example "#!/usr/bin/env yaegi". In that case, the initial
 file is interpreted in REPL mode.
 
+The arguments following the initial file are passed to the interpreted
+program in os.Args, where os.Args[0] is the file path.
+
 REPL mode
 
 In REPL mode, the interpreter parses the code incrementally. As soon
@@ -40,7 +43,12 @@ It is not necessary to have a package statement, or a main function in
 REPL mode. Import statements for preloaded binary packages can also
 be avoided (i.e. all the standard library except the few packages
 where default names collide, as "math/rand" and "crypto/rand", for which
-an explicit import is still necessary).
+an explicit import is still necessary). In the interactive REPL, started
+when no file is given, the binary packages are instead imported on their
+first use, unless their name is ambiguous, and each import is reported by
+a comment as:
+
+	// imported "strings"
 
 Note that the source packages are always interpreted in file mode,
 even if imported from REPL.
@@ -58,9 +66,58 @@ Example of a one liner:
 
 	$ yaegi -e 'println(reflect.TypeOf(fmt.Print))'
 
+Check Mode
+
+The check command parses and type checks the given files, without running
+them, and prints the errors found, one JSON object per line, with the fields
+"file", "line", "column", "severity", "message" and "phase" ("parse" or
+"check"). The exit status is 1 if any error is found.
+
+	$ yaegi check main.go
+
+With the -gotypes option, the files are also type checked with go/types, and
+the divergences are reported as warnings in the "gotypes" phase: the errors
+found by only one of them, and the package level declarations of which the
+types differ, with the fields "interp" and "goTypes" set to their results.
+
+	$ yaegi -gotypes check main.go
+
+Test Mode
+
+The test command interprets the source package in the given directory
+(by default the current one), including its test files, and runs its test
+functions, as "go test". The supported flags are -v, -run regexp and -short.
+Interpreted tests use an implementation of testing.T where Parallel has no
+effect: tests and subtests are run sequentially. The test files of external
+test packages (package xxx_test) are ignored.
+
+	$ yaegi test -v -run TestFoo ./foo
+
+Benchmark functions are run with the -bench regexp flag, completed by
+-benchmem and -benchtime, and reported in the format of "go test -bench",
+to compare the performances of interpreted and compiled code, i.e. with
+benchstat. The testing.B loop and timers are handled by the host testing
+package.
+
+	$ yaegi test -run '^$' -bench . ./foo
+
+Watch Mode
+
+With the -watch flag, the Go source files of the directory given as argument
+are evaluated together, then evaluated again each time they change, replacing
+the previous declarations. The main function, if defined, is restarted after
+each change. Errors are displayed, and don't stop the watch.
+
+	$ yaegi -watch ./scripts
+
 Options:
 	-e string
 	   evaluate the string and return.
+	-errsrc
+	   display the source line and a caret under the location of errors
+	   (default true).
+	-gotypes
+	   in check mode, report the divergences with go/types.
     -i
 	   start an interactive REPL after file execution.
 	-syscall
@@ -70,6 +127,8 @@ Options:
 	   the interpretation.
 	-unsafe
 	  include unsafe symbols.
+	-watch
+	   evaluate the files of the directory argument again on change.
 
 Debugging support (may be removed at any time):
   YAEGI_AST_DOT=1
@@ -85,12 +144,14 @@ Debugging support (may be removed at any time):
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"go/build"
 	"io/ioutil"
 	"log"
 	"os"
+	"os/signal"
 	"strings"
 
 	"github.com/containous/yaegi/interp"
@@ -105,6 +166,9 @@ func main() {
 	var useSyscall bool
 	var useUnrestricted bool
 	var useUnsafe bool
+	var errSource bool
+	var watch bool
+	var goTypes bool
 	var tags string
 	var cmd string
 	flag.BoolVar(&interactive, "i", false, "start an interactive REPL")
@@ -113,8 +177,14 @@ func main() {
 	flag.StringVar(&tags, "tags", "", "set a list of build tags")
 	flag.BoolVar(&useUnsafe, "unsafe", false, "include usafe symbols")
 	flag.StringVar(&cmd, "e", "", "set the command to be executed (instead of script or/and shell)")
+	flag.BoolVar(&errSource, "errsrc", true, "display source line and caret in error messages")
+	flag.BoolVar(&watch, "watch", false, "evaluate the files of the directory argument again on change")
+	flag.BoolVar(&goTypes, "gotypes", false, "in check mode, report the divergences with go/types")
 	flag.Usage = func() {
 		fmt.Println("Usage:", os.Args[0], "[options] [script] [args]")
+		fmt.Println("      ", os.Args[0], "[options] check files...")
+		fmt.Println("      ", os.Args[0], "[options] test [test flags] [dir]")
+		fmt.Println("      ", os.Args[0], "[options] -watch dir [args]")
 		fmt.Println("Options:")
 		flag.PrintDefaults()
 	}
@@ -122,20 +192,36 @@ func main() {
 	args := flag.Args()
 	log.SetFlags(log.Lshortfile)
 
-	i := interp.New(interp.Options{GoPath: build.Default.GOPATH, BuildTags: strings.Split(tags, ",")})
-	i.Use(stdlib.Symbols)
-	i.Use(interp.Symbols)
-	if useSyscall {
-		i.Use(syscall.Symbols)
+	// Binary packages are imported on demand in the interactive REPL.
+	autoImport := len(args) == 0 && (interactive || cmd == ``)
+
+	newInterp := func() *interp.Interpreter {
+		i := interp.New(interp.Options{GoPath: build.Default.GOPATH, BuildTags: strings.Split(tags, ","), ErrorSource: errSource, AutoImport: autoImport, AllowExit: true, CheckWithGoTypes: goTypes})
+		i.Use(stdlib.Symbols)
+		i.Use(interp.Symbols)
+		if useSyscall {
+			i.Use(syscall.Symbols)
+		}
+		if useUnsafe {
+			i.Use(unsafe.Symbols)
+		}
+		if useUnrestricted {
+			// Use of unrestricted symbols should always follow use of stdlib symbols, to update them.
+			i.Use(unrestricted.Symbols)
+		}
+		return i
 	}
-	if useUnsafe {
-		i.Use(unsafe.Symbols)
+
+	if len(args) > 0 && args[0] == "check" {
+		os.Exit(check(newInterp, args[1:], os.Stdout))
 	}
-	if useUnrestricted {
-		// Use of unrestricted symbols should always follow use of stdlib symbols, to update them.
-		i.Use(unrestricted.Symbols)
+
+	if len(args) > 0 && args[0] == "test" {
+		os.Exit(test(newInterp, args[1:], os.Stdout))
 	}
 
+	i := newInterp()
+
 	if cmd != `` {
 		i.REPL(strings.NewReader(cmd), os.Stderr)
 	}
@@ -147,10 +233,30 @@ func main() {
 		return
 	}
 
-	// Skip first os arg to set command line as expected by interpreted main
-	os.Args = os.Args[1:]
+	// Set command line as expected by interpreted main: the script path,
+	// followed by the arguments after it, flag parsing having stopped there.
+	os.Args = args
 	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
 
+	if watch {
+		ctx, cancel := context.WithCancel(context.Background())
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, os.Interrupt)
+		go func() {
+			<-c
+			cancel()
+		}()
+		interp.Watch(ctx, i, args[0], func(err error) {
+			if err != nil {
+				fmt.Println(err)
+				if p, ok := err.(interp.Panic); ok {
+					fmt.Println(string(p.Stack))
+				}
+			}
+		})
+		return
+	}
+
 	b, err := ioutil.ReadFile(args[0])
 	if err != nil {
 		log.Fatal("Could not read file: ", args[0])
@@ -158,7 +264,8 @@ func main() {
 
 	if s := string(b); strings.HasPrefix(s, "#!") {
 		// Allow executable go scripts, Have the same behavior as in interactive mode.
-		s = strings.Replace(s, "#!", "//", 1)
+		// The shebang line is blanked, to preserve line numbers.
+		s = s[strings.IndexByte(s+"\n", '\n'):]
 		i.REPL(strings.NewReader(s), os.Stdout)
 	} else {
 		// Files not starting with "#!" are supposed to be pure Go, directly Evaled.
@@ -6,9 +6,14 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/containous/yaegi/interp"
+	"github.com/containous/yaegi/stdlib"
 )
 
 const (
@@ -113,3 +118,77 @@ func TestYaegiCmdCancel(t *testing.T) {
 		}
 	}
 }
+
+func TestYaegiTest(t *testing.T) {
+	newInterp := func() *interp.Interpreter {
+		i := interp.New(interp.Options{})
+		i.Use(stdlib.Symbols)
+		return i
+	}
+	for _, tc := range []struct {
+		args   []string
+		status int
+		want   string
+	}{
+		{args: []string{"./testdata/foo"}, status: 1, want: `--- FAIL: TestAdd (0.00s)
+    --- FAIL: TestAdd/bad (0.00s)
+        got 4, want 5
+--- FAIL: TestFatal (0.00s)
+    fatal
+FAIL
+`},
+		{args: []string{"-v", "-run", "Skip|Add/^t", "./testdata/foo"}, want: `=== RUN   TestAdd
+=== RUN   TestAdd/two
+    --- PASS: TestAdd/two (0.00s)
+--- PASS: TestAdd (0.00s)
+=== RUN   TestSkip
+    skipped
+--- SKIP: TestSkip (0.00s)
+PASS
+`},
+		{args: []string{"-run", "None", "./testdata/foo"}, want: "testing: warning: no tests to run\nPASS\n"},
+	} {
+		var out bytes.Buffer
+		if status := test(newInterp, tc.args, &out); status != tc.status {
+			t.Errorf("%v: got status %d, want %d", tc.args, status, tc.status)
+		}
+		// Remove the last line, which contains the elapsed time.
+		got := out.String()
+		got = got[:strings.LastIndex(strings.TrimSuffix(got, "\n"), "\n")+1]
+		if got != tc.want {
+			t.Errorf("%v: got %q, want %q", tc.args, got, tc.want)
+		}
+	}
+}
+
+func TestYaegiTestBench(t *testing.T) {
+	newInterp := func() *interp.Interpreter {
+		i := interp.New(interp.Options{})
+		i.Use(stdlib.Symbols)
+		return i
+	}
+	var out bytes.Buffer
+	if status := test(newInterp, []string{"-run", "^$", "-bench", ".", "-benchtime", "10x", "./testdata/foo"}, &out); status != 0 {
+		t.Errorf("got status %d, want 0", status)
+	}
+	want := []string{
+		`testing: warning: no tests to run`,
+		`goos: \w+`,
+		`goarch: \w+`,
+		`pkg: ./testdata/foo`,
+		`BenchmarkAdd(-\d+)?\t +10\t +[\d.]+ ns/op\t +\d+ B/op\t +\d+ allocs/op`,
+		`BenchmarkSizes/n=1(-\d+)?\t +10\t +[\d.]+ ns/op`,
+		`BenchmarkSizes/n=10(-\d+)?\t +10\t +[\d.]+ ns/op`,
+		`PASS`,
+		`ok  \t./testdata/foo\t[\d.]+s`,
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("got %d lines, want %d: %q", len(lines), len(want), &out)
+	}
+	for i, line := range lines {
+		if !regexp.MustCompile("^" + want[i] + "$").MatchString(line) {
+			t.Errorf("line %d: got %q, want match of %q", i, line, want[i])
+		}
+	}
+}
@@ -73,9 +73,12 @@ func init() {
 {{range $key, $value := .Wrap -}}
 	// {{$value.Name}} is an interface wrapper for {{$key}} type
 	type {{$value.Name}} struct {
+		{{if $value.Embed}}{{$value.Embed}}
+		{{end -}}
 		{{range $m := $value.Method -}}
 		W{{$m.Name}} func{{$m.Param}} {{$m.Result}}
-		{{end}}
+		{{end -}}
+		IValue interface{}
 	}
 	{{range $m := $value.Method -}}
 		func (W {{$value.Name}}) {{$m.Name}}{{$m.Param}} {{$m.Result}} { {{$m.Ret}} W.W{{$m.Name}}{{$m.Arg}} }
@@ -95,9 +98,14 @@ type Method struct {
 }
 
 // Wrap stores information for generating interface wrapper.
+// The unexported methods of the interface can not be implemented by the
+// wrapper. They are promoted from the embedded type Embed, if a type of the
+// package provides them, otherwise the wrapper does not implement the
+// interface, and interpreted types can not be converted to it.
 type Wrap struct {
 	Name   string
 	Method []Method
+	Embed  string // "package.name" of the type providing the unexported methods, or ""
 }
 
 // restricted map defines symbols for which a special implementation is provided.
@@ -111,6 +119,14 @@ var restricted = map[string]bool{
 	"logNew":        true,
 }
 
+// archDependent map defines the untyped integer constants whose value depends
+// on the target architecture. They are exported from their expression, to be
+// evaluated at compilation, rather than from their value at generation.
+var archDependent = map[string]bool{
+	"bits.UintSize":   true,
+	"strconv.IntSize": true,
+}
+
 func genContent(dest, importPath, license string, p *types.Package, skip map[string]bool) ([]byte, error) {
 	prefix := "_" + importPath + "_"
 	prefix = strings.NewReplacer("/", "_", "-", "_", ".", "_").Replace(prefix)
@@ -150,6 +166,11 @@ func genContent(dest, importPath, license string, p *types.Package, skip map[str
 		switch o := o.(type) {
 		case *types.Const:
 			if b, ok := o.Type().(*types.Basic); ok && (b.Info()&types.IsUntyped) != 0 {
+				if archDependent[pname] {
+					imports["go/constant"] = true
+					val[name] = Val{"constant.MakeInt64(" + pname + ")", false}
+					break
+				}
 				// convert untyped constant to right type to avoid overflow
 				val[name] = Val{fixConst(pname, o.Val(), imports), false}
 			} else {
@@ -163,9 +184,11 @@ func genContent(dest, importPath, license string, p *types.Package, skip map[str
 			typ[name] = pname
 			if t, ok := o.Type().Underlying().(*types.Interface); ok {
 				var methods []Method
+				var unexported []*types.Func
 				for i := 0; i < t.NumMethods(); i++ {
 					f := t.Method(i)
 					if !f.Exported() {
+						unexported = append(unexported, f)
 						continue
 					}
 
@@ -196,7 +219,13 @@ func genContent(dest, importPath, license string, p *types.Package, skip map[str
 
 					methods = append(methods, Method{f.Name(), param, result, arg, ret})
 				}
-				wrap[name] = Wrap{prefix + name, methods}
+				w := Wrap{Name: prefix + name, Method: methods}
+				if len(unexported) > 0 {
+					if e := fallbackType(sc, unexported); e != "" {
+						w.Embed = path.Base(importPath) + "." + e
+					}
+				}
+				wrap[name] = w
 			}
 		}
 	}
@@ -240,6 +269,32 @@ func genContent(dest, importPath, license string, p *types.Package, skip map[str
 	return source, nil
 }
 
+// fallbackType returns the name of the first exported type of scope sc of
+// which the pointer method set includes the unexported methods, or "".
+// Embedded in a wrapper, it provides the methods not implementable outside of
+// the package.
+func fallbackType(sc *types.Scope, methods []*types.Func) string {
+	for _, name := range sc.Names() {
+		o, ok := sc.Lookup(name).(*types.TypeName)
+		if !ok || !o.Exported() || o.IsAlias() || types.IsInterface(o.Type()) {
+			continue
+		}
+		ptr := types.NewPointer(o.Type())
+		found := true
+		for _, m := range methods {
+			f, _, _ := types.LookupFieldOrMethod(ptr, false, m.Pkg(), m.Name())
+			if f, ok := f.(*types.Func); !ok || !types.Identical(f.Type(), m.Type()) {
+				found = false
+				break
+			}
+		}
+		if found {
+			return name
+		}
+	}
+	return ""
+}
+
 // fixConst checks untyped constant value, converting it if necessary to avoid overflow.
 func fixConst(name string, val constant.Value, imports map[string]bool) string {
 	var (
@@ -88,6 +88,17 @@ func TestPackages(t *testing.T) {
 			importPath: "guthib.com/baz",
 			expected:   expectedOutput,
 		},
+		{
+			desc:       "interface wrapper embedding the provider of unexported methods",
+			wd:         "./testdata/6/src/guthib.com/bar",
+			arg:        "../baz",
+			importPath: "guthib.com/baz",
+			contains: `type _guthib_com_baz_Message struct {
+	baz.MessageState
+	WName  func() string
+	IValue interface{}
+}`,
+		},
 	}
 
 	for _, test := range testCases {
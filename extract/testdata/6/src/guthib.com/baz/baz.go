@@ -0,0 +1,22 @@
+package baz
+
+// Message has an unexported method, provided by MessageState.
+type Message interface {
+	Name() string
+	state() *MessageState
+}
+
+// MessageState is embedded in the implementations of Message.
+type MessageState struct{ calls int }
+
+func (m *MessageState) state() *MessageState { return m }
+
+// Sealed has an unexported method provided by no exported type.
+type Sealed interface {
+	Kind() int
+	sealed()
+}
+
+func Hello() {
+	println("HELLO")
+}
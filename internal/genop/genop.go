@@ -196,6 +196,15 @@ func {{$name}}Const(n *node) {
 		{{- if $op.Shift}}
 		v := constant.Shift(vConstantValue(v0), token.{{tokenFromName $name}}, uint(vUint(v1)))
 		n.rval.Set(reflect.ValueOf(v))
+		{{- else if eq $name "quo"}}
+		c0, c1 := vConstantValue(v0), vConstantValue(v1)
+		tok := token.QUO
+		if isConstInt(n.child[0], c0) && isConstInt(n.child[1], c1) {
+			// Integer constants division truncates towards zero.
+			tok = token.QUO_ASSIGN
+		}
+		v := constant.BinaryOp(c0, tok, c1)
+		n.rval.Set(reflect.ValueOf(v))
 		{{- else}}
 		v := constant.BinaryOp(vConstantValue(v0), token.{{tokenFromName $name}}, vConstantValue(v1))
 		n.rval.Set(reflect.ValueOf(v))
@@ -349,7 +358,7 @@ func {{$name}}(n *node) {
 			v.SetInt(i {{$op.Name}} 1)
 			return next
 		}
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
 		v0 := genValueUint(n.child[0])
 		n.exec = func(f *frame) bltn {
 			v, i := v0(f)
@@ -395,10 +404,10 @@ func {{$name}}Const(n *node) {
 	case isConst:
 		v := constant.UnaryOp(token.{{tokenFromName $name}}, vConstantValue(v0), 0)
 		n.rval.Set(reflect.ValueOf(v))
-	case isInt(t):
-		n.rval.SetInt({{$op.Name}} v0.Int())
 	case isUint(t):
 		n.rval.SetUint({{$op.Name}} v0.Uint())
+	case isInt(t):
+		n.rval.SetInt({{$op.Name}} v0.Int())
 	{{- if $op.Float}}
 	case isFloat(t):
 		n.rval.SetFloat({{$op.Name}} v0.Float())
@@ -847,7 +856,7 @@ func {{$name}}(n *node) {
 		switch {
 		case c0.rval.IsValid():
 			i0 := c0.rval.Interface()
-			v1 := genValue(c1)
+			v1 := genValueUnwrap(c1)
 			if n.fnext != nil {
 				fnext := getExec(n.fnext)
 				n.exec = func(f *frame) bltn {
@@ -869,7 +878,7 @@ func {{$name}}(n *node) {
 			}
 		case c1.rval.IsValid():
 			i1 := c1.rval.Interface()
-			v0 := genValue(c0)
+			v0 := genValueUnwrap(c0)
 			if n.fnext != nil {
 				fnext := getExec(n.fnext)
 				n.exec = func(f *frame) bltn {
@@ -890,8 +899,8 @@ func {{$name}}(n *node) {
 				}
 			}
 		default:
-			v0 := genValue(c0)
-			v1 := genValue(c1)
+			v0 := genValueUnwrap(c0)
+			v1 := genValueUnwrap(c1)
 			if n.fnext != nil {
 				fnext := getExec(n.fnext)
 				n.exec = func(f *frame) bltn {
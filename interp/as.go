@@ -0,0 +1,259 @@
+package interp
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// AsOption configures the copy performed by As.
+type AsOption func(*asConfig)
+
+type asConfig struct {
+	tag string // struct tag key used to map fields, or empty to use field names
+}
+
+// AsTag returns an option which matches struct fields by the name given in
+// their tag key, as in `json:"name"`, instead of by field name. A field
+// without such a tag is matched by its name, and a field tagged "-" is ignored.
+func AsTag(key string) AsOption {
+	return func(c *asConfig) { c.tag = key }
+}
+
+// As copies the value v, usually returned by Eval or produced by interpreted
+// code, into the host variable pointed to by target. The copy is structural,
+// so the types of v and of the target do not need to be shared: the exported
+// struct fields are matched by name, the values of compatible kinds are
+// converted, and slices, arrays, maps and pointers are copied recursively.
+// Fields of target without a counterpart in v are left unchanged.
+//
+// An error is returned for values which cannot be converted, with the path of
+// the offending element, as in "field Config.Timeout: have string, want
+// time.Duration".
+func As(v reflect.Value, target interface{}, opts ...AsOption) error {
+	dst := reflect.ValueOf(target)
+	if dst.Kind() != reflect.Ptr || dst.IsNil() {
+		return fmt.Errorf("cannot copy value: %T is not a non-nil pointer", target)
+	}
+	c := &asConfig{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	dst = dst.Elem()
+	return c.copy(dst, v, dst.Type().Name())
+}
+
+// copy copies src to dst, which is settable. The path locates dst in the
+// destination value, for error messages.
+func (c *asConfig) copy(dst, src reflect.Value, path string) error {
+	// Unwrap interpreter interface values to their concrete value.
+	for src.IsValid() {
+		switch {
+		case src.Type() == valueInterfaceType:
+			src = src.Interface().(valueInterface).value
+			continue
+		case src.Kind() == reflect.Interface && dst.Kind() != reflect.Interface:
+			src = src.Elem()
+			continue
+		}
+		break
+	}
+	if !src.IsValid() {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+	if src.Type().AssignableTo(dst.Type()) && !hasInterpreterValues(src.Type()) {
+		dst.Set(src)
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.Ptr:
+		if src.Kind() == reflect.Ptr {
+			if src.IsNil() {
+				dst.Set(reflect.Zero(dst.Type()))
+				return nil
+			}
+			src = src.Elem()
+		}
+		p := reflect.New(dst.Type().Elem())
+		if err := c.copy(p.Elem(), src, path); err != nil {
+			return err
+		}
+		dst.Set(p)
+		return nil
+
+	case reflect.Struct:
+		if src.Kind() == reflect.Ptr && !src.IsNil() {
+			src = src.Elem()
+		}
+		if src.Kind() != reflect.Struct {
+			break
+		}
+		fields := c.fieldIndex(src.Type())
+		for i, t := 0, dst.Type(); i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, ok := c.fieldName(f)
+			if !ok {
+				continue
+			}
+			j, ok := fields[name]
+			if !ok {
+				continue
+			}
+			if err := c.copy(dst.Field(i), src.Field(j), path+"."+f.Name); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case reflect.Slice:
+		if src.Kind() != reflect.Slice && src.Kind() != reflect.Array {
+			break
+		}
+		if src.Kind() == reflect.Slice && src.IsNil() {
+			dst.Set(reflect.Zero(dst.Type()))
+			return nil
+		}
+		s := reflect.MakeSlice(dst.Type(), src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			if err := c.copy(s.Index(i), src.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		dst.Set(s)
+		return nil
+
+	case reflect.Array:
+		if src.Kind() != reflect.Slice && src.Kind() != reflect.Array {
+			break
+		}
+		if src.Len() != dst.Len() {
+			return fmt.Errorf("%s: have length %d, want %d", elemPath(path), src.Len(), dst.Len())
+		}
+		for i := 0; i < src.Len(); i++ {
+			if err := c.copy(dst.Index(i), src.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case reflect.Map:
+		if src.Kind() != reflect.Map {
+			break
+		}
+		if src.IsNil() {
+			dst.Set(reflect.Zero(dst.Type()))
+			return nil
+		}
+		t := dst.Type()
+		m := reflect.MakeMapWithSize(t, src.Len())
+		iter := src.MapRange()
+		for iter.Next() {
+			k, e := reflect.New(t.Key()).Elem(), reflect.New(t.Elem()).Elem()
+			p := fmt.Sprintf("%s[%v]", path, iter.Key())
+			if err := c.copy(k, iter.Key(), p); err != nil {
+				return err
+			}
+			if err := c.copy(e, iter.Value(), p); err != nil {
+				return err
+			}
+			m.SetMapIndex(k, e)
+		}
+		dst.Set(m)
+		return nil
+
+	case reflect.Interface:
+		if src.Type().Implements(dst.Type()) {
+			dst.Set(src)
+			return nil
+		}
+
+	case reflect.Bool:
+		if src.Kind() == reflect.Bool {
+			dst.SetBool(src.Bool())
+			return nil
+		}
+
+	case reflect.String:
+		if src.Kind() == reflect.String {
+			dst.SetString(src.String())
+			return nil
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		if isNumber(src.Type()) && src.Type().ConvertibleTo(dst.Type()) {
+			dst.Set(src.Convert(dst.Type()))
+			return nil
+		}
+	}
+	return fmt.Errorf("%s: have %s, want %s", elemPath(path), src.Type(), dst.Type())
+}
+
+// fieldName returns the name used to match the struct field f, and false if
+// the field is not to be copied.
+func (c *asConfig) fieldName(f reflect.StructField) (string, bool) {
+	if f.PkgPath != "" {
+		return "", false // unexported field
+	}
+	if c.tag == "" {
+		return f.Name, true
+	}
+	tag, ok := f.Tag.Lookup(c.tag)
+	if !ok {
+		return f.Name, true
+	}
+	if i := strings.Index(tag, ","); i >= 0 {
+		tag = tag[:i]
+	}
+	switch tag {
+	case "-":
+		return "", false
+	case "":
+		return f.Name, true
+	}
+	return tag, true
+}
+
+// fieldIndex returns the indexes of the fields of struct type t, by name.
+func (c *asConfig) fieldIndex(t reflect.Type) map[string]int {
+	fields := make(map[string]int, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if name, ok := c.fieldName(t.Field(i)); ok {
+			fields[name] = i
+		}
+	}
+	return fields
+}
+
+// hasInterpreterValues returns true if values of type t may contain values
+// internal to the interpreter, which must not be copied as is.
+func hasInterpreterValues(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Array, reflect.Chan, reflect.Map, reflect.Ptr, reflect.Slice:
+		if t.Kind() == reflect.Map && hasInterpreterValues(t.Key()) {
+			return true
+		}
+		return hasInterpreterValues(t.Elem())
+	case reflect.Struct:
+		if t == valueInterfaceType {
+			return true
+		}
+		for i := 0; i < t.NumField(); i++ {
+			if hasInterpreterValues(t.Field(i).Type) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// elemPath returns the description of the element at path, for error messages.
+func elemPath(path string) string {
+	if strings.ContainsAny(path, ".[") {
+		return "field " + path
+	}
+	return "value " + path
+}
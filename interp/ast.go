@@ -90,6 +90,7 @@ const (
 	typeAssertExpr
 	typeDecl
 	typeSpec
+	typeSpecAssign
 	typeSwitch
 	unaryExpr
 	valueSpec
@@ -170,6 +171,7 @@ var kinds = [...]string{
 	typeAssertExpr:    "typeAssertExpr",
 	typeDecl:          "typeDecl",
 	typeSpec:          "typeSpec",
+	typeSpecAssign:    "typeSpecAssign",
 	typeSwitch:        "typeSwitch",
 	unaryExpr:         "unaryExpr",
 	valueSpec:         "valueSpec",
@@ -186,6 +188,15 @@ func (k nkind) String() string {
 // astError represents an error during AST build stage.
 type astError error
 
+// typeParamsError returns the error of a generic declaration n, which is
+// rejected before go1.18, and not supported by the interpreter.
+func (n *node) typeParamsError() error {
+	if n.interp.goVersion < 18 {
+		return n.cfgErrorf("type parameter requires go1.18 or later")
+	}
+	return n.cfgErrorf("generic declarations are not supported")
+}
+
 // action defines the node action to perform at execution.
 type action uint
 
@@ -348,6 +359,7 @@ func (interp *Interpreter) ast(src, name string) (string, *node, error) {
 	inRepl := name == ""
 	var inFunc bool
 	var mode parser.Mode
+	var offset int
 
 	// Allow incremental parsing of declarations or statements, by inserting
 	// them in a pseudo file package or function. Those statements or
@@ -357,9 +369,11 @@ func (interp *Interpreter) ast(src, name string) (string, *node, error) {
 		case token.PACKAGE:
 			// nothing to do
 		case token.CONST, token.FUNC, token.IMPORT, token.TYPE, token.VAR:
+			offset = len("package main;")
 			src = "package main;" + src
 		default:
 			inFunc = true
+			offset = len("package main; func main() {")
 			src = "package main; func main() {" + src + "}"
 		}
 		// Parse comments in REPL mode, to allow tag setting
@@ -374,13 +388,41 @@ func (interp *Interpreter) ast(src, name string) (string, *node, error) {
 	if err != nil {
 		return "", nil, err
 	}
+	if interp.preCompile != nil {
+		if err := interp.preCompile(interp.fset, f); err != nil {
+			return "", nil, err
+		}
+	}
+	interp.mutex.Lock()
+	interp.files[name] = f
+	interp.mutex.Unlock()
+	if interp.errorSource {
+		interp.mutex.Lock()
+		text := src
+		if inFunc {
+			text = text[:len(text)-1] // Skip the closing brace inserted in REPL mode.
+		}
+		interp.sources[interp.fset.File(f.Package)] = source{text, offset}
+		interp.mutex.Unlock()
+	}
 
 	setYaegiTags(&interp.context, f.Comments)
 
-	var root *node
+	pkgName, root, err := interp.astNodes(f, 0)
+	if inFunc {
+		// Incremental parsing: statements were inserted in a pseudo function.
+		// Set root to function body so its statements are evaluated in global scope
+		root = root.child[1].child[3]
+		root.anc = nil
+	}
+	return pkgName, root, err
+}
+
+// astNodes generates the AST of the parsed file f. The positions of f are
+// shifted by delta, for a file parsed in another file set.
+func (interp *Interpreter) astNodes(f *ast.File, delta token.Pos) (pkgName string, root *node, err error) {
 	var anc astNode
 	var st nodestack
-	var pkgName string
 
 	addChild := func(root **node, anc astNode, pos token.Pos, kind nkind, act action) *node {
 		var i interface{}
@@ -425,8 +467,10 @@ func (interp *Interpreter) ast(src, name string) (string, *node, error) {
 	ast.Inspect(f, func(nod ast.Node) bool {
 		anc = st.top()
 		var pos token.Pos
-		if nod != nil {
-			pos = nod.Pos()
+		if nod != nil && nod.Pos().IsValid() {
+			pos = nod.Pos() + delta
+		} else if anc.node != nil {
+			pos = anc.node.pos // A node without position, as added by PreCompile.
 		}
 		switch a := nod.(type) {
 		case nil:
@@ -487,7 +531,9 @@ func (interp *Interpreter) ast(src, name string) (string, *node, error) {
 			n.ident = a.Value
 			switch a.Kind {
 			case token.CHAR:
-				v, _, _, _ := strconv.UnquoteChar(a.Value[1:len(a.Value)-1], '\'')
+				// A rune literal is an untyped integer constant, which keeps its
+				// rune type from the literal form.
+				v := constant.MakeFromLiteral(a.Value, a.Kind, 0)
 				n.rval = reflect.ValueOf(v)
 			case token.FLOAT:
 				v := constant.MakeFromLiteral(a.Value, a.Kind, 0)
@@ -662,7 +708,12 @@ func (interp *Interpreter) ast(src, name string) (string, *node, error) {
 			st.push(n, nod)
 
 		case *ast.FuncType:
-			st.push(addChild(&root, anc, pos, funcType, aNop), nod)
+			n := addChild(&root, anc, pos, funcType, aNop)
+			if typeParams(a) != nil {
+				err = n.typeParamsError()
+				return false
+			}
+			st.push(n, nod)
 
 		case *ast.GenDecl:
 			var kind nkind
@@ -794,7 +845,16 @@ func (interp *Interpreter) ast(src, name string) (string, *node, error) {
 			st.push(addChild(&root, anc, pos, typeAssertExpr, aTypeAssert), nod)
 
 		case *ast.TypeSpec:
-			st.push(addChild(&root, anc, pos, typeSpec, aNop), nod)
+			if a.Assign.IsValid() {
+				st.push(addChild(&root, anc, pos, typeSpecAssign, aNop), nod)
+				break
+			}
+			n := addChild(&root, anc, pos, typeSpec, aNop)
+			if typeParams(a) != nil {
+				err = n.typeParamsError()
+				return false
+			}
+			st.push(n, nod)
 
 		case *ast.TypeSwitchStmt:
 			n := addChild(&root, anc, pos, typeSwitch, aNop)
@@ -857,12 +917,6 @@ func (interp *Interpreter) ast(src, name string) (string, *node, error) {
 		}
 		return true
 	})
-	if inFunc {
-		// Incremental parsing: statements were inserted in a pseudo function.
-		// Set root to function body so its statements are evaluated in global scope
-		root = root.child[1].child[3]
-		root.anc = nil
-	}
 	return pkgName, root, err
 }
 
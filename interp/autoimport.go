@@ -0,0 +1,77 @@
+package interp
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// importUndefined imports in the universe scope the binary packages whose
+// names are used as selector operands in AST root, and are neither declared
+// nor imported. Each import is reported on the REPL output. A name matching
+// the base names of several binary packages is an error listing them.
+func (interp *Interpreter) importUndefined(root *node) error {
+	baseName := filepath.Base(interp.fset.Position(root.pos).Filename)
+	imported := map[string]bool{}
+	root.Walk(func(n *node) bool {
+		if n.kind != importSpec {
+			return true
+		}
+		if len(n.child) == 2 {
+			imported[n.child[0].ident] = true
+		} else {
+			imported[identifier.FindString(n.child[0].rval.String())] = true
+		}
+		return false
+	}, nil)
+
+	interp.mutex.RLock()
+	sc, ok := interp.scopes[interp.Name]
+	interp.mutex.RUnlock()
+	if !ok {
+		sc = interp.universe
+	}
+
+	var err error
+	root.Walk(func(n *node) bool {
+		if err != nil {
+			return false
+		}
+		if n.kind != selectorExpr || n.child[0].kind != identExpr {
+			return true
+		}
+		name := n.child[0].ident
+		if imported[name] {
+			return true
+		}
+		if _, _, found := sc.lookup(name); found {
+			return true
+		}
+		if _, _, found := sc.lookup(filepath.Join(name, baseName)); found {
+			return true
+		}
+
+		var paths []string
+		for path := range interp.binPkg {
+			if identifier.FindString(path) == name {
+				paths = append(paths, path)
+			}
+		}
+		switch len(paths) {
+		case 0:
+		case 1:
+			interp.universe.sym[name] = &symbol{kind: pkgSym, typ: &itype{cat: binPkgT, path: paths[0], scope: interp.universe}}
+			fmt.Fprintf(interp.notices, "// imported %q\n", paths[0])
+		default:
+			sort.Strings(paths)
+			for i, p := range paths {
+				paths[i] = fmt.Sprintf("%q", p)
+			}
+			err = n.child[0].cfgErrorf("undefined: %s, ambiguous import: %s", name, strings.Join(paths, ", "))
+		}
+		imported[name] = true
+		return true
+	}, nil)
+	return err
+}
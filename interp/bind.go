@@ -0,0 +1,60 @@
+package interp
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// BindGlobal makes the variable pointed to by ptr available to interpreted
+// code as a global variable called name, of the pointed-to type. The variable
+// is shared by reference: an assignment in interpreted code modifies the host
+// variable, and the host modifications are seen by interpreted code.
+//
+// If pkg is empty, the variable is declared in the current package, as by Eval,
+// and is used unqualified. Otherwise, pkg is the import path of a binary
+// package, as for Use, where the variable is added.
+//
+// A variable can be bound again to another pointer of the same type. In the
+// current package, the code already compiled then uses the new variable. In a
+// binary package, only the code compiled afterwards uses it. The concurrent
+// accesses to the variable must be synchronized by the user.
+func (interp *Interpreter) BindGlobal(pkg, name string, ptr interface{}) error {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("cannot bind %s: %T is not a non-nil pointer", name, ptr)
+	}
+	v = v.Elem()
+
+	if pkg != "" {
+		if old, ok := interp.binPkg[pkg][name]; ok && old.Type() != v.Type() {
+			return fmt.Errorf("cannot bind %s.%s: type %s does not match %s", pkg, name, v.Type(), old.Type())
+		}
+		if interp.binPkg[pkg] == nil {
+			interp.binPkg[pkg] = map[string]reflect.Value{}
+		}
+		interp.binPkg[pkg][name] = v
+		return nil
+	}
+
+	sc := interp.initScopePkg(interp.Name)
+	sym := sc.sym[name]
+	switch {
+	case sym == nil:
+		typ := &itype{cat: valueT, rtype: v.Type()}
+		sc.types = interp.universe.types
+		sym = &symbol{kind: varSym, global: true, index: sc.add(typ), typ: typ}
+		sc.sym[name] = sym
+		interp.universe.types = sc.types
+	case sym.kind != varSym || !sym.global:
+		return fmt.Errorf("cannot bind %s: not a variable", name)
+	case sym.typ.TypeOf() != v.Type():
+		return fmt.Errorf("cannot bind %s: type %s does not match %s", name, v.Type(), sym.typ.TypeOf())
+	}
+
+	// The frame value of a global variable is replaced by the host one.
+	interp.frame.mutex.Lock()
+	interp.resizeFrame()
+	interp.frame.data[sym.index] = v
+	interp.frame.mutex.Unlock()
+	return nil
+}
@@ -0,0 +1,113 @@
+package interp
+
+import (
+	"math/bits"
+	"reflect"
+)
+
+// bitsFunc computes a function of package math/bits with one result, from
+// its integer argument x and, for rotations, its rotation count k.
+type bitsFunc func(x uint64, k int) uint64
+
+// bitsFuncs are the functions of package math/bits computed directly from
+// the integer values of their arguments, instead of being called through
+// reflection, indexed by function entry point. The functions with several
+// results are called normally.
+var bitsFuncs = map[uintptr]bitsFunc{}
+
+func init() {
+	for _, b := range []struct {
+		fn interface{}
+		f  bitsFunc
+	}{
+		{bits.LeadingZeros, func(x uint64, _ int) uint64 { return uint64(bits.LeadingZeros(uint(x))) }},
+		{bits.LeadingZeros8, func(x uint64, _ int) uint64 { return uint64(bits.LeadingZeros8(uint8(x))) }},
+		{bits.LeadingZeros16, func(x uint64, _ int) uint64 { return uint64(bits.LeadingZeros16(uint16(x))) }},
+		{bits.LeadingZeros32, func(x uint64, _ int) uint64 { return uint64(bits.LeadingZeros32(uint32(x))) }},
+		{bits.LeadingZeros64, func(x uint64, _ int) uint64 { return uint64(bits.LeadingZeros64(x)) }},
+		{bits.TrailingZeros, func(x uint64, _ int) uint64 { return uint64(bits.TrailingZeros(uint(x))) }},
+		{bits.TrailingZeros8, func(x uint64, _ int) uint64 { return uint64(bits.TrailingZeros8(uint8(x))) }},
+		{bits.TrailingZeros16, func(x uint64, _ int) uint64 { return uint64(bits.TrailingZeros16(uint16(x))) }},
+		{bits.TrailingZeros32, func(x uint64, _ int) uint64 { return uint64(bits.TrailingZeros32(uint32(x))) }},
+		{bits.TrailingZeros64, func(x uint64, _ int) uint64 { return uint64(bits.TrailingZeros64(x)) }},
+		{bits.OnesCount, func(x uint64, _ int) uint64 { return uint64(bits.OnesCount(uint(x))) }},
+		{bits.OnesCount8, func(x uint64, _ int) uint64 { return uint64(bits.OnesCount8(uint8(x))) }},
+		{bits.OnesCount16, func(x uint64, _ int) uint64 { return uint64(bits.OnesCount16(uint16(x))) }},
+		{bits.OnesCount32, func(x uint64, _ int) uint64 { return uint64(bits.OnesCount32(uint32(x))) }},
+		{bits.OnesCount64, func(x uint64, _ int) uint64 { return uint64(bits.OnesCount64(x)) }},
+		{bits.Len, func(x uint64, _ int) uint64 { return uint64(bits.Len(uint(x))) }},
+		{bits.Len8, func(x uint64, _ int) uint64 { return uint64(bits.Len8(uint8(x))) }},
+		{bits.Len16, func(x uint64, _ int) uint64 { return uint64(bits.Len16(uint16(x))) }},
+		{bits.Len32, func(x uint64, _ int) uint64 { return uint64(bits.Len32(uint32(x))) }},
+		{bits.Len64, func(x uint64, _ int) uint64 { return uint64(bits.Len64(x)) }},
+		{bits.Reverse, func(x uint64, _ int) uint64 { return uint64(bits.Reverse(uint(x))) }},
+		{bits.Reverse8, func(x uint64, _ int) uint64 { return uint64(bits.Reverse8(uint8(x))) }},
+		{bits.Reverse16, func(x uint64, _ int) uint64 { return uint64(bits.Reverse16(uint16(x))) }},
+		{bits.Reverse32, func(x uint64, _ int) uint64 { return uint64(bits.Reverse32(uint32(x))) }},
+		{bits.Reverse64, func(x uint64, _ int) uint64 { return bits.Reverse64(x) }},
+		{bits.ReverseBytes, func(x uint64, _ int) uint64 { return uint64(bits.ReverseBytes(uint(x))) }},
+		{bits.ReverseBytes16, func(x uint64, _ int) uint64 { return uint64(bits.ReverseBytes16(uint16(x))) }},
+		{bits.ReverseBytes32, func(x uint64, _ int) uint64 { return uint64(bits.ReverseBytes32(uint32(x))) }},
+		{bits.ReverseBytes64, func(x uint64, _ int) uint64 { return bits.ReverseBytes64(x) }},
+		{bits.RotateLeft, func(x uint64, k int) uint64 { return uint64(bits.RotateLeft(uint(x), k)) }},
+		{bits.RotateLeft8, func(x uint64, k int) uint64 { return uint64(bits.RotateLeft8(uint8(x), k)) }},
+		{bits.RotateLeft16, func(x uint64, k int) uint64 { return uint64(bits.RotateLeft16(uint16(x), k)) }},
+		{bits.RotateLeft32, func(x uint64, k int) uint64 { return uint64(bits.RotateLeft32(uint32(x), k)) }},
+		{bits.RotateLeft64, func(x uint64, k int) uint64 { return bits.RotateLeft64(x, k) }},
+	} {
+		bitsFuncs[reflect.ValueOf(b.fn).Pointer()] = b.f
+	}
+}
+
+// callBits generates the execution of the call n of a math/bits function
+// computed directly, and returns false if the call must be performed through
+// reflection.
+func callBits(n *node) bool {
+	switch {
+	case n.anc.kind == deferStmt || n.anc.kind == goStmt || n.action == aCallSlice:
+		return false
+	case n.anc.action == aReturn && isInterfaceSrc(n.anc.val.(*node).typ.ret[childPos(n)]):
+		return false
+	}
+	fn := n.child[0].rval
+	if !fn.IsValid() || fn.Kind() != reflect.Func {
+		return false
+	}
+	op := bitsFuncs[fn.Pointer()]
+	if op == nil || len(n.child)-1 != fn.Type().NumIn() {
+		return false
+	}
+
+	for i, c := range n.child[1:] {
+		if c.kind == basicLit || c.rval.IsValid() {
+			convertLiteralValue(c, fn.Type().In(i))
+		}
+	}
+	x := genValueUint(n.child[1])
+	k := func(*frame) (reflect.Value, int64) { return reflect.Value{}, 0 }
+	if len(n.child) > 2 {
+		k = genValueInt(n.child[2])
+	}
+	if x == nil || k == nil {
+		return false
+	}
+
+	next := getExec(n.tnext)
+	index, level := n.findex, n.level
+	if fn.Type().Out(0).Kind() == reflect.Int {
+		n.exec = func(f *frame) bltn {
+			_, vx := x(f)
+			_, vk := k(f)
+			getFrame(f, level).data[index].SetInt(int64(op(vx, int(vk))))
+			return next
+		}
+		return true
+	}
+	n.exec = func(f *frame) bltn {
+		_, vx := x(f)
+		_, vk := k(f)
+		getFrame(f, level).data[index].SetUint(op(vx, int(vk)))
+		return next
+	}
+	return true
+}
@@ -1,6 +1,7 @@
 package interp
 
 import (
+	"fmt"
 	"go/ast"
 	"go/build"
 	"go/parser"
@@ -128,6 +129,28 @@ func goMinorVersion(ctx *build.Context) int {
 	return m
 }
 
+// goReleaseTags returns the release tags of Go version v, as "go1.21",
+// from go1.1 to v. A patch number or a pre-release suffix, as in "go1.21.3"
+// or "go1.21rc1", is ignored.
+func goReleaseTags(v string) ([]string, error) {
+	var m int
+	if s := strings.SplitN(v, ".", 3); len(s) > 1 && s[0] == "go1" {
+		minor := s[1]
+		if i := strings.IndexFunc(minor, func(r rune) bool { return r < '0' || r > '9' }); i >= 0 {
+			minor = minor[:i]
+		}
+		m, _ = strconv.Atoi(minor)
+	}
+	if m < 1 {
+		return nil, fmt.Errorf("invalid Go version: %q", v)
+	}
+	tags := make([]string, m)
+	for i := range tags {
+		tags[i] = "go1." + strconv.Itoa(i+1)
+	}
+	return tags, nil
+}
+
 // skipFile returns true if file should be skipped.
 func skipFile(ctx *build.Context, p string) bool {
 	if !strings.HasSuffix(p, ".go") {
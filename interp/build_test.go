@@ -2,6 +2,7 @@ package interp
 
 import (
 	"go/build"
+	"strings"
 	"testing"
 )
 
@@ -114,3 +115,39 @@ func Test_goMinorVersion(t *testing.T) {
 		})
 	}
 }
+
+func Test_goReleaseTags(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected string
+	}{
+		{version: "go1.3", expected: "go1.1 go1.2 go1.3"},
+		{version: "go1.3.2", expected: "go1.1 go1.2 go1.3"},
+		{version: "go1.3rc1", expected: "go1.1 go1.2 go1.3"},
+		{version: "go1.3beta2", expected: "go1.1 go1.2 go1.3"},
+		{version: "go1", expected: ""},
+		{version: "go1.0", expected: ""},
+		{version: "go1.x", expected: ""},
+		{version: "go2.1", expected: ""},
+		{version: "1.3", expected: ""},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.version, func(t *testing.T) {
+			tags, err := goReleaseTags(test.version)
+			if test.expected == "" {
+				if err == nil {
+					t.Errorf("got %v, want an error", tags)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r := strings.Join(tags, " "); r != test.expected {
+				t.Errorf("got %v, want %v", r, test.expected)
+			}
+		})
+	}
+}
@@ -3,12 +3,15 @@ package interp
 import (
 	"fmt"
 	"go/constant"
+	"go/token"
 	"log"
 	"math"
 	"path/filepath"
 	"reflect"
 	"regexp"
+	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
 // A cfgError represents an error during CFG build stage.
@@ -19,6 +22,24 @@ type cfgError struct {
 
 func (c *cfgError) Error() string { return c.error.Error() }
 
+// maxErrors is the maximum number of errors reported by the static analysis of
+// a source package, before giving up.
+const maxErrors = 10
+
+// ErrorList is a list of errors detected during the static analysis of source
+// files, prior to execution. It is returned by Eval and by the import of source
+// packages, and can be extracted using errors.As. Errors are formatted one per
+// line, prefixed by their position in source.
+type ErrorList []error
+
+func (e ErrorList) Error() string {
+	s := make([]string, len(e))
+	for i, err := range e {
+		s[i] = err.Error()
+	}
+	return strings.Join(s, "\n")
+}
+
 var constOp = map[action]func(*node){
 	aAdd:    addConst,
 	aSub:    subConst,
@@ -35,14 +56,32 @@ var constOp = map[action]func(*node){
 	aBitNot: bitNotConst,
 	aNeg:    negConst,
 	aPos:    posConst,
+
+	aEqual:        compareConst,
+	aNotEqual:     compareConst,
+	aGreater:      compareConst,
+	aGreaterEqual: compareConst,
+	aLower:        compareConst,
+	aLowerEqual:   compareConst,
 }
 
 var constBltn = map[string]func(*node){
 	"complex": complexConst,
 	"imag":    imagConst,
+	"len":     lenConst,
+	"max":     maxConst,
+	"min":     minConst,
 	"real":    realConst,
 }
 
+// bltnVersion is the minor version of Go which introduced a builtin,
+// if later than go1.
+var bltnVersion = map[string]int{
+	"clear": 21,
+	"max":   21,
+	"min":   21,
+}
+
 var identifier = regexp.MustCompile(`([\pL_][\pL_\d]*)$`)
 
 const nilIdent = "nil"
@@ -51,18 +90,34 @@ const nilIdent = "nil"
 // and pre-compute frame sizes and indexes for all un-named (temporary) and named
 // variables. A list of nodes of init functions is returned.
 // Following this pass, the CFG is ready to run.
+// For a file, the analysis resumes after an erroneous top level declaration,
+// and the errors are returned in an ErrorList.
 func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
-	sc := interp.initScopePkg(pkgID)
+	return interp.cfgScope(root, interp.initScopePkg(pkgID))
+}
+
+// cfgScope generates the CFG of root in scope sc, which is a package scope
+// except for constant expressions computed early in a function.
+func (interp *Interpreter) cfgScope(root *node, sc *scope) ([]*node, error) {
+	pkgID := sc.pkgID
+	pkgScope := sc
 	check := typecheck{}
 	var initNodes []*node
 	var err error
+	var errs ErrorList
 
 	baseName := filepath.Base(interp.fset.Position(root.pos).Filename)
 
 	root.Walk(func(n *node) bool {
 		// Pre-order processing
 		if err != nil {
-			return false
+			if root.kind != fileStmt || n.anc != root || len(errs) == maxErrors-1 {
+				return false
+			}
+			// Resume at next top level declaration, in package scope.
+			errs = append(errs, err)
+			err = nil
+			sc = pkgScope
 		}
 		switch n.kind {
 		case blockStmt:
@@ -86,6 +141,37 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 					e.typ = t.val
 					e.findex = index
 					n.anc.gen = rangeChan
+				} else if o := n.anc.child[len(n.anc.child)-2]; isInt(o.typ.TypeOf()) {
+					// range over integer
+					if interp.goVersion < 22 {
+						err = o.cfgErrorf("cannot range over %s: requires go1.22 or later", exprString(o))
+						return false
+					}
+					if len(n.anc.child) == 4 {
+						err = n.anc.child[1].cfgErrorf("range over %s permits only one iteration variable", exprString(o))
+						return false
+					}
+					k, ktyp := n.anc.child[0], o.typ
+					switch {
+					case ktyp.untyped && ktyp.cat == int32T:
+						ktyp = sc.getType("rune")
+					case ktyp.untyped:
+						ktyp = sc.getType("int")
+					}
+					n.anc.gen = rangeInt
+					sc.add(ktyp) // Add a dummy type to store the range limit
+					sc.add(ktyp) // Add a dummy type to store the next value for range
+					kindex := sc.add(ktyp)
+					sc.sym[k.ident] = &symbol{index: kindex, kind: varSym, typ: ktyp}
+					k.typ = ktyp
+					k.findex = kindex
+				} else if isFunc(o.typ) {
+					if interp.goVersion < 23 {
+						err = o.cfgErrorf("cannot range over %s: requires go1.23 or later", exprString(o))
+					} else {
+						err = o.cfgErrorf("cannot range over %s: range over function not supported", exprString(o))
+					}
+					return false
 				} else {
 					// range over array or map
 					var ktyp, vtyp *itype
@@ -107,7 +193,9 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 							ktyp = &itype{cat: valueT, rtype: typ.Key()}
 							vtyp = &itype{cat: valueT, rtype: typ.Elem()}
 						case reflect.String:
-							sc.add(sc.getType("int")) // Add a dummy type to store array shallow copy for range
+							n.anc.gen = rangeString
+							sc.add(sc.getType("string")) // Add a dummy type to store string copy for range
+							sc.add(sc.getType("int"))    // Add a dummy type to store next byte index for range
 							ktyp = sc.getType("int")
 							vtyp = sc.getType("rune")
 						case reflect.Array, reflect.Slice:
@@ -130,7 +218,9 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 							vtyp = vtyp.val
 						}
 					case stringT:
-						sc.add(sc.getType("int")) // Add a dummy type to store array shallow copy for range
+						n.anc.gen = rangeString
+						sc.add(sc.getType("string")) // Add a dummy type to store string copy for range
+						sc.add(sc.getType("int"))    // Add a dummy type to store next byte index for range
 						ktyp = sc.getType("int")
 						vtyp = sc.getType("rune")
 					case arrayT, variadicT:
@@ -138,6 +228,10 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 						ktyp = sc.getType("int")
 						vtyp = o.typ.val
 					}
+					if ktyp == nil {
+						err = o.cfgErrorf("cannot range over %s", exprString(o))
+						return false
+					}
 
 					kindex := sc.add(ktyp)
 					sc.sym[k.ident] = &symbol{index: kindex, kind: varSym, typ: ktyp}
@@ -156,7 +250,24 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 			n.val = nil
 			sc = sc.pushBloc()
 
-		case breakStmt, continueStmt, gotoStmt:
+		case breakStmt, continueStmt:
+			if len(n.child) > 0 {
+				// The label must refer to an enclosing for, switch or select
+				// statement, or only to a for statement for continue.
+				label := n.child[0].ident
+				l := enclosingLabel(n, label)
+				if l == nil || (n.kind == continueStmt && !isLoop(l.child[1])) {
+					stmt := "break"
+					if n.kind == continueStmt {
+						stmt = "continue"
+					}
+					err = n.child[0].cfgErrorf("invalid %s label %s", stmt, label)
+					return false
+				}
+				n.sym = l.sym
+			}
+
+		case gotoStmt:
 			if len(n.child) > 0 {
 				// Handle labeled statements
 				label := n.child[0].ident
@@ -195,7 +306,7 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 				if len(n.child) == 2 {
 					// 1 type in clause: define the var with this type in the case clause scope
 					switch {
-					case n.child[0].ident == nilIdent:
+					case interp.isNil(n.child[0], sc):
 						typ = sc.getType("interface{}")
 					case !n.child[0].isType(sc):
 						err = n.cfgErrorf("%s is not a type", n.child[0].ident)
@@ -221,7 +332,7 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 
 		case commClause:
 			sc = sc.pushBloc()
-			if len(n.child) > 0 && n.child[0].action == aAssign {
+			if len(n.child) > 0 && n.child[0].kind == defineStmt {
 				ch := n.child[0].child[1].child[0]
 				var typ *itype
 				if typ, err = nodeType(interp, sc, ch); err != nil {
@@ -246,29 +357,22 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 					return false
 				}
 				n.nleft = 1
-			} else {
-				// Get type from ancestor (implicit type)
-				if n.anc.kind == keyValueExpr && n == n.anc.child[0] {
-					n.typ = n.anc.typ.key
-				} else if n.anc.typ != nil {
-					n.typ = n.anc.typ.val
-				}
-				if n.typ == nil {
-					err = n.cfgErrorf("undefined type")
-					return false
-				}
+			} else if n.typ = elidedType(n); n.typ == nil {
+				// The type is implicit, from ancestor.
+				err = n.cfgErrorf("missing type in composite literal")
+				return false
+			}
+			if !isComposite(n.typ) {
+				err = n.cfgErrorf("invalid composite literal type %s", n.typ.id())
+				return false
 			}
-			// Propagate type to children, to handle implicit types
 			for _, c := range n.child {
 				switch c.kind {
-				case binaryExpr, unaryExpr:
-					// Do not attempt to propagate composite type to operator expressions,
-					// it breaks constant folding.
 				case callExpr:
 					if c.typ, err = nodeType(interp, sc, c); err != nil {
 						return false
 					}
-				default:
+				case keyValueExpr:
 					c.typ = n.typ
 				}
 			}
@@ -291,6 +395,9 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 
 		case funcDecl:
 			n.val = n
+			if err = checkParamNames(n); err != nil {
+				return false
+			}
 			// Compute function type before entering local scope to avoid
 			// possible collisions with function argument names.
 			n.child[2].typ, err = nodeType(interp, sc, n.child[2])
@@ -306,7 +413,7 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 					}
 					if len(c.child) > 1 {
 						for _, cc := range c.child[:len(c.child)-1] {
-							sc.sym[cc.ident] = &symbol{index: sc.add(typ), kind: varSym, typ: typ}
+							sc.sym[cc.ident] = &symbol{index: sc.add(typ), kind: varSym, typ: typ, node: c}
 						}
 					} else {
 						sc.add(typ)
@@ -318,13 +425,19 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 				var typ *itype
 				fr := n.child[0].child[0]
 				recvTypeNode := fr.lastChild()
+				// The receiver type set by gta may be a previous version of the
+				// type, redefined once its dependencies are known.
+				recvTypeNode.typ = nil
 				if typ, err = nodeType(interp, sc, recvTypeNode); err != nil {
 					return false
 				}
 				recvTypeNode.typ = typ
+				if err = checkRecvType(n, recvTypeNode, typ); err != nil {
+					return false
+				}
 				index := sc.add(typ)
 				if len(fr.child) > 1 {
-					sc.sym[fr.child[0].ident] = &symbol{index: index, kind: varSym, typ: typ}
+					sc.sym[fr.child[0].ident] = &symbol{index: index, kind: varSym, typ: typ, node: fr}
 				}
 			}
 			for _, c := range n.child[2].child[0].child {
@@ -334,7 +447,7 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 					return false
 				}
 				for _, cc := range c.child[:len(c.child)-1] {
-					sc.sym[cc.ident] = &symbol{index: sc.add(typ), kind: varSym, typ: typ}
+					sc.sym[cc.ident] = &symbol{index: sc.add(typ), kind: varSym, typ: typ, node: c}
 				}
 			}
 			if n.child[1].ident == "init" && len(n.child[0].child) == 0 {
@@ -344,12 +457,11 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 		case ifStmt0, ifStmt1, ifStmt2, ifStmt3:
 			sc = sc.pushBloc()
 
+		case selectStmt:
+			sc = sc.pushBloc()
+			sc.loop = n
+
 		case switchStmt, switchIfStmt, typeSwitch:
-			// Make sure default clause is in last position.
-			c := n.lastChild().child
-			if i, l := getDefault(n), len(c)-1; i >= 0 && i != l {
-				c[i], c[l] = c[l], c[i]
-			}
 			sc = sc.pushBloc()
 			sc.loop = n
 
@@ -384,9 +496,8 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 				return false
 			}
 
-			if _, exists := sc.sym[typeName]; exists {
-				// TODO(mpl): find the exact location of the previous declaration
-				err = n.cfgErrorf("%s redeclared in this block", typeName)
+			if sym, exists := sc.lookupBlock(typeName); exists {
+				err = redeclaredError(n.child[0], sym)
 				return false
 			}
 
@@ -396,9 +507,32 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 				n.typ = typ
 				n.typ.name = typeName
 			}
-			sc.sym[typeName] = &symbol{kind: typeSym, typ: n.typ}
+			sc.sym[typeName] = &symbol{kind: typeSym, typ: n.typ, node: n}
+			return false
+
+		case typeSpecAssign:
+			// processing already done in GTA pass for global types, only parses inlined types
+			if sc.def == nil {
+				return false
+			}
+			typeName := n.child[0].ident
+			if sym, exists := sc.lookupBlock(typeName); exists {
+				err = redeclaredError(n.child[0], sym)
+				return false
+			}
+			if n.typ, err = nodeType(interp, sc, n.child[1]); err != nil {
+				return false
+			}
+			sc.sym[typeName] = &symbol{kind: typeSym, typ: n.typ, node: n}
 			return false
 
+		case defineStmt:
+			if n.anc.kind == constDecl {
+				// The constant specs may be processed out of order, if they
+				// depend on constants declared later: iota is the spec index.
+				sc.iota = childPos(n)
+			}
+
 		case constDecl:
 			// Early parse of constDecl subtrees, to compute all constant
 			// values which may be used in further declarations.
@@ -447,10 +581,15 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 				n.gen = nop
 				break
 			}
-			if n.anc.kind == commClause {
+			if n.anc.kind == commClause && n.anc.child[0] == n {
+				// The channel receive is assigned by the select statement.
 				n.gen = nop
 				break
 			}
+			if isBlankDefine(n) || !sc.global && isShortDefine(n) && !hasNewVar(sc, n) {
+				err = n.cfgErrorf("no new variables on left side of :=")
+				break
+			}
 			var atyp *itype
 			if n.nleft+n.nright < len(n.child) {
 				if atyp, err = nodeType(interp, sc, n.child[n.nleft]); err != nil {
@@ -468,7 +607,19 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 				dest, src := n.child[i], n.child[sbase+i]
 				var sym *symbol
 				var level int
-				if n.kind == defineStmt || (n.kind == assignStmt && dest.ident == "_") {
+				var prev *symbol
+				if n.kind == defineStmt && dest.ident != "_" && !sc.global {
+					prev, _ = sc.lookupBlock(dest.ident)
+				}
+				if prev != nil && (!isShortDefine(n) || prev.kind != varSym) {
+					err = redeclaredError(dest, prev)
+					return
+				}
+				if prev != nil {
+					// The existing variable of a short variable declaration is assigned.
+					sym = prev
+					dest.typ, dest.findex = sym.typ, sym.index
+				} else if n.kind == defineStmt || (n.kind == assignStmt && dest.ident == "_") {
 					if atyp != nil {
 						dest.typ = atyp
 					} else {
@@ -491,16 +642,18 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 						dest.typ.size = arrayTypeLen(src)
 						dest.typ.rtype = nil
 					}
-					if sc.global {
+					if sc.global && !inScopeStmt(n, root) {
 						// Do not overload existing symbols (defined in GTA) in global scope
 						sym, _, _ = sc.lookup(dest.ident)
 					}
 					if sym == nil {
-						sym = &symbol{index: sc.add(dest.typ), kind: varSym, typ: dest.typ}
+						sym = &symbol{index: sc.add(dest.typ), kind: varSym, typ: dest.typ, node: n}
 						sc.sym[dest.ident] = sym
 					}
-					dest.val = src.val
-					dest.recv = src.recv
+					if src.recv == nil {
+						// A method value is only known at run time, with its receiver.
+						dest.val = src.val
+					}
 					dest.findex = sym.index
 					sym.rval = src.rval
 				} else {
@@ -518,7 +671,10 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 				// Propagate type
 				// TODO: Check that existing destination type matches source type
 				switch {
-				case n.action == aAssign && isCall(src) && dest.typ.cat != interfaceT && !isMapEntry(dest) && !isRecursiveField(dest):
+				case n.nleft > 1:
+					// The multiple assignment is performed by the assign action,
+					// after all the sources are evaluated.
+				case n.action == aAssign && isCall(src) && !src.rval.IsValid() && !isInterface(dest.typ) && !isMapEntry(dest) && !isRecursiveField(dest) && !isBinVar(dest) && !isGlobalVar(dest):
 					// Call action may perform the assignment directly.
 					n.gen = nop
 					src.level = level
@@ -531,8 +687,8 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 					n.gen = nop
 					src.findex = dest.findex // Set recv address to LHS
 					dest.typ = src.typ
-				case n.action == aAssign && src.action == aCompositeLit && !isMapEntry(dest):
-					if dest.typ.cat == valueT && dest.typ.rtype.Kind() == reflect.Interface {
+				case n.action == aAssign && src.action == aCompositeLit && !isMapEntry(dest) && !isRecursiveField(dest) && !isBinVar(dest) && !isGlobalVar(dest):
+					if isInterfaceBin(dest.typ) {
 						// Skip optimisation for assigned binary interface or map entry
 						// which require and additional operation to set the value
 						break
@@ -549,11 +705,15 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 				n.typ = dest.typ
 				if sym != nil {
 					sym.typ = n.typ
-					sym.recv = src.recv
 				}
 				n.level = level
-				if isMapEntry(dest) {
+				switch {
+				case !isMapEntry(dest):
+				case n.action == aAssign:
 					dest.gen = nop // skip getIndexMap
+				default:
+					// Read the current map entry, then write back the operation result.
+					n.gen = setMapEntry(n.gen)
 				}
 				if n.anc.kind == constDecl {
 					n.gen = nop
@@ -570,7 +730,13 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 			}
 
 		case incDecStmt:
+			if err = check.incDecStmt(n); err != nil {
+				break
+			}
 			wireChild(n)
+			if isMapEntry(n.child[0]) {
+				n.gen = setMapEntry(n.gen)
+			}
 			n.findex = n.child[0].findex
 			n.level = n.child[0].level
 			n.typ = n.child[0].typ
@@ -591,27 +757,25 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 					err = n.cfgErrorf("assignment mismatch: %d variables but %s returns %d values", l, lc.child[0].name(), r)
 				}
 				n.gen = nop
-			case indexExpr:
-				lc.gen = getIndexMap2
-				n.gen = nop
-			case typeAssertExpr:
-				if n.child[0].ident == "_" {
-					lc.gen = typeAssertStatus
-				} else {
-					lc.gen = typeAssert2
-				}
-				n.gen = nop
-			case unaryExpr:
-				if lc.action == aRecv {
-					lc.gen = recv2
-					n.gen = nop
+			default:
+				var typ *itype
+				if typ, err = commaOk(sc, n); err != nil {
+					break
 				}
+				err = check.assignCommaOk(n, typ, sc.getType("bool"))
 			}
 
 		case defineXStmt:
+			if isBlankDefine(n) || !sc.global && isShortDefine(n) && !hasNewVar(sc, n) {
+				err = n.cfgErrorf("no new variables on left side of :=")
+				break
+			}
 			wireChild(n)
 			if sc.def == nil {
 				// In global scope, type definition already handled by GTA.
+				if n.lastChild().kind != callExpr {
+					_, err = commaOk(sc, n)
+				}
 				break
 			}
 			err = compDefineX(sc, n)
@@ -652,6 +816,9 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 			if c0.rval.IsValid() && c1.rval.IsValid() && !isInterface(n.typ) && constOp[n.action] != nil {
 				n.typ.TypeOf()       // Force compute of reflection type.
 				constOp[n.action](n) // Compute a constant result now rather than during exec.
+				if err = check.constResult(n); err != nil {
+					break
+				}
 			}
 			switch {
 			case n.rval.IsValid():
@@ -659,14 +826,17 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 				// by constOp and available in n.rval. Nothing else to do at execution.
 				n.gen = nop
 				n.findex = -1
-			case n.anc.kind == assignStmt && n.anc.action == aAssign:
+			case n.anc.kind == assignStmt && n.anc.action == aAssign && n.anc.nleft == 1 &&
+				!isInterfaceBin(n.anc.child[childPos(n)-n.anc.nright].typ) && !isBinVar(n.anc.child[childPos(n)-n.anc.nright]):
 				// To avoid a copy in frame, if the result is to be assigned, store it directly
-				// at the frame location of destination.
+				// at the frame location of destination, unless a conversion to a binary
+				// interface is required, the destination is a binary variable, not in
+				// frame, or the assignment is multiple.
 				dest := n.anc.child[childPos(n)-n.anc.nright]
 				n.typ = dest.typ
 				n.findex = dest.findex
 				n.level = dest.level
-			case n.anc.kind == returnStmt:
+			case isDirectReturn(n, sc.def):
 				// To avoid a copy in frame, if the result is to be returned, store it directly
 				// at the frame location reserved for output arguments.
 				pos := childPos(n)
@@ -699,6 +869,9 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 			default:
 				n.typ = t.val
 			}
+			if err = check.indexExpr(n); err != nil {
+				break
+			}
 			n.findex = sc.add(n.typ)
 			typ := t.TypeOf()
 			switch k := typ.Kind(); k {
@@ -752,14 +925,14 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 
 		case breakStmt:
 			if len(n.child) > 0 {
-				gotoLabel(n.sym)
+				n.tnext = n.sym.node.child[1]
 			} else {
 				n.tnext = sc.loop
 			}
 
 		case continueStmt:
 			if len(n.child) > 0 {
-				gotoLabel(n.sym)
+				n.tnext = loopRestart(n.sym.node.child[1])
 			} else {
 				n.tnext = sc.loopRestart
 			}
@@ -775,56 +948,75 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 		case callExpr:
 			wireChild(n)
 			switch {
-			case interp.isBuiltinCall(n):
+			case interp.isBuiltinCall(n, sc):
 				n.gen = n.child[0].sym.builtin
 				n.child[0].typ = &itype{cat: builtinT}
 				if n.typ, err = nodeType(interp, sc, n); err != nil {
 					return
 				}
+				if name := n.child[0].ident; (name == "min" || name == "max") && !n.typ.untyped {
+					// Untyped arguments must be representable by the type of typed ones.
+					for _, c := range n.child[1:] {
+						if err = check.convertUntyped(c, n.typ); err != nil {
+							return
+						}
+					}
+				}
+				if op, ok := constBltn[n.child[0].ident]; ok {
+					op(n) // Compute a constant result now rather than during exec.
+				}
 				switch {
+				case n.rval.IsValid():
+					n.findex = -1
 				case n.typ.cat == builtinT:
 					n.findex = -1
 					n.val = nil
-				case n.anc.kind == returnStmt:
+				case isDirectReturn(n, sc.def):
 					// Store result directly to frame output location, to avoid a frame copy.
-					n.findex = 0
+					n.findex = childPos(n)
 				default:
 					n.findex = sc.add(n.typ)
 				}
-				if op, ok := constBltn[n.child[0].ident]; ok && n.anc.action != aAssign {
-					op(n) // pre-compute non-assigned constant :
-				}
 
 			case n.child[0].isType(sc):
 				// Type conversion expression
-				if isInt(n.child[0].typ.TypeOf()) && n.child[1].kind == basicLit && isFloat(n.child[1].typ.TypeOf()) {
-					err = n.cfgErrorf("truncated to integer")
+				c0, c1 := n.child[0], n.child[1]
+				if err = check.conversion(n); err != nil {
 					break
 				}
 				n.action = aConvert
 				switch {
-				case isInterface(n.child[0].typ) && !n.child[1].isNil():
-					// Convert to interface: just check that all required methods are defined by concrete type.
-					c0, c1 := n.child[0], n.child[1]
-					if !c1.typ.implements(c0.typ) {
-						err = n.cfgErrorf("type %v does not implement interface %v", c1.typ.id(), c0.typ.id())
+				case isInterface(c0.typ) && !c1.isNil():
+					// Convert to interface: the required methods are checked by conversion.
+					if isInterfaceSrc(c0.typ) && !isInterfaceSrc(c1.typ) ||
+						(c0.typ.cat == valueT || c0.typ.cat == errorT) && c1.typ.cat != valueT && !isInterface(c1.typ) {
+						// Convert value to interpreted interface, keeping its dynamic
+						// type, or interpreted value to binary interface: wrap it.
+						n.gen = convert
+						n.typ = c0.typ
+						n.findex = sc.add(n.typ)
+						break
 					}
 					// Pass value as is
 					n.gen = nop
-					n.typ = n.child[1].typ
-					n.findex = n.child[1].findex
-					n.level = n.child[1].level
-					n.val = n.child[1].val
-					n.rval = n.child[1].rval
-				case n.child[1].rval.IsValid() && isConstType(n.child[0].typ):
+					n.typ = c1.typ
+					n.findex = c1.findex
+					n.level = c1.level
+					n.val = c1.val
+					n.rval = c1.rval
+				case c1.rval.IsValid() && !isBinVar(c1) && isConstType(c0.typ):
 					n.gen = nop
 					n.findex = -1
-					n.typ = n.child[0].typ
-					n.rval = n.child[1].rval
-					convertConstantValue(n)
+					n.typ = c0.typ
+					if _, ok := c1.constValue(); ok {
+						n.rval = c1.rval
+						convertConstantValue(n)
+					} else {
+						n.rval = c1.rval.Convert(n.typ.TypeOf())
+					}
 				default:
 					n.gen = convert
-					n.typ = n.child[0].typ
+					n.typ = c0.typ
 					n.findex = sc.add(n.typ)
 				}
 			case isBinCall(n):
@@ -833,7 +1025,7 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 				numIn := len(n.child) - 1
 				tni := typ.NumIn()
 				if numIn == 1 && isCall(n.child[1]) {
-					numIn = n.child[1].typ.numOut()
+					numIn = n.child[1].child[0].typ.numOut()
 				}
 				if n.child[0].action == aGetMethod {
 					tni-- // The first argument is the method receiver.
@@ -845,6 +1037,9 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 					err = n.cfgErrorf("not enough arguments in call to %v", n.child[0].name())
 					break
 				}
+				if err = check.binArguments(n); err != nil {
+					break
+				}
 				if typ.NumOut() > 0 {
 					if funcType := n.child[0].typ.val; funcType != nil {
 						// Use the original unwrapped function type, to allow future field and
@@ -856,7 +1051,7 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 						}
 					} else {
 						n.typ = &itype{cat: valueT, rtype: typ.Out(0)}
-						if n.anc.kind == returnStmt {
+						if isDirectReturn(n, sc.def) {
 							n.findex = childPos(n)
 						} else {
 							n.findex = sc.add(n.typ)
@@ -873,7 +1068,7 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 				}
 				if typ := n.child[0].typ; len(typ.ret) > 0 {
 					n.typ = typ.ret[0]
-					if n.anc.kind == returnStmt {
+					if isDirectReturn(n, sc.def) {
 						n.findex = childPos(n)
 					} else {
 						n.findex = sc.add(n.typ)
@@ -926,9 +1121,31 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 			n.findex = sc.add(n.typ)
 			// TODO: Check that composite literal expr matches corresponding type
 			n.gen = compositeGenerator(n)
+			switch n.typ.cat {
+			case arrayT:
+				l := -1
+				if n.typ.sizedef {
+					l = n.typ.size
+				}
+				if err = check.arrayLitExpr(n.child[n.nleft:], l); err != nil {
+					break
+				}
+				err = check.compositeLitValues(n.child[n.nleft:], n.typ.val)
+			case valueT:
+				switch rt := n.typ.rtype; rt.Kind() {
+				case reflect.Array:
+					err = check.arrayLitExpr(n.child[n.nleft:], rt.Len())
+				case reflect.Slice:
+					err = check.arrayLitExpr(n.child[n.nleft:], -1)
+				case reflect.Struct:
+					err = check.structLitExpr(n, pkgID)
+				}
+			case structT:
+				err = check.structLitExpr(n, pkgID)
+			}
 
 		case fallthroughtStmt:
-			if n.anc.kind != caseBody {
+			if n.anc.kind != caseBody || n.anc.lastChild() != n {
 				err = n.cfgErrorf("fallthrough statement out of place")
 			}
 
@@ -941,6 +1158,7 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 			body := n.child[0]
 			n.start = body.start
 			body.tnext = n.start
+			setLoopVars(n)
 			sc = sc.pop()
 
 		case forStmt1: // for cond {}
@@ -960,6 +1178,7 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 				body.tnext = cond.start
 			}
 			setFNext(cond, n)
+			setLoopVars(n)
 			sc = sc.pop()
 
 		case forStmt2: // for init; cond; {}
@@ -982,6 +1201,7 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 			}
 			cond.tnext = body.start
 			setFNext(cond, n)
+			setLoopVars(n)
 			sc = sc.pop()
 
 		case forStmt3: // for ; cond; post {}
@@ -1002,6 +1222,7 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 			cond.tnext = body.start
 			setFNext(cond, n)
 			body.tnext = post.start
+			setLoopVars(n)
 			sc = sc.pop()
 
 		case forStmt3a: // for init; ; post {}
@@ -1010,6 +1231,7 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 			init.tnext = body.start
 			body.tnext = post.start
 			post.tnext = body.start
+			setLoopVars(n)
 			sc = sc.pop()
 
 		case forStmt4: // for init; cond; post {}
@@ -1033,11 +1255,13 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 			cond.tnext = body.start
 			setFNext(cond, n)
 			body.tnext = post.start
+			setLoopVars(n)
 			sc = sc.pop()
 
 		case forRangeStmt:
 			n.start = n.child[0].start
 			setFNext(n.child[0], n)
+			setLoopVars(n)
 			sc = sc.pop()
 
 		case funcDecl:
@@ -1045,7 +1269,7 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 			n.types = sc.types
 			sc = sc.pop()
 			funcName := n.child[1].ident
-			if sym := sc.sym[funcName]; !isMethod(n) && sym != nil {
+			if sym := sc.sym[funcName]; !isMethod(n) && funcName != "_" && sym != nil {
 				sym.index = -1 // to force value to n.val
 				sym.typ = n.typ
 				sym.kind = funcSym
@@ -1061,7 +1285,16 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 			wireChild(n)
 
 		case identExpr:
-			if isKey(n) || isNewDefine(n, sc) {
+			if isKey(n) {
+				break
+			}
+			if n.ident == "_" {
+				if !isBlankDest(n) {
+					err = n.cfgErrorf("cannot use _ as value")
+				}
+				break
+			}
+			if isNewDefine(n, sc) {
 				break
 			}
 			if n.anc.kind == funcDecl && n.anc.child[1] == n {
@@ -1089,10 +1322,10 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 				case sym.kind == constSym && sym.rval.IsValid():
 					n.rval = sym.rval
 					n.kind = basicLit
-				case n.ident == "iota":
+				case sym == interp.universe.sym["iota"]:
 					n.rval = reflect.ValueOf(constant.MakeInt64(int64(sc.iota)))
 					n.kind = basicLit
-				case n.ident == nilIdent:
+				case sym == interp.universe.sym[nilIdent]:
 					n.kind = basicLit
 				case sym.kind == binSym:
 					n.typ = sym.typ
@@ -1100,12 +1333,11 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 				case sym.kind == bltnSym:
 					if n.anc.kind != callExpr {
 						err = n.cfgErrorf("use of builtin %s not in function call", n.ident)
+					} else if v := bltnVersion[n.ident]; interp.goVersion < v {
+						err = n.cfgErrorf("%s requires go1.%d or later", n.ident, v)
 					}
 				}
 			}
-			if n.sym != nil {
-				n.recv = n.sym.recv
-			}
 
 		case ifStmt0: // if cond {}
 			cond, tbody := n.child[0], n.child[1]
@@ -1199,6 +1431,10 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 			n.child[1].tnext = n
 			n.typ = n.child[0].typ
 			n.findex = sc.add(n.typ)
+			if c0 := n.child[0]; c0.rval.IsValid() {
+				// Constant left operand, branch on its value directly.
+				n.start, c0.gen = c0, branch
+			}
 			if n.start.action == aNop {
 				n.start.gen = branch
 			}
@@ -1210,6 +1446,10 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 			n.child[1].tnext = n
 			n.typ = n.child[0].typ
 			n.findex = sc.add(n.typ)
+			if c0 := n.child[0]; c0.rval.IsValid() {
+				// Constant left operand, branch on its value directly.
+				n.start, c0.gen = c0, branch
+			}
 			if n.start.action == aNop {
 				n.start.gen = branch
 			}
@@ -1283,6 +1523,15 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 				err = n.cfgErrorf("undefined type")
 				break
 			}
+			if n.child[1].ident == "_" {
+				err = n.child[1].cfgErrorf("cannot refer to blank field or method")
+				break
+			}
+			if isPtrPtr(n.typ) {
+				// A selector dereferences a single pointer level.
+				err = n.cfgErrorf("undefined selector: %s", n.child[1].ident)
+				break
+			}
 			if n.typ.cat == valueT || n.typ.cat == errorT {
 				// Handle object defined in runtime, try to find field or method
 				// Search for method first, as it applies both to types T and *T
@@ -1293,7 +1542,7 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 					n.gen = getIndexBinMethod
 					n.action = aGetMethod
 					n.recv = &receiver{node: n.child[0]}
-					n.typ = &itype{cat: valueT, rtype: method.Type, isBinMethod: true}
+					n.typ = &itype{cat: valueT, rtype: methodValueType(n, method.Type)}
 				case n.typ.rtype.Kind() == reflect.Ptr:
 					if field, ok := n.typ.rtype.Elem().FieldByName(n.child[1].ident); ok {
 						n.typ = &itype{cat: valueT, rtype: field.Type}
@@ -1311,9 +1560,13 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 						// method lookup failed on type, now lookup on pointer to type
 						pt := reflect.PtrTo(n.typ.rtype)
 						if m2, ok2 := pt.MethodByName(n.child[1].ident); ok2 {
+							if !isAddressable(n.child[0]) {
+								err = n.cfgErrorf("cannot call pointer method %s on %s", n.child[1].ident, n.child[0].typ.id())
+								break
+							}
 							n.val = m2.Index
 							n.gen = getIndexBinPtrMethod
-							n.typ = &itype{cat: valueT, rtype: m2.Type}
+							n.typ = &itype{cat: valueT, rtype: methodValueType(n, m2.Type)}
 							n.recv = &receiver{node: n.child[0]}
 							n.action = aGetMethod
 						} else {
@@ -1324,18 +1577,20 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 					err = n.cfgErrorf("undefined field or method: %s", n.child[1].ident)
 				}
 			} else if n.typ.cat == ptrT && (n.typ.val.cat == valueT || n.typ.val.cat == errorT) {
-				// Handle pointer on object defined in runtime
-				if method, ok := n.typ.val.rtype.MethodByName(n.child[1].ident); ok {
+				// Handle pointer on object defined in runtime. The method index must be
+				// the one of the pointer method set, which differs from the value one
+				// if the type has methods with pointer receivers.
+				if method, ok := reflect.PtrTo(n.typ.val.rtype).MethodByName(n.child[1].ident); ok {
 					n.val = method.Index
-					n.typ = &itype{cat: valueT, rtype: method.Type}
-					n.recv = &receiver{node: n.child[0]}
 					n.gen = getIndexBinMethod
+					n.typ = &itype{cat: valueT, rtype: methodValueType(n, method.Type)}
+					n.recv = &receiver{node: n.child[0]}
 					n.action = aGetMethod
-				} else if method, ok := reflect.PtrTo(n.typ.val.rtype).MethodByName(n.child[1].ident); ok {
+				} else if method, ok := n.typ.val.rtype.MethodByName(n.child[1].ident); ok {
 					n.val = method.Index
-					n.gen = getIndexBinMethod
-					n.typ = &itype{cat: valueT, rtype: method.Type}
+					n.typ = &itype{cat: valueT, rtype: methodValueType(n, method.Type)}
 					n.recv = &receiver{node: n.child[0]}
+					n.gen = getIndexBinMethod
 					n.action = aGetMethod
 				} else if field, ok := n.typ.val.rtype.FieldByName(n.child[1].ident); ok {
 					n.typ = &itype{cat: valueT, rtype: field.Type}
@@ -1349,10 +1604,17 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 				name := n.child[1].ident
 				pkg := n.child[0].sym.typ.path
 				if s, ok := interp.binPkg[pkg][name]; ok {
-					if isBinType(s) {
+					switch {
+					case isBinType(s):
 						n.typ = &itype{cat: valueT, rtype: s.Type().Elem()}
-					} else {
-						n.typ = &itype{cat: valueT, rtype: s.Type(), untyped: isValueUntyped(s)}
+					case s.CanSet():
+						// A variable is bound to its addressable value, read
+						// at each use, not folded as a constant.
+						n.typ = &itype{cat: valueT, rtype: s.Type()}
+						n.val = s
+						n.findex = -1
+					default:
+						n.typ = binValueType(s)
 						n.rval = s
 					}
 					n.action = aGetSym
@@ -1363,7 +1625,11 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 			} else if n.typ.cat == srcPkgT {
 				pkg, name := n.child[0].sym.typ.path, n.child[1].ident
 				// Resolve source package symbol
-				if sym, ok := interp.srcPkg[pkg][name]; ok {
+				// A package evaluated in the REPL is visible under its name, without
+				// scope of its own: its unexported symbols remain accessible.
+				if !canExport(name) && pkg != sc.pkgID && interp.scopes[pkg] != nil {
+					err = n.cfgErrorf("cannot refer to unexported name %s.%s", n.child[0].ident, name)
+				} else if sym, ok := interp.srcPkg[pkg][name]; ok {
 					n.findex = sym.index
 					n.val = sym.node
 					n.gen = nop
@@ -1374,6 +1640,11 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 				} else {
 					err = n.cfgErrorf("undefined selector: %s.%s", pkg, name)
 				}
+			} else if s := n.typ.lookupSelector(n.child[1].ident); len(s) > 1 {
+				err = n.cfgErrorf("ambiguous selector %s: %s", n.child[1].ident, selectionPaths(s))
+			} else if len(s) == 1 && s[0].owner.hides(n.child[1].ident, sc.pkgID) ||
+				isInterfaceSrc(n.typ) && n.typ.hides(n.child[1].ident, sc.pkgID) {
+				err = n.cfgErrorf("cannot refer to unexported field or method %s", n.child[1].ident)
 			} else if m, lind := n.typ.lookupMethod(n.child[1].ident); m != nil {
 				n.action = aGetMethod
 				if n.child[0].isType(sc) {
@@ -1386,6 +1657,10 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 					n.typ.arg = append([]*itype{n.child[0].typ}, m.typ.arg...)
 				} else {
 					// Handle method with receiver
+					if isPtrRecv(m) && n.typ.cat != ptrT && n.typ.fieldSeq(lind).cat != ptrT && !isAddressable(n.child[0]) {
+						err = n.cfgErrorf("cannot call pointer method %s on %s", n.child[1].ident, n.child[0].typ.id())
+						break
+					}
 					n.gen = getMethod
 					n.val = m
 					n.typ = m.typ
@@ -1394,13 +1669,17 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 			} else if m, lind, isPtr, ok := n.typ.lookupBinMethod(n.child[1].ident); ok {
 				n.action = aGetMethod
 				if isPtr && n.typ.fieldSeq(lind).cat != ptrT {
+					if n.typ.cat != ptrT && !isAddressable(n.child[0]) {
+						err = n.cfgErrorf("cannot call pointer method %s on %s", n.child[1].ident, n.child[0].typ.id())
+						break
+					}
 					n.gen = getIndexSeqPtrMethod
 				} else {
 					n.gen = getIndexSeqMethod
 				}
 				n.recv = &receiver{node: n.child[0], index: lind}
 				n.val = append([]int{m.Index}, lind...)
-				n.typ = &itype{cat: valueT, rtype: m.Type}
+				n.typ = &itype{cat: valueT, rtype: methodValueType(n, m.Type)}
 			} else if ti := n.typ.lookupField(n.child[1].ident); len(ti) > 0 {
 				// Handle struct field
 				n.val = ti
@@ -1409,6 +1688,13 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 					n.typ = n.typ.fieldSeq(ti)
 					n.gen = getMethodByName
 					n.action = aMethod
+				case embeddedInterface(n.typ, ti) > 0:
+					// Method of an interface embedded in a struct: the field
+					// holding the interface value is at n.val.
+					n.val = ti[:embeddedInterface(n.typ, ti)]
+					n.typ = n.typ.fieldSeq(ti)
+					n.gen = getMethodByName
+					n.action = aMethod
 				case n.typ.cat == ptrT:
 					n.typ = n.typ.fieldSeq(ti)
 					n.gen = getPtrIndexSeq
@@ -1441,6 +1727,7 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 
 		case selectStmt:
 			wireChild(n)
+			sc = sc.pop()
 			// Move action to block statement, so select node can be an exit point.
 			n.child[0].gen = _select
 			// Chain channel init actions in commClauses prior to invoke select.
@@ -1513,21 +1800,29 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 
 		case typeSwitch:
 			// Check that cases expressions are all different
-			usedCase := map[string]bool{}
+			usedCase := map[string]*node{}
 			for _, c := range n.lastChild().child {
+				if len(c.child) == 0 {
+					continue // empty default clause
+				}
 				for _, t := range c.child[:len(c.child)-1] {
 					tid := t.typ.id()
-					if usedCase[tid] {
-						err = c.cfgErrorf("duplicate case %s in type switch", t.ident)
+					if prev, ok := usedCase[tid]; ok {
+						err = t.cfgErrorf("duplicate case %s in type switch\n\tprevious case at %v", t.ident, interp.fset.Position(prev.pos))
 						return
 					}
-					usedCase[tid] = true
+					usedCase[tid] = t
 				}
 			}
 			fallthrough
 
 		case switchStmt:
 			sc = sc.pop()
+			if n.kind == switchStmt {
+				if err = check.switchCases(n); err != nil {
+					return
+				}
+			}
 			sbn := n.lastChild() // switch block node
 			clauses := sbn.child
 			l := len(clauses)
@@ -1535,40 +1830,42 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 				// Switch is empty
 				break
 			}
-			// Chain case clauses.
-			for i, c := range clauses[:l-1] {
-				// Chain to next clause.
-				setFNext(c, clauses[i+1])
-				if len(c.child) == 0 {
-					c.tnext = n // Clause body is empty, exit.
+			// Chain case clauses in evaluation order.
+			order := caseOrder(n)
+			for i, c := range order {
+				if i < l-1 {
+					setFNext(c, order[i+1].start)
 				} else {
-					body := c.lastChild()
-					c.tnext = body.start
-					if len(body.child) > 0 && body.lastChild().kind == fallthroughtStmt {
-						if n.kind == typeSwitch {
-							err = body.lastChild().cfgErrorf("cannot fallthrough in type switch")
-						}
-						if len(clauses[i+1].child) == 0 {
-							body.tnext = n // Fallthrough to next with empty body, just exit.
-						} else {
-							body.tnext = clauses[i+1].lastChild().start
-						}
-					} else {
-						body.tnext = n // Exit switch at end of clause body.
-					}
+					c.fnext = n
 				}
 			}
-			c := clauses[l-1] // Last clause.
-			c.fnext = n
-			if len(c.child) == 0 {
-				c.tnext = n // Clause body is empty, exit.
-			} else {
+			for i, c := range clauses {
+				if len(c.child) == 0 {
+					c.tnext = n // Clause body is empty, exit.
+					continue
+				}
 				body := c.lastChild()
 				c.tnext = body.start
-				body.tnext = n
+				if err = checkFallthrough(n, i); err != nil {
+					return
+				}
+				body.tnext = caseExit(n, i)
 			}
+			sbn.start = order[0].start
 			n.start = n.child[0].start
-			n.child[0].tnext = sbn.start
+			switch {
+			case n.kind == typeSwitch:
+				// Evaluate the type switch guard operand after the init statement.
+				src := n.child[1].lastChild().child[0]
+				n.child[0].tnext = src.start
+				src.tnext = sbn.start
+			case len(n.child) > 2:
+				// Evaluate the switch tag after the init statement.
+				n.child[0].tnext = n.child[1].start
+				n.child[1].tnext = sbn.start
+			default:
+				n.child[0].tnext = sbn.start
+			}
 
 		case switchIfStmt: // like an if-else chain
 			sc = sc.pop()
@@ -1579,36 +1876,40 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 				// Switch is empty
 				break
 			}
-			// Wire case clauses in reverse order so the next start node is already resolved when used.
+			// Wire case clauses in reverse evaluation order so the next start node is already resolved when used.
+			order := caseOrder(n)
 			for i := l - 1; i >= 0; i-- {
-				c := clauses[i]
+				c := order[i]
 				c.gen = nop
 				if len(c.child) == 0 {
 					c.tnext = n
 					c.fnext = n
-				} else {
-					body := c.lastChild()
-					if len(c.child) > 1 {
-						cond := c.child[0]
-						cond.tnext = body.start
-						if i == l-1 {
-							setFNext(cond, n)
-						} else {
-							setFNext(cond, clauses[i+1].start)
-						}
-						c.start = cond.start
-					} else {
-						c.start = body.start
-					}
-					// If last case body statement is a fallthrough, then jump to next case body
-					if i < l-1 && len(body.child) > 0 && body.lastChild().kind == fallthroughtStmt {
-						body.tnext = clauses[i+1].lastChild().start
+					continue
+				}
+				body := c.lastChild()
+				if len(c.child) > 1 {
+					cond := c.child[0]
+					cond.tnext = body.start
+					if i == l-1 {
+						setFNext(cond, n)
 					} else {
-						body.tnext = n
+						setFNext(cond, order[i+1].start)
 					}
+					c.start = cond.start
+				} else {
+					c.start = body.start
+				}
+			}
+			for i, c := range clauses {
+				if len(c.child) == 0 {
+					continue
+				}
+				if err = checkFallthrough(n, i); err != nil {
+					return
 				}
+				c.lastChild().tnext = caseExit(n, i)
 			}
-			sbn.start = clauses[0].start
+			sbn.start = order[0].start
 			n.start = n.child[0].start
 			n.child[0].tnext = sbn.start
 
@@ -1668,17 +1969,22 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 			if n.child[0].rval.IsValid() && !isInterface(n.typ) && constOp[n.action] != nil {
 				n.typ.TypeOf() // init reflect type
 				constOp[n.action](n)
+				if err = check.constResult(n); err != nil {
+					break
+				}
 			}
 			switch {
 			case n.rval.IsValid():
 				n.gen = nop
 				n.findex = -1
-			case n.anc.kind == assignStmt && n.anc.action == aAssign:
+			case n.anc.kind == assignStmt && n.anc.action == aAssign && n.anc.nleft == 1 && !isBinVar(n.anc.child[childPos(n)-n.anc.nright]):
 				dest := n.anc.child[childPos(n)-n.anc.nright]
 				n.typ = dest.typ
 				n.findex = dest.findex
 				n.level = dest.level
-			case n.anc.kind == returnStmt:
+			case n.action != aRecv && isDirectReturn(n, sc.def):
+				// A received value replaces its frame location, which is not
+				// the one of the caller result.
 				pos := childPos(n)
 				n.typ = sc.def.typ.ret[pos]
 				n.findex = pos
@@ -1700,21 +2006,12 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 					// Global object allocation is already performed in GTA.
 					index = sc.sym[c.ident].index
 				} else {
-					if sym, exists := sc.sym[c.ident]; exists {
-						if sym.typ.node != nil &&
-							sym.typ.node.anc != nil {
-							// for non-predeclared identifiers (struct, map, etc)
-							prevDecl := n.interp.fset.Position(sym.typ.node.anc.pos)
-							err = n.cfgErrorf("%s redeclared in this block\n\tprevious declaration at %v", c.ident, prevDecl)
-							return
-						}
-						// for predeclared identifiers (int, string, etc)
-						// TODO(mpl): find the exact location of the previous declaration in all cases.
-						err = n.cfgErrorf("%s redeclared in this block", c.ident)
+					if sym, exists := sc.lookupBlock(c.ident); exists && c.ident != "_" {
+						err = redeclaredError(c, sym)
 						return
 					}
 					index = sc.add(n.typ)
-					sc.sym[c.ident] = &symbol{index: index, kind: varSym, typ: n.typ}
+					sc.sym[c.ident] = &symbol{index: index, kind: varSym, typ: n.typ, node: n}
 				}
 				c.typ = n.typ
 				c.findex = index
@@ -1725,7 +2022,16 @@ func (interp *Interpreter) cfg(root *node, pkgID string) ([]*node, error) {
 	if sc != interp.universe {
 		sc.pop()
 	}
-	return initNodes, err
+	if err != nil {
+		errs = append(errs, err)
+	}
+	switch {
+	case len(errs) == 0:
+		return initNodes, nil
+	case root.kind != fileStmt:
+		return initNodes, errs[0]
+	}
+	return initNodes, errs
 }
 
 func compDefineX(sc *scope, n *node) error {
@@ -1754,41 +2060,83 @@ func compDefineX(sc *scope, n *node) error {
 		}
 		n.gen = nop
 
-	case indexExpr:
-		types = append(types, src.typ, sc.getType("bool"))
-		n.child[l].gen = getIndexMap2
-		n.gen = nop
-
-	case typeAssertExpr:
-		if n.child[0].ident == "_" {
-			n.child[l].gen = typeAssertStatus
-		} else {
-			n.child[l].gen = typeAssert2
-		}
-		types = append(types, n.child[l].child[1].typ, sc.getType("bool"))
-		n.gen = nop
-
-	case unaryExpr:
-		if n.child[l].action == aRecv {
-			types = append(types, src.typ, sc.getType("bool"))
-			n.child[l].gen = recv2
-			n.gen = nop
-		}
-
 	default:
-		return n.cfgErrorf("unsupported assign expression")
+		typ, err := commaOk(sc, n)
+		if err != nil {
+			return err
+		}
+		types = append(types, typ, sc.getType("bool"))
 	}
 
 	for i, t := range types {
+		c := n.child[i]
+		c.typ = t
+		if c.ident == "_" {
+			continue // no storage for a discarded value
+		}
+		if sym, ok := sc.lookupBlock(c.ident); ok && !sc.global {
+			if !isShortDefine(n) || sym.kind != varSym {
+				return redeclaredError(c, sym)
+			}
+			if sym.typ.id() == t.id() {
+				// The existing variable of a short variable declaration is assigned.
+				c.findex = sym.index
+				continue
+			}
+		}
 		index := sc.add(t)
-		sc.sym[n.child[i].ident] = &symbol{index: index, kind: varSym, typ: t}
-		n.child[i].typ = t
-		n.child[i].findex = index
+		sc.sym[c.ident] = &symbol{index: index, kind: varSym, typ: t, node: n}
+		c.findex = index
 	}
 
 	return nil
 }
 
+// commaOk sets the generator of the comma-ok expression assigned by n, a map
+// index, a type assertion or a channel receive, and returns the type of its
+// value. An error is returned for any other expression.
+func commaOk(sc *scope, n *node) (*itype, error) {
+	src := n.lastChild()
+	if len(n.child) != 3 || len(src.child) == 0 {
+		return nil, n.cfgErrorf("assignment mismatch: %d variables but 1 value", len(n.child)-1)
+	}
+	// In global scope, the types are not yet set by CFG.
+	typeOf := func(c *node) (*itype, error) {
+		if c.typ != nil {
+			return c.typ, nil
+		}
+		return nodeType(n.interp, sc, c)
+	}
+	t, err := typeOf(src.child[0])
+	if err != nil {
+		return nil, err
+	}
+	var typ *itype
+	switch {
+	case src.kind == indexExpr && isMap(t):
+		typ = mapElement(t)
+		src.gen = getIndexMap2
+	case src.kind == typeAssertExpr && len(src.child) > 1:
+		if typ, err = typeOf(src.child[1]); err != nil {
+			return nil, err
+		}
+		src.gen = typeAssert2
+	case src.kind == unaryExpr && src.action == aRecv && isChan(t):
+		typ = chanElement(t)
+		src.gen = recv2
+	default:
+		return nil, n.cfgErrorf("assignment mismatch: %d variables but 1 value", len(n.child)-1)
+	}
+	src.typ = typ
+	for _, c := range n.child[:2] {
+		if isMapEntry(c) {
+			c.gen = nop // assigned by the comma-ok expression
+		}
+	}
+	n.gen = nop
+	return typ, nil
+}
+
 // TODO used for allocation optimization, temporarily disabled
 // func isAncBranch(n *node) bool {
 //	switch n.anc.kind {
@@ -1809,9 +2157,81 @@ func childPos(n *node) int {
 
 func (n *node) cfgErrorf(format string, a ...interface{}) *cfgError {
 	a = append([]interface{}{n.interp.fset.Position(n.pos)}, a...)
+	if n.interp.errorSource {
+		if s := n.interp.sourceLine(n.pos); s != "" {
+			format += "%s"
+			a = append(a, s)
+		}
+	}
 	return &cfgError{n, fmt.Errorf("%s: "+format, a...)}
 }
 
+// maxSourceLine is the maximum number of characters displayed for a source
+// line in error messages.
+const maxSourceLine = 100
+
+// sourceLine returns the source line at pos, followed by a caret under
+// the position column, to be appended to an error message. It returns an
+// empty string if the source is not available.
+func (interp *Interpreter) sourceLine(pos token.Pos) string {
+	f := interp.fset.File(pos)
+	if f == nil {
+		return ""
+	}
+	interp.mutex.RLock()
+	src, ok := interp.sources[f]
+	interp.mutex.RUnlock()
+	if !ok {
+		return ""
+	}
+	p := f.Position(pos)
+	start := f.Offset(f.LineStart(p.Line))
+	col := p.Column - 1
+	line := src.text[start:]
+	if i := strings.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+	}
+	if p.Line == 1 {
+		// Skip the code inserted in REPL mode.
+		line = line[src.offset:]
+		col -= src.offset
+	}
+	if col < 0 || col > len(line) {
+		return ""
+	}
+
+	// Compute the caret position in runes, and truncate long lines around it.
+	r := []rune(strings.TrimRight(line, "\r"))
+	c := utf8.RuneCountInString(line[:col])
+	var prefix, suffix string
+	if len(r) > maxSourceLine {
+		begin := c - maxSourceLine/2
+		if begin < 0 {
+			begin = 0
+		}
+		end := begin + maxSourceLine
+		if end > len(r) {
+			end, begin = len(r), len(r)-maxSourceLine
+		}
+		if begin > 0 {
+			prefix = "..."
+		}
+		if end < len(r) {
+			suffix = "..."
+		}
+		r, c = r[begin:end], c-begin
+	}
+	caret := []rune(strings.Repeat(" ", len(prefix)))
+	for _, x := range r[:c] {
+		if x == '\t' {
+			caret = append(caret, x)
+		} else {
+			caret = append(caret, ' ')
+		}
+	}
+	return "\n\t" + prefix + string(r) + suffix + "\n\t" + string(caret) + "^"
+}
+
 func genRun(nod *node) error {
 	var err error
 
@@ -1866,9 +2286,16 @@ func getVars(n *node) (vars []*node) {
 func genGlobalVarDecl(nodes []*node, sc *scope) (*node, error) {
 	varNode := &node{kind: varDecl, action: aNop, gen: nop}
 
+	refs := map[*node][]globalRef{}
 	deps := map[*node][]*node{}
 	for _, n := range nodes {
-		deps[n] = getVarDependencies(n, sc)
+		deps[n] = getVarDependencies(n, sc, refs)
+	}
+
+	// The variables declared by previous evaluations are already initialized.
+	pending := map[*node]bool{}
+	for _, n := range nodes {
+		pending[n] = true
 	}
 
 	inited := map[*node]bool{}
@@ -1877,7 +2304,7 @@ func genGlobalVarDecl(nodes []*node, sc *scope) (*node, error) {
 		for _, n := range nodes {
 			canInit := true
 			for _, d := range deps[n] {
-				if !inited[d] {
+				if pending[d] && !inited[d] {
 					canInit = false
 				}
 			}
@@ -1899,27 +2326,114 @@ func genGlobalVarDecl(nodes []*node, sc *scope) (*node, error) {
 	}
 
 	if len(revisit) > 0 {
-		return nil, revisit[0].cfgErrorf("variable definition loop")
+		return nil, initCycleError(revisit[0], refs)
 	}
 	wireChild(varNode)
 	return varNode, nil
 }
 
-func getVarDependencies(nod *node, sc *scope) (deps []*node) {
-	nod.Walk(func(n *node) bool {
-		if n.kind == identExpr {
-			if sym, _, ok := sc.lookup(n.ident); ok {
-				if sym.kind != varSym || !sym.global || sym.node == nod {
-					return false
-				}
-				deps = append(deps, sym.node)
+// globalRef is a reference to a global variable or function, made by the
+// initialization expression of a global variable or by the body of a function.
+type globalRef struct {
+	name string // referred identifier
+	decl *node  // variable specification or function declaration
+}
+
+// getVarDependencies returns the global variables to initialize before the
+// variables of the specification nod: the ones referred to by its expression,
+// directly or through the bodies of the functions and methods it refers to.
+// The references of the visited declarations are recorded in refs.
+func getVarDependencies(nod *node, sc *scope, refs map[*node][]globalRef) (deps []*node) {
+	seen := map[*node]bool{}
+	var visit func(d *node)
+	visit = func(d *node) {
+		if _, ok := refs[d]; !ok {
+			refs[d] = getGlobalRefs(d, sc)
+		}
+		for _, r := range refs[d] {
+			if seen[r.decl] {
+				continue
 			}
+			seen[r.decl] = true
+			if r.decl.kind == funcDecl {
+				visit(r.decl)
+				continue
+			}
+			deps = append(deps, r.decl)
 		}
-		return true
-	}, nil)
+	}
+	visit(nod)
 	return deps
 }
 
+// getGlobalRefs returns the references to global variables and functions of
+// the declaration d, a variable specification or a function declaration.
+func getGlobalRefs(d *node, sc *scope) (refs []globalRef) {
+	root := d
+	if d.kind == funcDecl {
+		root = d.lastChild()
+	}
+	root.Walk(func(n *node) bool {
+		if f, ok := n.val.(*node); ok && f.kind == funcDecl && (n.kind == identExpr || n.kind == selectorExpr) {
+			// A function, or a method from its selector.
+			refs = append(refs, globalRef{f.child[1].ident, f})
+			return n.kind == selectorExpr
+		}
+		if n.kind != identExpr {
+			return true
+		}
+		sym := n.sym
+		if d.kind != funcDecl {
+			sym, _, _ = sc.lookup(n.ident)
+		}
+		if sym != nil && sym.kind == varSym && sym.global && sym.node != d && sym.node != nil {
+			refs = append(refs, globalRef{n.ident, sym.node})
+		}
+		return false
+	}, nil)
+	return refs
+}
+
+// initCycleError returns the error of the global variables of specification
+// nod, whose initialization depends on itself, with the references forming
+// the cycle.
+func initCycleError(nod *node, refs map[*node][]globalRef) error {
+	// Search the references from nod back to nod, depth first.
+	var path []globalRef
+	seen := map[*node]bool{}
+	var search func(d *node) bool
+	search = func(d *node) bool {
+		for _, r := range refs[d] {
+			path = append(path, r)
+			if r.decl == nod {
+				return true
+			}
+			if !seen[r.decl] {
+				seen[r.decl] = true
+				if search(r.decl) {
+					return true
+				}
+			}
+			path = path[:len(path)-1]
+		}
+		return false
+	}
+	if !search(nod) {
+		return nod.cfgErrorf("variable definition loop")
+	}
+
+	id := declIdent(nod, path[len(path)-1].name)
+	lines := make([]string, len(path))
+	for i, r := range path {
+		from := id
+		if i > 0 {
+			from = declIdent(path[i-1].decl, path[i-1].name)
+		}
+		lines[i] = fmt.Sprintf("%v: %s refers to %s", nod.interp.fset.Position(from.pos), from.ident, r.name)
+	}
+	return id.cfgErrorf("initialization cycle for %s\n\t%s", id.ident, strings.Join(lines, "\n\t"))
+}
+
 // setFnext sets the cond fnext field to next, propagates it for parenthesis blocks
 // and sets the action to branch.
 func setFNext(cond, next *node) {
@@ -1935,6 +2449,53 @@ func setFNext(cond, next *node) {
 	cond.fnext = next
 }
 
+// caseOrder returns the case clauses of the switch statement n in evaluation
+// order: the cases in source order, then the default clause, if any.
+func caseOrder(n *node) []*node {
+	clauses := n.lastChild().child
+	d := getDefault(n)
+	if d < 0 {
+		return clauses
+	}
+	order := make([]*node, 0, len(clauses))
+	order = append(order, clauses[:d]...)
+	order = append(order, clauses[d+1:]...)
+	return append(order, clauses[d])
+}
+
+// checkFallthrough returns an error if the body of the case clause i of the
+// switch statement n ends with an invalid fallthrough statement.
+func checkFallthrough(n *node, i int) error {
+	clauses := n.lastChild().child
+	body := clauses[i].lastChild()
+	if len(body.child) == 0 || body.lastChild().kind != fallthroughtStmt {
+		return nil
+	}
+	switch {
+	case n.kind == typeSwitch:
+		return body.lastChild().cfgErrorf("cannot fallthrough in type switch")
+	case i == len(clauses)-1:
+		return body.lastChild().cfgErrorf("cannot fallthrough final case in switch")
+	}
+	return nil
+}
+
+// caseExit returns the node following the body of the case clause i of the
+// switch statement n: the body of the next clause in source order, without
+// evaluating its expressions, if the body ends with fallthrough, or the
+// switch exit otherwise.
+func caseExit(n *node, i int) *node {
+	clauses := n.lastChild().child
+	body := clauses[i].lastChild()
+	if len(body.child) == 0 || body.lastChild().kind != fallthroughtStmt {
+		return n
+	}
+	if next := clauses[i+1]; len(next.child) > 0 {
+		return next.lastChild().start
+	}
+	return n // Fallthrough to next with empty body, just exit.
+}
+
 // GetDefault return the index of default case clause in a switch statement, or -1.
 func getDefault(n *node) int {
 	for i, c := range n.lastChild().child {
@@ -1950,7 +2511,11 @@ func getDefault(n *node) int {
 	return -1
 }
 
-func isBinType(v reflect.Value) bool { return v.IsValid() && v.Kind() == reflect.Ptr && v.IsNil() }
+// isBinType returns true if v represents a binary type, as a nil pointer. A
+// variable of pointer type is addressable, even if nil.
+func isBinType(v reflect.Value) bool {
+	return v.IsValid() && v.Kind() == reflect.Ptr && v.IsNil() && !v.CanSet()
+}
 
 // isType returns true if node refers to a type definition, false otherwise.
 func (n *node) isType(sc *scope) bool {
@@ -1963,7 +2528,12 @@ func (n *node) isType(sc *scope) bool {
 		}
 	case selectorExpr:
 		pkg, name := n.child[0].ident, n.child[1].ident
-		if sym, _, ok := sc.lookup(pkg); ok && sym.kind == pkgSym {
+		sym, _, ok := sc.lookup(pkg)
+		if !ok {
+			// retry with the filename, in case pkg is an import name.
+			sym, _, ok = sc.lookup(filepath.Join(pkg, filepath.Base(n.interp.fset.Position(n.pos).Filename)))
+		}
+		if ok && sym.kind == pkgSym {
 			path := sym.typ.path
 			if p, ok := n.interp.binPkg[path]; ok && isBinType(p[name]) {
 				return true // Imported binary type
@@ -1984,6 +2554,9 @@ func wireChild(n *node, exclude ...nkind) {
 
 	// Set start node, in subtree (propagated to ancestors by post-order processing)
 	for _, c := range child {
+		if c.kind == parenExpr && c.start == c {
+			continue // A parenthesized value, with nothing to execute.
+		}
 		switch c.kind {
 		case arrayType, chanType, chanTypeRecv, chanTypeSend, funcDecl, importDecl, mapType, basicLit, identExpr, typeDecl:
 			continue
@@ -2010,6 +2583,9 @@ func wireChild(n *node, exclude ...nkind) {
 
 	// Chain subtree next to self
 	for i := len(child) - 1; i >= 0; i-- {
+		if child[i].kind == parenExpr && child[i].start == child[i] {
+			continue
+		}
 		switch child[i].kind {
 		case arrayType, chanType, chanTypeRecv, chanTypeSend, importDecl, mapType, funcDecl, basicLit, identExpr, typeDecl:
 			continue
@@ -2098,6 +2674,15 @@ func (n *node) isNatural() bool {
 // isNil returns true if node is a literal nil value, false otherwise.
 func (n *node) isNil() bool { return n.kind == basicLit && !n.rval.IsValid() }
 
+// constValue returns the constant value of n, if any.
+func (n *node) constValue() (constant.Value, bool) {
+	if !n.rval.IsValid() || !n.rval.CanInterface() {
+		return nil, false
+	}
+	c, ok := n.rval.Interface().(constant.Value)
+	return c, ok
+}
+
 // fieldType returns the nth parameter field node (type) of a fieldList node.
 func (n *node) fieldType(m int) *node {
 	k := 0
@@ -2150,6 +2735,28 @@ func isRecursiveField(n *node) bool {
 	return false
 }
 
+// isScopeStmt returns true if n is a statement with its own scope, which
+// includes the variables declared in its init statement.
+func isScopeStmt(n *node) bool {
+	switch n.kind {
+	case forStmt0, forStmt1, forStmt2, forStmt3, forStmt3a, forStmt4, forRangeStmt,
+		ifStmt0, ifStmt1, ifStmt2, ifStmt3, switchStmt, switchIfStmt, typeSwitch, selectStmt:
+		return true
+	}
+	return false
+}
+
+// inScopeStmt returns true if n is below root in a statement block or in a
+// statement with its own scope, where GTA does not declare variables.
+func inScopeStmt(n, root *node) bool {
+	for a := n.anc; a != nil && a != root; a = a.anc {
+		if a.kind == blockStmt || isScopeStmt(a) {
+			return true
+		}
+	}
+	return false
+}
+
 // isNewDefine returns true if node refers to a new definition.
 func isNewDefine(n *node, sc *scope) bool {
 	if n.ident == "_" {
@@ -2170,20 +2777,226 @@ func isNewDefine(n *node, sc *scope) bool {
 	return false
 }
 
+// isBlankDest returns true if the blank identifier n is in a position
+// where it can be assigned or declared, as opposed to being read.
+func isBlankDest(n *node) bool {
+	a := n.anc
+	switch a.kind {
+	case assignStmt:
+		return a.action == aAssign && childPos(n) < a.nleft
+	case assignXStmt, defineStmt, defineXStmt, valueSpec:
+		return childPos(n) < a.nleft
+	case rangeStmt:
+		return a.child[0] == n || a.child[1] == n && len(a.child) == 4
+	case funcDecl:
+		return a.child[1] == n
+	}
+	return false
+}
+
+// isShortDefine returns true if n is a short variable declaration.
+func isShortDefine(n *node) bool {
+	return (n.kind == defineStmt || n.kind == defineXStmt) && n.anc.kind != constDecl && n.anc.kind != varDecl
+}
+
+// hasNewVar returns true if the short variable declaration n declares at
+// least one new non-blank variable in the current block of scope sc.
+func hasNewVar(sc *scope, n *node) bool {
+	for _, c := range n.child[:n.nleft] {
+		if _, ok := sc.lookupBlock(c.ident); !ok && c.ident != "_" {
+			return true
+		}
+	}
+	return false
+}
+
+// redeclaredError returns the error of the redeclaration of the identifier
+// id, previously declared by symbol sym.
+func redeclaredError(id *node, sym *symbol) error {
+	if sym.node == nil {
+		return id.cfgErrorf("%s redeclared in this block", id.ident)
+	}
+	prev := id.interp.fset.Position(declIdent(sym.node, id.ident).pos)
+	return id.cfgErrorf("%s redeclared in this block\n\tprevious declaration at %v", id.ident, prev)
+}
+
+// declIdent returns the identifier node of ident in the declaration n.
+func declIdent(n *node, ident string) *node {
+	switch n.kind {
+	case funcDecl:
+		return n.child[1]
+	case typeSpec, typeSpecAssign:
+		return n.child[0]
+	}
+	for _, c := range n.child {
+		if c.kind == identExpr && c.ident == ident {
+			return c
+		}
+	}
+	return n
+}
+
+// checkParamNames checks that the receiver, parameters and results of the
+// function n have distinct names.
+func checkParamNames(n *node) error {
+	var fields []*node
+	if len(n.child[0].child) > 0 {
+		fields = append(fields, n.child[0].child[0])
+	}
+	for _, c := range n.child[2].child {
+		fields = append(fields, c.child...)
+	}
+	names := map[string]*node{}
+	for _, f := range fields {
+		for _, c := range f.child[:len(f.child)-1] {
+			if c.ident == "_" {
+				continue
+			}
+			if prev, ok := names[c.ident]; ok {
+				return c.cfgErrorf("%s redeclared in this block\n\tprevious declaration at %v", c.ident, n.interp.fset.Position(prev.pos))
+			}
+			names[c.ident] = c
+		}
+	}
+	return nil
+}
+
+// isBlankDefine returns true if n is a short variable declaration
+// where all the left hand side identifiers are blank.
+func isBlankDefine(n *node) bool {
+	if !isShortDefine(n) {
+		return false
+	}
+	for _, c := range n.child[:n.nleft] {
+		if c.ident != "_" {
+			return false
+		}
+	}
+	return true
+}
+
 func isMethod(n *node) bool {
 	return len(n.child[0].child) > 0 // receiver defined
 }
 
+// isCallee returns true if n is the function expression of a call.
+func isCallee(n *node) bool {
+	return n.anc.kind == callExpr && n.anc.child[0] == n
+}
+
+// isAddressable returns true if the value of expression n is addressable,
+// i.e. a variable, a pointer indirection, a slice index, or a field selector
+// or an array index of an addressable operand.
+func isAddressable(n *node) bool {
+	switch n.kind {
+	case identExpr:
+		return n.sym == nil || n.sym.kind == varSym
+	case parenExpr:
+		return isAddressable(n.child[0])
+	case starExpr:
+		return true
+	case selectorExpr:
+		c0 := n.child[0]
+		switch c0.typ.cat {
+		case binPkgT:
+			return isBinVar(n)
+		case srcPkgT:
+			return n.sym != nil && n.sym.kind == varSym
+		case ptrT:
+			return true
+		}
+		if t := c0.typ.TypeOf(); t != nil && t.Kind() == reflect.Ptr {
+			return true
+		}
+		return isAddressable(c0)
+	case indexExpr:
+		c0 := n.child[0]
+		switch c0.typ.TypeOf().Kind() {
+		case reflect.Slice, reflect.Ptr:
+			return true
+		case reflect.Array:
+			return isAddressable(c0)
+		}
+	}
+	return false
+}
+
+// embeddedInterface returns the length of the prefix of the field index path
+// from the struct type t leading to an embedded interface, or 0 if none.
+func embeddedInterface(t *itype, index []int) int {
+	for i := 1; i < len(index); i++ {
+		if isInterfaceSrc(t.fieldSeq(index[:i])) {
+			return i
+		}
+	}
+	return 0
+}
+
+// isPtrRecv returns true if the method defined by n has a pointer receiver.
+func isPtrRecv(n *node) bool {
+	t := defRecvType(n)
+	return t != nil && t.cat == ptrT
+}
+
+// methodValueType returns the type of the method value n, obtained from the
+// type t of a binary method, which includes the receiver as first argument,
+// except for interface methods. In a call, the receiver is passed at
+// invocation, and t is kept.
+func methodValueType(n *node, t reflect.Type) reflect.Type {
+	if isCallee(n) || t.NumIn() == 0 || n.child[0].typ.TypeOf().Kind() == reflect.Interface {
+		return t
+	}
+	in := make([]reflect.Type, t.NumIn()-1)
+	for i := range in {
+		in[i] = t.In(i + 1)
+	}
+	out := make([]reflect.Type, t.NumOut())
+	for i := range out {
+		out[i] = t.Out(i)
+	}
+	return reflect.FuncOf(in, out, t.IsVariadic())
+}
+
 func isMapEntry(n *node) bool {
 	return n.action == aGetIndex && isMap(n.child[0].typ)
 }
 
+// isBinVar returns true if n is a variable of a binary package, which is
+// not stored in the interpreter frame.
+func isBinVar(n *node) bool {
+	v, ok := n.val.(reflect.Value)
+	return n.action == aGetSym && ok && v.CanSet()
+}
+
+// isGlobalVar returns true if n is a global variable of the interpreter. It
+// is accessed directly in the global frame, which is not an ancestor of the
+// frames of functions called from other functions.
+func isGlobalVar(n *node) bool {
+	return n.sym != nil && n.sym.global
+}
+
+// isDirectReturn returns true if the value of n is returned by the function
+// def, and can be stored directly at the frame location reserved for the
+// result. It is not possible if the value must be wrapped in a binary
+// interface, the wrapping in an interpreted interface being done in place.
+func isDirectReturn(n, def *node) bool {
+	if n.anc.kind != returnStmt {
+		return false
+	}
+	if isCall(n) && n.child[0].typ.numOut() > 1 {
+		// All returned values of the call are stored in place.
+		return true
+	}
+	t := def.typ.ret[childPos(n)]
+	return !isInterface(t) || isInterfaceSrc(t) || n.typ.id() == t.id()
+}
+
 func isCall(n *node) bool {
 	return n.action == aCall || n.action == aCallSlice
 }
 
 func isBinCall(n *node) bool {
-	return n.kind == callExpr && n.child[0].typ.cat == valueT && n.child[0].typ.rtype.Kind() == reflect.Func
+	return n.kind == callExpr && n.action != aConvert && n.child[0].typ.cat == valueT && n.child[0].typ.rtype.Kind() == reflect.Func
 }
 
 func mustReturnValue(n *node) bool {
@@ -2199,7 +3012,7 @@ func mustReturnValue(n *node) bool {
 }
 
 func isRegularCall(n *node) bool {
-	return n.kind == callExpr && n.child[0].typ.cat == funcT
+	return n.kind == callExpr && n.action != aConvert && n.child[0].typ.cat == funcT
 }
 
 func variadicPos(n *node) int {
@@ -2271,6 +3084,111 @@ func typeSwichAssign(n *node) bool {
 	return ts.kind == typeSwitch && ts.child[1].action == aAssign
 }
 
+// enclosingLabel returns the labeled statement of label enclosing n in the
+// same function, or nil.
+func enclosingLabel(n *node, label string) *node {
+	for a := n.anc; a != nil; a = a.anc {
+		switch a.kind {
+		case funcDecl, funcLit:
+			return nil
+		case labeledStmt:
+			if a.child[0].ident != label {
+				continue
+			}
+			switch c := a.child[1]; c.kind {
+			case selectStmt, switchStmt, switchIfStmt, typeSwitch:
+				return a
+			default:
+				if isLoop(c) {
+					return a
+				}
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+// isLoop returns true if n is a for statement.
+func isLoop(n *node) bool {
+	switch n.kind {
+	case forStmt0, forStmt1, forStmt2, forStmt3, forStmt3a, forStmt4, forRangeStmt:
+		return true
+	}
+	return false
+}
+
+// setLoopVars makes the body of the for statement n allocate anew, at the end
+// of each iteration, the variables declared in the loop which are referenced
+// by a function literal or of which the address is taken, so that each
+// iteration has its own variables. Since go1.22, this applies also to the
+// variables declared by the for clause or the range clause.
+func setLoopVars(n *node) {
+	var vars []*node
+	body := n.lastChild()
+	switch n.kind {
+	case forStmt2, forStmt3a, forStmt4:
+		if c := n.child[0]; n.interp.goVersion >= 22 && (c.kind == defineStmt || c.kind == defineXStmt) {
+			vars = append(vars, c.child[:c.nleft]...)
+		}
+	case forRangeStmt:
+		c := n.child[0]
+		if n.interp.goVersion >= 22 {
+			vars = append(vars, c.child[:len(c.child)-2]...)
+		}
+		body = c.lastChild()
+	}
+
+	captured := map[string]bool{}
+	body.Walk(func(c *node) bool {
+		switch c.kind {
+		case funcLit:
+			c.Walk(func(c *node) bool {
+				if c.kind == identExpr {
+					captured[c.ident] = true
+				}
+				return true
+			}, nil)
+			return false
+		case addressExpr, sliceExpr:
+			c0 := c.child[0]
+			for c0.kind == parenExpr || c0.kind == selectorExpr || c0.kind == indexExpr {
+				c0 = c0.child[0]
+			}
+			if c0.kind == identExpr {
+				captured[c0.ident] = true
+			}
+		case defineStmt, defineXStmt:
+			vars = append(vars, c.child[:c.nleft]...)
+		case valueSpec:
+			vars = append(vars, c.child[:len(c.child)-1]...)
+		case rangeStmt:
+			vars = append(vars, c.child[:len(c.child)-2]...)
+		}
+		return true
+	}, nil)
+
+	var renewed []*node
+	for _, c := range vars {
+		if c.kind == identExpr && c.findex >= 0 && c.level == 0 && captured[c.ident] {
+			renewed = append(renewed, c)
+		}
+	}
+	if len(renewed) > 0 {
+		body.gen = renew
+		body.val = renewed
+	}
+}
+
+// loopRestart returns the node where the for statement n continues, as
+// recorded in scope for the unlabeled continue statements.
+func loopRestart(n *node) *node {
+	if n.kind == forRangeStmt {
+		return n.child[0]
+	}
+	return n.lastChild()
+}
+
 func gotoLabel(s *symbol) {
 	if s.node == nil {
 		return
@@ -2308,6 +3226,11 @@ func compositeGenerator(n *node) (gen bltnGenerator) {
 		}
 	case valueT:
 		switch k := n.typ.rtype.Kind(); k {
+		case reflect.Ptr:
+			n.typ = &itype{cat: valueT, rtype: n.typ.rtype.Elem()}
+			gen = compositeGenerator(n)
+		case reflect.Array, reflect.Slice:
+			gen = compositeBinSlice
 		case reflect.Struct:
 			gen = compositeBinStruct
 		case reflect.Map:
@@ -2319,6 +3242,71 @@ func compositeGenerator(n *node) (gen bltnGenerator) {
 	return gen
 }
 
+// elidedType returns the implicit type of the composite literal n, which
+// elides its type as an element, key or value of the enclosing composite
+// literal, or nil if the type can not be elided.
+func elidedType(n *node) *itype {
+	a := n.anc
+	if a.kind == keyValueExpr {
+		a = a.anc
+	}
+	if a.kind != compositeLitExpr || a.typ == nil {
+		return nil
+	}
+	t := a.typ
+	for t.cat == aliasT || t.cat == ptrT {
+		// The enclosing literal may itself have its &T elided.
+		t = t.val
+	}
+	isKey := n.anc.kind == keyValueExpr && n.anc.child[0] == n
+	switch t.cat {
+	case arrayT:
+		if !isKey {
+			return t.val
+		}
+	case mapT:
+		if isKey {
+			return t.key
+		}
+		return t.val
+	case valueT:
+		switch rt := t.rtype; rt.Kind() {
+		case reflect.Array, reflect.Slice:
+			if !isKey {
+				return &itype{cat: valueT, rtype: rt.Elem()}
+			}
+		case reflect.Map:
+			if isKey {
+				return &itype{cat: valueT, rtype: rt.Key()}
+			}
+			return &itype{cat: valueT, rtype: rt.Elem()}
+		}
+	}
+	return nil
+}
+
+// isComposite returns true if t is a valid composite literal type,
+// or a pointer to such a type for an element literal with an elided &T.
+func isComposite(t *itype) bool {
+	for t.cat == aliasT || t.cat == ptrT {
+		t = t.val
+	}
+	switch t.cat {
+	case arrayT, mapT, structT:
+		return true
+	case valueT:
+		rt := t.rtype
+		if rt.Kind() == reflect.Ptr {
+			rt = rt.Elem()
+		}
+		switch rt.Kind() {
+		case reflect.Array, reflect.Map, reflect.Slice, reflect.Struct:
+			return true
+		}
+	}
+	return false
+}
+
 // arrayTypeLen returns the node's array length. If the expression is an
 // array variable it is determined from the value's type, otherwise it is
 // computed from the source definition.
@@ -2326,12 +3314,15 @@ func arrayTypeLen(n *node) int {
 	if n.typ != nil && n.typ.sizedef {
 		return n.typ.size
 	}
-	max := -1
-	for i, c := range n.child[1:] {
-		r := i
+	max, r := -1, -1
+	for _, c := range n.child[1:] {
+		// An element without a key has the index of the previous element plus one.
+		r++
 		if c.kind == keyValueExpr {
-			if v := c.child[0].rval; v.IsValid() {
-				r = int(c.child[0].rval.Int())
+			if k, ok := c.child[0].constValue(); ok {
+				r = constToInt(k)
+			} else if v := c.child[0].rval; v.IsValid() {
+				r = int(vInt(v))
 			}
 		}
 		if r > max {
@@ -2341,6 +3332,17 @@ func arrayTypeLen(n *node) int {
 	return max + 1
 }
 
+// binValueType returns the type of the binary symbol value v. An untyped
+// constant has the type of a literal constant of the same kind.
+func binValueType(v reflect.Value) *itype {
+	if c, ok := v.Interface().(constant.Value); ok {
+		if t := untypedConstType(c); t != nil {
+			return t
+		}
+	}
+	return &itype{cat: valueT, rtype: v.Type(), untyped: isValueUntyped(v)}
+}
+
 // isValueUntyped returns true if value is untyped.
 func isValueUntyped(v reflect.Value) bool {
 	// Consider only constant values.
@@ -2353,3 +3355,24 @@ func isValueUntyped(v reflect.Value) bool {
 	}
 	return t.String() == t.Kind().String()
 }
+
+// checkRecvType checks that the base type of the receiver of method n, of
+// type expression recv and type t, is neither a pointer nor an interface, and
+// has no field of the same name than the method.
+func checkRecvType(n, recv *node, t *itype) error {
+	base := t
+	if recv.kind == starExpr && t.cat == ptrT {
+		base, recv = t.val, recv.child[0]
+	}
+	switch base.cat {
+	case ptrT, interfaceT:
+		return recv.cfgErrorf("invalid receiver type %s (pointer or interface type)", exprString(recv))
+	case structT:
+		for _, f := range base.field {
+			if f.name == n.ident && n.ident != "_" {
+				return n.child[1].cfgErrorf("type %s has both field and method named %s", base.name, n.ident)
+			}
+		}
+	}
+	return nil
+}
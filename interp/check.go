@@ -0,0 +1,104 @@
+package interp
+
+import (
+	"fmt"
+	"go/scanner"
+	"io/ioutil"
+	"strings"
+)
+
+// Diagnostic phases.
+const (
+	ParsePhase = "parse" // syntax error
+	CheckPhase = "check" // type or declaration error
+
+	// GoTypesPhase is a divergence with go/types, see Options.CheckWithGoTypes.
+	GoTypesPhase = "gotypes"
+)
+
+// A Diagnostic describes an error found in source code by Check.
+// The end position is set only if known. In the GoTypesPhase, Interp and
+// GoTypes are the error messages or types found by the interpreter and by
+// go/types, if any.
+type Diagnostic struct {
+	File      string `json:"file"`
+	Line      int    `json:"line,omitempty"`
+	Column    int    `json:"column,omitempty"`
+	EndLine   int    `json:"endLine,omitempty"`
+	EndColumn int    `json:"endColumn,omitempty"`
+	Severity  string `json:"severity"`
+	Message   string `json:"message"`
+	Phase     string `json:"phase"`
+	Interp    string `json:"interp,omitempty"`
+	GoTypes   string `json:"goTypes,omitempty"`
+}
+
+// Check parses and type checks the Go source file at path, without executing
+// any code, including in the imported source packages. It returns the list of
+// errors found, or nil if the source is correct. If Options.CheckWithGoTypes
+// is set, the divergences with go/types are reported as warnings.
+func (interp *Interpreter) Check(path string) (diags []Diagnostic) {
+	name, noRun := interp.Name, interp.noRun
+	interp.Name, interp.noRun = path, true
+	defer func() {
+		interp.Name, interp.noRun = name, noRun
+		if r := recover(); r != nil {
+			diags = append(diags, interp.diagnostic(path, CheckPhase, r))
+		}
+	}()
+
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return []Diagnostic{{File: path, Severity: "error", Message: err.Error(), Phase: ParsePhase}}
+	}
+
+	pkgName, root, err := interp.ast(string(buf), path)
+	if err != nil {
+		if l, ok := err.(scanner.ErrorList); ok {
+			for _, e := range l {
+				diags = append(diags, interp.diagnostic(path, ParsePhase, e))
+			}
+			return diags
+		}
+		return []Diagnostic{interp.diagnostic(path, ParsePhase, err)}
+	}
+	if root == nil {
+		return nil
+	}
+
+	if err = interp.gtaRetry([]*node{root}, pkgName, path); err == nil {
+		_, err = interp.cfg(root, path)
+	}
+	if l, ok := err.(ErrorList); ok {
+		for _, e := range l {
+			diags = append(diags, interp.diagnostic(path, CheckPhase, e))
+		}
+	} else if err != nil {
+		diags = append(diags, interp.diagnostic(path, CheckPhase, err))
+	}
+	if interp.goTypes {
+		diags = append(diags, interp.goTypesDiagnostics(path, pkgName, root, diags)...)
+	}
+	return diags
+}
+
+// diagnostic returns the diagnostic corresponding to the error or panic value v.
+func (interp *Interpreter) diagnostic(path, phase string, v interface{}) Diagnostic {
+	d := Diagnostic{File: path, Severity: "error", Phase: phase}
+	switch e := v.(type) {
+	case *scanner.Error:
+		d.File, d.Line, d.Column, d.Message = e.Pos.Filename, e.Pos.Line, e.Pos.Column, e.Msg
+	case *cfgError:
+		p := interp.fset.Position(e.pos)
+		d.File, d.Line, d.Column = p.Filename, p.Line, p.Column
+		d.Message = strings.TrimPrefix(e.Error(), p.String()+": ")
+		if i := strings.Index(d.Message, "\n\t"); i >= 0 && interp.errorSource {
+			d.Message = d.Message[:i] // Remove the source line.
+		}
+	case error:
+		d.Message = e.Error()
+	default:
+		d.Message = "panic: " + fmt.Sprint(v)
+	}
+	return d
+}
@@ -0,0 +1,32 @@
+package interp
+
+import (
+	"reflect"
+	"time"
+)
+
+// Clock is the source of time of the interpreted code, set by the Clock
+// option, for example to replay a recorded execution deterministically.
+// Sleep must block until the time of the clock has advanced by d.
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+}
+
+// clockSymbols returns the functions of package time operating on clock c.
+func clockSymbols(c Clock) map[string]reflect.Value {
+	return map[string]reflect.Value{
+		"After": reflect.ValueOf(func(d time.Duration) <-chan time.Time {
+			ch := make(chan time.Time, 1)
+			go func() {
+				c.Sleep(d)
+				ch <- c.Now()
+			}()
+			return ch
+		}),
+		"Now":   reflect.ValueOf(c.Now),
+		"Since": reflect.ValueOf(func(t time.Time) time.Duration { return c.Now().Sub(t) }),
+		"Sleep": reflect.ValueOf(c.Sleep),
+		"Until": reflect.ValueOf(func(t time.Time) time.Duration { return t.Sub(c.Now()) }),
+	}
+}
@@ -0,0 +1,103 @@
+package interp
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"path/filepath"
+)
+
+// CompiledPackage is a source package prepared once by CompilePackage, to be
+// imported by several interpreters with UseCompiled, without reading, checking
+// build constraints and parsing its files again. It is immutable and safe for
+// concurrent use.
+//
+// The global types analysis and the generation of the executable code depend
+// on the state of each interpreter, as the binary packages it uses, and are
+// still performed at import by each interpreter, where the package variables
+// are allocated.
+type CompiledPackage struct {
+	path  string         // import path
+	name  string         // package name
+	fset  *token.FileSet // file set of the parsed files
+	files []compiledFile
+}
+
+// compiledFile is a parsed source file of a compiled package.
+type compiledFile struct {
+	src  string // source text, for line information and error messages
+	file *ast.File
+}
+
+// CompilePackage prepares the package of import path path, from the non test
+// Go source files in directory dir, for the GOOS, GOARCH and build tags of
+// options. The package is made importable in interpreters by UseCompiled. To
+// account for modifications of the source files, a new compiled package must
+// be created and used.
+func CompilePackage(dir, path string, options Options) (*CompiledPackage, error) {
+	i := New(options) // Build context and constraints.
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &CompiledPackage{path: path, fset: token.NewFileSet()}
+	for _, e := range entries {
+		if e.IsDir() || skipFile(&i.context, e.Name()) {
+			continue
+		}
+		name := filepath.Join(dir, e.Name())
+		buf, err := ioutil.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		src := string(buf)
+		if ok, err := i.buildOk(&i.context, name, src); err != nil {
+			return nil, err
+		} else if !ok {
+			continue
+		}
+		f, err := parser.ParseFile(p.fset, name, src, 0)
+		if err != nil {
+			return nil, err
+		}
+		if p.name == "" {
+			p.name = f.Name.Name
+		} else if p.name != f.Name.Name {
+			return nil, fmt.Errorf("found packages %s and %s in %s", p.name, f.Name.Name, dir)
+		}
+		p.files = append(p.files, compiledFile{src: src, file: f})
+	}
+	if len(p.files) == 0 {
+		return nil, fmt.Errorf("no Go source files in %s", dir)
+	}
+	return p, nil
+}
+
+// Path returns the import path of the compiled package.
+func (p *CompiledPackage) Path() string { return p.path }
+
+// UseCompiled makes the compiled package pkg importable by interpreted code,
+// with its import path, in place of a source package found in GOPATH. A
+// package already imported by the interpreter is not replaced.
+func (interp *Interpreter) UseCompiled(pkg *CompiledPackage) {
+	interp.mutex.Lock()
+	interp.compiled[pkg.path] = pkg
+	interp.mutex.Unlock()
+}
+
+// astCompiled returns the package name and the AST of the compiled file cf,
+// with positions in the interpreter file set.
+func (interp *Interpreter) astCompiled(p *CompiledPackage, cf compiledFile) (string, *node, error) {
+	tf := p.fset.File(cf.file.Pos())
+	f := interp.fset.AddFile(tf.Name(), -1, tf.Size())
+	f.SetLinesForContent([]byte(cf.src))
+	if interp.errorSource {
+		interp.mutex.Lock()
+		interp.sources[f] = source{text: cf.src}
+		interp.mutex.Unlock()
+	}
+	return interp.astNodes(cf.file, token.Pos(f.Base()-tf.Base()))
+}
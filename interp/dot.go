@@ -1,6 +1,7 @@
 package interp
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -10,25 +11,103 @@ import (
 	"strings"
 )
 
+// ASTDot writes in w, in graphviz dot(1) format, the abstract syntax tree of
+// name, which is either the path of an evaluated or imported source file, or
+// the name of an interpreted function. For code evaluated by Eval, the file
+// path is the interpreter Name, and the last evaluated source is displayed.
+// Functions are designated by their name, possibly qualified by their package
+// path and receiver type, i.e. "f", "main.T.Method" or "github.com/foo/bar.F".
+// Each node is labelled with its index, kind, action, type and source position.
+func (interp *Interpreter) ASTDot(name string, w io.Writer) error {
+	n, err := interp.dotNode(name)
+	if err != nil {
+		return err
+	}
+	n.astDot(w, name)
+	return nil
+}
+
+// CFGDot writes in w, in graphviz dot(1) format, the control flow graph of
+// name, as designated in ASTDot. True branches are drawn in green, and false
+// branches in red. Each node is labelled as in ASTDot, plus its frame index.
+func (interp *Interpreter) CFGDot(name string, w io.Writer) error {
+	n, err := interp.dotNode(name)
+	if err != nil {
+		return err
+	}
+	n.cfgDot(w)
+	return nil
+}
+
+// dotNode returns the AST root node of a source file or of a function definition.
+func (interp *Interpreter) dotNode(name string) (*node, error) {
+	interp.mutex.RLock()
+	defer interp.mutex.RUnlock()
+
+	if n := interp.roots[name]; n != nil {
+		return n, nil
+	}
+
+	// Split the package path, if any, from the function name.
+	sym := interp.scopes[interp.Name].sym
+	fname := name
+	slash := strings.LastIndex(name, "/") + 1
+	if i := strings.Index(name[slash:], "."); i >= 0 {
+		if s, ok := interp.srcPkg[name[:slash+i]]; ok {
+			sym, fname = s, name[slash+i+1:]
+		}
+	}
+
+	var n *node
+	if i := strings.Index(fname, "."); i >= 0 {
+		if s, ok := sym[fname[:i]]; ok && s.kind == typeSym && s.typ != nil {
+			n = s.typ.getMethod(fname[i+1:])
+		}
+	} else if s, ok := sym[fname]; ok && s.kind == funcSym {
+		n = s.node
+	}
+	if n == nil {
+		return nil, errors.New("no source file or function " + name)
+	}
+	return n, nil
+}
+
+// dotLabel returns the dot label of node n, with its index, kind, action,
+// type and source position, if set.
+func (n *node) dotLabel() string {
+	label := fmt.Sprintf("%d: %s", n.index, n.kind)
+	switch n.kind {
+	case basicLit, identExpr:
+		label += " " + strings.Replace(n.ident, "\"", "\\\"", -1)
+	}
+	if n.action != aNop {
+		label += "\\naction: " + n.action.String()
+	}
+	if n.typ != nil {
+		t := n.typ.id()
+		if t == "" && n.typ.rtype != nil {
+			t = n.typ.rtype.String()
+		}
+		if t == "" {
+			t = n.typ.cat.String()
+		}
+		label += "\\ntype: " + strings.Replace(t, "\"", "\\\"", -1)
+	}
+	if p := n.interp.fset.Position(n.pos); p.IsValid() {
+		label += "\\npos: " + p.String()
+	}
+	return label
+}
+
 // astDot displays an AST in graphviz dot(1) format using dotty(1) co-process.
 func (n *node) astDot(out io.Writer, name string) {
 	fmt.Fprintf(out, "digraph ast {\n")
 	fmt.Fprintf(out, "labelloc=\"t\"\n")
 	fmt.Fprintf(out, "label=\"%s\"\n", name)
+	root := n
 	n.Walk(func(n *node) bool {
-		var label string
-		switch n.kind {
-		case basicLit, identExpr:
-			label = strings.Replace(n.ident, "\"", "\\\"", -1)
-		default:
-			if n.action != aNop {
-				label = n.action.String()
-			} else {
-				label = n.kind.String()
-			}
-		}
-		fmt.Fprintf(out, "%d [label=\"%d: %s\"]\n", n.index, n.index, label)
-		if n.anc != nil {
+		fmt.Fprintf(out, "%d [label=\"%s\"]\n", n.index, n.dotLabel())
+		if n.anc != nil && n != root {
 			fmt.Fprintf(out, "%d -> %d\n", n.anc.index, n.index)
 		}
 		return true
@@ -39,17 +118,17 @@ func (n *node) astDot(out io.Writer, name string) {
 // cfgDot displays a CFG in graphviz dot(1) format using dotty(1) co-process.
 func (n *node) cfgDot(out io.Writer) {
 	fmt.Fprintf(out, "digraph cfg {\n")
+	// Terminal nodes, without successor, are displayed only if reached.
+	reached := map[*node]bool{}
+	n.Walk(func(n *node) bool {
+		reached[n.tnext], reached[n.fnext] = true, true
+		return true
+	}, nil)
 	n.Walk(nil, func(n *node) {
-		if n.kind == basicLit || n.tnext == nil {
+		if n.kind == basicLit || n.tnext == nil && !reached[n] {
 			return
 		}
-		var label string
-		if n.action == aNop {
-			label = "nop: end_" + n.kind.String()
-		} else {
-			label = n.action.String()
-		}
-		fmt.Fprintf(out, "%d [label=\"%d: %v %d\"]\n", n.index, n.index, label, n.findex)
+		fmt.Fprintf(out, "%d [label=\"%s\\nfindex: %d\"]\n", n.index, n.dotLabel(), n.findex)
 		if n.fnext != nil {
 			fmt.Fprintf(out, "%d -> %d [color=green]\n", n.index, n.tnext.index)
 			fmt.Fprintf(out, "%d -> %d [color=red]\n", n.index, n.fnext.index)
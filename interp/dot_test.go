@@ -0,0 +1,55 @@
+package interp_test
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/containous/yaegi/interp"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestDot(t *testing.T) {
+	src, err := ioutil.ReadFile(filepath.Join("testdata", "dot", "fib.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := interp.New(interp.Options{})
+	if _, err := i.Eval(string(src)); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		golden string
+		dot    func(string, io.Writer) error
+	}{
+		{"fib.ast.dot", i.ASTDot},
+		{"fib.cfg.dot", i.CFGDot},
+	} {
+		var buf bytes.Buffer
+		if err := test.dot("main.fib", &buf); err != nil {
+			t.Fatal(err)
+		}
+		golden := filepath.Join("testdata", "dot", test.golden)
+		if *update {
+			if err := ioutil.WriteFile(golden, buf.Bytes(), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		want, err := ioutil.ReadFile(golden)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.String(); got != string(want) {
+			t.Errorf("%s: got\n%s\nwant\n%s", test.golden, got, want)
+		}
+	}
+
+	if err := i.ASTDot("main.nofunc", ioutil.Discard); err == nil {
+		t.Error("expected error for undefined function")
+	}
+}
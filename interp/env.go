@@ -0,0 +1,89 @@
+package interp
+
+import (
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// environment is the private environment of the interpreted code, set by the
+// Env option.
+type environment struct {
+	mutex sync.RWMutex
+	vars  map[string]string
+}
+
+func newEnvironment(env []string) *environment {
+	e := &environment{vars: make(map[string]string, len(env))}
+	for _, kv := range env {
+		if i := strings.Index(kv, "="); i > 0 {
+			e.vars[kv[:i]] = kv[i+1:]
+		}
+	}
+	return e
+}
+
+// symbols returns the functions of package os operating on the environment.
+func (e *environment) symbols() map[string]reflect.Value {
+	return map[string]reflect.Value{
+		"Clearenv":  reflect.ValueOf(e.clearenv),
+		"Environ":   reflect.ValueOf(e.environ),
+		"ExpandEnv": reflect.ValueOf(e.expandEnv),
+		"Getenv":    reflect.ValueOf(e.getenv),
+		"LookupEnv": reflect.ValueOf(e.lookupEnv),
+		"Setenv":    reflect.ValueOf(e.setenv),
+		"Unsetenv":  reflect.ValueOf(e.unsetenv),
+	}
+}
+
+func (e *environment) getenv(key string) string {
+	v, _ := e.lookupEnv(key)
+	return v
+}
+
+func (e *environment) lookupEnv(key string) (string, bool) {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	v, ok := e.vars[key]
+	return v, ok
+}
+
+func (e *environment) setenv(key, value string) error {
+	if key == "" || strings.ContainsAny(key, "=\x00") || strings.Contains(value, "\x00") {
+		return os.NewSyscallError("setenv", syscall.EINVAL)
+	}
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.vars[key] = value
+	return nil
+}
+
+func (e *environment) unsetenv(key string) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	delete(e.vars, key)
+	return nil
+}
+
+func (e *environment) clearenv() {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.vars = map[string]string{}
+}
+
+// environ returns the environment in the form "key=value", sorted by key.
+func (e *environment) environ() []string {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	env := make([]string, 0, len(e.vars))
+	for k, v := range e.vars {
+		env = append(env, k+"="+v)
+	}
+	sort.Strings(env)
+	return env
+}
+
+func (e *environment) expandEnv(s string) string { return os.Expand(s, e.getenv) }
@@ -0,0 +1,53 @@
+package interp
+
+import "fmt"
+
+// ExitError is the error returned by Eval when the interpreted code calls
+// os.Exit, unless the AllowExit option is set.
+type ExitError struct {
+	Code int // exit status passed to os.Exit
+}
+
+func (e ExitError) Error() string { return fmt.Sprintf("exit status %d", e.Code) }
+
+// goexit is the value of the panic raised by runtime.Goexit in interpreted
+// code, to unwind the interpreted frames of the goroutine.
+type goexit struct{}
+
+// exit replaces os.Exit for the interpreted code, if the AllowExit option
+// is not set.
+func exit(code int) { panic(ExitError{Code: code}) }
+
+// runtimeGoexit replaces runtime.Goexit for the interpreted code. The deferred
+// calls of the unwound interpreted functions are run, but, contrary to a
+// panic, they can not recover it.
+func runtimeGoexit() { panic(goexit{}) }
+
+// isExit returns true if the recovered value r is raised by os.Exit or
+// runtime.Goexit in interpreted code, instead of a panic.
+func isExit(r interface{}) bool {
+	switch panicValue(r).(type) {
+	case ExitError, goexit:
+		return true
+	}
+	return false
+}
+
+// exitError returns the error of an evaluation terminated by the recovered
+// value r of os.Exit or runtime.Goexit. It is nil for runtime.Goexit, which
+// ends the evaluation as a return of main.
+func exitError(r interface{}) error {
+	if e, ok := panicValue(r).(ExitError); ok {
+		return e
+	}
+	return nil
+}
+
+// recoverExit ends quietly a goroutine started by interpreted code, which
+// called os.Exit or runtime.Goexit. It must be deferred. The other panics
+// are propagated.
+func recoverExit() {
+	if r := recover(); r != nil && !isExit(r) {
+		panic(untraced(r))
+	}
+}
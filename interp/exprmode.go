@@ -0,0 +1,78 @@
+package interp
+
+// checkExprOnly returns an error if the AST root contains constructs not
+// allowed by the ExprOnly option: only expressions, operators, conversions,
+// non nested indexing and the calls of the AllowedCalls functions are allowed.
+// Import declarations are allowed, as they do not execute code.
+func (interp *Interpreter) checkExprOnly(root *node) error {
+	if root.kind == fileStmt {
+		for _, c := range root.child {
+			if c.kind != importDecl && c.kind != identExpr { // identExpr is the package name
+				return c.cfgErrorf("declarations are not allowed in expression mode")
+			}
+		}
+		return nil
+	}
+
+	sc := interp.initScopePkg(interp.Name)
+	var err error
+	root.Walk(func(n *node) bool {
+		if err != nil {
+			return false
+		}
+		switch n.kind {
+		case blockStmt:
+			if n == root {
+				return true
+			}
+			err = n.cfgErrorf("statements are not allowed in expression mode")
+		case basicLit, binaryExpr, exprStmt, identExpr, landExpr, lorExpr, parenExpr, selectorExpr:
+			return true
+		case unaryExpr:
+			if n.action != aRecv {
+				return true
+			}
+			err = n.cfgErrorf("channel operations are not allowed in expression mode")
+		case indexExpr, sliceExpr:
+			for anc := n.anc; anc != nil; anc = anc.anc {
+				if anc.kind == indexExpr || anc.kind == sliceExpr {
+					err = n.cfgErrorf("nested indexing is not allowed in expression mode")
+					return false
+				}
+			}
+			return true
+		case callExpr:
+			c0 := n.child[0]
+			if c0.isType(sc) {
+				return true // A conversion.
+			}
+			if c0.kind == identExpr || c0.kind == selectorExpr {
+				name := exprString(c0)
+				if interp.allowedCalls[name] {
+					return true
+				}
+				if len(interp.allowedCalls) > 0 {
+					err = n.cfgErrorf("function call %s is not allowed in expression mode", name)
+					return false
+				}
+			}
+			err = n.cfgErrorf("function calls are not allowed in expression mode")
+		case declStmt, defineStmt, defineXStmt:
+			err = n.cfgErrorf("declarations are not allowed in expression mode")
+		case forStmt0, forStmt1, forStmt2, forStmt3, forStmt3a, forStmt4, forRangeStmt, rangeStmt:
+			err = n.cfgErrorf("loops are not allowed in expression mode")
+		case compositeLitExpr:
+			err = n.cfgErrorf("composite literals are not allowed in expression mode")
+		case funcLit:
+			err = n.cfgErrorf("function literals are not allowed in expression mode")
+		default:
+			if n.anc == root {
+				err = n.cfgErrorf("statements are not allowed in expression mode")
+			} else {
+				err = n.cfgErrorf("expression not allowed in expression mode")
+			}
+		}
+		return false
+	}, nil)
+	return err
+}
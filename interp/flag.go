@@ -0,0 +1,84 @@
+package interp
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+)
+
+// commandLine is the private command line flag set of the interpreted
+// program, parsing the arguments of the Args option instead of those of the
+// current process.
+type commandLine struct {
+	set   *flag.FlagSet
+	args  []string
+	usage func()
+	exit  func(int)
+}
+
+func newCommandLine(args []string, output io.Writer, allowExit bool) *commandLine {
+	if len(args) == 0 {
+		args = []string{""}
+	}
+	c := &commandLine{set: flag.NewFlagSet(args[0], flag.ContinueOnError), args: args, exit: exit}
+	if allowExit {
+		c.exit = os.Exit
+	}
+	c.set.SetOutput(output)
+	c.usage = func() {
+		fmt.Fprintf(c.set.Output(), "Usage of %s:\n", c.set.Name())
+		c.set.PrintDefaults()
+	}
+	c.set.Usage = func() { c.usage() }
+	return c
+}
+
+// parse parses the command line, as flag.Parse, exiting with status 2 on
+// error, or 0 if the help flag is given.
+func (c *commandLine) parse() {
+	switch err := c.set.Parse(c.args[1:]); {
+	case err == flag.ErrHelp:
+		c.exit(0)
+	case err != nil:
+		c.exit(2)
+	}
+}
+
+// symbols returns the functions and variables of package flag operating on
+// the command line.
+func (c *commandLine) symbols() map[string]reflect.Value {
+	return map[string]reflect.Value{
+		"Arg":           reflect.ValueOf(c.set.Arg),
+		"Args":          reflect.ValueOf(c.set.Args),
+		"Bool":          reflect.ValueOf(c.set.Bool),
+		"BoolVar":       reflect.ValueOf(c.set.BoolVar),
+		"CommandLine":   reflect.ValueOf(&c.set).Elem(),
+		"Duration":      reflect.ValueOf(c.set.Duration),
+		"DurationVar":   reflect.ValueOf(c.set.DurationVar),
+		"Float64":       reflect.ValueOf(c.set.Float64),
+		"Float64Var":    reflect.ValueOf(c.set.Float64Var),
+		"Int":           reflect.ValueOf(c.set.Int),
+		"Int64":         reflect.ValueOf(c.set.Int64),
+		"Int64Var":      reflect.ValueOf(c.set.Int64Var),
+		"IntVar":        reflect.ValueOf(c.set.IntVar),
+		"Lookup":        reflect.ValueOf(c.set.Lookup),
+		"NArg":          reflect.ValueOf(c.set.NArg),
+		"NFlag":         reflect.ValueOf(c.set.NFlag),
+		"Parse":         reflect.ValueOf(c.parse),
+		"Parsed":        reflect.ValueOf(c.set.Parsed),
+		"PrintDefaults": reflect.ValueOf(c.set.PrintDefaults),
+		"Set":           reflect.ValueOf(c.set.Set),
+		"String":        reflect.ValueOf(c.set.String),
+		"StringVar":     reflect.ValueOf(c.set.StringVar),
+		"Uint":          reflect.ValueOf(c.set.Uint),
+		"Uint64":        reflect.ValueOf(c.set.Uint64),
+		"Uint64Var":     reflect.ValueOf(c.set.Uint64Var),
+		"UintVar":       reflect.ValueOf(c.set.UintVar),
+		"Usage":         reflect.ValueOf(&c.usage).Elem(),
+		"Var":           reflect.ValueOf(c.set.Var),
+		"Visit":         reflect.ValueOf(c.set.Visit),
+		"VisitAll":      reflect.ValueOf(c.set.VisitAll),
+	}
+}
@@ -0,0 +1,600 @@
+package interp
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// _formatter is the wrapper of an interpreted value passed in the
+// ...interface{} arguments of a binary function, i.e. of package fmt. The
+// reflect representation of interpreted types has neither their names nor
+// their unexported field names, so its Format method prints the value from
+// its interpreter type, as the compiled program would.
+type _formatter struct {
+	typ   *itype
+	value reflect.Value
+}
+
+// Format implements fmt.Formatter.
+func (w _formatter) Format(s fmt.State, verb rune) {
+	p := &formatPrinter{s: s, verb: verb, format: formatString(s, verb), plus: s.Flag('+'), sharp: s.Flag('#')}
+	p.printValue(w.typ, w.value, 0)
+}
+
+// formatString returns the format string of verb, with the flags, width and
+// precision of s.
+func formatString(s fmt.State, verb rune) string {
+	format := "%"
+	for _, c := range "+-# 0" {
+		if s.Flag(int(c)) {
+			format += string(c)
+		}
+	}
+	if width, ok := s.Width(); ok {
+		format += strconv.Itoa(width)
+	}
+	if prec, ok := s.Precision(); ok {
+		format += "." + strconv.Itoa(prec)
+	}
+	return format + string(verb)
+}
+
+// formatMethods are the methods used by fmt to print a value.
+var formatMethods = []string{"Error", "Format", "GoString", "String"}
+
+// isFormatted returns true if the values of the interpreter type t are
+// passed to fmt wrapped in a _formatter: t contains interpreted structs,
+// defined composite types or types with methods used by fmt, printed
+// differently from their reflect representation. A value whose type has a
+// method used by fmt is left to the binary wrappers of the value.
+func isFormatted(t *itype) bool {
+	return !hasFormatMethod(t) && checkFormatted(t, map[*itype]bool{})
+}
+
+// hasFormatMethod returns true if the interpreter type t has a method used by
+// fmt.
+func hasFormatMethod(t *itype) bool {
+	if len(t.method) == 0 && t.cat != structT && t.cat != ptrT {
+		return false
+	}
+	m := t.methods()
+	for _, name := range formatMethods {
+		if _, found := m[name]; found {
+			return true
+		}
+	}
+	return false
+}
+
+// checkFormatted returns true if the values of type t print differently from
+// their reflect representation.
+func checkFormatted(t *itype, visited map[*itype]bool) bool {
+	if visited[t] {
+		return false
+	}
+	visited[t] = true
+	switch {
+	case t.cat == valueT || t.cat == errorT:
+		return false
+	case hasFormatMethod(t):
+		// The value is printed by its method.
+		return true
+	}
+
+	var elems []*itype
+	formatted := t.isDefined() && !isBasicType(t.TypeOf())
+	switch t.cat {
+	case aliasT, arrayT, variadicT, ptrT:
+		elems = []*itype{t.val}
+	case mapT:
+		elems = []*itype{t.key, t.val}
+	case interfaceT:
+		// The dynamic value is checked at execution.
+		formatted = true
+	case structT:
+		formatted = true
+		for _, f := range t.field {
+			elems = append(elems, f.typ)
+		}
+	}
+	for _, e := range elems {
+		formatted = checkFormatted(e, visited) || formatted
+	}
+	return formatted
+}
+
+// genFormatter returns the value of n wrapped in a _formatter if it is an
+// interpreted value printed differently from its reflect representation, or
+// the value itself. As the %T verb prints the type of the wrapper, the value
+// is not wrapped if it is the argument arg of a %T verb of the format string
+// of the call.
+func genFormatter(n *node, format func(*frame) reflect.Value, arg int) func(*frame) reflect.Value {
+	value := genValue(n)
+	return func(f *frame) reflect.Value {
+		typ, v := n.typ, value(f)
+		if isInterfaceSrc(typ) {
+			if typ, v = interfaceValue(v); typ == nil {
+				return reflect.New(interf).Elem()
+			}
+			if !isFormatted(typ) {
+				return v
+			}
+		}
+		if format != nil && isTypeVerbArg(format(f).String(), arg) {
+			return v
+		}
+		return reflect.ValueOf(_formatter{typ, v})
+	}
+}
+
+// isTypeVerbArg returns true if the argument arg of the format string is
+// printed by a %T verb. Arguments are numbered as by fmt, including explicit
+// argument indexes and the arguments of * widths and precisions.
+func isTypeVerbArg(format string, arg int) bool {
+	num := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		i++
+		for i < len(format) && strings.IndexByte("+-# 0", format[i]) >= 0 {
+			i++
+		}
+		num, i = argNumber(format, i, num)
+		if i < len(format) && format[i] == '*' {
+			num, i = num+1, i+1
+		}
+		for i < len(format) && '0' <= format[i] && format[i] <= '9' {
+			i++
+		}
+		if i < len(format) && format[i] == '.' {
+			num, i = argNumber(format, i+1, num)
+			if i < len(format) && format[i] == '*' {
+				num, i = num+1, i+1
+			}
+			for i < len(format) && '0' <= format[i] && format[i] <= '9' {
+				i++
+			}
+		}
+		num, i = argNumber(format, i, num)
+		if i >= len(format) {
+			break
+		}
+		verb, size := utf8.DecodeRuneInString(format[i:])
+		i += size - 1
+		switch {
+		case verb == '%':
+			continue
+		case verb == 'T' && num == arg:
+			return true
+		}
+		num++
+	}
+	return false
+}
+
+// argNumber returns the argument number set by an explicit argument index
+// [n] at position i of format, and the position following it, or num and i
+// if there is no valid index.
+func argNumber(format string, i, num int) (int, int) {
+	if i >= len(format) || format[i] != '[' {
+		return num, i
+	}
+	j := strings.IndexByte(format[i:], ']')
+	if j < 0 {
+		return num, i
+	}
+	n, err := strconv.Atoi(format[i+1 : i+j])
+	if err != nil || n < 1 {
+		return num, i
+	}
+	return n - 1, i + j + 1
+}
+
+// interfaceValue returns the dynamic type and value of v, an interpreted
+// interface value, or a nil type if v is nil.
+func interfaceValue(v reflect.Value) (*itype, reflect.Value) {
+	if v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, v
+		}
+		v = v.Elem()
+	}
+	vi, ok := v.Interface().(valueInterface)
+	if !ok {
+		return &itype{cat: valueT, rtype: v.Type()}, v
+	}
+	if vi.node == nil || !vi.value.IsValid() || vi.value.Kind() == reflect.Interface && vi.value.IsNil() {
+		return nil, v
+	}
+	if vi.node.typ.cat == nilT || isInterfaceSrc(vi.node.typ) {
+		return interfaceValue(vi.value)
+	}
+	if vi.value.Kind() == reflect.Interface && vi.node.typ.TypeOf().Kind() != reflect.Interface {
+		return vi.node.typ, vi.value.Elem()
+	}
+	return vi.node.typ, vi.value
+}
+
+// formatPrinter prints interpreted values as fmt does, from their
+// interpreter types. The values of binary types are printed by fmt.
+type formatPrinter struct {
+	s      fmt.State
+	verb   rune
+	format string // format of verb, applied to the values printed by fmt
+	plus   bool   // %+v
+	sharp  bool   // %#v
+
+	// unexported is the number of unexported fields holding the value being
+	// printed. As fmt can not call the methods of such values, neither does
+	// the printer.
+	unexported int
+}
+
+func (p *formatPrinter) write(s string) { _, _ = p.s.Write([]byte(s)) }
+
+func (p *formatPrinter) goSyntax() bool { return p.sharp && p.verb == 'v' }
+
+func (p *formatPrinter) printValue(t *itype, v reflect.Value, depth int) {
+	u := t
+	for u.cat == aliasT {
+		u = u.val
+	}
+	if v.Kind() == reflect.Interface && u.cat != interfaceT && u.cat != errorT && u.cat != valueT {
+		// The value of a recursive type, represented as an interface{}.
+		if v.IsNil() {
+			v = reflect.Zero(u.TypeOf())
+		} else {
+			v = v.Elem()
+		}
+	}
+
+	if u.cat != interfaceT && u.cat != errorT && u.cat != valueT && p.unexported == 0 && p.handleMethods(t, v) {
+		return
+	}
+
+	switch u.cat {
+	case interfaceT:
+		typ, dv := interfaceValue(v)
+		switch {
+		case typ != nil:
+			p.printValue(typ, dv, depth+1)
+		case p.goSyntax():
+			p.write(goSyntaxType(t) + "(nil)")
+		default:
+			p.write("<nil>")
+		}
+
+	case structT:
+		if p.goSyntax() {
+			p.write(goSyntaxType(t))
+		}
+		p.write("{")
+		for i, f := range u.field {
+			if i > 0 {
+				if p.goSyntax() {
+					p.write(", ")
+				} else {
+					p.write(" ")
+				}
+			}
+			if p.plus || p.goSyntax() {
+				p.write(f.name + ":")
+			}
+			if !canExport(f.name) {
+				p.unexported++
+			}
+			p.printValue(f.typ, v.Field(i), depth+1)
+			if !canExport(f.name) {
+				p.unexported--
+			}
+		}
+		p.write("}")
+
+	case arrayT, variadicT:
+		if p.goSyntax() {
+			p.write(goSyntaxType(t))
+			if v.Kind() == reflect.Slice && v.IsNil() {
+				p.write("(nil)")
+				return
+			}
+			p.write("{")
+		} else {
+			p.write("[")
+		}
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				if p.goSyntax() {
+					p.write(", ")
+				} else {
+					p.write(" ")
+				}
+			}
+			p.printValue(u.val, v.Index(i), depth+1)
+		}
+		if p.goSyntax() {
+			p.write("}")
+		} else {
+			p.write("]")
+		}
+
+	case mapT:
+		if p.goSyntax() {
+			p.write(goSyntaxType(t))
+			if v.IsNil() {
+				p.write("(nil)")
+				return
+			}
+			p.write("{")
+		} else {
+			p.write("map[")
+		}
+		keys := v.MapKeys()
+		sortKeys(keys)
+		for i, k := range keys {
+			if i > 0 {
+				if p.goSyntax() {
+					p.write(", ")
+				} else {
+					p.write(" ")
+				}
+			}
+			p.printValue(u.key, k, depth+1)
+			p.write(":")
+			p.printValue(u.val, v.MapIndex(k), depth+1)
+		}
+		if p.goSyntax() {
+			p.write("}")
+		} else {
+			p.write("]")
+		}
+
+	case ptrT:
+		if depth == 0 && !v.IsNil() {
+			// A pointer to a composite value is printed as &{...} at top level.
+			e := u.val
+			for e.cat == aliasT {
+				e = e.val
+			}
+			switch e.cat {
+			case arrayT, mapT, structT:
+				p.write("&")
+				p.printValue(u.val, v.Elem(), depth+1)
+				return
+			}
+		}
+		p.printPointer(goSyntaxType(t), v)
+
+	default:
+		p.printBin(v, depth)
+	}
+}
+
+var (
+	stringMethodType = reflect.TypeOf((func() string)(nil))
+	formatMethodType = reflect.TypeOf((func(fmt.State, rune))(nil))
+)
+
+// handleMethods prints the value v of type t with its method, as fmt does for
+// a Formatter, a GoStringer, an error or a Stringer, and returns false if t has
+// no method to print v with the verb.
+func (p *formatPrinter) handleMethods(t *itype, v reflect.Value) (handled bool) {
+	name, m := p.formatMethod(t, v)
+	if !m.IsValid() {
+		return false
+	}
+	handled = true
+	defer p.catchPanic(v, name)
+	switch name {
+	case "Format":
+		m.Call([]reflect.Value{reflect.ValueOf(p.s), reflect.ValueOf(p.verb)})
+	case "GoString":
+		p.write(m.Call(nil)[0].String())
+	default:
+		fmt.Fprintf(p.s, p.format, m.Call(nil)[0].String())
+	}
+	return handled
+}
+
+// formatMethod returns the name of the method used by fmt to print the value v
+// of type t with the verb, and the method bound to v, or an invalid value.
+func (p *formatPrinter) formatMethod(t *itype, v reflect.Value) (string, reflect.Value) {
+	if m := methodValue(t, v, "Format", formatMethodType); m.IsValid() {
+		return "Format", m
+	}
+	if p.goSyntax() {
+		return "GoString", methodValue(t, v, "GoString", stringMethodType)
+	}
+	switch p.verb {
+	case 'v', 's', 'x', 'X', 'q':
+		for _, name := range []string{"Error", "String"} {
+			if m := methodValue(t, v, name, stringMethodType); m.IsValid() {
+				return name, m
+			}
+		}
+	}
+	return "", reflect.Value{}
+}
+
+// catchPanic prints the panic of the method name called to print v, as fmt
+// does. A nil pointer receiver prints as <nil>.
+func (p *formatPrinter) catchPanic(v reflect.Value, name string) {
+	r := recover()
+	switch {
+	case r == nil:
+	case v.Kind() == reflect.Ptr && v.IsNil():
+		p.write("<nil>")
+	default:
+		p.write("%!" + string(p.verb) + "(PANIC=" + name + " method: " + fmt.Sprint(r) + ")")
+	}
+}
+
+// methodValue returns the method name of the interpreter type t, bound to the
+// value v, as a function of type ft callable by reflect, or an invalid value if
+// t has no such method.
+func methodValue(t *itype, v reflect.Value, name string, ft reflect.Type) reflect.Value {
+	var f reflect.Value
+	if m, index := t.lookupMethod(name); m != nil {
+		if isPtrRecv(m) && t.cat != ptrT && t.fieldSeq(index).cat != ptrT {
+			// A method with a pointer receiver is not in the method set of t.
+			return reflect.Value{}
+		}
+		nod := *m
+		nod.recv = &receiver{val: v, index: index}
+		f = genFunctionWrapper(&nod)(m.interp.frame)
+	} else if v.IsValid() && v.Kind() != reflect.Interface && v.CanInterface() {
+		// A binary method, promoted from an embedded field.
+		f = v.MethodByName(name)
+	}
+	if !f.IsValid() || f.Type() != ft {
+		return reflect.Value{}
+	}
+	return f
+}
+
+// printPointer prints the pointer v of type typ, not at top level.
+func (p *formatPrinter) printPointer(typ string, v reflect.Value) {
+	switch {
+	case p.goSyntax() && v.IsNil():
+		p.write("(" + typ + ")(nil)")
+	case p.goSyntax():
+		p.write("(" + typ + ")(0x" + strconv.FormatUint(uint64(v.Pointer()), 16) + ")")
+	case v.IsNil():
+		p.write("<nil>")
+	case p.verb == 'v':
+		p.write("0x" + strconv.FormatUint(uint64(v.Pointer()), 16))
+	default:
+		fmt.Fprintf(p.s, p.format, v.Pointer())
+	}
+}
+
+// printBin prints the value v of binary type, at depth.
+func (p *formatPrinter) printBin(v reflect.Value, depth int) {
+	if v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			if p.goSyntax() {
+				p.write(v.Type().String() + "(nil)")
+			} else {
+				p.write("<nil>")
+			}
+			return
+		}
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Ptr && depth > 0 && !p.hasMethod(v.Type()) {
+		p.printPointer(v.Type().String(), v)
+		return
+	}
+	fmt.Fprintf(p.s, p.format, v.Interface())
+}
+
+// hasMethod returns true if fmt prints the values of the binary type t with
+// one of their methods.
+func (p *formatPrinter) hasMethod(t reflect.Type) bool {
+	for _, name := range formatMethods {
+		if p.goSyntax() && name != "Format" && name != "GoString" {
+			continue
+		}
+		if _, ok := t.MethodByName(name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// sortKeys sorts the map keys k as fmt does for the keys of basic kinds.
+func sortKeys(k []reflect.Value) {
+	sort.SliceStable(k, func(i, j int) bool {
+		a, b := k[i], k[j]
+		switch a.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return a.Int() < b.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return a.Uint() < b.Uint()
+		case reflect.Float32, reflect.Float64:
+			return a.Float() < b.Float()
+		case reflect.String:
+			return a.String() < b.String()
+		case reflect.Bool:
+			return !a.Bool() && b.Bool()
+		}
+		return false
+	})
+}
+
+// typeString returns the representation of the interpreter type t as printed
+// by fmt for %#v, where a defined type is qualified by its package name.
+func goSyntaxType(t *itype) string {
+	if t.cat == valueT {
+		return typeString(t)
+	}
+	if t.name != "" {
+		if t.path == "" {
+			return t.name
+		}
+		return path.Base(t.path) + "." + t.name
+	}
+	switch t.cat {
+	case aliasT:
+		return goSyntaxType(t.val)
+	case errorT, interfaceT:
+		return typeString(t)
+	case arrayT, variadicT:
+		if t.sizedef {
+			return "[" + strconv.Itoa(t.size) + "]" + goSyntaxType(t.val)
+		}
+		return "[]" + goSyntaxType(t.val)
+	case chanT:
+		return "chan " + goSyntaxType(t.val)
+	case chanRecvT:
+		return "<-chan " + goSyntaxType(t.val)
+	case chanSendT:
+		return "chan<- " + goSyntaxType(t.val)
+	case funcT:
+		args := make([]string, len(t.arg))
+		for i, a := range t.arg {
+			args[i] = goSyntaxType(a)
+		}
+		rets := make([]string, len(t.ret))
+		for i, r := range t.ret {
+			rets[i] = goSyntaxType(r)
+		}
+		s := "func(" + strings.Join(args, ", ") + ")"
+		switch len(rets) {
+		case 0:
+		case 1:
+			s += " " + rets[0]
+		default:
+			s += " (" + strings.Join(rets, ", ") + ")"
+		}
+		return s
+	case mapT:
+		return "map[" + goSyntaxType(t.key) + "]" + goSyntaxType(t.val)
+	case ptrT:
+		return "*" + goSyntaxType(t.val)
+	case structT:
+		if len(t.field) == 0 {
+			return "struct {}"
+		}
+		fields := make([]string, len(t.field))
+		for i, f := range t.field {
+			fields[i] = goSyntaxType(f.typ)
+			if !f.embed {
+				fields[i] = f.name + " " + fields[i]
+			}
+			if f.tag != "" {
+				fields[i] += " " + strconv.Quote(f.tag)
+			}
+		}
+		return "struct { " + strings.Join(fields, "; ") + " }"
+	}
+	if rt := t.TypeOf(); rt != nil {
+		return rt.String()
+	}
+	return t.id()
+}
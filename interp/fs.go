@@ -0,0 +1,706 @@
+package interp
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// vfs is the file system of the interpreted code, set by the FSRoot and
+// FSOverlay options. The files of the root directory are never modified: the
+// files created or modified are stored in the overlay directory, if any, and
+// the removed ones are hidden. Within vfs, the paths of the interpreted
+// code, absolute or relative to the virtual working directory, are resolved
+// to virtual absolute paths, noted vp, and then to host paths in the overlay
+// or root directory.
+type vfs struct {
+	root    string              // host root directory
+	overlay string              // host overlay directory, or "" if read-only
+	getenv  func(string) string // environment of the interpreted code, or nil
+
+	mutex   sync.RWMutex
+	cwd     string          // virtual working directory
+	deleted map[string]bool // removed files of root, by virtual path
+}
+
+const sep = string(filepath.Separator)
+
+func newVFS(root, overlay string, getenv func(string) string) *vfs {
+	v := &vfs{getenv: getenv, cwd: sep, deleted: map[string]bool{}}
+	v.root, _ = filepath.Abs(root)
+	if overlay != "" {
+		v.overlay, _ = filepath.Abs(overlay)
+	}
+	return v
+}
+
+// symbols returns the functions of packages os, io/ioutil and path/filepath
+// operating on the file system, by package path.
+func (v *vfs) symbols() map[string]map[string]reflect.Value {
+	return map[string]map[string]reflect.Value{
+		"os": {
+			"Chdir":        reflect.ValueOf(v.chdir),
+			"Chmod":        reflect.ValueOf(v.chmod),
+			"Chown":        reflect.ValueOf(v.chown),
+			"Chtimes":      reflect.ValueOf(v.chtimes),
+			"Create":       reflect.ValueOf(v.create),
+			"CreateTemp":   reflect.ValueOf(v.tempFile),
+			"Getwd":        reflect.ValueOf(v.getwd),
+			"Lchown":       reflect.ValueOf(v.lchown),
+			"Link":         reflect.ValueOf(v.link),
+			"Lstat":        reflect.ValueOf(v.lstat),
+			"Mkdir":        reflect.ValueOf(v.mkdir),
+			"MkdirAll":     reflect.ValueOf(v.mkdirAll),
+			"MkdirTemp":    reflect.ValueOf(v.tempDir),
+			"Open":         reflect.ValueOf(v.open),
+			"OpenFile":     reflect.ValueOf(v.openFile),
+			"ReadFile":     reflect.ValueOf(v.readFile),
+			"Readlink":     reflect.ValueOf(v.readlink),
+			"Remove":       reflect.ValueOf(v.remove),
+			"RemoveAll":    reflect.ValueOf(v.removeAll),
+			"Rename":       reflect.ValueOf(v.rename),
+			"StartProcess": reflect.ValueOf(v.startProcess),
+			"Stat":         reflect.ValueOf(v.stat),
+			"Symlink":      reflect.ValueOf(v.symlink),
+			"TempDir":      reflect.ValueOf(v.tempDirName),
+			"Truncate":     reflect.ValueOf(v.truncate),
+			"WriteFile":    reflect.ValueOf(v.writeFile),
+		},
+		"io/ioutil": {
+			"ReadDir":   reflect.ValueOf(v.readDir),
+			"ReadFile":  reflect.ValueOf(v.readFile),
+			"TempDir":   reflect.ValueOf(v.tempDir),
+			"TempFile":  reflect.ValueOf(v.tempFile),
+			"WriteFile": reflect.ValueOf(v.writeFile),
+		},
+		"path/filepath": {
+			"Abs":          reflect.ValueOf(v.abs),
+			"EvalSymlinks": reflect.ValueOf(v.evalSymlinks),
+			"Glob":         reflect.ValueOf(v.glob),
+			"Walk":         reflect.ValueOf(v.walk),
+		},
+	}
+}
+
+// path returns the virtual absolute path of name. The host paths of the
+// overlay and root directories, as the names of the opened files, are
+// converted back to virtual paths.
+func (v *vfs) path(name string) string {
+	for _, dir := range []string{v.overlay, v.root} {
+		if dir != "" && strings.HasPrefix(name, dir+sep) {
+			name = name[len(dir):]
+			break
+		}
+	}
+	if !filepath.IsAbs(name) {
+		v.mutex.RLock()
+		name = filepath.Join(v.cwd, name)
+		v.mutex.RUnlock()
+	}
+	// Joined to the root, ".." elements can not go above it.
+	return filepath.Join(sep, name)
+}
+
+func (v *vfs) isDeleted(vp string) bool {
+	v.mutex.RLock()
+	defer v.mutex.RUnlock()
+	return v.deleted[vp]
+}
+
+func (v *vfs) setDeleted(vp string, deleted bool) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	if deleted {
+		v.deleted[vp] = true
+	} else {
+		delete(v.deleted, vp)
+	}
+}
+
+// inDir returns true if the host path h, resolved from its symbolic
+// links, is in the host directory dir. The last element of h is not resolved
+// if follow is false. The missing elements of h are ignored.
+func inDir(dir, h string, follow bool) bool {
+	d, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return false
+	}
+	if !follow && h != dir {
+		h = filepath.Dir(h)
+	}
+	for {
+		r, err := filepath.EvalSymlinks(h)
+		if err == nil {
+			return r == d || strings.HasPrefix(r, strings.TrimSuffix(d, sep)+sep)
+		}
+		if h == dir {
+			return false
+		}
+		h = filepath.Dir(h)
+	}
+}
+
+// find returns the host path of the file name, in the overlay if it exists
+// there, or else in root. The symbolic links of root leading outside of it
+// are rejected, the last element of name being followed if follow is true.
+func (v *vfs) find(op, name string, follow bool) (string, error) {
+	vp := v.path(name)
+	if v.overlay != "" {
+		// The overlay contains no symbolic links.
+		h := filepath.Join(v.overlay, vp)
+		if _, err := os.Lstat(h); err == nil {
+			return h, nil
+		}
+	}
+	if v.isDeleted(vp) {
+		return "", &os.PathError{Op: op, Path: name, Err: syscall.ENOENT}
+	}
+	h := filepath.Join(v.root, vp)
+	if !inDir(v.root, h, follow) {
+		return "", &os.PathError{Op: op, Path: name, Err: os.ErrPermission}
+	}
+	return h, nil
+}
+
+// writable returns the host path in the overlay of the file name, to create
+// or modify it. The missing parent directories are created in the overlay,
+// and an existing file of root is copied, with its content if data is true.
+func (v *vfs) writable(op, name string, data bool) (string, error) {
+	if v.overlay == "" {
+		return "", &os.PathError{Op: op, Path: name, Err: syscall.EROFS}
+	}
+	vp := v.path(name)
+	h := filepath.Join(v.overlay, vp)
+	if _, err := os.Lstat(h); err == nil {
+		return h, nil
+	}
+	if err := v.mkdirUp(op, name, filepath.Dir(vp)); err != nil {
+		return "", err
+	}
+	if v.isDeleted(vp) {
+		return h, nil
+	}
+	r := filepath.Join(v.root, vp)
+	if !inDir(v.root, r, true) {
+		return "", &os.PathError{Op: op, Path: name, Err: os.ErrPermission}
+	}
+	fi, err := os.Stat(r)
+	switch {
+	case err != nil:
+		return h, nil // A new file.
+	case fi.IsDir():
+		return h, virtualError(os.Mkdir(h, fi.Mode().Perm()), name)
+	case !fi.Mode().IsRegular():
+		return "", &os.PathError{Op: op, Path: name, Err: os.ErrPermission}
+	}
+	return h, virtualError(copyFile(h, r, fi.Mode().Perm(), data), name)
+}
+
+// copyFile creates the host file dst with the permissions perm, and the
+// content of the host file src if data is true.
+func copyFile(dst, src string, perm os.FileMode, data bool) error {
+	w, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if data {
+		var r *os.File
+		if r, err = os.Open(src); err == nil {
+			_, err = io.Copy(w, r)
+			r.Close()
+		}
+	}
+	if err1 := w.Close(); err == nil {
+		err = err1
+	}
+	return err
+}
+
+// mkdirUp creates in the overlay the directory of virtual path vdir and its
+// parents, which must exist in root, for the operation op on the file name.
+func (v *vfs) mkdirUp(op, name, vdir string) error {
+	h := filepath.Join(v.overlay, vdir)
+	if fi, err := os.Stat(h); err == nil {
+		if !fi.IsDir() {
+			return &os.PathError{Op: op, Path: name, Err: syscall.ENOTDIR}
+		}
+		return nil
+	}
+	if vdir != sep {
+		if err := v.mkdirUp(op, name, filepath.Dir(vdir)); err != nil {
+			return err
+		}
+	}
+	fi, err := v.stat(vdir)
+	if err != nil {
+		return &os.PathError{Op: op, Path: name, Err: syscall.ENOENT}
+	}
+	if !fi.IsDir() {
+		return &os.PathError{Op: op, Path: name, Err: syscall.ENOTDIR}
+	}
+	return virtualError(os.Mkdir(h, fi.Mode().Perm()), name)
+}
+
+// virtualError returns the error err of a file operation where the host path
+// is replaced by the path name of the interpreted code.
+func virtualError(err error, name string) error {
+	if e, ok := err.(*os.PathError); ok {
+		return &os.PathError{Op: e.Op, Path: name, Err: e.Err}
+	}
+	return err
+}
+
+func (v *vfs) open(name string) (*os.File, error) { return v.openFile(name, os.O_RDONLY, 0) }
+
+func (v *vfs) create(name string) (*os.File, error) {
+	return v.openFile(name, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
+}
+
+func (v *vfs) openFile(name string, flag int, perm os.FileMode) (*os.File, error) {
+	var h string
+	var err error
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_APPEND|os.O_CREATE|os.O_TRUNC) == 0 {
+		h, err = v.find("open", name, true)
+	} else {
+		h, err = v.writable("open", name, flag&os.O_TRUNC == 0)
+	}
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(h, flag, perm)
+	if err != nil {
+		return nil, virtualError(err, name)
+	}
+	if flag&os.O_CREATE != 0 {
+		v.setDeleted(v.path(name), false)
+	}
+	return f, nil
+}
+
+func (v *vfs) readFile(name string) ([]byte, error) {
+	f, err := v.open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ioutil.ReadAll(f)
+}
+
+func (v *vfs) writeFile(name string, data []byte, perm os.FileMode) error {
+	f, err := v.openFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if err1 := f.Close(); err == nil {
+		err = err1
+	}
+	return err
+}
+
+func (v *vfs) stat(name string) (os.FileInfo, error) {
+	h, err := v.find("stat", name, true)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := os.Stat(h)
+	return fi, virtualError(err, name)
+}
+
+func (v *vfs) lstat(name string) (os.FileInfo, error) {
+	h, err := v.find("lstat", name, false)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := os.Lstat(h)
+	return fi, virtualError(err, name)
+}
+
+func (v *vfs) readlink(name string) (string, error) {
+	h, err := v.find("readlink", name, false)
+	if err != nil {
+		return "", err
+	}
+	s, err := os.Readlink(h)
+	return s, virtualError(err, name)
+}
+
+// readDir returns the files of directory name, in the overlay and in root,
+// sorted by name.
+func (v *vfs) readDir(name string) ([]os.FileInfo, error) {
+	vp := v.path(name)
+	var list []os.FileInfo
+	seen := map[string]bool{}
+	found := false
+	for _, dir := range []string{v.overlay, v.root} {
+		if dir == "" || dir == v.root && v.isDeleted(vp) {
+			continue
+		}
+		h := filepath.Join(dir, vp)
+		if dir == v.root && !inDir(dir, h, true) {
+			return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrPermission}
+		}
+		infos, err := ioutil.ReadDir(h)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, virtualError(err, name)
+		}
+		found = true
+		for _, fi := range infos {
+			if seen[fi.Name()] || dir == v.root && v.isDeleted(filepath.Join(vp, fi.Name())) {
+				continue
+			}
+			seen[fi.Name()] = true
+			list = append(list, fi)
+		}
+	}
+	if !found {
+		return nil, &os.PathError{Op: "open", Path: name, Err: syscall.ENOENT}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name() < list[j].Name() })
+	return list, nil
+}
+
+func (v *vfs) readDirNames(name string) ([]string, error) {
+	list, err := v.readDir(name)
+	names := make([]string, len(list))
+	for i, fi := range list {
+		names[i] = fi.Name()
+	}
+	return names, err
+}
+
+func (v *vfs) mkdir(name string, perm os.FileMode) error {
+	if _, err := v.lstat(name); err == nil {
+		return &os.PathError{Op: "mkdir", Path: name, Err: syscall.EEXIST}
+	}
+	if v.overlay == "" {
+		return &os.PathError{Op: "mkdir", Path: name, Err: syscall.EROFS}
+	}
+	vp := v.path(name)
+	if err := v.mkdirUp("mkdir", name, filepath.Dir(vp)); err != nil {
+		return err
+	}
+	if err := os.Mkdir(filepath.Join(v.overlay, vp), perm); err != nil {
+		return virtualError(err, name)
+	}
+	v.setDeleted(vp, false)
+	return nil
+}
+
+func (v *vfs) mkdirAll(name string, perm os.FileMode) error {
+	if fi, err := v.stat(name); err == nil {
+		if !fi.IsDir() {
+			return &os.PathError{Op: "mkdir", Path: name, Err: syscall.ENOTDIR}
+		}
+		return nil
+	}
+	vp := v.path(name)
+	if parent := filepath.Dir(vp); parent != vp {
+		if err := v.mkdirAll(parent, perm); err != nil {
+			return err
+		}
+	}
+	return v.mkdir(vp, perm)
+}
+
+func (v *vfs) remove(name string) error {
+	fi, err := v.lstat(name)
+	if err != nil {
+		return &os.PathError{Op: "remove", Path: name, Err: err.(*os.PathError).Err}
+	}
+	if v.overlay == "" {
+		return &os.PathError{Op: "remove", Path: name, Err: syscall.EROFS}
+	}
+	vp := v.path(name)
+	if fi.IsDir() {
+		names, err := v.readDirNames(vp)
+		if err != nil {
+			return virtualError(err, name)
+		}
+		if len(names) > 0 {
+			return &os.PathError{Op: "remove", Path: name, Err: syscall.ENOTEMPTY}
+		}
+	}
+	if err := os.Remove(filepath.Join(v.overlay, vp)); err != nil && !os.IsNotExist(err) {
+		return virtualError(err, name)
+	}
+	v.setDeleted(vp, true)
+	return nil
+}
+
+func (v *vfs) removeAll(name string) error {
+	if _, err := v.lstat(name); err != nil {
+		return nil
+	}
+	if v.overlay == "" {
+		return &os.PathError{Op: "unlinkat", Path: name, Err: syscall.EROFS}
+	}
+	vp := v.path(name)
+	_ = v.walk(vp, func(p string, _ os.FileInfo, _ error) error {
+		v.setDeleted(p, true)
+		return nil
+	})
+	return virtualError(os.RemoveAll(filepath.Join(v.overlay, vp)), name)
+}
+
+func (v *vfs) rename(oldpath, newpath string) error {
+	linkError := func(err error) error {
+		if e, ok := err.(*os.PathError); ok {
+			err = e.Err
+		}
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: err}
+	}
+	if _, err := v.lstat(oldpath); err != nil {
+		return linkError(err)
+	}
+	if v.overlay == "" {
+		return linkError(syscall.EROFS)
+	}
+	vold, vnew := v.path(oldpath), v.path(newpath)
+	if vold == vnew {
+		return nil
+	}
+
+	// Copy the files of root to the overlay, to move them all.
+	var olds []string
+	err := v.walk(vold, func(p string, _ os.FileInfo, err error) error {
+		if err == nil {
+			olds = append(olds, p)
+			_, err = v.writable("rename", p, true)
+		}
+		return err
+	})
+	if err != nil {
+		return linkError(err)
+	}
+	if err := v.mkdirUp("rename", newpath, filepath.Dir(vnew)); err != nil {
+		return linkError(err)
+	}
+	if err := os.Rename(filepath.Join(v.overlay, vold), filepath.Join(v.overlay, vnew)); err != nil {
+		return linkError(err)
+	}
+	for _, p := range olds {
+		v.setDeleted(p, true)
+		v.setDeleted(vnew+strings.TrimPrefix(p, vold), false)
+	}
+	return nil
+}
+
+func (v *vfs) chmod(name string, mode os.FileMode) error {
+	h, err := v.writable("chmod", name, true)
+	if err != nil {
+		return err
+	}
+	return virtualError(os.Chmod(h, mode), name)
+}
+
+func (v *vfs) chtimes(name string, atime, mtime time.Time) error {
+	h, err := v.writable("chtimes", name, true)
+	if err != nil {
+		return err
+	}
+	return virtualError(os.Chtimes(h, atime, mtime), name)
+}
+
+func (v *vfs) truncate(name string, size int64) error {
+	h, err := v.writable("truncate", name, true)
+	if err != nil {
+		return err
+	}
+	return virtualError(os.Truncate(h, size), name)
+}
+
+// The following functions are rejected, as the interpreted code could access
+// the host file system.
+
+func (v *vfs) chown(name string, uid, gid int) error {
+	return &os.PathError{Op: "chown", Path: name, Err: os.ErrPermission}
+}
+
+func (v *vfs) lchown(name string, uid, gid int) error {
+	return &os.PathError{Op: "lchown", Path: name, Err: os.ErrPermission}
+}
+
+func (v *vfs) link(oldname, newname string) error {
+	return &os.LinkError{Op: "link", Old: oldname, New: newname, Err: os.ErrPermission}
+}
+
+func (v *vfs) symlink(oldname, newname string) error {
+	return &os.LinkError{Op: "symlink", Old: oldname, New: newname, Err: os.ErrPermission}
+}
+
+func (v *vfs) startProcess(name string, argv []string, attr *os.ProcAttr) (*os.Process, error) {
+	return nil, &os.PathError{Op: "fork/exec", Path: name, Err: os.ErrPermission}
+}
+
+func (v *vfs) chdir(dir string) error {
+	fi, err := v.stat(dir)
+	if err != nil {
+		return &os.PathError{Op: "chdir", Path: dir, Err: err.(*os.PathError).Err}
+	}
+	if !fi.IsDir() {
+		return &os.PathError{Op: "chdir", Path: dir, Err: syscall.ENOTDIR}
+	}
+	vp := v.path(dir)
+	v.mutex.Lock()
+	v.cwd = vp
+	v.mutex.Unlock()
+	return nil
+}
+
+func (v *vfs) getwd() (string, error) {
+	v.mutex.RLock()
+	defer v.mutex.RUnlock()
+	return v.cwd, nil
+}
+
+// tempDirName returns the virtual temporary directory: $TMPDIR in the Env
+// option, or /tmp.
+func (v *vfs) tempDirName() string {
+	if v.getenv != nil {
+		if dir := v.getenv("TMPDIR"); dir != "" {
+			return dir
+		}
+	}
+	return sep + "tmp"
+}
+
+func (v *vfs) tempFile(dir, pattern string) (*os.File, error) {
+	if dir == "" {
+		dir = v.tempDirName()
+	}
+	if v.overlay == "" {
+		return nil, &os.PathError{Op: "open", Path: dir, Err: syscall.EROFS}
+	}
+	vp := v.path(dir)
+	if err := v.mkdirUp("open", dir, vp); err != nil {
+		return nil, err
+	}
+	f, err := ioutil.TempFile(filepath.Join(v.overlay, vp), pattern)
+	return f, virtualError(err, filepath.Join(dir, pattern))
+}
+
+func (v *vfs) tempDir(dir, pattern string) (string, error) {
+	if dir == "" {
+		dir = v.tempDirName()
+	}
+	if v.overlay == "" {
+		return "", &os.PathError{Op: "mkdir", Path: dir, Err: syscall.EROFS}
+	}
+	vp := v.path(dir)
+	if err := v.mkdirUp("mkdir", dir, vp); err != nil {
+		return "", err
+	}
+	h, err := ioutil.TempDir(filepath.Join(v.overlay, vp), pattern)
+	if err != nil {
+		return "", virtualError(err, filepath.Join(dir, pattern))
+	}
+	return filepath.Join(dir, filepath.Base(h)), nil
+}
+
+func (v *vfs) abs(path string) (string, error) { return v.path(path), nil }
+
+// evalSymlinks returns the virtual absolute path of path, after the
+// evaluation of its symbolic links.
+func (v *vfs) evalSymlinks(path string) (string, error) {
+	h, err := v.find("lstat", path, true)
+	if err != nil {
+		return "", err
+	}
+	r, err := filepath.EvalSymlinks(h)
+	if err != nil {
+		return "", virtualError(err, path)
+	}
+	for _, dir := range []string{v.overlay, v.root} {
+		if d, err := filepath.EvalSymlinks(dir); dir != "" && err == nil && (r == d || strings.HasPrefix(r, d+sep)) {
+			return filepath.Join(sep, strings.TrimPrefix(r, d)), nil
+		}
+	}
+	return "", &os.PathError{Op: "lstat", Path: path, Err: os.ErrPermission}
+}
+
+// walk is filepath.Walk on the virtual file system.
+func (v *vfs) walk(root string, fn filepath.WalkFunc) error {
+	info, err := v.lstat(root)
+	if err != nil {
+		err = fn(root, nil, err)
+	} else {
+		err = v.walkDir(root, info, fn)
+	}
+	if err == filepath.SkipDir {
+		return nil
+	}
+	return err
+}
+
+func (v *vfs) walkDir(path string, info os.FileInfo, fn filepath.WalkFunc) error {
+	if !info.IsDir() {
+		return fn(path, info, nil)
+	}
+	names, err := v.readDirNames(path)
+	if err1 := fn(path, info, err); err != nil || err1 != nil {
+		return err1
+	}
+	for _, name := range names {
+		name = filepath.Join(path, name)
+		fi, err := v.lstat(name)
+		if err != nil {
+			if err = fn(name, fi, err); err != nil && err != filepath.SkipDir {
+				return err
+			}
+			continue
+		}
+		if err = v.walkDir(name, fi, fn); err != nil && (!fi.IsDir() || err != filepath.SkipDir) {
+			return err
+		}
+	}
+	return nil
+}
+
+// glob is filepath.Glob on the virtual file system.
+func (v *vfs) glob(pattern string) ([]string, error) {
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+	if !strings.ContainsAny(pattern, `*?[\`) {
+		if _, err := v.lstat(pattern); err != nil {
+			return nil, nil
+		}
+		return []string{pattern}, nil
+	}
+	dir, file := filepath.Split(pattern)
+	switch dir {
+	case "":
+		dir = "."
+	case sep:
+	default:
+		dir = dir[:len(dir)-1]
+	}
+	dirs := []string{dir}
+	if strings.ContainsAny(dir, `*?[\`) {
+		var err error
+		if dirs, err = v.glob(dir); err != nil {
+			return nil, err
+		}
+	}
+	var matches []string
+	for _, d := range dirs {
+		names, _ := v.readDirNames(d)
+		for _, name := range names {
+			if ok, _ := filepath.Match(file, name); ok {
+				matches = append(matches, filepath.Join(d, name))
+			}
+		}
+	}
+	return matches, nil
+}
@@ -0,0 +1,656 @@
+package interp
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// goTypesDiagnostics type checks with go/types the file at path, of package
+// pkgName, already checked by the interpreter with the diagnostics diags, and
+// returns the divergences: the errors found by only one of them, and the
+// package level declarations of which the types differ.
+func (interp *Interpreter) goTypesDiagnostics(path, pkgName string, root *node, diags []Diagnostic) []Diagnostic {
+	interp.mutex.RLock()
+	f := interp.files[path]
+	interp.mutex.RUnlock()
+
+	var errs []types.Error
+	conf := types.Config{
+		Importer: newGoTypesImporter(interp),
+		Error:    func(err error) { errs = append(errs, err.(types.Error)) },
+	}
+	info := &types.Info{Defs: map[*ast.Ident]types.Object{}}
+	_, _ = conf.Check(pkgName, interp.fset, []*ast.File{f}, info)
+
+	// The errors are compared by line. As the interpreter stops at its first
+	// error, the following errors of go/types are ignored, as well as the
+	// continuation lines of go/types multi-part errors.
+	var res []Diagnostic
+	first := token.Pos(f.End())
+	lines := map[int]bool{}
+	for _, d := range diags {
+		if d.Phase != CheckPhase || d.File != path || d.Line == 0 {
+			continue
+		}
+		lines[d.Line] = true
+		if pos := interp.fset.File(f.Pos()).LineStart(d.Line) + token.Pos(d.Column-1); pos < first {
+			first = pos
+		}
+	}
+	failed := map[ast.Decl]bool{}
+	goTypesLines := map[int]bool{}
+	for _, e := range errs {
+		if strings.HasPrefix(e.Msg, "\t") {
+			continue
+		}
+		p := interp.fset.Position(e.Pos)
+		goTypesLines[p.Line] = true
+		if decl := enclosingDecl(f, e.Pos); decl != nil {
+			failed[decl] = true
+		}
+		if lines[p.Line] || e.Pos > first {
+			continue
+		}
+		res = append(res, Diagnostic{
+			File: p.Filename, Line: p.Line, Column: p.Column, Severity: "warning",
+			Message: "error not found by the interpreter", Phase: GoTypesPhase, GoTypes: e.Msg,
+		})
+	}
+	for _, d := range diags {
+		if d.Phase != CheckPhase || d.File != path || d.Line == 0 || goTypesLines[d.Line] {
+			continue
+		}
+		res = append(res, Diagnostic{
+			File: d.File, Line: d.Line, Column: d.Column, Severity: "warning",
+			Message: "error not found by go/types", Phase: GoTypesPhase, Interp: d.Message,
+		})
+	}
+
+	// Compare the types of the package level declarations without error. The
+	// types of the interpreter may be incomplete if it failed.
+	sc := interp.scopes[path]
+	funcs := map[token.Pos]*node{}
+	for _, n := range root.child {
+		if n.kind == funcDecl {
+			funcs[n.pos] = n
+		}
+	}
+	conv := newGoTypesImporter(interp)
+	compare := func(id *ast.Ident, t *itype) {
+		obj := info.Defs[id]
+		if obj == nil || t == nil || id.Name == "_" {
+			return
+		}
+		want := goTypeString(obj.Type())
+		got := goTypeString(conv.typeOf(t))
+		if obj, ok := obj.(*types.TypeName); ok {
+			want = goTypeString(obj.Type().Underlying())
+			got = goTypeString(conv.typeOf(t).Underlying())
+		}
+		if got == want {
+			return
+		}
+		p := interp.fset.Position(id.Pos())
+		res = append(res, Diagnostic{
+			File: p.Filename, Line: p.Line, Column: p.Column, Severity: "warning",
+			Message: "type of " + id.Name + " differs from go/types", Phase: GoTypesPhase, Interp: got, GoTypes: want,
+		})
+	}
+	for _, decl := range f.Decls {
+		if failed[decl] || len(diags) > 0 {
+			continue
+		}
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if n := funcs[d.Pos()]; n != nil {
+				compare(d.Name, n.typ)
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					if sym := sc.sym[s.Name.Name]; sym != nil && sym.kind == typeSym {
+						compare(s.Name, sym.typ)
+					}
+				case *ast.ValueSpec:
+					for _, id := range s.Names {
+						if sym := sc.sym[id.Name]; sym != nil && (sym.kind == varSym || sym.kind == constSym) {
+							compare(id, sym.typ)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Line < res[j].Line || res[i].Line == res[j].Line && res[i].Column < res[j].Column
+	})
+	return res
+}
+
+// enclosingDecl returns the top level declaration of f which contains pos, or nil.
+func enclosingDecl(f *ast.File, pos token.Pos) ast.Decl {
+	for _, d := range f.Decls {
+		if d.Pos() <= pos && pos <= d.End() {
+			return d
+		}
+	}
+	return nil
+}
+
+// goTypesImporter imports for go/types the binary and source packages known
+// by the interpreter, and converts the types of the interpreter to go/types.
+type goTypesImporter struct {
+	interp *Interpreter
+	pkgs   map[string]*types.Package
+	named  map[interface{}]*types.Named // indexed by reflect.Type or "path.name"
+	loaded map[string]bool
+}
+
+func newGoTypesImporter(interp *Interpreter) *goTypesImporter {
+	return &goTypesImporter{
+		interp: interp,
+		pkgs:   map[string]*types.Package{},
+		named:  map[interface{}]*types.Named{},
+		loaded: map[string]bool{},
+	}
+}
+
+// Import implements types.Importer.
+func (imp *goTypesImporter) Import(path string) (*types.Package, error) {
+	imp.interp.mutex.RLock()
+	bin, src := imp.interp.binPkg[path], imp.interp.srcPkg[path]
+	imp.interp.mutex.RUnlock()
+
+	if path == "unsafe" && bin != nil {
+		return types.Unsafe, nil
+	}
+	pkg := imp.pkg(path, "")
+	if imp.loaded[path] {
+		return pkg, nil
+	}
+
+	switch {
+	case bin != nil:
+		for name, v := range bin {
+			if strings.HasPrefix(name, "_") {
+				continue // Interface wrapper.
+			}
+			if obj := imp.binObject(pkg, name, v); obj != nil {
+				pkg.Scope().Insert(obj)
+			}
+		}
+	case src != nil:
+		for name, sym := range src {
+			if !ast.IsExported(name) {
+				continue
+			}
+			if obj := imp.srcObject(pkg, name, sym); obj != nil {
+				pkg.Scope().Insert(obj)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("package %s not found", path)
+	}
+	imp.loaded[path] = true
+	pkg.MarkComplete()
+	return pkg, nil
+}
+
+// pkg returns the package of path, created with name if new.
+func (imp *goTypesImporter) pkg(path, name string) *types.Package {
+	if pkg := imp.pkgs[path]; pkg != nil {
+		return pkg
+	}
+	if name == "" {
+		if name = imp.interp.pkgNames[path]; name == "" {
+			name = identifier.FindString(path)
+		}
+	}
+	pkg := types.NewPackage(path, name)
+	imp.pkgs[path] = pkg
+	return pkg
+}
+
+// binObject returns the object of the binary symbol name of value v, as
+// exported by the extract command, or nil.
+func (imp *goTypesImporter) binObject(pkg *types.Package, name string, v reflect.Value) types.Object {
+	t := v.Type()
+	switch {
+	case t.Implements(constVal):
+		c := v.Interface().(constant.Value)
+		return types.NewConst(token.NoPos, pkg, name, untypedGoType(c.Kind()), c)
+	case v.CanAddr():
+		return types.NewVar(token.NoPos, pkg, name, imp.rtypeOf(t))
+	case t.Kind() == reflect.Func:
+		return types.NewFunc(token.NoPos, pkg, name, imp.rtypeOf(t).(*types.Signature))
+	case t.Kind() == reflect.Ptr && v.IsNil():
+		typ := imp.rtypeOf(t.Elem())
+		if named, ok := typ.(*types.Named); ok && named.Obj().Pkg() == pkg && named.Obj().Name() == name {
+			return named.Obj()
+		}
+		return types.NewTypeName(token.NoPos, pkg, name, typ) // Alias.
+	}
+	if c := constantOf(v); c != nil {
+		return types.NewConst(token.NoPos, pkg, name, imp.rtypeOf(t), c)
+	}
+	return types.NewVar(token.NoPos, pkg, name, imp.rtypeOf(t))
+}
+
+// srcObject returns the object of the source symbol name, or nil.
+func (imp *goTypesImporter) srcObject(pkg *types.Package, name string, sym *symbol) types.Object {
+	if sym.typ == nil {
+		return nil
+	}
+	switch sym.kind {
+	case constSym:
+		var c constant.Value
+		if sym.rval.IsValid() {
+			if c = constantOf(sym.rval); c == nil && sym.rval.Type().Implements(constVal) {
+				c = sym.rval.Interface().(constant.Value)
+			}
+		}
+		if c == nil {
+			return nil
+		}
+		return types.NewConst(token.NoPos, pkg, name, imp.typeOf(sym.typ), c)
+	case varSym:
+		return types.NewVar(token.NoPos, pkg, name, imp.typeOf(sym.typ))
+	case funcSym:
+		if sig, ok := imp.typeOf(sym.typ).(*types.Signature); ok {
+			return types.NewFunc(token.NoPos, pkg, name, sig)
+		}
+	case typeSym:
+		typ := imp.typeOf(sym.typ)
+		if named, ok := typ.(*types.Named); ok && named.Obj().Pkg() == pkg && named.Obj().Name() == name {
+			return named.Obj()
+		}
+		return types.NewTypeName(token.NoPos, pkg, name, typ) // Alias.
+	}
+	return nil
+}
+
+// constantOf returns the constant value of the basic value v, or nil.
+func constantOf(v reflect.Value) constant.Value {
+	switch v.Kind() {
+	case reflect.Bool:
+		return constant.MakeBool(v.Bool())
+	case reflect.String:
+		return constant.MakeString(v.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return constant.MakeInt64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return constant.MakeUint64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return constant.MakeFloat64(v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		return constant.BinaryOp(constant.MakeFloat64(real(c)), token.ADD, constant.MakeImag(constant.MakeFloat64(imag(c))))
+	}
+	return nil
+}
+
+// untypedGoType returns the untyped go/types type of the constants of kind k.
+func untypedGoType(k constant.Kind) types.Type {
+	switch k {
+	case constant.Bool:
+		return types.Typ[types.UntypedBool]
+	case constant.String:
+		return types.Typ[types.UntypedString]
+	case constant.Int:
+		return types.Typ[types.UntypedInt]
+	case constant.Float:
+		return types.Typ[types.UntypedFloat]
+	case constant.Complex:
+		return types.Typ[types.UntypedComplex]
+	}
+	return types.Typ[types.Invalid]
+}
+
+var basicKinds = map[reflect.Kind]types.BasicKind{
+	reflect.Bool:          types.Bool,
+	reflect.Int:           types.Int,
+	reflect.Int8:          types.Int8,
+	reflect.Int16:         types.Int16,
+	reflect.Int32:         types.Int32,
+	reflect.Int64:         types.Int64,
+	reflect.Uint:          types.Uint,
+	reflect.Uint8:         types.Uint8,
+	reflect.Uint16:        types.Uint16,
+	reflect.Uint32:        types.Uint32,
+	reflect.Uint64:        types.Uint64,
+	reflect.Uintptr:       types.Uintptr,
+	reflect.Float32:       types.Float32,
+	reflect.Float64:       types.Float64,
+	reflect.Complex64:     types.Complex64,
+	reflect.Complex128:    types.Complex128,
+	reflect.String:        types.String,
+	reflect.UnsafePointer: types.UnsafePointer,
+}
+
+// rtypeOf returns the go/types type of the runtime type t.
+func (imp *goTypesImporter) rtypeOf(t reflect.Type) types.Type {
+	if t.Name() == "" || t.PkgPath() == "" && t != errorType {
+		return imp.rtypeLit(t)
+	}
+	if t == errorType {
+		return types.Universe.Lookup("error").Type()
+	}
+	if named := imp.named[t]; named != nil {
+		return named
+	}
+	obj := types.NewTypeName(token.NoPos, imp.pkg(t.PkgPath(), ""), t.Name(), nil)
+	named := types.NewNamed(obj, nil, nil)
+	imp.named[t] = named
+	named.SetUnderlying(imp.rtypeLit(t).Underlying())
+	if t.Kind() != reflect.Interface {
+		for _, rt := range []reflect.Type{t, reflect.PtrTo(t)} {
+			for i := 0; i < rt.NumMethod(); i++ {
+				m := rt.Method(i)
+				if rt != t {
+					if _, ok := t.MethodByName(m.Name); ok {
+						continue // Method of the value receiver.
+					}
+				}
+				recv := types.NewVar(token.NoPos, obj.Pkg(), "", imp.rtypeOf(rt))
+				sig := imp.signature(recv, m.Type, 1)
+				named.AddMethod(types.NewFunc(token.NoPos, obj.Pkg(), m.Name, sig))
+			}
+		}
+	}
+	return named
+}
+
+// rtypeLit returns the go/types type of the structure of runtime type t.
+func (imp *goTypesImporter) rtypeLit(t reflect.Type) types.Type {
+	switch t.Kind() {
+	case reflect.Array:
+		return types.NewArray(imp.rtypeOf(t.Elem()), int64(t.Len()))
+	case reflect.Chan:
+		dir := types.SendRecv
+		switch t.ChanDir() {
+		case reflect.RecvDir:
+			dir = types.RecvOnly
+		case reflect.SendDir:
+			dir = types.SendOnly
+		}
+		return types.NewChan(dir, imp.rtypeOf(t.Elem()))
+	case reflect.Func:
+		return imp.signature(nil, t, 0)
+	case reflect.Interface:
+		methods := make([]*types.Func, t.NumMethod())
+		for i := range methods {
+			m := t.Method(i)
+			pkg := imp.pkg(t.PkgPath(), "")
+			if m.PkgPath != "" {
+				pkg = imp.pkg(m.PkgPath, "")
+			}
+			methods[i] = types.NewFunc(token.NoPos, pkg, m.Name, imp.signature(nil, m.Type, 0))
+		}
+		return types.NewInterface(methods, nil).Complete()
+	case reflect.Map:
+		return types.NewMap(imp.rtypeOf(t.Key()), imp.rtypeOf(t.Elem()))
+	case reflect.Ptr:
+		return types.NewPointer(imp.rtypeOf(t.Elem()))
+	case reflect.Slice:
+		return types.NewSlice(imp.rtypeOf(t.Elem()))
+	case reflect.Struct:
+		fields := make([]*types.Var, t.NumField())
+		tags := make([]string, t.NumField())
+		for i := range fields {
+			f := t.Field(i)
+			pkg := imp.pkg(t.PkgPath(), "")
+			if f.PkgPath != "" {
+				pkg = imp.pkg(f.PkgPath, "")
+			}
+			fields[i] = types.NewField(token.NoPos, pkg, f.Name, imp.rtypeOf(f.Type), f.Anonymous)
+			tags[i] = string(f.Tag)
+		}
+		return types.NewStruct(fields, tags)
+	}
+	if k, ok := basicKinds[t.Kind()]; ok {
+		return types.Typ[k]
+	}
+	return types.Typ[types.Invalid]
+}
+
+// signature returns the go/types signature of the runtime function type t,
+// of which the skip first parameters are ignored.
+func (imp *goTypesImporter) signature(recv *types.Var, t reflect.Type, skip int) *types.Signature {
+	params := make([]*types.Var, t.NumIn()-skip)
+	for i := range params {
+		params[i] = types.NewParam(token.NoPos, nil, "", imp.rtypeOf(t.In(i+skip)))
+	}
+	results := make([]*types.Var, t.NumOut())
+	for i := range results {
+		results[i] = types.NewParam(token.NoPos, nil, "", imp.rtypeOf(t.Out(i)))
+	}
+	return types.NewSignature(recv, types.NewTuple(params...), types.NewTuple(results...), t.IsVariadic())
+}
+
+// typeOf returns the go/types type of the interpreter type t.
+func (imp *goTypesImporter) typeOf(t *itype) types.Type {
+	switch {
+	case t.cat == valueT:
+		return imp.rtypeOf(t.rtype)
+	case t.cat == errorT:
+		return types.Universe.Lookup("error").Type()
+	case t.untyped:
+		switch t.cat {
+		case boolT:
+			return types.Typ[types.UntypedBool]
+		case stringT:
+			return types.Typ[types.UntypedString]
+		case int32T:
+			return types.Typ[types.UntypedRune]
+		case float32T, float64T:
+			return types.Typ[types.UntypedFloat]
+		case complex64T, complex128T:
+			return types.Typ[types.UntypedComplex]
+		case nilT:
+			return types.Typ[types.UntypedNil]
+		}
+		return types.Typ[types.UntypedInt]
+	case t.name == "" || t.path == "":
+		return imp.typeLit(t)
+	}
+	key := t.path + "." + t.name
+	if named := imp.named[key]; named != nil {
+		return named
+	}
+	obj := types.NewTypeName(token.NoPos, imp.pkg(t.path, ""), t.name, nil)
+	named := types.NewNamed(obj, nil, nil)
+	imp.named[key] = named
+	named.SetUnderlying(imp.typeLit(t).Underlying())
+	for _, m := range t.method {
+		if m.typ == nil || len(m.child) == 0 || len(m.child[0].child) == 0 {
+			continue
+		}
+		recv := types.NewVar(token.NoPos, obj.Pkg(), "", named)
+		if m.child[0].child[0].lastChild().kind == starExpr {
+			recv = types.NewVar(token.NoPos, obj.Pkg(), "", types.NewPointer(named))
+		}
+		sig := imp.typeLit(m.typ).(*types.Signature)
+		sig = types.NewSignature(recv, sig.Params(), sig.Results(), sig.Variadic())
+		named.AddMethod(types.NewFunc(token.NoPos, obj.Pkg(), m.child[1].ident, sig))
+	}
+	return named
+}
+
+var basicCats = map[tcat]types.BasicKind{
+	boolT:       types.Bool,
+	intT:        types.Int,
+	int8T:       types.Int8,
+	int16T:      types.Int16,
+	int32T:      types.Int32,
+	int64T:      types.Int64,
+	uintT:       types.Uint,
+	uint8T:      types.Uint8,
+	uint16T:     types.Uint16,
+	uint32T:     types.Uint32,
+	uint64T:     types.Uint64,
+	uintptrT:    types.Uintptr,
+	float32T:    types.Float32,
+	float64T:    types.Float64,
+	complex64T:  types.Complex64,
+	complex128T: types.Complex128,
+	stringT:     types.String,
+}
+
+// typeLit returns the go/types type of the structure of interpreter type t.
+func (imp *goTypesImporter) typeLit(t *itype) types.Type {
+	switch t.cat {
+	case aliasT:
+		return imp.typeOf(t.val)
+	case arrayT:
+		if t.sizedef {
+			return types.NewArray(imp.typeOf(t.val), int64(t.size))
+		}
+		return types.NewSlice(imp.typeOf(t.val))
+	case chanT:
+		return types.NewChan(types.SendRecv, imp.typeOf(t.val))
+	case chanRecvT:
+		return types.NewChan(types.RecvOnly, imp.typeOf(t.val))
+	case chanSendT:
+		return types.NewChan(types.SendOnly, imp.typeOf(t.val))
+	case errorT, valueT:
+		return imp.typeOf(t)
+	case funcT:
+		params := make([]*types.Var, len(t.arg))
+		variadic := false
+		for i, a := range t.arg {
+			typ := imp.typeOf(a)
+			if a.cat == variadicT {
+				typ, variadic = types.NewSlice(imp.typeOf(a.val)), true
+			}
+			params[i] = types.NewParam(token.NoPos, nil, "", typ)
+		}
+		results := make([]*types.Var, len(t.ret))
+		for i, r := range t.ret {
+			results[i] = types.NewParam(token.NoPos, nil, "", imp.typeOf(r))
+		}
+		return types.NewSignature(nil, types.NewTuple(params...), types.NewTuple(results...), variadic)
+	case interfaceT:
+		var methods []*types.Func
+		var embedded []types.Type
+		for _, f := range t.field {
+			if f.embed {
+				embedded = append(embedded, imp.typeOf(f.typ))
+				continue
+			}
+			if sig, ok := imp.typeOf(f.typ).(*types.Signature); ok {
+				methods = append(methods, types.NewFunc(token.NoPos, imp.pkg(t.path, ""), f.name, sig))
+			}
+		}
+		return types.NewInterfaceType(methods, embedded).Complete()
+	case mapT:
+		return types.NewMap(imp.typeOf(t.key), imp.typeOf(t.val))
+	case nilT:
+		return types.Typ[types.UntypedNil]
+	case ptrT:
+		return types.NewPointer(imp.typeOf(t.val))
+	case structT:
+		fields := make([]*types.Var, len(t.field))
+		tags := make([]string, len(t.field))
+		for i, f := range t.field {
+			fields[i] = types.NewField(token.NoPos, imp.pkg(t.path, ""), f.name, imp.typeOf(f.typ), f.embed)
+			tags[i] = f.tag
+		}
+		return types.NewStruct(fields, tags)
+	case variadicT:
+		return types.NewSlice(imp.typeOf(t.val))
+	}
+	if k, ok := basicCats[t.cat]; ok {
+		return types.Typ[k]
+	}
+	return types.Typ[types.Invalid]
+}
+
+// goTypeString returns the string of type t, with the named types qualified
+// by their package path, and without parameter names, so the types converted
+// from the interpreter and the ones of go/types can be compared.
+func goTypeString(t types.Type) string {
+	switch t := unalias(t).(type) {
+	case *types.Basic:
+		if t.Info()&types.IsUntyped != 0 {
+			return t.Name()
+		}
+		return types.Typ[t.Kind()].Name()
+	case *types.Named:
+		if t.Obj().Pkg() == nil {
+			return t.Obj().Name()
+		}
+		return t.Obj().Pkg().Path() + "." + t.Obj().Name()
+	case *types.Pointer:
+		return "*" + goTypeString(t.Elem())
+	case *types.Slice:
+		return "[]" + goTypeString(t.Elem())
+	case *types.Array:
+		return "[" + strconv.FormatInt(t.Len(), 10) + "]" + goTypeString(t.Elem())
+	case *types.Map:
+		return "map[" + goTypeString(t.Key()) + "]" + goTypeString(t.Elem())
+	case *types.Chan:
+		switch t.Dir() {
+		case types.RecvOnly:
+			return "<-chan " + goTypeString(t.Elem())
+		case types.SendOnly:
+			return "chan<- " + goTypeString(t.Elem())
+		}
+		return "chan " + goTypeString(t.Elem())
+	case *types.Signature:
+		return "func" + signatureString(t)
+	case *types.Struct:
+		fields := make([]string, t.NumFields())
+		for i := range fields {
+			f := t.Field(i)
+			if fields[i] = goTypeString(f.Type()); !f.Embedded() {
+				fields[i] = f.Name() + " " + fields[i]
+			}
+			if tag := t.Tag(i); tag != "" {
+				fields[i] += " " + strconv.Quote(tag)
+			}
+		}
+		return "struct{" + strings.Join(fields, "; ") + "}"
+	case *types.Interface:
+		methods := make([]string, t.NumMethods())
+		for i := range methods {
+			m := t.Method(i)
+			methods[i] = m.Name() + signatureString(m.Type().(*types.Signature))
+		}
+		sort.Strings(methods)
+		return "interface{" + strings.Join(methods, "; ") + "}"
+	}
+	return t.String()
+}
+
+// signatureString returns the parameters and results of signature s.
+func signatureString(s *types.Signature) string {
+	tuple := func(t *types.Tuple, variadic bool) string {
+		l := make([]string, t.Len())
+		for i := range l {
+			if variadic && i == len(l)-1 {
+				l[i] = "..." + goTypeString(t.At(i).Type().(*types.Slice).Elem())
+				continue
+			}
+			l[i] = goTypeString(t.At(i).Type())
+		}
+		return strings.Join(l, ", ")
+	}
+	res := "(" + tuple(s.Params(), s.Variadic()) + ")"
+	switch s.Results().Len() {
+	case 0:
+	case 1:
+		res += " " + tuple(s.Results(), false)
+	default:
+		res += " (" + tuple(s.Results(), false) + ")"
+	}
+	return res
+}
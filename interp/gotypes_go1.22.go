@@ -0,0 +1,9 @@
+//go:build go1.22
+// +build go1.22
+
+package interp
+
+import "go/types"
+
+// unalias returns the type denoted by the alias type t, since go1.22.
+func unalias(t types.Type) types.Type { return types.Unalias(t) }
@@ -0,0 +1,9 @@
+//go:build !go1.22
+// +build !go1.22
+
+package interp
+
+import "go/types"
+
+// unalias returns t, as go/types does not represent alias types before go1.22.
+func unalias(t types.Type) types.Type { return t }
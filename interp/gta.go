@@ -1,8 +1,10 @@
 package interp
 
 import (
+	"fmt"
 	"path/filepath"
 	"reflect"
+	"strings"
 )
 
 // gta performs a global types analysis on the AST, registering types,
@@ -17,8 +19,8 @@ func (interp *Interpreter) gta(root *node, rpath, pkgID string) ([]*node, error)
 	baseName := filepath.Base(interp.fset.Position(root.pos).Filename)
 
 	root.Walk(func(n *node) bool {
-		if err != nil {
-			return false
+		if err != nil || isScopeStmt(n) {
+			return false // skip statement with its own scope, including its init statement
 		}
 		switch n.kind {
 		case constDecl:
@@ -48,11 +50,19 @@ func (interp *Interpreter) gta(root *node, rpath, pkgID string) ([]*node, error)
 				sbase = len(n.child) - n.nright
 			}
 
+			if n.anc.kind == constDecl {
+				sc.iota = childPos(n)
+			}
 			for i := 0; i < n.nleft; i++ {
 				dest, src := n.child[i], n.child[sbase+i]
 				val := reflect.ValueOf(sc.iota)
 				if n.anc.kind == constDecl {
 					if _, err2 := interp.cfg(n, pkgID); err2 != nil {
+						if isConstReady(sc, src) {
+							// All dependencies are known, the constant expression is invalid.
+							err = err2
+							return false
+						}
 						// Constant value can not be computed yet.
 						// Come back when child dependencies are known.
 						revisit = append(revisit, n)
@@ -78,8 +88,21 @@ func (interp *Interpreter) gta(root *node, rpath, pkgID string) ([]*node, error)
 				if typ.isBinMethod {
 					typ = &itype{cat: valueT, rtype: typ.methodCallType(), isBinMethod: true, scope: sc}
 				}
-				if sc.sym[dest.ident] == nil || sc.sym[dest.ident].typ.incomplete {
+				sym := sc.sym[dest.ident]
+				if dest.ident != "_" && sym != nil && sym.node != nil && sym.node != n {
+					if isShortDefine(n) && !interp.isRedefinition(sym.node, n) && sym.kind == varSym {
+						// The existing variable of a short variable declaration is assigned.
+						continue
+					}
+					if err = interp.checkRedeclared(sc, dest, n, baseName); err != nil {
+						return false
+					}
+					sym = nil // Redefinition of a previous evaluation.
+				}
+				if sym == nil || sym.typ.incomplete {
 					sc.sym[dest.ident] = &symbol{kind: varSym, global: true, index: sc.add(typ), typ: typ, rval: val, node: n}
+				} else {
+					sym.node = n
 				}
 				if n.anc.kind == constDecl {
 					sc.sym[dest.ident].kind = constSym
@@ -93,6 +116,13 @@ func (interp *Interpreter) gta(root *node, rpath, pkgID string) ([]*node, error)
 			return false
 
 		case defineXStmt:
+			if !isShortDefine(n) {
+				for _, c := range n.child[:n.nleft] {
+					if err = interp.checkRedeclared(sc, c, n, baseName); err != nil {
+						return false
+					}
+				}
+			}
 			err = compDefineX(sc, n)
 
 		case valueSpec:
@@ -108,30 +138,12 @@ func (interp *Interpreter) gta(root *node, rpath, pkgID string) ([]*node, error)
 				}
 			}
 			for _, c := range n.child[:l] {
-				asImportName := filepath.Join(c.ident, baseName)
-				sym1, exists1 := sc.sym[asImportName]
-				sym2, exists2 := sc.sym[c.ident]
-				if !exists1 && !exists2 {
-					sc.sym[c.ident] = &symbol{index: sc.add(n.typ), kind: varSym, global: true, typ: n.typ, node: n}
-					continue
-				}
-
-				var sym *symbol
-				if exists1 {
-					// prev declaration is an import statement
-					sym = sym1
-				} else {
-					// prev declaration is whatever else (var, type, etc)
-					sym = sym2
-				}
-				// redeclaration error
-				if sym.typ.node != nil && sym.typ.node.anc != nil {
-					prevDecl := n.interp.fset.Position(sym.typ.node.anc.pos)
-					err = n.cfgErrorf("%s redeclared in this block\n\tprevious declaration at %v", c.ident, prevDecl)
+				if err = interp.checkRedeclared(sc, c, n, baseName); err != nil {
 					return false
 				}
-				err = n.cfgErrorf("%s redeclared in this block", c.ident)
-				return false
+				if sym, ok := sc.sym[c.ident]; !ok || sym.node != n || c.ident == "_" {
+					sc.sym[c.ident] = &symbol{index: sc.add(n.typ), kind: varSym, global: true, typ: n.typ, node: n}
+				}
 			}
 
 		case funcDecl:
@@ -141,12 +153,14 @@ func (interp *Interpreter) gta(root *node, rpath, pkgID string) ([]*node, error)
 			ident := n.child[1].ident
 			switch {
 			case isMethod(n):
-				// TODO(mpl): redeclaration detection
 				// Add a method symbol in the receiver type name space
 				var rcvrtype *itype
 				n.ident = ident
 				rcvr := n.child[0].child[0]
 				rtn := rcvr.lastChild()
+				if err = interp.checkRecvName(sc, rtn); err != nil {
+					return false
+				}
 				typeName := rtn.ident
 				if typeName == "" {
 					// The receiver is a pointer, retrieve typeName from indirection
@@ -158,6 +172,9 @@ func (interp *Interpreter) gta(root *node, rpath, pkgID string) ([]*node, error)
 						elementType = sc.sym[typeName].typ
 					}
 					rcvrtype = &itype{cat: ptrT, val: elementType, incomplete: elementType.incomplete, node: rtn, scope: sc}
+					if err = checkMethod(n, rtn, rcvrtype, typeName); err != nil {
+						return false
+					}
 					elementType.method = append(elementType.method, n)
 				} else {
 					rcvrtype = sc.getType(typeName)
@@ -166,34 +183,21 @@ func (interp *Interpreter) gta(root *node, rpath, pkgID string) ([]*node, error)
 						sc.sym[typeName] = &symbol{kind: typeSym, typ: &itype{name: typeName, path: rpath, incomplete: true, node: rtn, scope: sc}}
 						rcvrtype = sc.sym[typeName].typ
 					}
+					if err = checkMethod(n, rtn, rcvrtype, typeName); err != nil {
+						return false
+					}
 				}
 				rcvrtype.method = append(rcvrtype.method, n)
 				n.child[0].child[0].lastChild().typ = rcvrtype
 			case ident == "init":
 				// TODO(mpl): use constant instead of hardcoded string?
 				// init functions do not get declared as per the Go spec.
+			case ident == "_":
+				// Blank functions are compiled but not declared.
 			default:
-				asImportName := filepath.Join(ident, baseName)
-				if _, exists := sc.sym[asImportName]; exists {
-					// redeclaration error
-					// TODO(mpl): improve error with position of previous declaration.
-					err = n.cfgErrorf("%s redeclared in this block", ident)
+				if err = interp.checkRedeclared(sc, n.child[1], n, baseName); err != nil {
 					return false
 				}
-				sym, exists := sc.sym[ident]
-				if exists {
-					// Make sure the symbol we found seems to be about another node, before calling
-					// it a redeclaration.
-					if sym.typ.isComplete() {
-						// TODO(mpl): this check might be too permissive?
-						if sym.kind != funcSym || sym.typ.cat != n.typ.cat || sym.node != n || sym.index != -1 {
-							// redeclaration error
-							// TODO(mpl): improve error with position of previous declaration.
-							err = n.cfgErrorf("%s redeclared in this block", ident)
-							return false
-						}
-					}
-				}
 				// Add a function symbol in the package name space except for init
 				sc.sym[n.child[1].ident] = &symbol{kind: funcSym, typ: n.typ, node: n, index: -1}
 			}
@@ -212,6 +216,10 @@ func (interp *Interpreter) gta(root *node, rpath, pkgID string) ([]*node, error)
 			}
 			// Try to import a binary package first, or a source package
 			var pkgName string
+			if interp.binPkg[ipath] != nil && interp.srcPkg[ipath] != nil {
+				err = n.cfgErrorf("import %q error: binary package conflicts with source package of same path", ipath)
+				return false
+			}
 			if interp.binPkg[ipath] != nil {
 				switch name {
 				case "_": // no import of symbols
@@ -242,7 +250,7 @@ func (interp *Interpreter) gta(root *node, rpath, pkgID string) ([]*node, error)
 					err = n.cfgErrorf("%s redeclared in this block", name)
 					return false
 				}
-			} else if pkgName, err = interp.importSrc(rpath, ipath); err == nil {
+			} else if pkgName, err = interp.importSrc(rpath, ipath, true); err == nil {
 				sc.types = interp.universe.types
 				switch name {
 				case "_": // no import of symbols
@@ -264,6 +272,18 @@ func (interp *Interpreter) gta(root *node, rpath, pkgID string) ([]*node, error)
 				err = n.cfgErrorf("import %q error: %v", ipath, err)
 			}
 
+		case typeSpecAssign:
+			// An alias declaration denotes the same type as the aliased one.
+			typeName := n.child[0].ident
+			if n.typ, err = nodeType(interp, sc, n.child[1]); err != nil {
+				return false
+			}
+			sc.sym[typeName] = &symbol{kind: typeSym, typ: n.typ}
+			if !n.typ.isComplete() {
+				revisit = append(revisit, n)
+			}
+			return false
+
 		case typeSpec:
 			typeName := n.child[0].ident
 			var typ *itype
@@ -280,11 +300,7 @@ func (interp *Interpreter) gta(root *node, rpath, pkgID string) ([]*node, error)
 				n.typ.path = rpath
 			}
 
-			asImportName := filepath.Join(typeName, baseName)
-			if _, exists := sc.sym[asImportName]; exists {
-				// redeclaration error
-				// TODO(mpl): improve error with position of previous declaration.
-				err = n.cfgErrorf("%s redeclared in this block", typeName)
+			if err = interp.checkRedeclared(sc, n.child[0], n, baseName); err != nil {
 				return false
 			}
 			sym, exists := sc.sym[typeName]
@@ -295,14 +311,16 @@ func (interp *Interpreter) gta(root *node, rpath, pkgID string) ([]*node, error)
 					// Type has already been seen as a receiver in a method function
 					n.typ.method = append(n.typ.method, sym.typ.method...)
 				} else {
-					// TODO(mpl): figure out how to detect redeclarations without breaking type aliases.
-					// Allow redeclarations for now.
 					sc.sym[typeName] = &symbol{kind: typeSym}
 				}
 			}
 			sc.sym[typeName].typ = n.typ
+			sc.sym[typeName].node = n
 			if !n.typ.isComplete() {
 				revisit = append(revisit, n)
+			} else if n.typ.hasValueCycle() {
+				delete(sc.sym, typeName)
+				err = n.cfgErrorf("invalid recursive type %s", typeName)
 			}
 			return false
 		}
@@ -336,11 +354,169 @@ func (interp *Interpreter) gtaRetry(nodes []*node, rpath, pkgID string) error {
 	}
 
 	if len(revisit) > 0 {
-		return revisit[0].cfgErrorf("constant definition loop")
+		return interp.loopError(interp.scopes[pkgID], revisit)
 	}
 	return nil
 }
 
+// loopError returns the error of the global declarations nodes, which can
+// not be resolved as they depend on each other. The members of the first
+// dependency loop are reported with their positions. If there is no loop,
+// the first identifier not declared in the package scope sc is reported.
+func (interp *Interpreter) loopError(sc *scope, nodes []*node) error {
+	type decl struct {
+		id    *node   // declared identifier
+		exprs []*node // type and value expressions
+	}
+	var decls []*decl
+	declOf := map[string]*decl{}
+	add := func(id *node, exprs ...*node) {
+		if id.ident == "_" {
+			return
+		}
+		d := &decl{id: id, exprs: exprs}
+		decls = append(decls, d)
+		declOf[id.ident] = d
+	}
+	for _, n := range nodes {
+		switch n.kind {
+		case defineStmt:
+			sbase := len(n.child) - n.nright
+			for i := 0; i < n.nleft; i++ {
+				exprs := append([]*node{}, n.child[n.nleft:sbase]...)
+				if n.nright > 0 {
+					exprs = append(exprs, n.child[sbase+i])
+				}
+				add(n.child[i], exprs...)
+			}
+		case valueSpec:
+			l := len(n.child) - 1
+			for _, c := range n.child[:l] {
+				add(c, n.child[l])
+			}
+		case typeSpec:
+			add(n.child[0], n.child[1])
+		case funcDecl:
+			add(n.child[1], n.child[2])
+		}
+	}
+
+	if len(decls) == 0 {
+		return interp.unresolvedError(sc, nodes)
+	}
+
+	// refs returns the first declaration referred to by d, or nil.
+	refs := func(d *decl) (ref *decl) {
+		for _, e := range d.exprs {
+			e.Walk(func(n *node) bool {
+				switch {
+				case ref != nil:
+				case n.kind == selectorExpr:
+					// Only the operand of a selector may refer to a declaration.
+					n = n.child[0]
+					fallthrough
+				case n.kind == identExpr:
+					ref = declOf[n.ident]
+				default:
+					return true
+				}
+				return false
+			}, nil)
+		}
+		return ref
+	}
+
+	// Follow the references from the first declaration, until one is repeated.
+	var path, loop []*decl
+	seen := map[*decl]int{}
+	for d := decls[0]; d != nil && loop == nil; d = refs(d) {
+		if i, ok := seen[d]; ok {
+			loop = path[i:]
+		}
+		seen[d] = len(path)
+		path = append(path, d)
+	}
+	if loop == nil {
+		return interp.unresolvedError(sc, nodes)
+	}
+
+	msg := "constant definition loop"
+	lines := make([]string, len(loop))
+	for i, d := range loop {
+		switch a := d.id.anc; {
+		case a.kind == typeSpec:
+			msg = "invalid cycle in declaration of " + loop[0].id.ident
+		case a.anc.kind != constDecl && !strings.HasPrefix(msg, "invalid"):
+			msg = "variable definition loop"
+		}
+		to := loop[(i+1)%len(loop)].id.ident
+		if len(loop) == 1 {
+			to = "itself"
+		}
+		lines[i] = fmt.Sprintf("%v: %s refers to %s", interp.fset.Position(d.id.pos), d.id.ident, to)
+	}
+	return loop[0].id.cfgErrorf("%s\n\t%s", msg, strings.Join(lines, "\n\t"))
+}
+
+// unresolvedError returns the error of the global declarations nodes, which
+// can not be resolved without depending on each other. The first identifier
+// of the declarations which is not declared in the package scope sc is
+// reported as undefined.
+func (interp *Interpreter) unresolvedError(sc *scope, nodes []*node) error {
+	var id *node
+	var walk func(n *node) bool
+	walk = func(n *node) bool {
+		switch {
+		case id != nil:
+		case n.kind == funcLit:
+			// The function body has its own scope, only its type is resolved by gta.
+			n.child[2].Walk(walk, nil)
+		case n.kind == fieldExpr:
+			// Field and parameter names are not package level identifiers.
+			n.lastChild().Walk(walk, nil)
+		case n.kind == keyValueExpr:
+			// The key of a struct literal is a field name.
+			n.child[1].Walk(walk, nil)
+		case n.kind == selectorExpr:
+			if c := n.child[0]; c.kind == identExpr {
+				baseName := filepath.Base(interp.fset.Position(c.pos).Filename)
+				if _, ok := sc.sym[filepath.Join(c.ident, baseName)]; ok {
+					return false // Package selector.
+				}
+			}
+			n.child[0].Walk(walk, nil)
+		case n.kind == identExpr:
+			// A placeholder for a forward reference is an incomplete type symbol
+			// without declaration.
+			sym, _, ok := sc.lookup(n.ident)
+			if n.ident != "_" && (!ok || sym.kind == typeSym && sym.node == nil && sym.typ.incomplete) {
+				id = n
+			}
+		default:
+			return true
+		}
+		return false
+	}
+
+	for _, n := range nodes {
+		switch n.kind {
+		case defineStmt:
+			for _, c := range n.child[n.nleft:] {
+				c.Walk(walk, nil)
+			}
+		case valueSpec, typeSpec, typeSpecAssign:
+			n.lastChild().Walk(walk, nil)
+		case funcDecl:
+			n.child[0].Walk(walk, nil)
+			n.child[2].Walk(walk, nil)
+		}
+		if id != nil {
+			return id.cfgErrorf("undefined: %s", id.ident)
+		}
+	}
+	return nodes[0].cfgErrorf("constant definition loop")
+}
+
 // equalNodes returns true if two slices of nodes are identical.
 func equalNodes(a, b []*node) bool {
 	if len(a) != len(b) {
@@ -353,3 +529,82 @@ func equalNodes(a, b []*node) bool {
 	}
 	return true
 }
+
+// checkRecvName checks the receiver type expression n of a method, which
+// must be T or *T, where T is the name of a type defined in the package.
+func (interp *Interpreter) checkRecvName(sc *scope, n *node) error {
+	base := n
+	if base.kind == starExpr {
+		base = base.child[0]
+	}
+	switch base.kind {
+	case identExpr:
+		if _, ok := sc.sym[base.ident]; !ok {
+			if sym, ok := interp.universe.sym[base.ident]; ok && sym.kind == typeSym {
+				return base.cfgErrorf("cannot define new methods on non-local type %s", base.ident)
+			}
+		}
+		return nil
+	case selectorExpr:
+		return base.cfgErrorf("cannot define new methods on non-local type %s", exprString(base))
+	case starExpr:
+		return base.cfgErrorf("invalid receiver type %s", exprString(n))
+	}
+	name := base.kind.String()
+	if t, err := nodeType(interp, sc, n); err == nil {
+		name = t.id()
+	}
+	return base.cfgErrorf("invalid receiver type %s", name)
+}
+
+// checkMethod checks the receiver type t, of expression recv, of the method
+// n, which must not be already declared on the base type of name typeName.
+// The base type may still be incomplete at this stage, in which case it is
+// checked again in cfg.
+func checkMethod(n, recv *node, t *itype, typeName string) error {
+	if err := checkRecvType(n, recv, t); err != nil {
+		return err
+	}
+	if n.ident == "_" {
+		return nil // blank methods are not declared
+	}
+	if t.cat == ptrT {
+		t = t.val
+	}
+	for _, m := range t.method {
+		if m != n && m.ident == n.ident {
+			return n.child[1].cfgErrorf("method %s.%s already declared at %s", typeName, n.ident, n.interp.fset.Position(m.child[1].pos))
+		}
+	}
+	return nil
+}
+
+// checkRedeclared returns an error if the package level identifier id, of
+// the declaration n, is already declared in scope sc by another declaration.
+// A declaration of a previous evaluation of the same source, as in the REPL,
+// may be redefined.
+func (interp *Interpreter) checkRedeclared(sc *scope, id, n *node, baseName string) error {
+	if id.ident == "_" {
+		return nil
+	}
+	if sym, exists := sc.sym[filepath.Join(id.ident, baseName)]; exists && sym.kind == pkgSym {
+		// TODO(mpl): improve error with position of previous declaration.
+		return id.cfgErrorf("%s redeclared in this block", id.ident)
+	}
+	sym, exists := sc.sym[id.ident]
+	switch {
+	case !exists:
+	case sym.kind == binSym:
+		return id.cfgErrorf("%s redeclared in this block", id.ident)
+	case sym.node != nil && sym.node != n && !interp.isRedefinition(sym.node, n):
+		return redeclaredError(id, sym)
+	}
+	return nil
+}
+
+// isRedefinition returns true if the declaration n replaces the previous
+// declaration prev of an earlier evaluation of the same source, as in the REPL.
+func (interp *Interpreter) isRedefinition(prev, n *node) bool {
+	pf, nf := interp.fset.File(prev.pos), interp.fset.File(n.pos)
+	return pf != nil && nf != nil && pf != nf && pf.Name() == nf.Name()
+}
@@ -3,19 +3,23 @@ package interp
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"go/ast"
 	"go/build"
 	"go/scanner"
 	"go/token"
 	"io"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"reflect"
 	"runtime"
 	"runtime/debug"
 	"strconv"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 // Interpreter node structure for AST and CFG.
@@ -67,6 +71,8 @@ type frame struct {
 	deferred  [][]reflect.Value  // defer stack
 	recovered interface{}        // to handle panic recover
 	done      reflect.SelectCase // for cancellation of channel operations
+	labels    context.Context    // runtime/pprof labels of the current function, if profiling
+	stats     *Stats             // accounting of the execution, if enabled
 }
 
 func newFrame(anc *frame, len int, id uint64) *frame {
@@ -93,6 +99,8 @@ func (f *frame) clone() *frame {
 		recovered: f.recovered,
 		id:        f.runid(),
 		done:      f.done,
+		labels:    f.labels,
+		stats:     f.stats,
 	}
 }
 
@@ -108,10 +116,31 @@ type opt struct {
 	cfgDot bool // display CFG graph (debug)
 	// dotCmd is the command to process the dot graph produced when astDot and/or
 	// cfgDot is enabled. It defaults to 'dot -Tdot -o <filename>.dot'.
-	dotCmd   string
-	noRun    bool          // compile, but do not run
-	fastChan bool          // disable cancellable chan operations
-	context  build.Context // build context: GOPATH, build constraints
+	dotCmd      string
+	noRun       bool          // compile, but do not run
+	errorSource bool          // display source line in error messages
+	args        []string      // os.Args of the interpreted program, if not nil
+	fastChan    bool          // disable cancellable chan operations
+	context     build.Context // build context: GOPATH, build constraints
+	// panicToError recovers the panics of interpreted functions called from
+	// binary code, and panicHandler receives those without error result.
+	panicToError bool
+	panicHandler func(PanicError)
+	panicLocals  int             // record the locals of frames unwound by panics
+	evalTimeout  time.Duration   // default timeout of Eval
+	exprOnly     bool            // restrict sources to expressions
+	stats        bool            // account for the work of evaluations
+	allowedCalls map[string]bool // functions callable in expression mode
+	autoImport   bool            // import binary packages on demand in REPL
+	preCompile   func(*token.FileSet, *ast.File) error
+	allowExit    bool         // let os.Exit terminate the process
+	env          *environment // environment of the interpreted code, if not nil
+	fs           *vfs         // file system of the interpreted code, if not nil
+	clock        Clock        // time of the interpreted code, if not nil
+	stderr       io.Writer    // output of the print and println builtins
+	stdout       io.Writer    // output of the fmt print functions, if not nil
+	goVersion    int          // minor version of the Go language, as 21 for go1.21
+	goTypes      bool         // compare the results of Check with go/types
 }
 
 // Interpreter contains global resources and state.
@@ -131,13 +160,25 @@ type Interpreter struct {
 	binPkg     Exports         // binary packages used in interpreter, indexed by path
 	rdir       map[string]bool // for src import cycle detection
 
+	profile *profile // execution time of interpreted functions, if enabled
+
+	compileMutex sync.Mutex // serializes compilations of programs
+
 	mutex    sync.RWMutex
-	frame    *frame            // program data storage during execution
-	universe *scope            // interpreter global level scope
-	scopes   map[string]*scope // package level scopes, indexed by package name
-	srcPkg   imports           // source packages used in interpreter, indexed by path
-	pkgNames map[string]string // package names, indexed by path
-	done     chan struct{}     // for cancellation of channel operations
+	frame    *frame                 // program data storage during execution
+	universe *scope                 // interpreter global level scope
+	scopes   map[string]*scope      // package level scopes, indexed by package name
+	srcPkg   imports                // source packages used in interpreter, indexed by path
+	pkgNames map[string]string      // package names, indexed by path
+	roots    map[string]*node       // last AST roots, indexed by source file path
+	files    map[string]*ast.File   // last parsed files, indexed by source file path
+	sources  map[*token.File]source // source code, for error messages, if errorSource
+	done     chan struct{}          // for cancellation of channel operations
+	stopped  chan string            // location of the interrupted execution, for EvalWithTimeout
+
+	compiled map[string]*CompiledPackage // compiled packages set by UseCompiled, indexed by path
+	locals   sync.Map                    // []local of functions by definition node, if panicLocals
+	notices  io.Writer                   // output of the implicit imports in REPL, if autoImport
 
 	hooks *hooks // symbol hooks
 }
@@ -159,13 +200,57 @@ var Symbols = Exports{
 
 func init() { Symbols[selfPath]["Symbols"] = reflect.ValueOf(Symbols) }
 
-// _error is a wrapper of error interface type.
+// _error is a wrapper of error interface type. The wrapped interpreted value
+// is kept in IValue, so the identity of errors is preserved for errors.Is.
 type _error struct {
-	WError func() string
+	WError  func() string
+	IValue  interface{}
+	WUnwrap func() error // optional, set if the wrapped value has an Unwrap method
 }
 
 func (w _error) Error() string { return w.WError() }
 
+// Is reports whether target wraps the same interpreted value as w.
+func (w _error) Is(target error) bool {
+	t, ok := target.(*_error)
+	if !ok || w.IValue == nil || !reflect.TypeOf(w.IValue).Comparable() {
+		return false
+	}
+	return w.IValue == t.IValue
+}
+
+// Unwrap returns the result of the Unwrap method of the wrapped value, if any.
+func (w _error) Unwrap() error {
+	if w.WUnwrap == nil {
+		return nil
+	}
+	return w.WUnwrap()
+}
+
+// _stringer is the wrapper of an interpreted value with a String method,
+// passed to binary code as an empty interface, i.e. to fmt. Its Format
+// method calls String for the verbs where fmt would do it for a Stringer,
+// and formats the wrapped value for the other verbs, as %d for an enum.
+type _stringer struct {
+	WString func() string
+	IValue  interface{}
+}
+
+func (w _stringer) String() string { return w.WString() }
+
+// Format implements fmt.Formatter.
+func (w _stringer) Format(s fmt.State, verb rune) {
+	format := formatString(s, verb)
+	switch verb {
+	case 'v', 's', 'q', 'x', 'X':
+		if verb != 'v' || !s.Flag('#') {
+			fmt.Fprintf(s, format, w.WString())
+			return
+		}
+	}
+	fmt.Fprintf(s, format, w.IValue)
+}
+
 // Panic is an error recovered from a panic call in interpreted code.
 type Panic struct {
 	// Value is the recovered value of a call to panic.
@@ -177,13 +262,44 @@ type Panic struct {
 
 	// Stack is the call stack buffer for debug.
 	Stack []byte
+
+	frames []FrameInfo
 }
 
+// Frames returns the frames of the interpreted functions unwound by the
+// panic, innermost first, if the PanicLocals option is set.
+func (e Panic) Frames() []FrameInfo { return e.frames }
+
 // TODO: Capture interpreter stack frames also and remove
 // fmt.Println(n.cfgErrorf("panic")) in runCfg.
 
 func (e Panic) Error() string { return fmt.Sprint(e.Value) }
 
+// ErrTimeout is matched by the errors returned by EvalWithTimeout on timeout.
+var ErrTimeout = errors.New("evaluation timeout")
+
+// TimeoutError is the error returned by EvalWithTimeout when the evaluation
+// is interrupted.
+type TimeoutError struct {
+	// Timeout is the duration after which the evaluation was interrupted.
+	Timeout time.Duration
+
+	// Location is the interpreted function where the execution was interrupted,
+	// with the position of its declaration. It is empty if the execution is not
+	// interrupted yet, i.e. while a binary function call is in progress.
+	Location string
+}
+
+func (e *TimeoutError) Error() string {
+	if e.Location == "" {
+		return fmt.Sprintf("%v after %v", ErrTimeout, e.Timeout)
+	}
+	return fmt.Sprintf("%v after %v in %s", ErrTimeout, e.Timeout, e.Location)
+}
+
+// Unwrap returns ErrTimeout.
+func (e *TimeoutError) Unwrap() error { return ErrTimeout }
+
 // Walk traverses AST n in depth first order, call cbin function
 // at node entry and cbout function at node exit.
 func (n *node) Walk(in func(n *node) bool, out func(n *node)) {
@@ -204,6 +320,125 @@ type Options struct {
 	GoPath string
 	// BuildTags sets build constraints for the interpreter
 	BuildTags []string
+	// GoVersion, if not empty, is the version of the Go language accepted by
+	// the interpreter, as "go1.21", instead of the version of the toolchain
+	// which built it. The language features of later versions, as range over
+	// integers in go1.22, are rejected, and the build constraints are
+	// evaluated for that version. Before go1.22, the variables declared by a
+	// for statement are shared by all its iterations. A patch number or a
+	// pre-release suffix, as in "go1.21.3" or "go1.21rc1", is ignored. An
+	// invalid version leaves the version of the toolchain in effect.
+	GoVersion string
+	// CheckWithGoTypes makes Check type check the source with go/types too,
+	// and report as warnings the errors found by only one of them, and the
+	// package level declarations of which the types differ.
+	CheckWithGoTypes bool
+	// Profile enables the profiling of interpreted functions, see Interpreter.Profile
+	Profile bool
+	// Args sets the value of os.Args seen by the interpreted program, instead of
+	// the arguments of the current process. Args[0] is the program name.
+	// The command line of package flag, as flag.Parse and flag.Args, operates
+	// on Args too, with its errors and usage written to Stderr. Other binary
+	// packages using os.Args internally are not affected.
+	Args []string
+	// ErrorSource appends the offending source line and a caret under the error
+	// column to messages of errors and runtime panics located in source
+	ErrorSource bool
+	// PanicToError recovers the panics of interpreted functions called from
+	// binary code, i.e. function values passed to binary functions or returned
+	// by Eval, so they do not unwind the host frames. The panic is returned as
+	// a PanicError if the last result of the function is an error, and passed
+	// to PanicHandler otherwise, or printed on stderr if PanicHandler is nil.
+	// Panics originated in binary code are not recovered.
+	PanicToError bool
+	// PanicHandler receives the panics recovered by PanicToError of interpreted
+	// functions without error result.
+	PanicHandler func(PanicError)
+	// PanicLocals, if not zero, makes the Panic errors returned by Eval record
+	// the frames of the interpreted functions unwound by the panic, with a
+	// copy of their local variables, see Panic.Frames. Strings, arrays,
+	// slices and maps longer than PanicLocals are truncated to PanicLocals
+	// bytes or elements, to bound the memory retained by the error. If
+	// negative, the variables are not truncated.
+	PanicLocals int
+	// EvalTimeout, if not zero, is the timeout applied to each call of Eval,
+	// as by EvalWithTimeout.
+	EvalTimeout time.Duration
+	// ExprOnly restricts the sources evaluated or compiled to expressions,
+	// for example to evaluate user formulas safely: declarations, statements,
+	// loops, function literals, composite literals, channel operations and
+	// nested indexing are rejected, and functions can only be called if their
+	// name, as "max" or "math.Sqrt", is in AllowedCalls. Conversions are
+	// allowed, as well as import declarations, which do not execute code.
+	ExprOnly bool
+	// AllowedCalls are the functions which can be called in ExprOnly mode.
+	AllowedCalls []string
+	// Stats enables the accounting of the work done by interpreted code,
+	// see EvalWithStats and Program.Stats.
+	Stats bool
+	// AutoImport makes the REPL import the binary packages on their first
+	// use, instead of making all the unambiguous ones visible at start. Each
+	// implicit import is reported in the REPL output. Eval is not affected.
+	AutoImport bool
+	// PreCompile, if not nil, is called for each parsed source file, before
+	// any analysis of the file. Returning an error aborts the evaluation or
+	// the import of the file with that error. The file can be modified:
+	// statements, declarations and expressions can be added, replaced or
+	// removed, for example to instrument function bodies or to wrap calls,
+	// provided the resulting tree is valid Go code, with ast.Ident nodes for
+	// identifiers and ast.BasicLit nodes for literals. The added nodes have
+	// no position, their errors are reported at the enclosing node position.
+	// Comments, and the positions of the existing nodes, must not be
+	// modified. The files of the packages used with UseCompiled are not
+	// passed to PreCompile.
+	PreCompile func(fset *token.FileSet, file *ast.File) error
+	// AllowExit lets os.Exit, called by interpreted code, terminate the host
+	// process. By default, os.Exit terminates only the current evaluation,
+	// after running the deferred calls of the interpreted functions, and Eval
+	// returns an ExitError with the exit code. Called in a goroutine started
+	// by interpreted code, it terminates only that goroutine, as
+	// runtime.Goexit.
+	AllowExit bool
+	// Env, if not nil, is the environment of the interpreted code, in the
+	// form "key=value", as returned by os.Environ. The environment functions
+	// of package os operate on a private copy of Env: the process environment
+	// is neither read nor modified. Binary packages using the environment
+	// internally are not affected.
+	Env []string
+	// FSRoot, if not empty, is the host directory seen by the interpreted
+	// code as the root of the file system. The file functions of packages
+	// os, io/ioutil and path/filepath resolve the paths in FSRoot, absolute
+	// or relative to a virtual working directory, initially the root, and
+	// reject the symbolic links leading outside of it. Creating processes,
+	// links and changing file owners is rejected. The file system is
+	// read-only, unless FSOverlay is set. The names of the opened files, as
+	// returned by File.Name, are host paths, which the file functions map to
+	// the virtual ones. Binary packages accessing files internally, as
+	// text/template or syscall, are not affected.
+	FSRoot string
+	// FSOverlay, if not empty, is the host directory where the files created
+	// or modified in FSRoot are stored, the files of FSRoot being never
+	// modified. The files of FSRoot removed by the interpreted code are
+	// hidden to it.
+	FSOverlay string
+	// Clock, if not nil, is the source of time of the functions After, Now,
+	// Since, Sleep and Until of package time, called by the interpreted code.
+	// The other functions, as timers and tickers, use the real time.
+	Clock Clock
+	// Stderr, if not nil, is the destination of the print and println
+	// builtins, and of the panics recovered by PanicToError without
+	// PanicHandler, instead of os.Stderr.
+	Stderr io.Writer
+	// Stdout, if not nil, is the destination of the Print, Printf and Println
+	// functions of package fmt, instead of os.Stdout. Writing to os.Stdout
+	// directly is not affected.
+	Stdout io.Writer
+}
+
+// source stores a source code text, for error messages.
+type source struct {
+	text   string
+	offset int // length of text added to the first line in REPL mode
 }
 
 // New returns a new interpreter.
@@ -216,7 +451,11 @@ func New(options Options) *Interpreter {
 		scopes:   map[string]*scope{},
 		binPkg:   Exports{"": map[string]reflect.Value{"_error": reflect.ValueOf((*_error)(nil))}},
 		srcPkg:   imports{},
+		compiled: map[string]*CompiledPackage{},
 		pkgNames: map[string]string{},
+		roots:    map[string]*node{},
+		files:    map[string]*ast.File{},
+		sources:  map[*token.File]source{},
 		rdir:     map[string]bool{},
 		hooks:    &hooks{},
 	}
@@ -225,6 +464,45 @@ func New(options Options) *Interpreter {
 	if len(options.BuildTags) > 0 {
 		i.opt.context.BuildTags = options.BuildTags
 	}
+	if tags, err := goReleaseTags(options.GoVersion); err == nil {
+		i.opt.context.ReleaseTags = tags
+	}
+	i.opt.goVersion = goMinorVersion(&i.opt.context)
+	i.opt.goTypes = options.CheckWithGoTypes
+	if options.Profile {
+		i.profile = newProfile()
+	}
+	i.opt.errorSource = options.ErrorSource
+	i.opt.args = options.Args
+	i.opt.panicToError = options.PanicToError
+	i.opt.panicHandler = options.PanicHandler
+	i.opt.panicLocals = options.PanicLocals
+	i.opt.evalTimeout = options.EvalTimeout
+	i.opt.stats = options.Stats
+	i.opt.autoImport = options.AutoImport
+	i.opt.preCompile = options.PreCompile
+	i.opt.allowExit = options.AllowExit
+	var getenv func(string) string
+	if options.Env != nil {
+		i.opt.env = newEnvironment(options.Env)
+		getenv = i.opt.env.getenv
+	}
+	if options.FSRoot != "" {
+		i.opt.fs = newVFS(options.FSRoot, options.FSOverlay, getenv)
+	}
+	i.opt.clock = options.Clock
+	i.opt.stderr = os.Stderr
+	if options.Stderr != nil {
+		i.opt.stderr = options.Stderr
+	}
+	i.opt.stdout = options.Stdout
+	if options.ExprOnly {
+		i.opt.exprOnly = true
+		i.opt.allowedCalls = make(map[string]bool, len(options.AllowedCalls))
+		for _, name := range options.AllowedCalls {
+			i.opt.allowedCalls[name] = true
+		}
+	}
 
 	// astDot activates AST graph display for the interpreter
 	i.opt.astDot, _ = strconv.ParseBool(os.Getenv("YAEGI_AST_DOT"))
@@ -248,6 +526,7 @@ func New(options Options) *Interpreter {
 func initUniverse() *scope {
 	sc := &scope{global: true, sym: map[string]*symbol{
 		// predefined Go types
+		"any":         {kind: typeSym, typ: &itype{cat: interfaceT}},
 		"bool":        {kind: typeSym, typ: &itype{cat: boolT, name: "bool"}},
 		"byte":        {kind: typeSym, typ: &itype{cat: uint8T, name: "uint8"}},
 		"complex64":   {kind: typeSym, typ: &itype{cat: complex64T, name: "complex64"}},
@@ -281,6 +560,7 @@ func initUniverse() *scope {
 		// predefined Go builtins
 		"append":  {kind: bltnSym, builtin: _append},
 		"cap":     {kind: bltnSym, builtin: _cap},
+		"clear":   {kind: bltnSym, builtin: _clear},
 		"close":   {kind: bltnSym, builtin: _close},
 		"complex": {kind: bltnSym, builtin: _complex},
 		"imag":    {kind: bltnSym, builtin: _imag},
@@ -288,6 +568,8 @@ func initUniverse() *scope {
 		"delete":  {kind: bltnSym, builtin: _delete},
 		"len":     {kind: bltnSym, builtin: _len},
 		"make":    {kind: bltnSym, builtin: _make},
+		"max":     {kind: bltnSym, builtin: _max},
+		"min":     {kind: bltnSym, builtin: _min},
 		"new":     {kind: bltnSym, builtin: _new},
 		"panic":   {kind: bltnSym, builtin: _panic},
 		"print":   {kind: bltnSym, builtin: _print},
@@ -324,42 +606,116 @@ func (interp *Interpreter) main() *node {
 
 // Eval evaluates Go code represented as a string. It returns a map on
 // current interpreted package exported symbols.
+// If the EvalTimeout option is set, the evaluation is bounded as by
+// EvalWithTimeout.
+// If the evaluation fails, at compilation or at run time, the declarations
+// of src are removed, so the next evaluation starts from the previous package
+// state. The side effects of the executed statements are not reverted.
 func (interp *Interpreter) Eval(src string) (res reflect.Value, err error) {
+	if interp.evalTimeout > 0 {
+		return interp.EvalWithTimeout(src, interp.evalTimeout)
+	}
+	return interp.eval([]string{interp.Name}, []string{src}, nil)
+}
+
+// eval evaluates the source code srcs of files names in the current package.
+// If rl is not nil, the declarations of the previous evaluation of the same
+// files are replaced.
+func (interp *Interpreter) eval(names, srcs []string, rl *reload) (res reflect.Value, err error) {
+	var ps *pkgState
 	defer func() {
 		r := recover()
-		if r != nil {
+		switch {
+		case isExit(r):
+			err = exitError(r)
+		case r != nil:
 			var pc [64]uintptr // 64 frames should be enough.
 			n := runtime.Callers(1, pc[:])
-			err = Panic{Value: r, Callers: pc[:n], Stack: debug.Stack()}
+			err = Panic{Value: panicValue(r), Callers: pc[:n], Stack: debug.Stack(), frames: panicFrames(r)}
+		}
+		switch {
+		case err == nil:
+		case rl != nil:
+			rl.restore()
+		case ps != nil:
+			ps.restore()
 		}
 	}()
 
-	// Parse source to AST.
-	pkgName, root, err := interp.ast(src, interp.Name)
-	if err != nil || root == nil {
+	// Parse sources to AST.
+	var pkgName string
+	var roots []*node
+	var rootNames []string
+	for i, src := range srcs {
+		pname, root, err := interp.ast(src, names[i])
+		if err != nil {
+			return res, err
+		}
+		if root == nil {
+			continue
+		}
+		if interp.exprOnly {
+			if err = interp.checkExprOnly(root); err != nil {
+				return res, err
+			}
+		}
+		if interp.notices != nil {
+			if err = interp.importUndefined(root); err != nil {
+				return res, err
+			}
+		}
+		if pkgName != "" && pname != pkgName {
+			return res, fmt.Errorf("found packages %s and %s", pkgName, pname)
+		}
+		pkgName = pname
+
+		if interp.astDot {
+			dotCmd := interp.dotCmd
+			if dotCmd == "" {
+				dotCmd = defaultDotCmd(names[i], "yaegi-ast-")
+			}
+			root.astDot(dotWriter(dotCmd), names[i])
+		}
+		roots = append(roots, root)
+		rootNames = append(rootNames, names[i])
+	}
+	if len(roots) == 0 || interp.astDot && interp.noRun {
 		return res, err
 	}
 
-	if interp.astDot {
-		dotCmd := interp.dotCmd
-		if dotCmd == "" {
-			dotCmd = defaultDotCmd(interp.Name, "yaegi-ast-")
-		}
-		root.astDot(dotWriter(dotCmd), interp.Name)
-		if interp.noRun {
-			return res, err
-		}
+	if rl != nil {
+		// Remove the previous declarations, they are restored in case of error.
+		rl.forget()
+	} else {
+		// The declarations of a failed evaluation are rolled back.
+		ps = interp.savePkgState()
 	}
 
+	// Reset the execution state left in the global frame by a previous
+	// evaluation interrupted by a panic.
+	interp.frame.mutex.Lock()
+	interp.frame.deferred = nil
+	interp.frame.recovered = nil
+	interp.frame.mutex.Unlock()
+
 	// Perform global types analysis.
-	if err = interp.gtaRetry([]*node{root}, pkgName, interp.Name); err != nil {
+	if err = interp.gtaRetry(roots, pkgName, interp.Name); err != nil {
 		return res, err
 	}
+	if rl != nil {
+		if err = rl.check(); err != nil {
+			return res, err
+		}
+	}
 
 	// Annotate AST with CFG infos
-	initNodes, err := interp.cfg(root, interp.Name)
-	if err != nil {
-		return res, err
+	var initNodes []*node
+	for _, root := range roots {
+		nodes, err := interp.cfg(root, interp.Name)
+		if err != nil {
+			return res, err
+		}
+		initNodes = append(initNodes, nodes...)
 	}
 
 	// Add main to list of functions to run, after all inits
@@ -367,24 +723,29 @@ func (interp *Interpreter) Eval(src string) (res reflect.Value, err error) {
 		initNodes = append(initNodes, m)
 	}
 
-	if root.kind != fileStmt {
-		// REPL may skip package statement
-		setExec(root.start)
-	}
 	interp.mutex.Lock()
 	if interp.universe.sym[pkgName] == nil {
 		// Make the package visible under a path identical to its name
 		interp.srcPkg[pkgName] = interp.scopes[interp.Name].sym
 		interp.universe.sym[pkgName] = &symbol{kind: pkgSym, typ: &itype{cat: srcPkgT, path: pkgName}}
 	}
+	for i, root := range roots {
+		if root.kind != fileStmt {
+			// REPL may skip package statement
+			setExec(root.start)
+		}
+		interp.roots[rootNames[i]] = root
+	}
 	interp.mutex.Unlock()
 
 	if interp.cfgDot {
-		dotCmd := interp.dotCmd
-		if dotCmd == "" {
-			dotCmd = defaultDotCmd(interp.Name, "yaegi-cfg-")
+		for i, root := range roots {
+			dotCmd := interp.dotCmd
+			if dotCmd == "" {
+				dotCmd = defaultDotCmd(rootNames[i], "yaegi-cfg-")
+			}
+			root.cfgDot(dotWriter(dotCmd))
 		}
-		root.cfgDot(dotWriter(dotCmd))
 	}
 
 	if interp.noRun {
@@ -392,8 +753,13 @@ func (interp *Interpreter) Eval(src string) (res reflect.Value, err error) {
 	}
 
 	// Generate node exec closures
-	if err = genRun(root); err != nil {
-		return res, err
+	for _, root := range roots {
+		if err = genRun(root); err != nil {
+			return res, err
+		}
+	}
+	if rl != nil {
+		rl.replace(roots)
 	}
 
 	// Init interpreter execution memory frame
@@ -403,10 +769,12 @@ func (interp *Interpreter) Eval(src string) (res reflect.Value, err error) {
 	interp.frame.mutex.Unlock()
 
 	// Execute node closures
-	interp.run(root, nil)
+	for _, root := range roots {
+		interp.run(root, nil)
+	}
 
 	// Wire and execute global vars
-	n, err := genGlobalVars([]*node{root}, interp.scopes[interp.Name])
+	n, err := genGlobalVars(roots, interp.scopes[interp.Name])
 	if err != nil {
 		return res, err
 	}
@@ -415,7 +783,7 @@ func (interp *Interpreter) Eval(src string) (res reflect.Value, err error) {
 	for _, n := range initNodes {
 		interp.run(n, interp.frame)
 	}
-	v := genValue(root)
+	v := genValue(roots[len(roots)-1])
 	res = v(interp.frame)
 
 	// If result is an interpreter node, wrap it in a runtime callable function
@@ -428,6 +796,51 @@ func (interp *Interpreter) Eval(src string) (res reflect.Value, err error) {
 	return res, err
 }
 
+// pkgState is the state of the package scope prior to an evaluation, so the
+// declarations of the evaluation can be removed if it fails.
+type pkgState struct {
+	sc      *scope
+	sym     map[string]*symbol
+	val     map[*symbol]symbol
+	methods map[*itype][]*node
+}
+
+// savePkgState returns the current state of the main package scope.
+func (interp *Interpreter) savePkgState() *pkgState {
+	sc := interp.initScopePkg(interp.Name)
+	ps := &pkgState{
+		sc:      sc,
+		sym:     make(map[string]*symbol, len(sc.sym)),
+		val:     make(map[*symbol]symbol, len(sc.sym)),
+		methods: map[*itype][]*node{},
+	}
+	for k, s := range sc.sym {
+		ps.sym[k] = s
+		ps.val[s] = *s
+		if s.kind == typeSym && s.typ != nil {
+			ps.methods[s.typ] = s.typ.method
+		}
+	}
+	return ps
+}
+
+// restore reverts the package scope to the saved state. The symbols modified
+// in place, and the methods added to existing types, are restored as well.
+func (ps *pkgState) restore() {
+	for k := range ps.sc.sym {
+		if _, ok := ps.sym[k]; !ok {
+			delete(ps.sc.sym, k)
+		}
+	}
+	for k, s := range ps.sym {
+		*s = ps.val[s]
+		ps.sc.sym[k] = s
+	}
+	for t, m := range ps.methods {
+		t.method = m
+	}
+}
+
 // EvalWithContext evaluates Go code represented as a string. It returns
 // a map on current interpreted package exported symbols.
 func (interp *Interpreter) EvalWithContext(ctx context.Context, src string) (reflect.Value, error) {
@@ -442,7 +855,7 @@ func (interp *Interpreter) EvalWithContext(ctx context.Context, src string) (ref
 	done := make(chan struct{})
 	go func() {
 		defer close(done)
-		v, err = interp.Eval(src)
+		v, err = interp.eval([]string{interp.Name}, []string{src}, nil)
 	}()
 
 	select {
@@ -454,6 +867,123 @@ func (interp *Interpreter) EvalWithContext(ctx context.Context, src string) (ref
 	}
 }
 
+// stopDelay is the time given to an interrupted execution to report where it
+// stopped, in EvalWithTimeout.
+const stopDelay = 50 * time.Millisecond
+
+// EvalWithTimeout evaluates Go code represented as a string, as Eval, and
+// interrupts the execution if it lasts more than d, using the same mechanism
+// as the cancellation of EvalWithContext. The goroutines started by the
+// interpreted code are also stopped. A binary function called by the
+// interpreted code can not be interrupted: the execution stops when it returns.
+// On timeout, the returned error is a *TimeoutError, which matches ErrTimeout.
+func (interp *Interpreter) EvalWithTimeout(src string, d time.Duration) (reflect.Value, error) {
+	stopped := make(chan string, 1)
+	interp.mutex.Lock()
+	interp.stopped = stopped
+	interp.mutex.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	v, err := interp.EvalWithContext(ctx, src)
+	if err != context.DeadlineExceeded {
+		return v, err
+	}
+
+	e := &TimeoutError{Timeout: d}
+	select {
+	case e.Location = <-stopped:
+	case <-time.After(stopDelay):
+	}
+	return v, e
+}
+
+// EvalTest evaluates the source package in directory path, including its test
+// files, and returns the package functions, indexed by name. Only test files
+// of the same package are considered, external test packages are ignored.
+// The path is resolved as an import path, and may be relative, i.e. "./foo".
+func (interp *Interpreter) EvalTest(path string) (funcs map[string]reflect.Value, err error) {
+	defer func() {
+		r := recover()
+		switch {
+		case isExit(r):
+			err = exitError(r)
+		case r != nil:
+			var pc [64]uintptr // 64 frames should be enough.
+			n := runtime.Callers(1, pc[:])
+			err = Panic{Value: panicValue(r), Callers: pc[:n], Stack: debug.Stack(), frames: panicFrames(r)}
+		}
+	}()
+
+	if _, err = interp.importSrc(mainID, path, false); err != nil {
+		return nil, err
+	}
+
+	funcs = map[string]reflect.Value{}
+	interp.mutex.RLock()
+	defer interp.mutex.RUnlock()
+	for name, sym := range interp.srcPkg[path] {
+		if sym.kind == funcSym && sym.node != nil {
+			funcs[name] = genFunctionWrapper(sym.node)(interp.frame)
+		}
+	}
+	return funcs, nil
+}
+
+// RunMain runs the main package in directory path as a program, with the
+// command line args, where args[0] is the program name, defaulting to the
+// base name of path. The global variables and the init functions of the
+// imported packages, then of the main package, are evaluated in order, then
+// the main function is run, as os.Args and the command line of package flag
+// are set to args. RunMain returns nil once main returns, or an ExitError if
+// os.Exit is called, unless the AllowExit option is set. The path is
+// resolved as by EvalTest. The package must not be already evaluated.
+func (interp *Interpreter) RunMain(path string, args []string) (err error) {
+	defer func() {
+		r := recover()
+		switch {
+		case isExit(r):
+			err = exitError(r)
+		case r != nil:
+			var pc [64]uintptr // 64 frames should be enough.
+			n := runtime.Callers(1, pc[:])
+			err = Panic{Value: panicValue(r), Callers: pc[:n], Stack: debug.Stack(), frames: panicFrames(r)}
+		}
+	}()
+
+	interp.mutex.RLock()
+	loaded := interp.srcPkg[path] != nil
+	interp.mutex.RUnlock()
+	if loaded {
+		return fmt.Errorf("package %s is already evaluated", path)
+	}
+
+	if len(args) == 0 {
+		args = []string{filepath.Base(path)}
+	}
+	interp.args = args
+	interp.overrideArgs(interp.binPkg)
+
+	pkgName, err := interp.importSrc(mainID, path, true)
+	if err != nil {
+		return err
+	}
+	if pkgName != mainID {
+		return fmt.Errorf("package %s is not a main package", path)
+	}
+
+	interp.mutex.RLock()
+	sym := interp.scopes[path].sym[mainID]
+	interp.mutex.RUnlock()
+	if sym == nil || sym.kind != funcSym || sym.node == nil {
+		return fmt.Errorf("function main is undeclared in the main package %s", path)
+	}
+	if !interp.noRun && sym.node != interp.main() {
+		interp.run(sym.node, interp.frame)
+	}
+	return nil
+}
+
 // stop sends a semaphore to all running frames and closes the chan
 // operation short circuit channel. stop may only be called once per
 // invocation of EvalWithContext.
@@ -464,10 +994,31 @@ func (interp *Interpreter) stop() {
 
 func (interp *Interpreter) runid() uint64 { return atomic.LoadUint64(&interp.id) }
 
+// interrupted reports the location of the execution of n, interrupted
+// by stop, to EvalWithTimeout. Only the first location is kept.
+func (interp *Interpreter) interrupted(n *node) {
+	interp.mutex.RLock()
+	stopped := interp.stopped
+	interp.mutex.RUnlock()
+	if stopped == nil {
+		return
+	}
+	select {
+	case stopped <- location(n):
+	default:
+	}
+}
+
 // getWrapper returns the wrapper type of the corresponding interface, or nil if not found.
+// The unexported methods of the interface can only be provided by a type of its
+// package embedded in the wrapper: a wrapper not implementing them is ignored.
 func (interp *Interpreter) getWrapper(t reflect.Type) reflect.Type {
-	if p, ok := interp.binPkg[t.PkgPath()]; ok {
-		return p["_"+t.Name()].Type().Elem()
+	w, ok := interp.binPkg[t.PkgPath()]["_"+t.Name()]
+	if !ok {
+		return nil
+	}
+	if wt := w.Type().Elem(); reflect.PtrTo(wt).Implements(t) {
+		return wt
 	}
 	return nil
 }
@@ -490,22 +1041,113 @@ func (interp *Interpreter) Use(values Exports) {
 			interp.binPkg[k][s] = sym
 		}
 	}
+
+	// Override os.Args and the flag command line, the environment, the file
+	// system, the time and the standard output with the interpreter options,
+	// and os.Exit and runtime.Goexit so they terminate only the interpreted
+	// code.
+	if interp.args != nil {
+		interp.overrideArgs(values)
+	}
+	if !interp.allowExit && values["os"]["Exit"].IsValid() {
+		interp.overrideBin("os", map[string]reflect.Value{"Exit": reflect.ValueOf(exit)})
+	}
+	if values["runtime"]["Goexit"].IsValid() {
+		interp.overrideBin("runtime", map[string]reflect.Value{"Goexit": reflect.ValueOf(runtimeGoexit)})
+	}
+	if interp.env != nil && values["os"] != nil {
+		interp.overrideBin("os", interp.env.symbols())
+	}
+	if interp.clock != nil && values["time"] != nil {
+		interp.overrideBin("time", clockSymbols(interp.clock))
+	}
+	if interp.fs != nil {
+		for path, syms := range interp.fs.symbols() {
+			if values[path] != nil {
+				interp.overrideBin(path, syms)
+			}
+		}
+	}
+	if interp.stdout != nil && values["fmt"] != nil {
+		interp.overrideBin("fmt", stdoutSymbols(interp.stdout))
+	}
+}
+
+// overrideArgs replaces os.Args, and the command line of package flag, of
+// the packages of values by the interpreter arguments.
+func (interp *Interpreter) overrideArgs(values Exports) {
+	if values["os"]["Args"].IsValid() {
+		interp.overrideBin("os", map[string]reflect.Value{"Args": reflect.ValueOf(&interp.args).Elem()})
+	}
+	if values["flag"] != nil {
+		interp.overrideBin("flag", newCommandLine(interp.args, interp.stderr, interp.allowExit).symbols())
+	}
+}
+
+// overrideBin replaces the symbols of the binary package path with those of
+// syms, in a copy of the package symbols, in order to leave the original ones
+// unchanged. The symbols of syms not exported by the package are ignored.
+func (interp *Interpreter) overrideBin(path string, syms map[string]reflect.Value) {
+	m := make(map[string]reflect.Value, len(interp.binPkg[path]))
+	for s, sym := range interp.binPkg[path] {
+		m[s] = sym
+	}
+	for s, sym := range syms {
+		if _, ok := m[s]; ok {
+			m[s] = sym
+		}
+	}
+	interp.binPkg[path] = m
+}
+
+// UseAndImport loads the binary runtime symbols of the package of import path
+// path, as Use, and makes the package visible in subsequent evaluations under
+// its default name, without an import declaration, as in the REPL. It returns
+// an error if a source package of the same path is already imported, or if
+// another package is already visible under the same name.
+func (interp *Interpreter) UseAndImport(path string, symbols map[string]reflect.Value) error {
+	if interp.srcPkg[path] != nil {
+		return fmt.Errorf("cannot use package %s: already imported from source", path)
+	}
+	name := identifier.FindString(path)
+	if name == "" {
+		return fmt.Errorf("cannot use package %s: invalid import path", path)
+	}
+	sc := interp.universe
+	if sym, ok := sc.sym[name]; ok && (sym.kind != pkgSym || sym.typ.path != path) {
+		return fmt.Errorf("cannot import package %s: %s already declared", path, name)
+	}
+	interp.Use(Exports{path: symbols})
+	sc.sym[name] = &symbol{kind: pkgSym, typ: &itype{cat: binPkgT, path: path, scope: sc}}
+	return nil
 }
 
 // REPL performs a Read-Eval-Print-Loop on input reader.
 // Results are printed on output writer.
 func (interp *Interpreter) REPL(in io.Reader, out io.Writer) {
-	// Preimport used bin packages, to avoid having to import these packages manually
-	// in REPL mode. These packages are already loaded anyway.
-	sc := interp.universe
-	for k := range interp.binPkg {
-		name := identifier.FindString(k)
-		if name == "" || name == "rand" || name == "scanner" || name == "template" || name == "pprof" {
-			// Skip any package with an ambiguous name (i.e crypto/rand vs math/rand).
-			// Those will have to be imported explicitly.
-			continue
+	if interp.autoImport {
+		// Binary packages are imported on demand, when evaluating sources.
+		interp.mutex.Lock()
+		interp.notices = out
+		interp.mutex.Unlock()
+		defer func() {
+			interp.mutex.Lock()
+			interp.notices = nil
+			interp.mutex.Unlock()
+		}()
+	} else {
+		// Preimport used bin packages, to avoid having to import these packages manually
+		// in REPL mode. These packages are already loaded anyway.
+		sc := interp.universe
+		for k := range interp.binPkg {
+			name := identifier.FindString(k)
+			if name == "" || name == "rand" || name == "scanner" || name == "template" || name == "pprof" {
+				// Skip any package with an ambiguous name (i.e crypto/rand vs math/rand).
+				// Those will have to be imported explicitly.
+				continue
+			}
+			sc.sym[name] = &symbol{kind: pkgSym, typ: &itype{cat: binPkgT, path: k, scope: sc}}
 		}
-		sc.sym[name] = &symbol{kind: pkgSym, typ: &itype{cat: binPkgT, path: k, scope: sc}}
 	}
 
 	// Set prompt.
@@ -534,6 +1176,9 @@ func (interp *Interpreter) REPL(in io.Reader, out io.Writer) {
 			case Panic:
 				fmt.Fprintln(out, e.Value)
 				fmt.Fprintln(out, string(e.Stack))
+			case ErrorList:
+				// Only report the first error, as subsequent ones may result from it.
+				fmt.Fprintln(out, e[0])
 			default:
 				fmt.Fprintln(out, err)
 			}
@@ -38,6 +38,10 @@ func TestInterpConsistencyBuild(t *testing.T) {
 			file.Name() == "assign15.go" || // expect error
 			file.Name() == "bad0.go" || // expect error
 			file.Name() == "const9.go" || // expect error
+			file.Name() == "const21.go" || // expect error
+			file.Name() == "const22.go" || // expect error
+			file.Name() == "const23.go" || // expect error
+			file.Name() == "const20.go" || // expect error
 			file.Name() == "export1.go" || // non-main package
 			file.Name() == "export0.go" || // non-main package
 			file.Name() == "for7.go" || // expect error
@@ -47,15 +51,20 @@ func TestInterpConsistencyBuild(t *testing.T) {
 			file.Name() == "import6.go" || // expect error
 			file.Name() == "init1.go" || // expect error
 			file.Name() == "io0.go" || // use random number
+			file.Name() == "new4.go" || // expect error
 			file.Name() == "op1.go" || // expect error
 			file.Name() == "op7.go" || // expect error
 			file.Name() == "op9.go" || // expect error
+			file.Name() == "print2.go" || // expect error
 			file.Name() == "bltn0.go" || // expect error
-			file.Name() == "method16.go" || // private struct field
 			file.Name() == "switch8.go" || // expect error
 			file.Name() == "switch9.go" || // expect error
 			file.Name() == "switch13.go" || // expect error
 			file.Name() == "switch19.go" || // expect error
+			file.Name() == "switch43.go" || // expect error
+			file.Name() == "type30.go" || // expect error
+			file.Name() == "var15.go" || // expect error
+			file.Name() == "var17.go" || // expect error
 			file.Name() == "time0.go" || // display time (similar to random number)
 			file.Name() == "factor.go" || // bench
 			file.Name() == "fib.go" || // bench
@@ -69,6 +78,9 @@ func TestInterpConsistencyBuild(t *testing.T) {
 			file.Name() == "redeclaration3.go" || // expect error
 			file.Name() == "redeclaration4.go" || // expect error
 			file.Name() == "redeclaration5.go" || // expect error
+			file.Name() == "redeclaration6.go" || // expect error
+			file.Name() == "redeclaration7.go" || // expect error
+			file.Name() == "redeclaration8.go" || // expect error
 			file.Name() == "redeclaration-global0.go" || // expect error
 			file.Name() == "redeclaration-global1.go" || // expect error
 			file.Name() == "redeclaration-global2.go" || // expect error
@@ -76,6 +88,8 @@ func TestInterpConsistencyBuild(t *testing.T) {
 			file.Name() == "redeclaration-global4.go" || // expect error
 			file.Name() == "redeclaration-global5.go" || // expect error
 			file.Name() == "redeclaration-global6.go" || // expect error
+			file.Name() == "redeclaration-global7.go" || // expect error
+			file.Name() == "redeclaration-global8.go" || // expect error
 			file.Name() == "restricted0.go" || // expect error
 			file.Name() == "restricted1.go" || // expect error
 			file.Name() == "restricted2.go" || // expect error
@@ -89,6 +103,9 @@ func TestInterpConsistencyBuild(t *testing.T) {
 			file.Name() == "server1.go" || // syntax parsing
 			file.Name() == "server0.go" || // syntax parsing
 			file.Name() == "server.go" || // syntax parsing
+			file.Name() == "bltn2.go" || // min, max and clear require go1.21
+			file.Name() == "interface47.go" || // any requires go1.18
+			file.Name() == "interface49.go" || // interpreted types implement interfaces with unexported methods
 			file.Name() == "range9.go" { // expect error
 			continue
 		}
@@ -110,7 +127,7 @@ func TestInterpConsistencyBuild(t *testing.T) {
 			r, w, _ := os.Pipe()
 			os.Stdout = w
 
-			i := interp.New(interp.Options{GoPath: build.Default.GOPATH})
+			i := interp.New(interp.Options{GoPath: build.Default.GOPATH, Stderr: w})
 			i.Name = filePath
 			i.Use(stdlib.Symbols)
 			i.Use(interp.Symbols)
@@ -182,6 +199,11 @@ func TestInterpErrorConsistency(t *testing.T) {
 			expectedInterp: "5:2: constant definition loop",
 			expectedExec:   "5:2: constant definition loop",
 		},
+		{
+			fileName:       "const20.go",
+			expectedInterp: "7:11: time.Duration(n) * time.Second (value of type time.Duration) is not constant",
+			expectedExec:   "7:11: time.Duration(n) * time.Second (value of int64 type time.Duration) is not constant",
+		},
 		{
 			fileName:       "if2.go",
 			expectedInterp: "7:5: non-bool used as if condition",
@@ -233,8 +255,13 @@ func TestInterpErrorConsistency(t *testing.T) {
 		},
 		{
 			fileName:       "switch19.go",
-			expectedInterp: "37:2: duplicate case Bir in type switch",
-			expectedExec:   "37:2: duplicate case Bir in type switch",
+			expectedInterp: "37:7: duplicate case Bir in type switch",
+			expectedExec:   "37:7: duplicate case Bir in type switch",
+		},
+		{
+			fileName:       "switch43.go",
+			expectedInterp: "9:10: duplicate case 2.0 in expression switch",
+			expectedExec:   "9:10: duplicate case 2.0 (constant 2 of type uint8) in expression switch",
 		},
 	}
 
@@ -1,12 +1,26 @@
 package interp_test
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"go/ast"
+	"go/build"
+	"go/token"
+	"io"
+	"io/ioutil"
 	"log"
+	"math/bits"
+	"math/rand"
 	"net/http"
+	"os"
+	"path/filepath"
 	"reflect"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -42,6 +56,9 @@ func TestEvalArithmetic(t *testing.T) {
 		{desc: "mul_II", src: "2 * 3", res: "6"},
 		{desc: "mul_FI", src: "2.2 * 3", res: "6.6"},
 		{desc: "mul_IF", src: "3 * 2.2", res: "6.6"},
+		{desc: "quo_II", src: "-7 / 2", res: "-3"},
+		{desc: "quo_IF", src: "7 / 2 * 1.0", res: "3"},
+		{desc: "quo_FI", src: "7.0 / 2", res: "3.5"},
 		{desc: "quo_Z", src: "3 / 0", err: "1:28: invalid operation: division by zero"},
 		{desc: "rem_FI", src: "8.2 % 4", err: "1:28: invalid operation: operator % not defined on float64"},
 		{desc: "rem_Z", src: "8 % 0", err: "1:28: invalid operation: division by zero"},
@@ -54,12 +71,57 @@ func TestEvalArithmetic(t *testing.T) {
 		{desc: "shr_IN", src: "1 >> -1", err: "1:28: invalid operation: shift count type int, must be integer"},
 		{desc: "shr_IF", src: "1 >> 1.0", res: "0"},
 		{desc: "shr_IF1", src: "1 >> 1.1", err: "1:28: invalid operation: shift count type float64, must be integer"},
+		{desc: "add_OV", src: "uint8(200) + uint8(100)", err: "1:28: constant 300 overflows uint8"},
+		{desc: "quo_OV", src: "int8(-128) / -1", err: "1:28: constant 128 overflows int8"},
+		{desc: "shl_OV", src: "int32(1) << 31", err: "1:28: constant 2147483648 overflows int32"},
 		{desc: "neg_I", src: "-2", res: "-2"},
+		{desc: "neg_U", src: "u := uint8(1); -u", res: "255"},
+		{desc: "neg_OV", src: "-int8(-128)", err: "1:28: constant 128 overflows int8"},
+		{desc: "mul_FOV", src: "float32(3e38) * 2", err: "1:28: constant 6e+38 overflows float32"},
+		{desc: "add_FOV", src: "f := float32(1); f + 1e39", err: "1:49: 1e+39 overflows float32"},
+		{desc: "add_IOV", src: "i8 := int8(1); i8 + 300", err: "1:48: 300 overflows int8"},
+		{desc: "var_FOV", src: "var f32 float32 = 1e39", err: "1:32: 1e+39 overflows float32"},
+		{desc: "shr_BIG", src: "(1<<128 - 1) >> 100", res: "268435455"},
+		{desc: "gtr_BIG", src: "1<<70 > 1<<69", res: "true"},
+		{desc: "conv_BIG", src: "int64(1 << 70)", err: "1:28: constant 1180591620717411303424 overflows int64"},
+		{desc: "var_BIG", src: "var i64 int64 = 1 << 70", err: "1:30: 1180591620717411303424 overflows int64"},
 		{desc: "pos_I", src: "+2", res: "2"},
 		{desc: "bitnot_I", src: "^2", res: "-3"},
 		{desc: "bitnot_F", src: "^0.2", err: "1:28: invalid operation: operator ^ not defined on float64"},
 		{desc: "not_B", src: "!false", res: "true"},
 		{desc: "not_I", src: "!0", err: "1:28: invalid operation: operator ! not defined on int"},
+		{desc: "land_P", src: "a := 1; (1) == a && a < 2", res: "true"},
+	})
+}
+
+func TestEvalLiterals(t *testing.T) {
+	i := interp.New(interp.Options{})
+	runTests(t, i, []testCase{
+		{src: "1_000_000", res: "1000000"},
+		{src: "0b1010 + 0B1", res: "11"},
+		{src: "0o755 == 0755", res: "true"},
+		{src: "0x_ff", res: "255"},
+		{src: "0x1p-2", res: "0.25"},
+		{src: "0x1.8p1 * 2", res: "6"},
+		{src: "0b101i", res: "(0+5i)"},
+		{src: "0x1p4i", res: "(0+16i)"},
+		{src: "1_000_000_000_000_000_000_000 / 1e21", res: "1"},
+		{src: "uint8(0b1111_1111)", res: "255"},
+		{src: "var u uint8 = 0b1_0000_0000", err: "1:28: 256 overflows uint8"},
+		{src: "var i int = 0x1p-2", err: "1:26: 1/4 truncated to int"},
+		{src: "1__0", err: "1:30: '_' must separate successive digits"},
+		{src: "0b102", err: "1:32: invalid digit '2' in binary literal"},
+		{src: "0x1.0", err: "1:33: hexadecimal mantissa requires a 'p' exponent"},
+		{src: `'\U0001F600'`, res: "128512"},
+		{src: `'\x41' + '\101'`, res: "130"},
+		{src: `"\u00e9" == "é"`, res: "true"},
+		{src: `len("\xff\377")`, res: "2"},
+		{src: "len(`a\r\nb`)", res: "3"},
+		{src: `r := 'é'; r == '\u00e9'`, res: "true"},
+		{src: `r := '\101'; string(r)`, res: "A"},
+		{src: `'\q'`, err: "1:30: unknown escape sequence"},
+		{src: `'\uD800'`, err: "1:30: escape sequence is invalid Unicode code point"},
+		{src: `"\xZZ"`, err: "1:31: illegal character U+005A 'Z' in escape sequence"},
 	})
 }
 
@@ -70,14 +132,98 @@ func TestEvalAssign(t *testing.T) {
 		{src: `b := "Hello"; b += 1`, err: "1:42: invalid operation: mismatched types string and int"},
 		{src: `c := "Hello"; c -= " world"`, err: "1:42: invalid operation: operator -= not defined on string"},
 		{src: "e := 64.4; e %= 64", err: "1:39: invalid operation: operator %= not defined on float64"},
-		{src: "f := int64(3.2)", err: "1:33: truncated to integer"},
+		{src: "f := int64(3.2)", err: "1:33: constant 3.2 truncated to integer"},
 		{src: "g := 1; g <<= 8", res: "256"},
 		{src: "h := 1; h >>= 8", res: "0"},
+		{src: "j := []int{1}; u, v := j[0]", err: "1:43: assignment mismatch: 2 variables but 1 value"},
+		{pre: func() { eval(t, i, "var s string") }, src: "k := map[int]int{}; _, s = k[1]", err: "1:55: cannot use untyped bool as type string in assignment"},
+		{src: "l := map[int]int{}; s, _ = l[1]", err: "1:55: cannot use type int as type string in assignment"},
+		{src: "m := map[int]int{1: 2}; n, o := m[1]; o", res: "true"},
+		{src: `p := map[string]int{}; p["a"] += 2; p["a"]++; p["b"]--; p`, res: "map[a:3 b:-1]"},
+		{pre: func() { eval(t, i, "type T struct{ X struct{ Y int } }") }, src: "q := map[int]T{}; q[0].X = struct{ Y int }{}", err: "1:46: cannot assign to struct field q[0].X in map"},
+		{src: "r := map[int]T{}; r[0].X.Y++", err: "1:46: cannot assign to r[0].X.Y (neither addressable nor a map index expression)"},
+		{src: "w := map[int]*T{0: {}}; w[0].X.Y = 2; w[0].X.Y", res: "2"},
 	})
 }
 
-func TestEvalBuiltin(t *testing.T) {
+func TestEvalIncDec(t *testing.T) {
+	i := interp.New(interp.Options{})
+	eval(t, i, `func f() int { return 1 }`)
+	runTests(t, i, []testCase{
+		{src: `a := 1.5; a++; a`, res: "2.5"},
+		{src: `b := 1 + 2i; b--; b`, res: "(0+2i)"},
+		{src: `c := uint8(255); p := &c; *p++; c`, res: "0"},
+		{src: `f()++`, err: "1:28: cannot assign to f() (neither addressable nor a map index expression)"},
+		{src: `5++`, err: "1:28: cannot assign to 5 (neither addressable nor a map index expression)"},
+		{pre: func() { eval(t, i, `const d = 1`) }, src: `d++`, err: "1:28: cannot assign to d (neither addressable nor a map index expression)"},
+		{src: `e := "a"; e++`, err: "1:38: invalid operation: e++ (non-numeric type string)"},
+		{src: `g := true; g--`, err: "1:39: invalid operation: g-- (non-numeric type bool)"},
+	})
+}
+
+func TestEvalTypeNames(t *testing.T) {
+	i := interp.New(interp.Options{})
+	eval(t, i, `type Point struct{ X int }`)
+	eval(t, i, `type Level int; const ( Debug Level = iota; Info; Warn ); type Pt = Point`)
+	runTests(t, i, []testCase{
+		{src: `var a []byte; var b Point = a`, err: "1:42: cannot use type []uint8 as type main.Point in assignment"},
+		{src: `var c map[string][]Point; var d int = c`, err: "1:52: cannot use type map[string][]main.Point as type int in assignment"},
+		{src: `var e [2]int; var f []int = e`, err: "1:42: cannot use type [2]int as type []int in assignment"},
+		{src: `int(Point{})`, err: "1:28: cannot convert type main.Point to type int"},
+		{src: `[]int("hello")`, err: "1:28: cannot convert type string to type []int"},
+		{src: `g := struct{ X, Y int }{1, 2}; h := struct{ X, Y int }{}; h = g; h`, res: "{1 2}"},
+		{src: `j := struct{ X, Y int }{}; k := struct{ Y, X int }{}; k = j`, err: "1:86: cannot use type struct{X int;Y int;} as type struct{Y int;X int;} in assignment"},
+		{src: "l := struct{ X int }{}; m := struct{ X int `json:\"x\"` }{}; m = l", err: "1:91: cannot use type struct{X int;} as type struct{X int \"json:\\\"x\\\"\";} in assignment"},
+		{src: `n := struct{ X int }{}; var p Point = n; p`, res: "{0}"},
+		{pre: func() { eval(t, i, `var q interface{ A(); B() }; var r interface{ B(); A() }`) }, src: `q = r; q == nil`, res: "true"},
+		{src: `var s int = Info`, err: "1:26: cannot use type main.Level as type int in assignment"},
+		{src: `var lv Level = 2; var w int = lv`, err: "1:44: cannot use type main.Level as type int in assignment"},
+		{src: `Info + Warn*2 == Level(5)`, res: "true"},
+		{src: `pt := Pt{1}; var u Point = pt; u`, res: "{1}"},
+	})
+}
+
+func TestEvalConversion(t *testing.T) {
 	i := interp.New(interp.Options{})
+	eval(t, i, `type MyString string`)
+	runTests(t, i, []testCase{
+		{src: `MyString([]byte("abc"))`, res: "abc"},
+		{src: `string(65)`, res: "A"},
+		{src: `MyString(-1)`, res: "�"},
+		{src: `int(2.0)`, res: "2"},
+		{src: `int(1.5)`, err: "1:28: constant 1.5 truncated to integer"},
+		{src: `int8(300)`, err: "1:28: constant 300 overflows int8"},
+		{src: `int8(200)`, err: "1:28: constant 200 overflows int8"},
+		{src: `uint(-1)`, err: "1:28: constant -1 overflows uint"},
+		{src: `float32(1e300)`, err: "1:28: constant 1e+300 overflows float32"},
+		{src: `bool(1)`, err: "1:28: cannot convert type int to type bool"},
+		{src: `int(nil)`, err: "1:28: cannot convert nil to type int"},
+		{pre: func() { eval(t, i, `var a interface{} = 1`) }, src: `int(a)`, err: "1:28: cannot convert type interface{} to type int: need type assertion"},
+		{src: `b := []int{1, 2}; [2]string(b)`, err: "1:46: cannot convert type []int to type [2]string"},
+		{src: "c := struct{ X int }{1}; struct{ X int `json:\"x\"` }(c)", res: "{1}"},
+		{src: `d := struct{ X int }{1}; struct{ Y int }(d)`, err: "1:53: cannot convert type struct{X int;} to type struct{Y int;}"},
+		{src: `e := interface{}(1); _, ok := e.(int); ok`, res: "true"},
+	})
+}
+
+func TestEvalString(t *testing.T) {
+	i := interp.New(interp.Options{})
+	eval(t, i, `const c = "hello"`)
+	runTests(t, i, []testCase{
+		{src: `a := "hello"; a[0] = 'j'`, err: "1:42: cannot assign to a[0] (strings are immutable)"},
+		{src: `b := "hello"; b[1]++`, err: "1:42: cannot assign to b[1] (strings are immutable)"},
+		{src: `d := "hello"; var e byte = d[1]; e`, res: "101"},
+		{src: `f := "hello"; f[1:3]`, res: "el"},
+		{src: `c[1:]`, res: "ello"},
+		{src: `c[0]`, res: "104"},
+		{src: `c[5]`, err: "1:30: invalid argument: index 5 out of bounds [0:5]"},
+		{src: `g := [2]int{}; g[2]`, err: "1:45: invalid argument: index 2 out of bounds [0:2]"},
+		{src: `h := []int{1}; h[-1]`, err: "1:45: invalid argument: index -1 (constant of type int) must not be negative"},
+	})
+}
+
+func TestEvalBuiltin(t *testing.T) {
+	i := interp.New(interp.Options{GoVersion: "go1.21"})
 	runTests(t, i, []testCase{
 		{src: `a := []int{}; a = append(a, 1); a`, res: "[1]"},
 		{src: `b := []int{1}; b = append(a, 2, 3); b`, res: "[1 2 3]"},
@@ -85,6 +231,24 @@ func TestEvalBuiltin(t *testing.T) {
 		{src: `string(append([]byte("hello "), "world"...))`, res: "hello world"},
 		{src: `e := "world"; string(append([]byte("hello "), e...))`, res: "hello world"},
 		{src: `f := []byte("Hello"); copy(f, "world"); string(f)`, res: "world"},
+		{src: `real(2 + 3i) + imag(2 + 3i)`, res: "5"},
+		{src: `real(1)`, res: "1"},
+		{src: `complex(1, 2) == 1+2i`, res: "true"},
+		{src: `g := complex(float32(1), 2); g * 2`, res: "(2+4i)"},
+		{src: `h := complex(3.0, 4.0); h / (1 + 2i)`, res: "(2.2-0.4i)"},
+		{src: `int(1 + complex(1, 0))`, res: "2"},
+		{src: `complex64(1) + complex128(2)`, err: "1:28: invalid operation: mismatched types complex64 and complex128"},
+		{src: `complex(1, "a")`, err: "1:28: invalid types int and string"},
+		{src: `imag("a")`, err: "1:28: invalid complex type string"},
+		{src: `max(1, 2.5, 'a')`, res: "97"},
+		{src: `n := 3; min(n, 2, 5)`, res: "2"},
+		{src: `s := []int{1, 2}; clear(s); s`, res: "[0 0]"},
+		{src: `min()`, err: "1:28: not enough arguments for min() (expected 1, found 0)"},
+		{src: `max(1, "a")`, err: "1:35: invalid argument: mismatched types int and string in max"},
+		{src: `max(true)`, err: "1:32: invalid argument: bool cannot be ordered"},
+		{src: `clear(1)`, err: "1:34: invalid argument: int is not a map or slice"},
+		{pre: func() { eval(t, i, `func len(s string) int { return 42 }`) }, src: `len("x") + cap([]int{})`, res: "42"},
+		{pre: func() { eval(t, i, `var iota, nil = 3, 4`) }, src: `iota + nil`, res: "7"},
 	})
 }
 
@@ -97,6 +261,56 @@ func TestEvalDecl(t *testing.T) {
 		{pre: func() { eval(t, i, "func f() int {return 4}") }, src: "f()", res: "4"},
 		{pre: func() { eval(t, i, `package foo; var I = 2`) }, src: "foo.I", res: "2"},
 		{pre: func() { eval(t, i, `package foo; func F() int {return 5}`) }, src: "foo.F()", res: "5"},
+		{src: "var a [2.5]int", err: "1:21: array length 2.5 must be integer"},
+		{src: "var b [-1]int", err: "1:21: invalid array length -1"},
+		{src: "n := 3; var c [n]int", err: "1:43: array length must be constant"},
+		{pre: func() { eval(t, i, "const N = 3") }, src: `len([N * 2]int{}) + len([len("ab")]int{})`, res: "8"},
+		{src: "len([...]int{5: 1, 2})", res: "7"},
+	})
+}
+
+func TestEvalBlank(t *testing.T) {
+	i := interp.New(interp.Options{})
+	runTests(t, i, []testCase{
+		{src: "a := _", err: "1:33: cannot use _ as value"},
+		{pre: func() { eval(t, i, "func f(i int) int { return i }") }, src: "f(_)", err: "1:30: cannot use _ as value"},
+		{src: "_ + 1", err: "1:28: cannot use _ as value"},
+		{src: "_ := 3", err: "1:28: no new variables on left side of :="},
+		{src: "_, _ := 1, 2", err: "1:28: no new variables on left side of :="},
+		{src: "func g(_ int) int { return _ }", err: "1:41: cannot use _ as value"},
+		{pre: func() { eval(t, i, "type S struct{ _ int; A int; _ string }") }, src: "s := S{A: 1}; s._", err: "1:44: cannot refer to blank field or method"},
+		{src: "S{_: 1}", err: "1:30: unknown field _ in struct literal of type main.S"},
+		{src: "S{2, 3, \"a\"}.A", res: "3"},
+		{pre: func() { eval(t, i, "type T struct{}; func (T) _() {}; func (T) _() {}") }, src: "T{}._()", err: "1:32: cannot refer to blank field or method"},
+		{src: "type I interface{ _() }", err: "1:32: methods must have a unique non-blank name"},
+		{pre: func() { eval(t, i, "var n int; func h() int { n++; return n }") }, src: "_ = h(); _, _ = h(), h(); n", res: "3"},
+	})
+}
+
+func TestEvalLabel(t *testing.T) {
+	i := interp.New(interp.Options{})
+	runTests(t, i, []testCase{
+		{src: `a := 0; L: for { for { a++; break L } }; a`, res: "1"},
+		{src: `L: for { for { break M } }`, err: "1:49: invalid break label M"},
+		{src: `L: switch { case true: for { continue L } }`, err: "1:66: invalid continue label L"},
+		{src: `L: if true { break L }`, err: "1:47: invalid break label L"},
+		{src: `L: for { func() { break L }() }`, err: "1:52: invalid break label L"},
+	})
+}
+
+func TestEvalSwitch(t *testing.T) {
+	i := interp.New(interp.Options{})
+	runTests(t, i, []testCase{
+		{src: `a := 1; switch a := 2; a { case 2: a = 5 }; a`, res: "1"},
+		{src: `switch b := 3; b { case 3: }; b`, err: "1:58: undefined: b"},
+		{src: `if c := 3; c > 1 {}; c`, err: "1:49: undefined: c"},
+		{src: `switch 1 { case 1: fallthrough }`, err: "1:47: cannot fallthrough final case in switch"},
+		{src: `switch 1 { case 1: fallthrough; a++; case 2: }`, err: "1:47: fallthrough statement out of place"},
+		{pre: func() { eval(t, i, `var d interface{}`) }, src: `switch d.(type) { case int: fallthrough; default: }`, err: "1:56: cannot fallthrough in type switch"},
+		{src: `switch 1 { case 1, 2: case 1: }`, err: "1:55: duplicate case 1 in expression switch\n\tprevious case at 1:44"},
+		{src: `switch s := "a"; s { case "a", "b": case "b": }`, err: "1:69: duplicate case \"b\" in expression switch\n\tprevious case at 1:59"},
+		{pre: func() { eval(t, i, `var u8 uint8`) }, src: `switch u8 { case 2: case 2.0: }`, err: "1:53: duplicate case 2.0 in expression switch\n\tprevious case at 1:45"},
+		{src: `switch d.(type) { case int, string: case int: }`, err: "1:69: duplicate case int in type switch\n\tprevious case at 1:51"},
 	})
 }
 
@@ -116,6 +330,8 @@ func TestEvalImport(t *testing.T) {
 	i.Use(stdlib.Symbols)
 	runTests(t, i, []testCase{
 		{pre: func() { eval(t, i, `import "time"`) }, src: "2 * time.Second", res: "2s"},
+		{pre: func() { eval(t, i, "const cd = time.Duration(10) * time.Millisecond") }, src: "cd", res: "10ms"},
+		{src: "x := 3; const cx = time.Duration(x); cx", err: "1:47: time.Duration(x) (value of type time.Duration) is not constant"},
 	})
 }
 
@@ -172,6 +388,14 @@ func TestEvalNil(t *testing.T) {
 			src: "Bar()",
 			res: "<nil>",
 		},
+		{
+			desc: "zero interface field",
+			pre: func() {
+				eval(t, i, `type S struct{ I interface{} }`)
+			},
+			src: "s := S{}; x := s.I; s.I == nil && x == nil",
+			res: "true",
+		},
 	})
 }
 
@@ -247,6 +471,142 @@ func f() string {
 	}
 }
 
+func TestEvalRecursiveType(t *testing.T) {
+	i := interp.New(interp.Options{})
+	runTests(t, i, []testCase{
+		{pre: func() { eval(t, i, "type L struct{ v int; next *L; sub []L; m map[string]*L }") }, src: "l := L{v: 1, next: &L{v: 2}}; l.next.v", res: "2"},
+		{pre: func() { eval(t, i, "type A struct{ b *B }; type B struct{ a *A; v int }") }, src: "a := A{b: &B{v: 3}}; a.b.v", res: "3"},
+		{
+			pre: func() {
+				eval(t, i, "type S struct{ kids []S; v int }")
+				eval(t, i, "func sum(s S) int { for _, k := range s.kids { s.v += sum(k) }; return s.v }")
+			},
+			src: "sum(S{kids: []S{{v: 1}, {v: 2, kids: []S{{v: 3}}}}, v: 4})",
+			res: "10",
+		},
+		{pre: func() { eval(t, i, "type F struct{ f func(F) int; v int }") }, src: "f := F{f: func(x F) int { return 2 * x.v }, v: 21}; f.f(f)", res: "42"},
+		{src: "type T struct{ t T }", err: "1:19: invalid recursive type T"},
+		{src: "type C struct{ d D }; type D struct{ c C }", err: "1:41: invalid recursive type D"},
+		{src: "type E [2]E", err: "1:24: invalid recursive type E"},
+	})
+}
+
+func TestEvalForwardDecl(t *testing.T) {
+	i := interp.New(interp.Options{})
+	runTests(t, i, []testCase{
+		{pre: func() { eval(t, i, "var v1 T1; type T1 struct{ a int }") }, src: "v1.a", res: "0"},
+		{pre: func() { eval(t, i, "var a2 [N2 * 2]int; const N2 = M2 + 1; const M2 = 2") }, src: "len(a2)", res: "6"},
+		{pre: func() { eval(t, i, "const (A3 = B3 + 1; B3 = iota * 10; C3)") }, src: "A3 + C3", res: "31"},
+		{pre: func() {
+			eval(t, i, "var v4 = f4(); func f4() T4 { return T4{u: 2} }; func (t T4) get() U4 { return t.u }; type T4 struct{ u U4 }; type U4 int")
+		}, src: "v4.get()", res: "2"},
+		{pre: func() { eval(t, i, `var s5 = S5{}.x; type S5 struct{ x [L5]byte }; const L5 = len("abcd")`) }, src: "len(s5)", res: "4"},
+		{src: "var x6 = y6", err: "1:23: undefined: y6"},
+		{src: "var x7 Undef7", err: "1:21: undefined: Undef7"},
+		{src: "const c8 = d8 + 1", err: "1:25: undefined: d8"},
+		{src: "var a9 [N9]int", err: "1:22: undefined: N9"},
+		{src: "type T10 struct{ u Undef10 }", err: "1:33: undefined: Undef10"},
+	})
+}
+
+func TestEvalEmbedded(t *testing.T) {
+	i := interp.New(interp.Options{})
+	i.Use(stdlib.Symbols)
+	eval(t, i, `
+		import "bytes"
+
+		type A struct{ X, Y int }
+
+		func (A) Hello() string { return "A" }
+
+		type B struct {
+			A
+			Y int
+		}
+
+		type C struct {
+			A
+			Z int
+		}
+
+		type D struct {
+			B
+			C
+		}
+
+		type H struct{ Len int }
+
+		type G struct {
+			H
+			*bytes.Buffer
+		}
+
+		var d D
+		var g G
+	`)
+	runTests(t, i, []testCase{
+		{src: "d.X", err: "1:28: ambiguous selector X: B.A.X and C.A.X"},
+		{src: "d.Hello()", err: "1:28: ambiguous selector Hello: B.A.Hello and C.A.Hello"},
+		{src: "d.Z", res: "0"},
+		{src: "d.Y", res: "0"},
+		{src: "g.Len", err: "1:28: ambiguous selector Len: H.Len and Buffer.Len"},
+		{src: "g.H.Len", res: "0"},
+	})
+}
+
+func TestEvalInterfaceEmbedded(t *testing.T) {
+	i := interp.New(interp.Options{})
+	i.Use(stdlib.Symbols)
+	eval(t, i, `
+		import (
+			"io"
+			"io/ioutil"
+			"strings"
+		)
+
+		type ReadCloser interface {
+			io.Reader
+			Close() error
+		}
+
+		type ReadWriteCloser interface {
+			ReadCloser
+			io.WriteCloser
+		}
+
+		type F struct{ s string }
+
+		func (f *F) Read(p []byte) (int, error)  { return copy(p, f.s), io.EOF }
+		func (f *F) Write(p []byte) (int, error) { f.s += string(p); return len(p), nil }
+		func (f *F) Close() error                { return nil }
+
+		var rwc ReadWriteCloser = &F{}
+		var rc ReadCloser = ioutil.NopCloser(strings.NewReader("bin"))
+		var m map[string]any = map[string]any{"a": 1}
+		var a any = rwc
+
+		func kind(v any) string {
+			switch v.(type) {
+			case nil:
+				return "nil"
+			case any:
+				return "any"
+			}
+			return ""
+		}
+	`)
+	runTests(t, i, []testCase{
+		{src: `rwc.Write([]byte("hello")); b, _ := ioutil.ReadAll(rwc); string(b)`, res: "hello"},
+		{src: "rwc.Close()", res: "<nil>"},
+		{src: "b, _ := ioutil.ReadAll(rc); string(b)", res: "bin"},
+		{src: "_, ok := rc.(io.WriteCloser); ok", res: "false"},
+		{src: "_, ok := a.(io.ReadWriteCloser); ok", res: "true"},
+		{src: `kind(m["a"]) + kind(m["b"])`, res: "anynil"},
+		{src: "type X interface { io.Reader; Read() int }", err: "1:44: duplicate method Read"},
+		{src: "type Y interface { M(); M() }", err: "1:38: duplicate method M"},
+	})
+}
+
 func TestEvalComposite0(t *testing.T) {
 	i := interp.New(interp.Options{})
 	eval(t, i, `
@@ -288,6 +648,19 @@ func Foo() {
 	}
 }
 
+func TestEvalCompositeElided(t *testing.T) {
+	i := interp.New(interp.Options{})
+	runTests(t, i, []testCase{
+		{src: "a := []int{{1}}", err: "1:39: invalid composite literal type int"},
+		{pre: func() { eval(t, i, "type Point struct{ X, Y int }") }, src: "b := struct{ P Point }{{1, 2}}", err: "1:51: missing type in composite literal"},
+		{src: "c := []interface{}{{1}}", err: "1:47: invalid composite literal type interface{}"},
+		{src: "d := struct{ P Point }{P: {1, 2}}", err: "1:54: missing type in composite literal"},
+		{src: "h := map[string]int{{1}: 2}", err: "1:48: invalid composite literal type string"},
+		{src: "e := []*Point{{1, 2}, {Y: 3}}; e[1].Y", res: "3"},
+		{src: "f := []*[][]int{{{1}, {2, 3}}}; (*f[0])[1][1]", res: "3"},
+	})
+}
+
 func TestEvalComparison(t *testing.T) {
 	i := interp.New(interp.Options{})
 	runTests(t, i, []testCase{
@@ -304,6 +677,21 @@ func TestEvalComparison(t *testing.T) {
 			`,
 			err: "7:13: invalid operation: mismatched types main.Foo and main.Bar",
 		},
+		{
+			desc: "slices",
+			pre:  func() { eval(t, i, "var a, b []int") },
+			src:  "a == b",
+			err:  "1:28: invalid operation: operator == not defined on []int (can only be compared to nil)",
+		},
+		{
+			desc: "maps",
+			pre:  func() { eval(t, i, "var c, d map[int]int") },
+			src:  "c != d",
+			err:  "1:28: invalid operation: operator != not defined on map[int]int (can only be compared to nil)",
+		},
+		{desc: "slice and map", src: "a == c", err: "1:28: invalid operation: mismatched types []int and map[int]int"},
+		{desc: "untyped nils", src: "nil == nil", err: "1:28: invalid operation: operator == not defined on nil"},
+		{desc: "nil func", pre: func() { eval(t, i, "var g func()") }, src: "nil != g", res: "false"},
 	})
 }
 
@@ -311,6 +699,67 @@ func TestEvalCompositeArray(t *testing.T) {
 	i := interp.New(interp.Options{})
 	runTests(t, i, []testCase{
 		{src: "a := []int{1, 2, 7: 20, 30}", res: "[1 2 0 0 0 0 0 20 30]"},
+		{pre: func() { eval(t, i, "const K = 3") }, src: `b := [...]string{K: "k", "l", 0: "z"}`, res: "[z   k l]"},
+		{src: "c := []int{1: 1, 1: 2}", err: "1:45: duplicate index 1 in array or slice literal"},
+		{src: "d := []int{1, 0: 2}", err: "1:42: duplicate index 0 in array or slice literal"},
+		{src: "e := []int{-1: 1}", err: "1:39: index must be non-negative integer constant"},
+		{src: "n := 1; f := []int{n: 1}", err: "1:47: index must be non-negative integer constant"},
+		{src: "g := [2]int{5: 1}", err: "1:40: index 5 out of bounds [0:2]"},
+		{src: "h := [2]int{1, 2, 3}", err: "1:46: index 2 out of bounds [0:2]"},
+		{src: "j := []int{1.5: 1}", err: "1:39: index must be non-negative integer constant"},
+	})
+}
+
+func TestEvalCompositeStruct(t *testing.T) {
+	i := interp.New(interp.Options{})
+	i.Use(stdlib.Symbols)
+	runTests(t, i, []testCase{
+		{pre: func() { eval(t, i, `type T struct{ X, Y int; s string }`) }, src: "a := T{1, Y: 2}", err: "1:38: mixture of field:value and value elements in struct literal"},
+		{src: "b := T{1, 2}", err: "1:33: too few values in struct literal of type main.T"},
+		{src: `c := T{1, 2, "a", 4}`, err: "1:46: too many values in struct literal of type main.T"},
+		{src: `d := T{1, "a", "b"}`, err: "1:38: cannot use \"a\" (type untyped string) as type int in struct literal"},
+		{src: "e := T{W: 1}", err: "1:35: unknown field W in struct literal of type main.T"},
+		{src: "f := T{X: 1, X: 2}", err: "1:41: duplicate field name X in struct literal"},
+		{pre: func() { eval(t, i, `type E struct{ T; Z int }`) }, src: "g := E{T: T{X: 1}, Z: 2}; g.X + g.Z", res: "3"},
+		{src: `h := E{T{1, 2, "a"}, 3}; h.s`, res: "a"},
+		{pre: func() { eval(t, i, `import ("image"; "time")`) }, src: "j := image.Point{1, Y: 2}", err: "1:48: mixture of field:value and value elements in struct literal"},
+		{src: "k := image.Point{1}", err: "1:33: too few values in struct literal of type image.Point"},
+		{src: "l := image.Point{Z: 1}", err: "1:45: unknown field Z in struct literal of type image.Point"},
+		{src: "m := time.Time{wall: 1}", err: "1:43: cannot refer to unexported field wall in struct literal of type time.Time"},
+		{src: "n := time.Time{1, 2, nil}", err: "1:43: implicit assignment to unexported field wall in struct literal of type time.Time"},
+		{src: "o := image.Rectangle{Min: image.Point{1, 2}}; o.Min.Y", res: "2"},
+	})
+}
+
+func TestEvalMethodReceiver(t *testing.T) {
+	i := interp.New(interp.Options{})
+	i.Use(stdlib.Symbols)
+	runTests(t, i, []testCase{
+		{pre: func() { eval(t, i, `type T struct{ X int }; type P *T; type I interface{ M() }`) }, src: "func (t **T) A() {}", err: "1:23: invalid receiver type **T"},
+		{pre: func() { eval(t, i, `import "time"`) }, src: "func (t time.Time) A() {}", err: "1:22: cannot define new methods on non-local type time.Time"},
+		{src: "func (t *time.Time) A() {}", err: "1:23: cannot define new methods on non-local type time.Time"},
+		{src: "func (i int) A() {}", err: "1:22: cannot define new methods on non-local type int"},
+		{src: "func (s []int) A() {}", err: "1:22: invalid receiver type []int"},
+		{src: "func (i I) A() {}", err: "1:22: invalid receiver type I (pointer or interface type)"},
+		{src: "func (i *I) A() {}", err: "1:23: invalid receiver type I (pointer or interface type)"},
+		{src: "func (p P) A() {}", err: "1:22: invalid receiver type P (pointer or interface type)"},
+		{src: "func (t T) X() {}", err: "1:25: type T has both field and method named X"},
+		{src: "func (t T) A() {}; func (t *T) A() {}", err: "1:45: method T.A already declared at 1:25"},
+		{pre: func() { eval(t, i, "func (t T) _() {}; func (t T) _() {}; func (t T) B() int { return t.X + 1 }") }, src: "T{1}.B()", res: "2"},
+	})
+}
+
+func TestEvalRedeclaration(t *testing.T) {
+	i := interp.New(interp.Options{})
+	runTests(t, i, []testCase{
+		{src: "var a int; var a string", err: "1:29: a redeclared in this block\n\tprevious declaration at 1:18"},
+		{src: "func f() {}; var f int", err: "1:31: f redeclared in this block\n\tprevious declaration at 1:19"},
+		{src: "func g() { x := 1; x := 2 }", err: "1:33: no new variables on left side of :="},
+		{src: "func h(a, b int) (b int) { return }", err: "1:32: b redeclared in this block\n\tprevious declaration at 1:24"},
+		// A declaration of a previous evaluation is redefined, as in the REPL.
+		{pre: func() { eval(t, i, "func k() int { return 1 }"); eval(t, i, "func k() int { return 2 }") }, src: "k()", res: "2"},
+		{pre: func() { eval(t, i, "var v = 1"); eval(t, i, `var v = "a"`) }, src: "v", res: "a"},
+		{pre: func() { eval(t, i, "w := 1") }, src: "w := 2; w", res: "2"},
 	})
 }
 
@@ -344,6 +793,12 @@ func TestEvalMethod(t *testing.T) {
 		var o = One{r}
 		var root interface{} = &Root{Name: "test1"}
 		var one interface{} = &One{Root{Name: "test2"}}
+		var m = map[string]Root{"a": r}
+		var np *Root
+		var no *One
+
+		func newRoot() Root { return Root{"N"} }
+		func newRoots() []Root { return []Root{{"L"}} }
 	`)
 	runTests(t, i, []testCase{
 		{src: "r.Hello()", res: "Hello R"},
@@ -352,6 +807,42 @@ func TestEvalMethod(t *testing.T) {
 		{src: "(&o).Hello()", res: "Hello R"},
 		{src: "root.(Hi).Hello()", res: "Hello test1"},
 		{src: "one.(Hi).Hello()", res: "Hello test2"},
+		{src: `h := r.Hello; r.Name = "S"; h()`, res: "Hello S"},
+		{src: `h := m["a"].Hello; h()`, err: "1:33: cannot call pointer method Hello on main.Root"},
+		{src: `m["a"].Hello()`, err: "1:28: cannot call pointer method Hello on main.Root"},
+		{src: `Root{"L"}.Hello()`, err: "1:28: cannot call pointer method Hello on main.Root"},
+		{src: "newRoot().Hello()", err: "1:28: cannot call pointer method Hello on main.Root"},
+		{src: "root.(Root).Hello()", err: "1:28: cannot call pointer method Hello on main.Root"},
+		{src: "[1]Root{r}[0].Hello()", err: "1:28: cannot call pointer method Hello on main.Root"},
+		{src: "newRoots()[0].Hello()", res: "Hello L"},
+		{src: "one.(*One).Root.Hello()", res: "Hello test2"},
+		{src: "pr := &r; ppr := &pr; ppr.Name", err: "1:50: undefined selector: Name"},
+		{src: "pr := &r; ppr := &pr; ppr.Hello()", err: "1:50: undefined selector: Hello"},
+		{src: "np.Name", err: "1:28: runtime error: invalid memory address or nil pointer dereference"},
+		{src: "no.Hello()", err: "1:28: runtime error: invalid memory address or nil pointer dereference"},
+		{src: "(*np).Name", err: "1:29: runtime error: invalid memory address or nil pointer dereference"},
+	})
+}
+
+func TestEvalLiteralReceiver(t *testing.T) {
+	i := interp.New(interp.Options{})
+	i.Use(stdlib.Symbols)
+	eval(t, i, `import ("bytes"; "time")`)
+	eval(t, i, `type Point struct{ X, Y int }`)
+	eval(t, i, `func (p Point) Sum() int { return p.X + p.Y }`)
+	eval(t, i, `func (p *Point) Scale(f int) { p.X *= f; p.Y *= f }`)
+	eval(t, i, `type W struct{ bytes.Buffer }`)
+	eval(t, i, `const c = 90`)
+	runTests(t, i, []testCase{
+		{src: "time.Minute.String()", res: "1m0s"},
+		{src: "time.Duration(c).String()", res: "90ns"},
+		{src: "Point{1, 2}.Sum()", res: "3"},
+		{src: "s := Point{3, 4}.Sum; s()", res: "7"},
+		{src: "[]bytes.Buffer{{}}[0].Len()", res: "0"},
+		{src: "Point{1, 2}.Scale(2)", err: "1:28: cannot call pointer method Scale on main.Point"},
+		{src: "bytes.Buffer{}.Len()", err: "1:28: cannot call pointer method Len on bytes.Buffer"},
+		{src: "time.Time{}.UnmarshalJSON", err: "1:28: cannot call pointer method UnmarshalJSON on time.Time"},
+		{src: "W{}.Len()", err: "1:28: cannot call pointer method Len on main.W"},
 	})
 }
 
@@ -406,6 +897,27 @@ func TestEvalFunctionCallWithFunctionParam(t *testing.T) {
 	}
 }
 
+func TestEvalProfile(t *testing.T) {
+	i := interp.New(interp.Options{Profile: true})
+	eval(t, i, `
+		type T struct{}
+		func (t *T) Get() int { return 1 }
+		func (t T) Val() int { return 2 }
+		func f() int { return func() int { return new(T).Get() + T{}.Val() }() }
+	`)
+	eval(t, i, "f()")
+
+	p := i.Profile()
+	for _, name := range []string{"main.f", "main.f.func1", "main.(*T).Get", "main.T.Val"} {
+		if _, ok := p[name]; !ok {
+			t.Errorf("missing %s in profile %v", name, p)
+		}
+	}
+	if p := interp.New(interp.Options{}).Profile(); p != nil {
+		t.Errorf("unexpected profile %v, profiling is not enabled", p)
+	}
+}
+
 func TestEvalMissingSymbol(t *testing.T) {
 	defer func() {
 		r := recover()
@@ -432,97 +944,2708 @@ func TestEvalMissingSymbol(t *testing.T) {
 	}
 }
 
-func TestEvalWithContext(t *testing.T) {
-	tests := []testCase{
-		{
-			desc: "for {}",
-			src: `(func() {
-				      for {}
-			      })()`,
-		},
-		{
-			desc: "select {}",
-			src: `(func() {
-				     select {}
-			     })()`,
-		},
-		{
-			desc: "blocked chan send",
-			src: `(func() {
-			         c := make(chan int)
-				     c <- 1
-				 })()`,
-		},
-		{
-			desc: "blocked chan recv",
-			src: `(func() {
-			         c := make(chan int)
-				     <-c
-			     })()`,
-		},
-		{
-			desc: "blocked chan recv2",
-			src: `(func() {
-			         c := make(chan int)
-				     _, _ = <-c
-			     })()`,
-		},
-		{
-			desc: "blocked range chan",
-			src: `(func() {
-			         c := make(chan int)
-				     for range c {}
-			     })()`,
-		},
-		{
-			desc: "double lock",
-			src: `(func() {
-			         var mu sync.Mutex
-				     mu.Lock()
-				     mu.Lock()
-			      })()`,
-		},
+type binVar struct {
+	N int
+	A [2]int
+}
+
+func (b *binVar) Inc() { b.N++ }
+
+func TestEvalBinVar(t *testing.T) {
+	var v binVar
+	var p *binVar
+	var n int
+	var l []int
+	i := interp.New(interp.Options{})
+	i.Use(interp.Exports{"p": map[string]reflect.Value{
+		"T": reflect.ValueOf((*binVar)(nil)),
+		"V": reflect.ValueOf(&v).Elem(),
+		"P": reflect.ValueOf(&p).Elem(),
+		"N": reflect.ValueOf(&n).Elem(),
+		"L": reflect.ValueOf(&l).Elem(),
+	}})
+	eval(t, i, `import "p"`)
+
+	runTests(t, i, []testCase{
+		{src: "p.N = 3; p.N++; p.N += 2; p.N", res: "6"},
+		{src: "p.V = p.T{N: 10}; p.V.N", res: "10"},
+		{src: "p.V.N++; p.V.Inc(); p.V.A[1] = 7; p.V", res: "{12 [0 7]}"},
+		{src: "p.P = &p.V; p.P.Inc(); p.P.N", res: "13"},
+		{src: "p.L = append(p.L, 1, 2); p.L = append(p.L, 3); len(p.L)", res: "3"},
+	})
+
+	// The host variables are modified.
+	if n != 6 || v.N != 13 || v.A[1] != 7 || p != &v || len(l) != 3 || l[2] != 3 {
+		t.Errorf("unexpected host values: %d %+v %p %v", n, v, p, l)
 	}
+	n, v.N = 100, 100
+	runTests(t, i, []testCase{
+		{src: "p.N + p.V.N", res: "200"},
+	})
+}
 
-	for _, test := range tests {
-		done := make(chan struct{})
-		src := test.src
-		go func() {
-			defer close(done)
-			i := interp.New(interp.Options{})
-			i.Use(stdlib.Symbols)
-			_, err := i.Eval(`import "sync"`)
-			if err != nil {
-				t.Errorf(`failed to import "sync": %v`, err)
-				return
-			}
-			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
-			defer cancel()
-			_, err = i.EvalWithContext(ctx, src)
-			switch err {
-			case context.DeadlineExceeded:
-				// Successful cancellation.
+type binHandler func(ctx context.Context, name string) (string, error)
 
-				// Check we can still execute an expression.
-				v, err := i.EvalWithContext(context.Background(), "1+1\n")
-				if err != nil {
-					t.Errorf("failed to evaluate expression after cancellation: %v", err)
-				}
-				got := v.Interface()
-				if got != 2 {
-					t.Errorf("unexpected result of eval(1+1): got %v, want 2", got)
-				}
-			case nil:
-				t.Errorf("unexpected success evaluating expression %q", test.desc)
-			default:
-				t.Errorf("failed to evaluate expression %q: %v", test.desc, err)
-			}
-		}()
-		select {
-		case <-time.After(time.Second):
-			t.Errorf("timeout failed to terminate execution of %q", test.desc)
-		case <-done:
+func TestEvalBinFuncType(t *testing.T) {
+	var handlers []binHandler
+	i := interp.New(interp.Options{})
+	i.Use(stdlib.Symbols)
+	i.Use(interp.Exports{"p": map[string]reflect.Value{
+		"Handler":  reflect.ValueOf((*binHandler)(nil)),
+		"Register": reflect.ValueOf(func(h binHandler) { handlers = append(handlers, h) }),
+	}})
+	eval(t, i, `
+import (
+	"context"
+	"p"
+)
+
+var prefix = "hello "
+
+func hello(c context.Context, s string) (string, error) { return prefix + s, nil }
+
+func register() {
+	p.Register(hello)
+	p.Register(func(_ context.Context, n string) (string, error) { return prefix + n + "!", nil })
+	var h p.Handler = hello
+	f := (func(context.Context, string) (string, error))(h)
+	p.Register(p.Handler(f))
+}`)
+
+	eval(t, i, "register()")
+
+	var got []string
+	for _, h := range handlers {
+		s, err := h(context.Background(), "world")
+		if err != nil {
+			t.Fatal(err)
 		}
+		got = append(got, s)
+	}
+	if want := "hello world,hello world!,hello world"; strings.Join(got, ",") != want {
+		t.Errorf("got %q, want %q", strings.Join(got, ","), want)
+	}
+	runTests(t, i, []testCase{
+		{pre: func() { eval(t, i, "var g func(context.Context, string) (string, error) = p.Handler(hello)") }, src: `prefix = "hi "; g(context.Background(), "you")`, res: "hi you"},
+		{src: "var k func(string) = func(int) {}", err: "1:35: cannot use type func(int,)() as type func(string,)() in assignment"},
+		{src: "var l func(...int) = func([]int) {}", err: "1:35: cannot use type func([]int,)() as type func(...int,)() in assignment"},
+	})
+}
+func TestEvalBinCall(t *testing.T) {
+	i := interp.New(interp.Options{})
+	i.Use(stdlib.Symbols)
+	eval(t, i, `import ("math"; "os"; "strings"; "time")`)
+
+	runTests(t, i, []testCase{
+		{src: "math.Abs(1 << 70)", res: "1.1805916207174113e+21"},
+		{src: "math.Float32bits(1.5)", res: "1069547520"},
+		{src: "time.Second.Truncate(1e6)", res: "1s"},
+		{src: "os.FileMode(0755) | os.ModeDir", res: "drwxr-xr-x"},
+		{src: "strings.Repeat(strings.Repeat(\"x\", 2), 2)", res: "xxxx"},
+		{src: "p := math.Pi; p", res: "3.141592653589793"},
+		{src: "time.Sleep(1.5)", err: "1:39: 3/2 truncated to int64"},
+		{src: "math.Float32bits(1e39)", err: "1:45: 1e+39 overflows float32"},
+		{src: "os.Chmod(\"x\", -1)", err: "1:42: -1 overflows uint32"},
+		{src: "math.Sqrt(\"a\")", err: "1:38: cannot convert string to float64"},
+		{src: "strings.Repeat(1, 2)", err: "1:43: cannot convert 1 to string"},
+	})
+}
+
+func TestEvalReader(t *testing.T) {
+	i := interp.New(interp.Options{})
+	i.Use(stdlib.Symbols)
+	eval(t, i, `import "io"`)
+	eval(t, i, `
+type R struct{ s string }
+
+func (r *R) Read(p []byte) (int, error) {
+	n := copy(p, r.s)
+	r.s = r.s[n:]
+	if len(r.s) == 0 {
+		return n, io.EOF
+	}
+	return n, nil
+}`)
+
+	// The bytes read with io.EOF are kept, and io.EOF is the sentinel value.
+	r := eval(t, i, `io.Reader(&R{"hello"})`).Interface().(io.Reader)
+	p := make([]byte, 3)
+	n, err := r.Read(p)
+	if n != 3 || err != nil || string(p) != "hel" {
+		t.Errorf("got %d, %v, %q, want 3, nil, hel", n, err, p)
+	}
+	n, err = r.Read(p)
+	if n != 2 || !errors.Is(err, io.EOF) || string(p[:n]) != "lo" {
+		t.Errorf("got %d, %v, %q, want 2, EOF, lo", n, err, p[:n])
+	}
+
+	r = eval(t, i, `io.Reader(&R{"hello world"})`).Interface().(io.Reader)
+	var b strings.Builder
+	if n, err := io.Copy(&b, r); n != 11 || err != nil || b.String() != "hello world" {
+		t.Errorf("got %d, %v, %q, want 11, nil, hello world", n, err, b.String())
+	}
+}
+
+func TestEvalErrorIdentity(t *testing.T) {
+	i := interp.New(interp.Options{})
+	i.Use(stdlib.Symbols)
+	eval(t, i, `
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+type myError struct{ msg string }
+
+func (e *myError) Error() string { return e.msg }
+
+var ErrMine = &myError{"mine"}
+
+func EOF() error                { return io.EOF }
+func IsEOF(err error) bool      { return err == io.EOF && errors.Is(err, io.EOF) }
+func Wrap(err error) error      { return fmt.Errorf("wrap: %w", err) }
+func Mine() error               { return ErrMine }
+func IsMine(err error) bool     { return err == ErrMine && errors.Is(err, ErrMine) }
+func IsMineWrapped(err error) bool { return errors.Is(err, ErrMine) }`)
+
+	// Binary errors flow through interpreted code unchanged.
+	if err := eval(t, i, "EOF").Interface().(func() error)(); err != io.EOF {
+		t.Errorf("got %v, want io.EOF", err)
+	}
+	if !eval(t, i, "IsEOF").Interface().(func(error) bool)(io.EOF) {
+		t.Error("io.EOF not identified in interpreted code")
+	}
+	err := eval(t, i, "Wrap").Interface().(func(error) error)(io.EOF)
+	if !errors.Is(err, io.EOF) || errors.Unwrap(err) != io.EOF {
+		t.Errorf("got %v, want a wrapped io.EOF", err)
+	}
+
+	// Interpreted errors keep their identity through binary code.
+	mine := eval(t, i, "Mine").Interface().(func() error)()
+	if mine.Error() != "mine" || !errors.Is(mine, mine) {
+		t.Errorf("got %v, want mine", mine)
+	}
+	if !eval(t, i, "IsMine").Interface().(func(error) bool)(mine) {
+		t.Error("interpreted error not identified after a round trip")
+	}
+	wrapped := eval(t, i, "Wrap").Interface().(func(error) error)(mine)
+	if !errors.Is(wrapped, mine) || !errors.Is(errors.Unwrap(wrapped), mine) {
+		t.Errorf("got %v, want a wrapped mine", wrapped)
+	}
+	if !eval(t, i, "IsMineWrapped").Interface().(func(error) bool)(wrapped) {
+		t.Error("wrapped interpreted error not identified")
+	}
+	runTests(t, i, []testCase{
+		{src: `w := fmt.Errorf("ctx: %w", ErrMine); errors.Unwrap(w) == ErrMine`, res: "true"},
+		{src: `e := error(ErrMine); e == ErrMine && errors.Is(Wrap(e), ErrMine)`, res: "true"},
+		{src: `e2 := Wrap(&myError{"other"}); errors.Is(e2, ErrMine)`, res: "false"},
+	})
+}
+
+func TestEvalErrorList(t *testing.T) {
+	i := interp.New(interp.Options{})
+	_, err := i.Eval(`package main
+
+func f() string { return "f" + 1 }
+
+func g() { undefined() }
+
+func main() { println("not run") }
+`)
+	var errs interp.ErrorList
+	if !errors.As(err, &errs) {
+		t.Fatalf("got %v, want an error list", err)
+	}
+	want := `3:26: invalid operation: mismatched types string and int
+5:12: undefined: undefined`
+	if got := err.Error(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if len(errs) != 2 {
+		t.Errorf("got %d errors, want 2", len(errs))
+	}
+}
+
+func TestEvalErrorSource(t *testing.T) {
+	i := interp.New(interp.Options{ErrorSource: true})
+	runTests(t, i, []testCase{
+		{src: `e, f := "é", "é" + 1`, err: "1:42: invalid operation: mismatched types string and int\n\te, f := \"é\", \"é\" + 1\n\t             ^"},
+		{src: "func g() {\n\tif true {\n\t\tundefined()\n\t}\n}", err: "3:3: undefined: undefined\n\t\t\tundefined()\n\t\t\t^"},
+		{src: "x := \"" + strings.Repeat("a", 200) + "\" + 1", err: "1:33: invalid operation: mismatched types string and int\n\tx := \"" + strings.Repeat("a", 94) + "...\n\t     ^"},
+	})
+}
+
+func TestCheck(t *testing.T) {
+	for _, test := range []struct {
+		file string
+		want []interp.Diagnostic
+	}{
+		{file: "../_test/fun21.go", want: []interp.Diagnostic{
+			{File: "../_test/fun21.go", Line: 4, Column: 2, Severity: "error", Message: "not enough arguments to return", Phase: interp.CheckPhase},
+		}},
+		{file: "../_test/bad0.go", want: []interp.Diagnostic{
+			{File: "../_test/bad0.go", Line: 1, Column: 1, Severity: "error", Message: "expected 'package', found println", Phase: interp.ParsePhase},
+		}},
+		{file: "../_test/fun10.go"},
+	} {
+		t.Run(test.file, func(t *testing.T) {
+			i := interp.New(interp.Options{})
+			i.Use(stdlib.Symbols)
+			if got := i.Check(test.file); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCheckWithGoTypes(t *testing.T) {
+	for _, test := range []struct {
+		file string
+		want []interp.Diagnostic
+	}{
+		{file: "../_test/complex5.go", want: []interp.Diagnostic{
+			{File: "../_test/complex5.go", Line: 6, Column: 7, Severity: "warning", Message: "type of r differs from go/types", Phase: interp.GoTypesPhase, Interp: "float64", GoTypes: "untyped float"},
+			{File: "../_test/complex5.go", Line: 7, Column: 7, Severity: "warning", Message: "type of im differs from go/types", Phase: interp.GoTypesPhase, Interp: "float64", GoTypes: "untyped float"},
+		}},
+		{file: "../_test/print2.go", want: []interp.Diagnostic{
+			{File: "../_test/print2.go", Line: 6, Column: 10, Severity: "error", Message: "illegal types for operand: println\n\tmain.T", Phase: interp.CheckPhase},
+			{File: "../_test/print2.go", Line: 6, Column: 10, Severity: "warning", Message: "error not found by go/types", Phase: interp.GoTypesPhase, Interp: "illegal types for operand: println\n\tmain.T"},
+		}},
+		{file: "../_test/redeclaration-global3.go", want: []interp.Diagnostic{
+			{File: "../_test/redeclaration-global3.go", Line: 7, Column: 5, Severity: "error", Message: "time redeclared in this block", Phase: interp.CheckPhase},
+			{File: "../_test/redeclaration-global3.go", Line: 4, Column: 2, Severity: "warning", Message: "error not found by the interpreter", Phase: interp.GoTypesPhase, GoTypes: `"time" imported and not used`},
+		}},
+		{file: "../_test/alias0.go"},
+		{file: "../_test/struct0.go"},
+	} {
+		t.Run(test.file, func(t *testing.T) {
+			i := interp.New(interp.Options{CheckWithGoTypes: true})
+			i.Use(stdlib.Symbols)
+			if got := i.Check(test.file); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestReEval(t *testing.T) {
+	dir, err := ioutil.TempDir("", "yaegi-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "script.go")
+
+	i := interp.New(interp.Options{})
+	i.Use(stdlib.Symbols)
+	reEval := func(src string) error {
+		if err := ioutil.WriteFile(path, []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := i.ReEval(path)
+		return err
+	}
+
+	if err := reEval(`package main
+
+import "fmt"
+
+type T struct{ A int }
+
+func (t T) Get() string { return fmt.Sprint("v1:", t.A) }
+
+var count = 1
+
+func F() string { return fmt.Sprint("v1:", count) }
+
+func G() int { return 1 }
+`); err != nil {
+		t.Fatal(err)
+	}
+	v, err := i.Eval("F")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := v.Interface().(func() string)
+	eval(t, i, "t := T{2}")
+	eval(t, i, "func H() string { return F() }")
+	if r := f(); r != "v1:1" {
+		t.Fatalf("got %q, want %q", r, "v1:1")
+	}
+
+	if err := reEval(`package main
+
+import "fmt"
+
+type T struct{ A int }
+
+func (t T) Get() string { return fmt.Sprint("v2:", t.A) }
+
+var count = 2
+
+func F() string { return fmt.Sprint("v2:", count) }
+`); err != nil {
+		t.Fatal(err)
+	}
+	runTests(t, i, []testCase{
+		{desc: "previous function value", pre: func() {
+			if r := f(); r != "v2:2" {
+				t.Errorf("got %q, want %q", r, "v2:2")
+			}
+		}, src: "F()", res: "v2:2"},
+		{desc: "previous caller", src: "H()", res: "v2:2"},
+		{desc: "previous value method", src: "t.Get()", res: "v2:2"},
+		{desc: "removed function", src: "G()", err: "1:28: undefined: G"},
+	})
+
+	// A change of struct layout is rejected, and the previous version kept.
+	if err := reEval(`package main
+
+type T struct{ A, B int }
+
+func F() string { return "v3" }
+`); err == nil || !strings.Contains(err.Error(), "cannot redefine type T: layout changed") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	runTests(t, i, []testCase{
+		{desc: "rejected redefinition", src: "F() + t.Get()", res: "v2:2v2:2"},
+	})
+}
+
+func TestWatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "yaegi-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	write := func(name, src string) {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("a.go", "package main\n\nvar V = 1\n")
+	write("b.go", "package main\n\nfunc F() int { return V }\n")
+
+	i := interp.New(interp.Options{})
+	i.Use(stdlib.Symbols)
+	ctx, cancel := context.WithCancel(context.Background())
+	reloaded := make(chan error)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		interp.Watch(ctx, i, dir, func(err error) { reloaded <- err })
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+	wait := func() error {
+		select {
+		case err := <-reloaded:
+			return err
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for reload")
+		}
+		return nil
+	}
+
+	if err := wait(); err != nil {
+		t.Fatal(err)
+	}
+	runTests(t, i, []testCase{{desc: "initial", src: "F()", res: "1"}})
+
+	// A burst of writes produces a single reload.
+	write("a.go", "package main\n\nvar V = 2\n")
+	write("a.go", "package main\n\nvar V = 3\n")
+	if err := wait(); err != nil {
+		t.Fatal(err)
+	}
+	runTests(t, i, []testCase{{desc: "changed", src: "F()", res: "3"}})
+
+	// Errors are reported, and the previous version is kept.
+	write("a.go", "package main\n\nvar V = \n")
+	if err := wait(); err == nil {
+		t.Fatal("missing error")
+	}
+	runTests(t, i, []testCase{{desc: "error", src: "F()", res: "3"}})
+
+	// Declarations of removed files are removed.
+	write("a.go", "package main\n\nvar V = 4\n\nfunc G() int { return 2 * V }\n")
+	if err := os.Remove(filepath.Join(dir, "b.go")); err != nil {
+		t.Fatal(err)
+	}
+	if err := wait(); err != nil {
+		t.Fatal(err)
+	}
+	runTests(t, i, []testCase{
+		{desc: "added", src: "G()", res: "8"},
+		{desc: "removed", src: "F()", err: "1:28: undefined: F"},
+	})
+}
+
+func TestExecuteConcurrent(t *testing.T) {
+	i := interp.New(interp.Options{})
+	i.Use(stdlib.Symbols)
+	eval(t, i, `import "fmt"`)
+	eval(t, i, `import "strings"`)
+	eval(t, i, "var base = 10")
+	eval(t, i, "type T struct{ N int }")
+	eval(t, i, "func (t T) Double() int { return 2 * t.N }")
+	eval(t, i, "func square(n int) int { return n * n }")
+
+	shared, err := i.Compile("fmt.Sprint(square(base) + T{base}.Double())")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 100)
+	for n := 0; n < 100; n++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			p, err := i.Compile(fmt.Sprintf("s := []string{}; for j := 0; j < %d; j++ { s = append(s, fmt.Sprint(square(j))) }; strings.Join(s, \",\")", n%10))
+			if err != nil {
+				errs <- err
+				return
+			}
+			want := make([]string, n%10)
+			for j := range want {
+				want[j] = fmt.Sprint(j * j)
+			}
+			for k := 0; k < 10; k++ {
+				res, err := i.Execute(p)
+				if err != nil {
+					errs <- err
+					return
+				}
+				if r := res.String(); r != strings.Join(want, ",") {
+					errs <- fmt.Errorf("got %q, want %q", r, strings.Join(want, ","))
+					return
+				}
+				if res, err = i.Execute(shared); err != nil {
+					errs <- err
+					return
+				}
+				if r := res.String(); r != "120" {
+					errs <- fmt.Errorf("got %q, want %q", r, "120")
+					return
+				}
+			}
+		}(n)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestCompile(t *testing.T) {
+	i := interp.New(interp.Options{})
+	for _, src := range []string{"var x = 1", "func f() {}", "import \"fmt\"", "x := 1; var y = x"} {
+		if _, err := i.Compile(src); err == nil || !strings.Contains(err.Error(), "declaration not allowed") {
+			t.Errorf("%s: unexpected error: %v", src, err)
+		}
+	}
+
+	// Short variable declarations are local to the program.
+	p, err := i.Compile("x := 1; x + 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res, err := i.Execute(p); err != nil || res.Interface() != 2 {
+		t.Errorf("got %v, %v, want 2", res, err)
+	}
+	if _, err := i.Eval("x"); err == nil {
+		t.Error("x should not be defined in package")
+	}
+	if _, err := i.Compile("for k := 0; k < 2; k++ {}"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := i.Eval("k"); err == nil {
+		t.Error("k should not be defined in package")
+	}
+	eval(t, i, "y := 1")
+	if _, err := i.Compile("y := 2"); err == nil || !strings.Contains(err.Error(), "cannot redeclare y") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestEvalExprWithVars(t *testing.T) {
+	type request struct {
+		Path string
+		Size int
+	}
+	i := interp.New(interp.Options{})
+	i.Use(stdlib.Symbols)
+	eval(t, i, `import "strings"`)
+	eval(t, i, "var limit = 100")
+
+	p, err := i.CompileWithVars(`strings.HasPrefix(req.Path, prefix) && req.Size < limit`, map[string]reflect.Type{
+		"req":    reflect.TypeOf(request{}),
+		"prefix": reflect.TypeOf(""),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		req  request
+		want bool
+	}{
+		{request{"/api/users", 10}, true},
+		{request{"/api/users", 1000}, false},
+		{request{"/static/x.png", 10}, false},
+	} {
+		res, err := i.ExecuteWithVars(p, map[string]interface{}{"req": test.req, "prefix": "/api/"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Bool() != test.want {
+			t.Errorf("%v: got %v, want %v", test.req, res, test.want)
+		}
+	}
+	if _, err := i.ExecuteWithVars(p, map[string]interface{}{"prefix": 1}); err == nil || !strings.Contains(err.Error(), "cannot use 1 (type int) as type string") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := i.ExecuteWithVars(p, map[string]interface{}{"foo": 1}); err == nil || err.Error() != "undefined variable: foo" {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// Bound variables shadow the package ones, which are left unchanged.
+	res, err := i.EvalExprWithVars("limit * n", map[string]interface{}{"limit": 3, "n": 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Interface() != 6 {
+		t.Errorf("got %v, want 6", res)
+	}
+	if res := eval(t, i, "limit"); res.Interface() != 100 {
+		t.Errorf("got %v, want 100", res)
+	}
+	if _, err := i.Eval("n"); err == nil {
+		t.Error("n should not be defined in package")
+	}
+
+	_, err = i.EvalExprWithVars("a + b", map[string]interface{}{"a": 1, "c": 2})
+	if err == nil || !strings.Contains(err.Error(), "undefined: b (available variables: a, c)") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestEvalExprOnly(t *testing.T) {
+	i := interp.New(interp.Options{GoVersion: "go1.21", ExprOnly: true, AllowedCalls: []string{"max", "math.Sqrt", "math.Abs"}})
+	i.Use(stdlib.Symbols)
+	eval(t, i, `import "math"`)
+	runTests(t, i, []testCase{
+		{desc: "arith", src: "(1 + 2) * 3 - 4/2", res: "7"},
+		{desc: "calls", src: "math.Sqrt(math.Abs(-16)) + float64(max(1, 2))", res: "6"},
+		{desc: "const", src: "math.Pi > 3 && !(1 > 2)", res: "true"},
+		{desc: "call", src: "math.Floor(2.5)", err: "1:28: function call math.Floor is not allowed in expression mode"},
+		{desc: "println", src: "1 + len(\"ab\")", err: "1:32: function call len is not allowed in expression mode"},
+		{desc: "define", src: "a := 1", err: "1:28: declarations are not allowed in expression mode"},
+		{desc: "var", src: "var a = 1", err: "1:14: declarations are not allowed in expression mode"},
+		{desc: "func", src: "func f() {}", err: "1:14: declarations are not allowed in expression mode"},
+		{desc: "assign", src: "math.Pi = 1", err: "1:28: statements are not allowed in expression mode"},
+		{desc: "for", src: "for {}", err: "1:28: loops are not allowed in expression mode"},
+		{desc: "if", src: "if true {}", err: "1:28: statements are not allowed in expression mode"},
+		{desc: "funcLit", src: "max(1, func() int { for {} })", err: "1:35: function literals are not allowed in expression mode"},
+		{desc: "compositeLit", src: "[]int{1}", err: "1:28: composite literals are not allowed in expression mode"},
+		{desc: "recv", src: "<-make(chan int)", err: "1:28: channel operations are not allowed in expression mode"},
+	})
+
+	// Expression mode composes with bound variables.
+	vars := map[string]interface{}{"x": []float64{3, 4}, "y": 2.0}
+	res, err := i.EvalExprWithVars("math.Sqrt(x[0]*x[0]+x[1]*x[1]) * y", vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Float() != 10 {
+		t.Errorf("got %v, want 10", res)
+	}
+	vars = map[string]interface{}{"x": [][]float64{{1}}, "i": 0}
+	if _, err = i.EvalExprWithVars("x[i][i]", vars); err == nil || err.Error() != "1:28: nested indexing is not allowed in expression mode" {
+		t.Errorf("got %v, want nested indexing error", err)
+	}
+	if _, err = i.CompileWithVars("x[0] = 1", map[string]reflect.Type{"x": reflect.TypeOf([]int{})}); err == nil || err.Error() != "1:28: statements are not allowed in expression mode" {
+		t.Errorf("got %v, want statements error", err)
+	}
+}
+
+// writeHelpers writes the source files of package helpers in a GOPATH
+// created in a temporary directory, and returns the GOPATH.
+func TestUseAfterEval(t *testing.T) {
+	goPath, err := ioutil.TempDir("", "yaegi-use")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(goPath)
+	dir := filepath.Join(goPath, "src", "upper")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	src := "package upper\n\nimport \"strings\"\n\nfunc Up(s string) string { return strings.ToUpper(s) }\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "upper.go"), []byte(src), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	i := interp.New(interp.Options{GoPath: goPath})
+	if _, err := i.Eval(`import "upper"`); err == nil {
+		t.Fatal("unexpected import of upper without strings")
+	}
+	// The import succeeds once the missing binary package is used.
+	i.Use(stdlib.Symbols)
+	if _, err := i.Eval(`import "upper"`); err != nil {
+		t.Fatal(err)
+	}
+	res, err := i.Eval(`upper.Up("abc")`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := res.String(); s != "ABC" {
+		t.Errorf("got %q, want %q", s, "ABC")
+	}
+	err = i.UseAndImport("upper", stdlib.Symbols["strings"])
+	if want := "cannot use package upper: already imported from source"; err == nil || err.Error() != want {
+		t.Errorf("got error %v, want %q", err, want)
+	}
+
+	j := interp.New(interp.Options{})
+	if _, err := j.Eval(`x := 2`); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.UseAndImport("strings", stdlib.Symbols["strings"]); err != nil {
+		t.Fatal(err)
+	}
+	res, err = j.Eval(`strings.Repeat("a", x)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := res.String(); s != "aa" {
+		t.Errorf("got %q, want %q", s, "aa")
+	}
+	err = j.UseAndImport("math/rand", stdlib.Symbols["math/rand"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = j.UseAndImport("crypto/rand", stdlib.Symbols["crypto/rand"])
+	if want := "cannot import package crypto/rand: rand already declared"; err == nil || err.Error() != want {
+		t.Errorf("got error %v, want %q", err, want)
+	}
+}
+
+func TestREPLAutoImport(t *testing.T) {
+	i := interp.New(interp.Options{AutoImport: true})
+	i.Use(stdlib.Symbols)
+	if _, err := i.Eval(`strings.ToUpper("x")`); err == nil || !strings.HasSuffix(err.Error(), "undefined: strings") {
+		t.Errorf("unexpected error in Eval: %v", err)
+	}
+
+	in := strings.NewReader(`s := strings.ToUpper(fmt.Sprint("x"))
+s += strings.Repeat("y", 2)
+n := rand.Intn(1)
+import "math/rand"
+n := rand.Intn(1)
+`)
+	var out bytes.Buffer
+	i.REPL(in, &out)
+	want := `// imported "strings"
+// imported "fmt"
+1:33: undefined: rand, ambiguous import: "crypto/rand", "math/rand"
+`
+	if s := out.String(); s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+	res, err := i.Eval(`s`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := res.String(); s != "Xyy" {
+		t.Errorf("got %q, want %q", s, "Xyy")
+	}
+}
+
+func TestEvalUnexportedSrc(t *testing.T) {
+	goPath, err := ioutil.TempDir("", "yaegi-unexported")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(goPath)
+	files := map[string]string{
+		"shape/shape.go":  "package shape\n\ntype T struct {\n\tName string\n\tside int\n}\n\nfunc (t T) area() int { return t.side * t.side }\n\nfunc (t T) Area() int { return t.area() }\n\nvar count int\n\ntype Area interface{ scale(int) }\n",
+		"shape/new.go":    "package shape\n\n// New refers to the unexported names of shape.go.\nfunc New(name string, side int) T { count++; return T{name, side} }\n",
+		"label/label.go":  "package label\n\ntype T struct{ Text string }\n\nfunc (t T) Area() string { return \"label \" + t.Text }\n",
+		"label/second.go": "package label\n\nfunc Of(s string) T { return T{Text: s} }\n",
+	}
+	for name, src := range files {
+		path := filepath.Join(goPath, "src", name)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(src), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	i := interp.New(interp.Options{GoPath: goPath})
+	i.Use(stdlib.Symbols)
+	eval(t, i, `import ("fmt"; "label"; "shape")`)
+	runTests(t, i, []testCase{
+		{src: `shape.New("square", 3).Area()`, res: "9"},
+		{src: `fmt.Sprint(shape.T{Name: "a"}.Area(), " ", label.Of("b").Area())`, res: "0 label b"},
+		{src: `shape.count`, err: "1:28: cannot refer to unexported name shape.count"},
+		{src: `shape.T{"a", 2}`, err: "1:41: implicit assignment to unexported field side in struct literal of type shape.T"},
+		{src: `shape.T{side: 2}`, err: "1:36: cannot refer to unexported field side in struct literal of type shape.T"},
+		{src: `shape.New("a", 1).side`, err: "1:28: cannot refer to unexported field or method side"},
+		{src: `shape.New("a", 1).area()`, err: "1:28: cannot refer to unexported field or method area"},
+		{src: `func f(a shape.Area) { a.scale(2) }`, err: "1:37: cannot refer to unexported field or method scale"},
+		{pre: func() { eval(t, i, "type S struct{ shape.T; side int }") }, src: "S{side: 2}.side", res: "2"},
+		{src: "S{}.area()", err: "1:28: cannot refer to unexported field or method area"},
+	})
+}
+
+func TestEvalForwardDeclFiles(t *testing.T) {
+	// The declarations of the package refer to each other across files.
+	srcs := []string{
+		"package shapes\n\nvar Registry = map[Kind]Shape{}\n\nvar Default = NewSquare(Unit)\n\nfunc init() { Registry[SquareKind] = Default }\n",
+		"package shapes\n\ntype Kind int\n\nconst (\n\tSquareKind Kind = iota + Base\n\tCircleKind\n)\n\nconst Base = Count - 1\n",
+		"package shapes\n\nvar names = [Count]string{\"square\", \"circle\"}\n\nconst Count = 2\n\nfunc (k Kind) String() string { return names[k-Base] }\n",
+		"package shapes\n\ntype Shape interface {\n\tKind() Kind\n\tArea() Size\n}\n\ntype Size float64\n\nconst Unit Size = 2\n",
+		"package shapes\n\ntype Square struct {\n\tside  Size\n\tattrs [Count]Attr\n}\n\ntype Attr struct{ owner *Square }\n\nfunc NewSquare(s Size) *Square { return &Square{side: s} }\n\nfunc (s *Square) Kind() Kind { return SquareKind }\n\nfunc (s *Square) Area() Size { return s.side * s.side }\n",
+	}
+	// Files are evaluated in the order of their names.
+	orders := [][]int{{0, 1, 2, 3, 4}, {4, 3, 2, 1, 0}, {2, 0, 4, 1, 3}, {1, 4, 0, 3, 2}, {3, 2, 1, 4, 0}}
+
+	for _, order := range orders {
+		t.Run(fmt.Sprint(order), func(t *testing.T) {
+			goPath, err := ioutil.TempDir("", "yaegi-forward")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(goPath)
+			dir := filepath.Join(goPath, "src", "shapes")
+			if err := os.MkdirAll(dir, 0700); err != nil {
+				t.Fatal(err)
+			}
+			for k, j := range order {
+				name := filepath.Join(dir, fmt.Sprintf("f%d.go", k))
+				if err := ioutil.WriteFile(name, []byte(srcs[j]), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			i := interp.New(interp.Options{GoPath: goPath})
+			i.Use(stdlib.Symbols)
+			eval(t, i, `import ("fmt"; "shapes")`)
+			runTests(t, i, []testCase{
+				{src: "s := shapes.Registry[shapes.SquareKind]; fmt.Sprint(s.Kind(), s.Area(), shapes.CircleKind)", res: "square 4 circle"},
+			})
+		})
+	}
+}
+
+func writeHelpers(t testing.TB, n int) string {
+	goPath, err := ioutil.TempDir("", "yaegi-compiled")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(goPath, "src", "helpers")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	b.WriteString("package helpers\n\nvar N int\n\nfunc Inc() int { N++; return N }\n")
+	for k := 0; k < n; k++ {
+		fmt.Fprintf(&b, "\nfunc F%d(a, b int) int {\n\tif a > b {\n\t\treturn a - b + %d\n\t}\n\treturn b - a\n}\n", k, k)
+	}
+	files := map[string]string{
+		"a.go":      b.String(),
+		"b.go":      "package helpers\n\ntype T struct{ X int }\n\nfunc (t T) Double() int { return 2 * t.X }\n",
+		"b_test.go": "package helpers\n\nfunc Test() {}\n",
+		"c.go":      "// +build ignore\n\npackage helpers\n\nfunc Inc() {}\n",
+	}
+	for name, src := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(src), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return goPath
+}
+
+func TestCompilePackage(t *testing.T) {
+	goPath := writeHelpers(t, 2)
+	defer os.RemoveAll(goPath)
+	dir := filepath.Join(goPath, "src", "helpers")
+
+	pkg, err := interp.CompilePackage(dir, "helpers", interp.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pkg.Path() != "helpers" {
+		t.Errorf("got path %s, want helpers", pkg.Path())
+	}
+
+	// The compiled package is attached concurrently, with package variables
+	// private to each interpreter.
+	var wg sync.WaitGroup
+	for k := 0; k < 4; k++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			i := interp.New(interp.Options{})
+			i.UseCompiled(pkg)
+			if _, err := i.Eval(`import "helpers"`); err != nil {
+				t.Error(err)
+				return
+			}
+			for n := int64(1); n <= 2; n++ {
+				if res, err := i.Eval("helpers.Inc()"); err != nil || res.Int() != n {
+					t.Errorf("got %v, %v, want %d", res, err, n)
+				}
+			}
+			if res, err := i.Eval("helpers.F1(5, 2) + helpers.T{4}.Double()"); err != nil || res.Int() != 12 {
+				t.Errorf("got %v, %v, want 12", res, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Positions refer to the compiled files.
+	if err := ioutil.WriteFile(filepath.Join(dir, "d.go"), []byte("package helpers\n\nvar x int = \"a\"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	pkg, err = interp.CompilePackage(dir, "helpers", interp.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := interp.New(interp.Options{})
+	i.UseCompiled(pkg)
+	want := filepath.Join(dir, "d.go") + `:3:13: cannot convert string to int`
+	if _, err := i.Eval(`import "helpers"`); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("got %v, want %s", err, want)
+	}
+
+	if _, err := interp.CompilePackage(filepath.Join(goPath, "src"), "src", interp.Options{}); err == nil || !strings.HasPrefix(err.Error(), "no Go source files in ") {
+		t.Errorf("got %v, want no Go source files", err)
+	}
+}
+
+func BenchmarkUseCompiled(b *testing.B) {
+	goPath := writeHelpers(b, 300)
+	defer os.RemoveAll(goPath)
+
+	b.Run("source", func(b *testing.B) {
+		for k := 0; k < b.N; k++ {
+			i := interp.New(interp.Options{GoPath: goPath})
+			if _, err := i.Eval(`import "helpers"`); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("compiled", func(b *testing.B) {
+		pkg, err := interp.CompilePackage(filepath.Join(goPath, "src", "helpers"), "helpers", interp.Options{})
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.ResetTimer()
+		for k := 0; k < b.N; k++ {
+			i := interp.New(interp.Options{})
+			i.UseCompiled(pkg)
+			if _, err := i.Eval(`import "helpers"`); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestBindGlobal(t *testing.T) {
+	type config struct {
+		Timeout int
+		Name    string
+	}
+	i := interp.New(interp.Options{})
+	i.Use(stdlib.Symbols)
+
+	cfg := config{Name: "host"}
+	var result string
+	if err := i.BindGlobal("", "Config", &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if err := i.BindGlobal("", "Result", &result); err != nil {
+		t.Fatal(err)
+	}
+	eval(t, i, `func run() { Config.Timeout = 5; Result = Config.Name + "!" }`)
+	eval(t, i, "run()")
+	if cfg.Timeout != 5 || result != "host!" {
+		t.Errorf("got %+v, %q, want Timeout 5 and host!", cfg, result)
+	}
+
+	// Host modifications are seen by the compiled code, also after rebinding.
+	cfg.Name = "changed"
+	eval(t, i, "run()")
+	if result != "changed!" {
+		t.Errorf("got %q, want changed!", result)
+	}
+	cfg2 := config{Name: "other"}
+	if err := i.BindGlobal("", "Config", &cfg2); err != nil {
+		t.Fatal(err)
+	}
+	eval(t, i, "run()")
+	if cfg2.Timeout != 5 || result != "other!" {
+		t.Errorf("got %+v, %q, want Timeout 5 and other!", cfg2, result)
+	}
+
+	// Binary package variable.
+	n := 3
+	if err := i.BindGlobal("host", "N", &n); err != nil {
+		t.Fatal(err)
+	}
+	eval(t, i, `import "host"`)
+	eval(t, i, "host.N *= 2")
+	if n != 6 {
+		t.Errorf("got %d, want 6", n)
+	}
+
+	for _, test := range []struct {
+		pkg, name string
+		ptr       interface{}
+		err       string
+	}{
+		{"", "Config", cfg, "cannot bind Config: interp_test.config is not a non-nil pointer"},
+		{"", "Config", (*config)(nil), "cannot bind Config: *interp_test.config is not a non-nil pointer"},
+		{"", "Config", &n, "cannot bind Config: type int does not match interp_test.config"},
+		{"", "run", &n, "cannot bind run: not a variable"},
+		{"host", "N", &result, "cannot bind host.N: type string does not match int"},
+	} {
+		if err := i.BindGlobal(test.pkg, test.name, test.ptr); err == nil || err.Error() != test.err {
+			t.Errorf("got error %v, want %s", err, test.err)
+		}
+	}
+}
+
+func TestSources(t *testing.T) {
+	i := interp.New(interp.Options{})
+	i.Name = "plugin.go"
+	_, err := i.Eval("package main\n\nfunc f() int {\n\treturn count\n}\n")
+	if err == nil {
+		t.Fatal("unexpected nil error")
+	}
+
+	f := i.Sources()["plugin.go"]
+	if f == nil {
+		t.Fatal("plugin.go not found in sources")
+	}
+	var pos token.Pos
+	ast.Inspect(f, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && id.Name == "count" {
+			pos = id.Pos()
+		}
+		return true
+	})
+	want := i.FileSet().Position(pos).String() + ": undefined: count"
+	if err.Error() != want {
+		t.Errorf("got error %q, want %q", err, want)
+	}
+}
+
+func TestPreCompile(t *testing.T) {
+	// Count the calls of functions, by incrementing a counter in each body,
+	// except in the main function where statements are evaluated.
+	count := func(fset *token.FileSet, f *ast.File) error {
+		for _, d := range f.Decls {
+			if fd, ok := d.(*ast.FuncDecl); ok && fd.Body != nil && fd.Name.Name != "main" {
+				inc := &ast.IncDecStmt{X: ast.NewIdent("calls"), Tok: token.INC}
+				fd.Body.List = append([]ast.Stmt{inc}, fd.Body.List...)
+			}
+		}
+		return nil
+	}
+	i := interp.New(interp.Options{PreCompile: count})
+	_, err := i.Eval(`package main
+
+var calls int
+
+func f(n int) int {
+	if n == 0 {
+		return 0
+	}
+	return f(n - 1)
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := i.Eval(`f(3)`); err != nil {
+		t.Fatal(err)
+	}
+	res, err := i.Eval(`calls`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := res.Int(); n != 4 {
+		t.Errorf("got %d calls, want 4", n)
+	}
+
+	// Reject goto statements.
+	noGoto := func(fset *token.FileSet, f *ast.File) (err error) {
+		ast.Inspect(f, func(n ast.Node) bool {
+			if b, ok := n.(*ast.BranchStmt); ok && b.Tok == token.GOTO && err == nil {
+				err = fmt.Errorf("%s: goto is not allowed", fset.Position(b.Pos()))
+			}
+			return err == nil
+		})
+		return err
+	}
+	i = interp.New(interp.Options{PreCompile: noGoto})
+	_, err = i.Eval(`package main
+
+func main() {
+loop:
+	goto loop
+}
+`)
+	if want := "5:2: goto is not allowed"; err == nil || err.Error() != want {
+		t.Errorf("got error %v, want %q", err, want)
+	}
+}
+
+func TestTypeByName(t *testing.T) {
+	i := interp.New(interp.Options{})
+	eval(t, i, `package plugin
+
+type Handler struct {
+	Name  string
+	Count int
+	Data  interface{}
+}
+
+type Handlers []*Handler
+
+func (h *Handler) Hello() string { return "hello " + h.Name }
+
+func Register(h *Handler) string { h.Count++; return h.Hello() }
+
+func Len(hs Handlers) int { return len(hs) }
+`)
+
+	typ, err := i.TypeByName("plugin", "Handler")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := reflect.New(typ)
+	v.Elem().Field(0).SetString("host")
+	register := eval(t, i, "plugin.Register")
+	if res := register.Call([]reflect.Value{v})[0]; res.String() != "hello host" {
+		t.Errorf("got %q, want %q", res, "hello host")
+	}
+	if c := v.Elem().Field(1).Int(); c != 1 {
+		t.Errorf("got count %d, want 1", c)
+	}
+
+	typ, err = i.TypeByName("plugin", "Handlers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hs := reflect.MakeSlice(typ, 2, 2)
+	if res := eval(t, i, "plugin.Len").Call([]reflect.Value{hs})[0]; res.Int() != 2 {
+		t.Errorf("got %d, want 2", res.Int())
+	}
+
+	names, err := i.TypeNames("plugin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(names); s != "[Handler Handlers]" {
+		t.Errorf("got %s, want [Handler Handlers]", s)
+	}
+
+	for _, test := range []struct {
+		pkg, name string
+		err       string
+	}{
+		{"plugin", "Register", `type Register not found in package "plugin"`},
+		{"plugin", "Missing", `type Missing not found in package "plugin"`},
+		{"other", "Handler", `package "other" not found`},
+	} {
+		if _, err := i.TypeByName(test.pkg, test.name); err == nil || err.Error() != test.err {
+			t.Errorf("got error %v, want %s", err, test.err)
+		}
+	}
+}
+
+func TestAs(t *testing.T) {
+	type Server struct {
+		Host string
+		Port uint16
+	}
+	type Config struct {
+		Name    string
+		Timeout time.Duration
+		Servers []Server
+		Labels  map[string]int
+		Primary *Server
+		Any     interface{}
+		Ignored string
+	}
+	i := interp.New(interp.Options{})
+	eval(t, i, `package conf
+
+type Srv struct {
+	Host string
+	Port int
+	priv int
+}
+
+type Config struct {
+	Name    string
+	Timeout int64
+	Servers []Srv
+	Labels  map[string]int
+	Primary *Srv
+	Any     interface{}
+	Extra   float64
+}
+
+func New() Config {
+	s := &Srv{"a", 80, 1}
+	return Config{"c", 2000, []Srv{*s, {"b", 81, 2}}, map[string]int{"x": 1}, s, *s, 1.5}
+}
+
+type Bad struct{ Timeout string }
+
+var bad = []Bad{{"1s"}}
+`)
+
+	var cfg Config
+	if err := interp.As(eval(t, i, "conf.New()"), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	want := Config{
+		Name:    "c",
+		Timeout: 2000,
+		Servers: []Server{{"a", 80}, {"b", 81}},
+		Labels:  map[string]int{"x": 1},
+		Primary: &Server{"a", 80},
+	}
+	if cfg.Any == nil || reflect.TypeOf(cfg.Any).Kind() != reflect.Struct {
+		t.Errorf("got Any %v, want a struct", cfg.Any)
+	}
+	cfg.Any = nil
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("got %+v, want %+v", cfg, want)
+	}
+
+	// Tag based mapping.
+	var tagged struct {
+		ServerName string `conf:"Host"`
+		Port       int    `conf:"-"`
+	}
+	if err := interp.As(eval(t, i, "conf.New().Primary"), &tagged, interp.AsTag("conf")); err != nil {
+		t.Fatal(err)
+	}
+	if tagged.ServerName != "a" || tagged.Port != 0 {
+		t.Errorf("got %+v, want {ServerName:a Port:0}", tagged)
+	}
+
+	var cfgs []Config
+	err := interp.As(eval(t, i, "conf.bad"), &cfgs)
+	if want := "field [0].Timeout: have string, want time.Duration"; err == nil || err.Error() != want {
+		t.Errorf("got error %v, want %s", err, want)
+	}
+	var single Config
+	err = interp.As(eval(t, i, "conf.bad[0]"), &single)
+	if want := "field Config.Timeout: have string, want time.Duration"; err == nil || err.Error() != want {
+		t.Errorf("got error %v, want %s", err, want)
+	}
+	err = interp.As(eval(t, i, "conf.bad[0]"), single)
+	if want := "cannot copy value: interp_test.Config is not a non-nil pointer"; err == nil || err.Error() != want {
+		t.Errorf("got error %v, want %s", err, want)
+	}
+}
+
+// group is a minimal errgroup.Group, calling interpreted closures from
+// binary goroutines.
+type group struct {
+	wg   sync.WaitGroup
+	once sync.Once
+	err  error
+}
+
+func (g *group) Go(f func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if err := f(); err != nil {
+			g.once.Do(func() { g.err = err })
+		}
+	}()
+}
+
+func (g *group) Wait() error {
+	g.wg.Wait()
+	return g.err
+}
+
+func TestEvalGoroutineSync(t *testing.T) {
+	i := interp.New(interp.Options{})
+	i.Use(stdlib.Symbols)
+	i.Use(interp.Exports{"errgroup": {"Group": reflect.ValueOf((*group)(nil))}})
+	eval(t, i, `import ("errgroup"; "errors"; "fmt"; "sync")`)
+	eval(t, i, `type E struct{ i int }`)
+	eval(t, i, `func (e E) Error() string { return fmt.Sprint("E", e.i) }`)
+	eval(t, i, `var ErrX = errors.New("x")`)
+	eval(t, i, `
+func task(i int) func() error {
+	return func() error {
+		if i == 42 {
+			return ErrX
+		}
+		return nil
+	}
+}`)
+	eval(t, i, `
+func count() int {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	n := 0
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			mu.Lock()
+			n++
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return n
+}`)
+	eval(t, i, `
+func run() error {
+	var g errgroup.Group
+	for i := 0; i < 100; i++ {
+		g.Go(task(i))
+	}
+	return g.Wait()
+}`)
+	eval(t, i, `
+func runE() error {
+	var g errgroup.Group
+	g.Go(func() error { var err error = E{7}; return err })
+	return g.Wait()
+}`)
+
+	if n := eval(t, i, "count()").Int(); n != 100 {
+		t.Errorf("got %d, want 100", n)
+	}
+	assertEval(t, i, "run() == ErrX", "", "true")
+	assertEval(t, i, "runE().Error()", "", "E7")
+}
+
+// forward copies the values received from in to out, with n binary workers,
+// then closes out.
+func forward(n int, in, out interface{}) {
+	vin, vout := reflect.ValueOf(in), reflect.ValueOf(out)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for v, ok := vin.Recv(); ok; v, ok = vin.Recv() {
+				vout.Send(v)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		vout.Close()
+	}()
+}
+
+func TestEvalChanBinary(t *testing.T) {
+	i := interp.New(interp.Options{})
+	i.Use(stdlib.Symbols)
+	i.Use(interp.Exports{"pool": {
+		"Forward": reflect.ValueOf(forward),
+		"Run": reflect.ValueOf(func(jobs <-chan func() int, res chan<- int) {
+			for j := range jobs {
+				res <- j()
+			}
+			close(res)
+		}),
+		"Serve": reflect.ValueOf(func(c chan chan int) {
+			go func() {
+				for r := range c {
+					r <- 42
+				}
+			}()
+		}),
+	}})
+	eval(t, i, `import ("fmt"; "pool")`)
+	eval(t, i, `type P struct { X, Y int; Name string; Tags []string }`)
+	eval(t, i, `
+func points() (int, int) {
+	in, out := make(chan P, 10), make(chan P, 10)
+	pool.Forward(4, in, out)
+	go func() {
+		for i := 0; i < 100; i++ {
+			in <- P{X: i, Y: 2 * i, Name: fmt.Sprint(i), Tags: []string{"a"}}
+		}
+		close(in)
+	}()
+	sum, bad := 0, 0
+	for p := range out {
+		sum += p.X
+		if p.Y != 2*p.X || p.Name != fmt.Sprint(p.X) || len(p.Tags) != 1 {
+			bad++
+		}
+	}
+	return sum, bad
+}`)
+	eval(t, i, `func mul(k int) func() int { return func() int { return k * 10 } }`)
+	eval(t, i, `
+func jobs() int {
+	jobs, res := make(chan func() int, 3), make(chan int, 3)
+	for k := 1; k <= 3; k++ {
+		jobs <- mul(k)
+	}
+	close(jobs)
+	pool.Run(jobs, res)
+	n := 0
+	for v := range res {
+		n += v
+	}
+	return n
+}`)
+	eval(t, i, `
+func serve() int {
+	c, r := make(chan chan int), make(chan int)
+	pool.Serve(c)
+	c <- r
+	return <-r
+}`)
+
+	assertEval(t, i, "fmt.Sprint(points())", "", "4950 0")
+	assertEval(t, i, "jobs()", "", "60")
+	assertEval(t, i, "serve()", "", "42")
+}
+
+func TestEvalPanicValue(t *testing.T) {
+	i := interp.New(interp.Options{})
+	i.Use(stdlib.Symbols)
+	i.Use(interp.Exports{"sentinel": {"Err": reflect.ValueOf(&errSentinel).Elem()}})
+	eval(t, i, `import ("fmt"; "sentinel")`)
+	eval(t, i, `type E struct{ msg string }`)
+	eval(t, i, `func (e E) Error() string { return "E: " + e.msg }`)
+
+	// The error passed to panic is recovered as is from binary code.
+	_, err := i.Eval(`panic(fmt.Errorf("boom: %w", sentinel.Err))`)
+	p, ok := err.(interp.Panic)
+	if !ok {
+		t.Fatalf("got %v, want a panic", err)
+	}
+	if e, ok := p.Value.(error); !ok || !errors.Is(e, errSentinel) {
+		t.Fatalf("got %#v, want an error wrapping %v", p.Value, errSentinel)
+	}
+
+	// An interpreted error is recovered as an error, and rendered by its method.
+	_, err = i.Eval(`panic(E{"x"})`)
+	if p, ok = err.(interp.Panic); !ok {
+		t.Fatalf("got %v, want a panic", err)
+	}
+	if _, ok := p.Value.(error); !ok || p.Error() != "E: x" {
+		t.Fatalf("got %#v (%v), want an error E: x", p.Value, p)
+	}
+
+	// Since go1.21, panic(nil) is recovered as a *runtime.PanicNilError.
+	want := "<nil>"
+	for _, tag := range build.Default.ReleaseTags {
+		if tag == "go1.21" {
+			want = "*runtime.PanicNilError"
+		}
+	}
+	eval(t, i, `func recoverNil() (r interface{}) { defer func() { r = recover() }(); panic(nil) }`)
+	if got := eval(t, i, `fmt.Sprintf("%T", recoverNil())`).String(); got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	_, err = i.Eval(`panic(nil)`)
+	if p, ok = err.(interp.Panic); !ok {
+		t.Fatalf("got %v, want a panic", err)
+	}
+	if got := fmt.Sprintf("%T", p.Value); got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+var errSentinel = errors.New("sentinel")
+
+func TestEvalPanicFrames(t *testing.T) {
+	i := interp.New(interp.Options{PanicLocals: 4})
+	i.Use(stdlib.Symbols)
+	eval(t, i, `import "sort"`)
+	eval(t, i, `type T struct{ name string }`)
+	eval(t, i, `func (t *T) check(s []int) { var e error; if len(s) > 3 { panic("too long: " + t.name) }; _ = e }`)
+	eval(t, i, `func b(n int, s string) { t := &T{s}; defer func() { n++ }(); t.check(make([]int, n)) }`)
+	eval(t, i, `func a(m map[string]int) { var x interface{} = 42; b(m["n"], "abcdefgh"); _ = x }`)
+
+	_, err := i.Eval(`a(map[string]int{"n": 10})`)
+	p, ok := err.(interp.Panic)
+	if !ok {
+		t.Fatalf("got %v, want a panic", err)
+	}
+	if p.Error() != "too long: abcdefgh" {
+		t.Fatalf("got %v, want too long: abcdefgh", p)
+	}
+	frames := p.Frames()
+	var names []string
+	for _, fi := range frames {
+		names = append(names, fi.Func)
+	}
+	if s := strings.Join(names, " "); s != "main.(*T).check main.b main.a" {
+		t.Fatalf("got frames %s", s)
+	}
+	if pos := frames[1].Pos; pos.String() != "1:14" {
+		t.Fatalf("got position %v", pos)
+	}
+
+	locals := frames[0].Locals()
+	if s := locals["s"]; s.Len() != 4 || s.Type().String() != "[]int" {
+		t.Fatalf("got s %v, want a slice truncated to 4", s)
+	}
+	if e := locals["e"]; !e.IsNil() {
+		t.Fatalf("got e %v, want nil", e)
+	}
+	if v := locals["t"].Elem().Field(0).String(); v != "abcdefgh" {
+		t.Fatalf("got t.name %v", v)
+	}
+	locals = frames[1].Locals()
+	if n := locals["n"].Int(); n != 10 {
+		t.Fatalf("got n %d, want 10 before deferred call", n)
+	}
+	if s := locals["s"].String(); s != "abcd" {
+		t.Fatalf("got s %q, want truncated string", s)
+	}
+	locals = frames[2].Locals()
+	if x := locals["x"].Interface(); x != 42 {
+		t.Fatalf("got x %v, want 42", x)
+	}
+	if m := locals["m"].Interface().(map[string]int); m["n"] != 10 {
+		t.Fatalf("got m %v", m)
+	}
+
+	// The frames of interpreted functions called by binary code are not recorded,
+	// and binary code recovers the value passed to panic.
+	_, err = i.Eval(`sort.Slice([]int{2, 1}, func(i, j int) bool { var k = i; panic(k) })`)
+	if p, ok = err.(interp.Panic); !ok {
+		t.Fatalf("got %v, want a panic", err)
+	}
+	if len(p.Frames()) != 0 {
+		t.Fatalf("got frames %v, want none", p.Frames())
+	}
+	if _, ok := p.Value.(int); !ok {
+		t.Fatalf("got %#v, want an int", p.Value)
+	}
+
+	i = interp.New(interp.Options{})
+	eval(t, i, `func f() { var y = 1; panic(y) }`)
+	_, err = i.Eval(`f()`)
+	if p, ok = err.(interp.Panic); !ok || p.Frames() != nil {
+		t.Fatalf("got %v, want a panic without frames", err)
+	}
+}
+
+func TestEvalRollback(t *testing.T) {
+	i := interp.New(interp.Options{})
+	eval(t, i, `e := 1`)
+	eval(t, i, `type T struct{}`)
+	eval(t, i, `func r() interface{} { return recover() }`)
+	runTests(t, i, []testCase{
+		{src: `a := 1; var b = []int{}[3]`, err: "index out of range"},
+		{src: "1+1", res: "2"},
+		{src: "a", err: "1:28: undefined: a"},
+		{src: "b", err: "1:28: undefined: b"},
+		{src: "c := 1; d := x", err: "1:41: undefined: x"},
+		{src: "c", err: "1:28: undefined: c"},
+		{src: `e := "s"; f := 2; panic("x")`, err: "x"},
+		{src: "e", res: "1"},
+		{src: "func (T) M() int { return 1 }; func g() int { panic(\"g\") }; var h = g()", err: "g"},
+		{src: "T{}.M()", err: "1:28: undefined selector: M"},
+		{src: "g", err: "1:28: undefined: g"},
+		{src: `panic("y")`, err: "y"},
+		{desc: "stale recover", src: "r() == nil", res: "true"},
+		{src: `defer func() { panic("in defer") }(); panic("z")`, err: "in defer"},
+		{src: "1+1", res: "2"},
+		{pre: func() { eval(t, i, "var n int") }, src: "defer func() { n++ }(); n", res: "1"},
+		{desc: "deferred once", src: "n", res: "1"},
+	})
+}
+
+func TestEvalBinPanic(t *testing.T) {
+	for _, panicToError := range []bool{false, true} {
+		i := interp.New(interp.Options{PanicToError: panicToError})
+		i.Use(stdlib.Symbols)
+		eval(t, i, `import ("fmt"; "regexp"; "sort")`)
+		eval(t, i, `func try(g func()) (r interface{}) { defer func() { r = recover() }(); g(); return }`)
+		eval(t, i, `func again() { defer func() { panic(fmt.Sprint("again: ", recover())) }(); regexp.MustCompile("(") }`)
+		eval(t, i, `func unwind() { defer func() {}(); regexp.MustCompile("(") }`)
+		eval(t, i, `func less() { sort.Slice([]int{2, 1}, func(i, j int) bool { regexp.MustCompile("("); return true }) }`)
+		const msg = "regexp: Compile(`(`): error parsing regexp: missing closing ): `(`"
+		runTests(t, i, []testCase{
+			{src: `fmt.Sprint(try(func() { regexp.MustCompile("(") }))`, res: msg},
+			{src: "fmt.Sprint(try(again))", res: "again: " + msg},
+			{src: "fmt.Sprint(try(less))", res: msg},
+			{src: "unwind()", err: msg},
+			{src: "again()", err: "again: " + msg},
+			{src: "1+1", res: "2"},
+		})
+
+		// The value of an unrecovered panic is the one of the binary function.
+		_, err := i.Eval("unwind()")
+		if p, ok := err.(interp.Panic); !ok || fmt.Sprintf("%T", p.Value) != "string" {
+			t.Errorf("got %#v, want a panic with a string value", err)
+		}
+	}
+}
+
+type HostItem struct {
+	Name string
+	Tags []struct{ K, V string }
+}
+
+type HostConfig struct {
+	HostItem
+	Items [2]struct{ Name string }
+	Err   error
+	Any   interface{}
+	Get   func() int
+}
+
+type HostPriv struct {
+	Name string
+	id   int
+}
+
+func TestEvalStructConversion(t *testing.T) {
+	i := interp.New(interp.Options{})
+	i.Use(stdlib.Symbols)
+	i.Use(interp.Exports{"host": {
+		"HostItem":   reflect.ValueOf((*HostItem)(nil)),
+		"HostConfig": reflect.ValueOf((*HostConfig)(nil)),
+		"HostPriv":   reflect.ValueOf((*HostPriv)(nil)),
+		"Describe": reflect.ValueOf(func(c HostConfig) string {
+			return fmt.Sprintf("%s %v %s %v %v %d", c.Name, c.Tags, c.Items[1].Name, c.Err, c.Any, c.Get())
+		}),
+		"Default": reflect.ValueOf(func() HostConfig {
+			return HostConfig{HostItem{"a", nil}, [2]struct{ Name string }{1: {"b"}}, errors.New("c"), 4, func() int { return 5 }}
+		}),
+	}})
+	eval(t, i, `import ("errors"; "fmt"; "host")`)
+	eval(t, i, `type item struct { Name string "json:\"name\""; Tags []struct{ K, V string "json:\"k\"" } }`)
+	eval(t, i, `type config struct { host.HostItem; Items [2]struct{ Name string "json:\"name\"" }; Err error; Any interface{}; Get func() int }`)
+	eval(t, i, `type priv struct { Name string; id int }`)
+	eval(t, i, `type other struct { Name string; Tags []item }`)
+	eval(t, i, `type myErr struct{}`)
+	eval(t, i, `func (myErr) Error() string { return "my error" }`)
+
+	runTests(t, i, []testCase{
+		{src: `host.HostItem(item{"n", []struct{ K, V string "json:\"k\"" }{{"k", "v"}}})`, res: "{n [{k v}]}"},
+		{src: `item(host.HostItem{Name: "n"}).Name`, res: "n"},
+		{
+			src: `host.Describe(host.HostConfig(config{host.HostItem{Name: "x"}, [2]struct{ Name string "json:\"name\"" }{1: {"y"}}, errors.New("z"), 1, func() int { return 2 }}))`,
+			res: "x [] y z 1 2",
+		},
+		{src: `host.Describe(host.HostConfig(config{Err: myErr{}, Get: func() int { return 3 }}))`, res: " []  my error <nil> 3"},
+		{src: `c := config(host.Default()); fmt.Sprint(c.Name, c.Items[1].Name, c.Err, c.Any, c.Get())`, res: "abc 4 5"},
+		{src: `c := config(host.HostConfig{}); c.Err == nil && c.Any == nil`, res: "true"},
+		{src: `host.HostPriv(priv{})`, err: "1:28: cannot convert type main.priv to type github.com/containous/yaegi/interp_test.HostPriv"},
+		{src: `host.HostItem(other{})`, err: "1:28: cannot convert type main.other to type github.com/containous/yaegi/interp_test.HostItem"},
+	})
+}
+
+func TestEvalWrappedRoundTrip(t *testing.T) {
+	registry := map[string]io.Reader{}
+	i := interp.New(interp.Options{})
+	i.Use(stdlib.Symbols)
+	i.Use(interp.Exports{"host": {
+		"Register": reflect.ValueOf(func(name string, r io.Reader) { registry[name] = r }),
+		"Lookup":   reflect.ValueOf(func(name string) io.Reader { return registry[name] }),
+	}})
+	eval(t, i, `import ("host"; "io")`)
+	eval(t, i, `type R struct{ name string }`)
+	eval(t, i, `func (r *R) Read(b []byte) (int, error) { return 0, io.EOF }`)
+	eval(t, i, `host.Register("r", &R{"script"})`)
+
+	if _, ok := registry["r"].(io.Reader); !ok {
+		t.Fatalf("got %T, want an io.Reader", registry["r"])
+	}
+	runTests(t, i, []testCase{
+		{src: `host.Lookup("r").(*R).name`, res: "script"},
+		{src: `switch r := host.Lookup("r").(type) { case *R: r.name = "found" }; host.Lookup("r").(*R).name`, res: "found"},
+		{src: `_, ok := host.Lookup("r").(interface{ Close() error }); ok`, res: "false"},
+		{src: `host.Lookup("r").(io.Reader) == host.Lookup("r")`, res: "true"},
+	})
+}
+
+func TestEvalNilFunc(t *testing.T) {
+	i := interp.New(interp.Options{})
+	i.Use(stdlib.Symbols)
+	eval(t, i, `import ("fmt"; "net/http")`)
+	eval(t, i, `var f func(int) int`)
+	eval(t, i, `type I interface{ M() }`)
+	eval(t, i, `var (i I; s fmt.Stringer; c http.Client; n int)`)
+	eval(t, i, `func try(g func()) (r interface{}) { defer func() { r = recover() }(); g(); return }`)
+	const nilDeref = "runtime error: invalid memory address or nil pointer dereference"
+	runTests(t, i, []testCase{
+		{src: "f(1)", err: "1:28: " + nilDeref},
+		{src: "1+1", res: "2"},
+		{src: "fmt.Sprint(try(func() { f(1) }))", res: "1:52: " + nilDeref},
+		{src: "fmt.Sprint(try(func() { defer f(1) }))", res: "1:58: " + nilDeref},
+		{src: "i.M()", err: "1:28: " + nilDeref},
+		{src: "fmt.Sprint(try(func() { i.M() }))", res: "1:52: " + nilDeref},
+		{src: "s.String()", err: "1:28: " + nilDeref},
+		{src: "c.CheckRedirect(nil, nil)", err: "1:28: " + nilDeref},
+		{src: "fmt.Sprint(try(func() { defer c.CheckRedirect(nil, nil) }))", res: "1:58: " + nilDeref},
+		{desc: "panic in defer", src: `fmt.Sprint(try(func() { defer func() { n++ }(); defer f(1); panic("p") }), n)`, res: "1:82: " + nilDeref + " 1"},
+		{src: "1+1", res: "2"},
+	})
+}
+
+func TestPanicToError(t *testing.T) {
+	var handled []interp.PanicError
+	var cleanup int
+	i := interp.New(interp.Options{
+		PanicToError: true,
+		PanicHandler: func(err interp.PanicError) { handled = append(handled, err) },
+	})
+	i.Use(interp.Exports{"host": map[string]reflect.Value{
+		"Call": reflect.ValueOf(func(f func()) string {
+			defer func() { cleanup++ }()
+			f()
+			return "done"
+		}),
+		"Boom":   reflect.ValueOf(func() { panic("host") }),
+		"Goexit": reflect.ValueOf(runtime.Goexit),
+	}})
+	eval(t, i, `import "host"`)
+	eval(t, i, `func fail(n int) (int, error) { if n > 0 { return n, nil }; panic("boom") }`)
+	eval(t, i, `func count() int { panic(2) }`)
+	eval(t, i, `func run() string { return host.Call(func() { panic("callback") }) }`)
+	eval(t, i, `func boom() error { host.Boom(); return nil }`)
+	eval(t, i, `func exit() { host.Goexit() }`)
+	eval(t, i, `func deferred() error { defer func() { panic("deferred") }(); return nil }`)
+	eval(t, i, `func rec() (s string) { defer func() { if recover() != nil { s = "recovered" } }(); panic("inner") }`)
+
+	fail := eval(t, i, "fail").Interface().(func(int) (int, error))
+	if n, err := fail(1); n != 1 || err != nil {
+		t.Errorf("got %d, %v, want 1, nil", n, err)
+	}
+	n, err := fail(0)
+	perr, ok := err.(interp.PanicError)
+	if !ok || n != 0 || perr.Recovered() != "boom" || err.Error() != "panic: boom" {
+		t.Fatalf("got %d, %v, want 0 and panic: boom", n, err)
+	}
+	if stack := perr.InterpStack(); len(stack) != 1 || !strings.HasPrefix(stack[0], "main.fail at ") {
+		t.Errorf("got stack %q, want main.fail", stack)
+	}
+
+	if n := eval(t, i, "count").Interface().(func() int)(); n != 0 || len(handled) != 1 || handled[0].Recovered() != 2 {
+		t.Errorf("got %d, %v, want 0 and panic 2 handled", n, handled)
+	}
+
+	// An interpreted callback panicking through binary code.
+	if res := eval(t, i, "run()"); res.String() != "done" || cleanup != 1 || len(handled) != 2 || handled[1].Recovered() != "callback" {
+		t.Errorf("got %v, cleanup %d, handled %v, want done, callback handled", res, cleanup, handled)
+	}
+	if stack := handled[1].InterpStack(); len(stack) != 1 || !strings.HasPrefix(stack[0], "main.run.func1 at ") {
+		t.Errorf("got stack %q, want main.run.func1", stack)
+	}
+
+	// The panics of deferred functions are recovered at the boundary only.
+	if err := eval(t, i, "deferred").Interface().(func() error)(); err == nil || err.Error() != "panic: deferred" || len(handled) != 2 {
+		t.Errorf("got %v, handled %v, want panic: deferred", err, handled)
+	}
+
+	// Panics recovered in interpreted code are not affected.
+	if res := eval(t, i, "rec()"); res.String() != "recovered" || len(handled) != 2 {
+		t.Errorf("got %v, handled %v, want recovered", res, handled)
+	}
+
+	// Panics of binary code are not recovered.
+	boom := eval(t, i, "boom").Interface().(func() error)
+	func() {
+		defer func() {
+			if r := recover(); r != "host" {
+				t.Errorf("got %v, want host panic", r)
+			}
+		}()
+		err := boom()
+		t.Errorf("unexpected return %v", err)
+	}()
+
+	// Nor goroutine exits.
+	exit := eval(t, i, "exit").Interface().(func())
+	done := make(chan bool)
+	go func() {
+		defer close(done)
+		exit()
+		t.Error("unexpected return")
+	}()
+	<-done
+	if len(handled) != 2 {
+		t.Errorf("got handled %v, want 2 panics", handled)
+	}
+}
+
+func TestEvalArgs(t *testing.T) {
+	i := interp.New(interp.Options{Args: []string{"script.go", "-v"}})
+	i.Use(stdlib.Symbols)
+	runTests(t, i, []testCase{
+		{pre: func() { eval(t, i, `import "os"`) }, src: "os.Args", res: "[script.go -v]"},
+	})
+	if args := stdlib.Symbols["os"]["Args"].Interface().([]string); reflect.DeepEqual(args, []string{"script.go", "-v"}) {
+		t.Error("os.Args of the process should not be modified")
+	}
+}
+
+func TestRunMain(t *testing.T) {
+	tests := []struct {
+		desc string
+		args []string
+		out  string
+		err  string
+	}{
+		{
+			desc: "default",
+			out:  "init1 init2\nprogram: greet\nhello world\n",
+		},
+		{
+			desc: "flags",
+			args: []string{"greet", "-name", "yaegi", "-n", "2", "a", "b"},
+			out:  "init1 init2\nprogram: greet\nhello yaegi\nhello yaegi\nargs: [a b]\n",
+		},
+		{
+			desc: "exit",
+			args: []string{"greet", "-name=exit"},
+			out:  "init1 init2\nprogram: greet\nhello exit\n",
+			err:  "exit status 3",
+		},
+		{
+			desc: "bad flag",
+			args: []string{"greet", "-bad"},
+			err:  "exit status 2",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			i := interp.New(interp.Options{Stdout: &stdout, Stderr: &stderr})
+			i.Use(stdlib.Symbols)
+			err := i.RunMain("./testdata/cmd/greet", test.args)
+			if test.err == "" && err != nil {
+				t.Fatal(err)
+			}
+			if test.err != "" {
+				var e interp.ExitError
+				if !errors.As(err, &e) || err.Error() != test.err {
+					t.Fatalf("got error %v, want %s", err, test.err)
+				}
+			}
+			if got := stdout.String(); got != test.out {
+				t.Errorf("got output %q, want %q", got, test.out)
+			}
+			if test.desc == "bad flag" && !strings.Contains(stderr.String(), "Usage of greet:") {
+				t.Errorf("got stderr %q, want usage", stderr.String())
+			}
+		})
+	}
+
+	i := interp.New(interp.Options{})
+	i.Use(stdlib.Symbols)
+	if err := i.RunMain("./testdata/src/github.com/foo/bar/baz", nil); err == nil || !strings.Contains(err.Error(), "not a main package") {
+		t.Errorf("got error %v, want not a main package", err)
+	}
+}
+
+func TestEvalWithContext(t *testing.T) {
+	tests := []testCase{
+		{
+			desc: "for {}",
+			src: `(func() {
+				      for {}
+			      })()`,
+		},
+		{
+			desc: "select {}",
+			src: `(func() {
+				     select {}
+			     })()`,
+		},
+		{
+			desc: "blocked chan send",
+			src: `(func() {
+			         c := make(chan int)
+				     c <- 1
+				 })()`,
+		},
+		{
+			desc: "blocked chan recv",
+			src: `(func() {
+			         c := make(chan int)
+				     <-c
+			     })()`,
+		},
+		{
+			desc: "blocked chan recv2",
+			src: `(func() {
+			         c := make(chan int)
+				     _, _ = <-c
+			     })()`,
+		},
+		{
+			desc: "blocked range chan",
+			src: `(func() {
+			         c := make(chan int)
+				     for range c {}
+			     })()`,
+		},
+		{
+			desc: "double lock",
+			src: `(func() {
+			         var mu sync.Mutex
+				     mu.Lock()
+				     mu.Lock()
+			      })()`,
+		},
+	}
+
+	for _, test := range tests {
+		done := make(chan struct{})
+		src := test.src
+		go func() {
+			defer close(done)
+			i := interp.New(interp.Options{})
+			i.Use(stdlib.Symbols)
+			_, err := i.Eval(`import "sync"`)
+			if err != nil {
+				t.Errorf(`failed to import "sync": %v`, err)
+				return
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			_, err = i.EvalWithContext(ctx, src)
+			switch err {
+			case context.DeadlineExceeded:
+				// Successful cancellation.
+
+				// Check we can still execute an expression.
+				v, err := i.EvalWithContext(context.Background(), "1+1\n")
+				if err != nil {
+					t.Errorf("failed to evaluate expression after cancellation: %v", err)
+				}
+				got := v.Interface()
+				if got != 2 {
+					t.Errorf("unexpected result of eval(1+1): got %v, want 2", got)
+				}
+			case nil:
+				t.Errorf("unexpected success evaluating expression %q", test.desc)
+			default:
+				t.Errorf("failed to evaluate expression %q: %v", test.desc, err)
+			}
+		}()
+		select {
+		case <-time.After(time.Second):
+			t.Errorf("timeout failed to terminate execution of %q", test.desc)
+		case <-done:
+		}
+	}
+}
+
+func TestEvalWithTimeout(t *testing.T) {
+	i := interp.New(interp.Options{})
+	i.Use(stdlib.Symbols)
+	eval(t, i, `import "time"`)
+	eval(t, i, `var n int`)
+	eval(t, i, `var done = make(chan struct{})`)
+	eval(t, i, `func loop() { defer close(done); for { n++ } }`)
+	eval(t, i, `func spin() { go loop(); for {} }`)
+
+	_, err := i.EvalWithTimeout("spin()", 50*time.Millisecond)
+	var te *interp.TimeoutError
+	if !errors.As(err, &te) || !errors.Is(err, interp.ErrTimeout) {
+		t.Fatalf("got %v, want a timeout error", err)
+	}
+	if !strings.HasPrefix(te.Location, "main.spin at ") && !strings.HasPrefix(te.Location, "main.loop at ") {
+		t.Errorf("got location %q, want main.spin or main.loop", te.Location)
+	}
+
+	// The goroutine started by the interpreted code is also stopped, running
+	// its deferred calls.
+	select {
+	case <-eval(t, i, "done").Interface().(chan struct{}):
+	case <-time.After(time.Second):
+		t.Fatal("the goroutine of loop is not stopped")
+	}
+	n1 := eval(t, i, "n").Int()
+	if n2 := eval(t, i, "n").Int(); n1 == 0 || n2 != n1 {
+		t.Errorf("got n %d then %d, want a stopped count", n1, n2)
+	}
+
+	// The timeout includes the time spent in binary calls.
+	start := time.Now()
+	_, err = i.EvalWithTimeout("time.Sleep(time.Second)", 50*time.Millisecond)
+	if !errors.Is(err, interp.ErrTimeout) || time.Since(start) > 500*time.Millisecond {
+		t.Errorf("got %v after %v, want a timeout error", err, time.Since(start))
+	}
+
+	if v, err := i.EvalWithTimeout("n > 0", time.Second); err != nil || !v.Bool() {
+		t.Errorf("got %v, %v, want true", v, err)
+	}
+
+	// Default timeout of Eval.
+	i = interp.New(interp.Options{EvalTimeout: 50 * time.Millisecond})
+	if _, err := i.Eval("for {}"); !errors.Is(err, interp.ErrTimeout) {
+		t.Errorf("got %v, want a timeout error", err)
+	}
+	if v, err := i.Eval("1 + 2"); err != nil || v.Int() != 3 {
+		t.Errorf("got %v, %v, want 3", v, err)
+	}
+}
+
+func TestExit(t *testing.T) {
+	i := interp.New(interp.Options{})
+	i.Use(stdlib.Symbols)
+	_, err := i.Eval(`
+import (
+	"os"
+	"runtime"
+)
+
+var cleanups string
+
+func cleanup(s string) { cleanups += s + " " }
+
+func exit() {
+	defer cleanup("exit")
+	defer func() {
+		if recover() != nil {
+			cleanup("recovered")
+		}
+	}()
+	os.Exit(3)
+}
+
+func goexit(done chan bool) {
+	defer close(done)
+	defer cleanup("goexit")
+	runtime.Goexit()
+	cleanup("not reached")
+}
+
+func run() {
+	done := make(chan bool)
+	go goexit(done)
+	<-done
+	defer cleanup("run")
+	exit()
+	cleanup("not reached")
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = i.Eval(`run()`)
+	if e, ok := err.(interp.ExitError); !ok || e.Code != 3 {
+		t.Fatalf("got error %v, want ExitError{Code: 3}", err)
+	}
+	res, err := i.Eval(`cleanups`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, want := res.String(), "goexit exit run "; s != want {
+		t.Errorf("got %s, want %s", s, want)
+	}
+
+	// The evaluation terminated by runtime.Goexit succeeds.
+	if _, err = i.Eval(`func stop() { defer cleanup("eval"); runtime.Goexit() }`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = i.Eval(`stop()`); err != nil {
+		t.Fatal(err)
+	}
+	if res, _ = i.Eval(`cleanups`); !strings.HasSuffix(res.String(), "run eval ") {
+		t.Errorf("got cleanups %q, want the eval cleanup", res)
+	}
+
+	// With the AllowExit option, the os.Exit symbol is left unchanged.
+	i = interp.New(interp.Options{AllowExit: true})
+	i.Use(stdlib.Symbols)
+	_, err = i.Eval(`
+import (
+	"fmt"
+	"os"
+)
+
+func exit() (r interface{}) {
+	defer func() { r = recover() }()
+	os.Exit(3)
+	return
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res, err = i.Eval(`fmt.Sprint(exit())`); err != nil {
+		t.Fatal(err)
+	}
+	if s := res.String(); s != "os.Exit(3)" {
+		t.Errorf("got %s, want the panic of the restricted os.Exit", s)
+	}
+}
+
+func TestEnv(t *testing.T) {
+	os.Setenv("YAEGI_TEST_ENV", "host")
+	defer os.Unsetenv("YAEGI_TEST_ENV")
+
+	i := interp.New(interp.Options{Env: []string{"HOME=/home/test", "A=1"}})
+	i.Use(stdlib.Symbols)
+	_, err := i.Eval(`
+import (
+	"fmt"
+	"os"
+)
+
+func env() string {
+	_, ok := os.LookupEnv("YAEGI_TEST_ENV")
+	os.Setenv("B", "2")
+	os.Setenv("YAEGI_TEST_ENV", "interp")
+	os.Unsetenv("A")
+	return fmt.Sprintln(ok, os.Environ(), os.ExpandEnv("$HOME/$B"))
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := i.Eval("env()")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, want := res.String(), "false [B=2 HOME=/home/test YAEGI_TEST_ENV=interp] /home/test/2\n"; s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+	if s := os.Getenv("YAEGI_TEST_ENV"); s != "host" {
+		t.Errorf("got host environment %q, want unchanged", s)
+	}
+}
+
+func TestFS(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "yaegi-fs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	root, overlay := filepath.Join(tmp, "root"), filepath.Join(tmp, "overlay")
+	for _, dir := range []string{filepath.Join(root, "dir"), overlay} {
+		if err = os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files := map[string]string{"a.txt": "a", "dir/b.txt": "b", "dir/c.txt": "c", "../secret": "secret"}
+	for name, data := range files {
+		if err = ioutil.WriteFile(filepath.Join(root, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = os.Symlink(filepath.Join(tmp, "secret"), filepath.Join(root, "escape")); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Symlink("a.txt", filepath.Join(root, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	src := `
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func read(name string) string {
+	b, err := ioutil.ReadFile(name)
+	if err != nil {
+		return err.Error()
+	}
+	return string(b)
+}
+
+func list() string {
+	var names []string
+	filepath.Walk("/", func(path string, info os.FileInfo, err error) error {
+		names = append(names, path)
+		return nil
+	})
+	return strings.Join(names, " ")
+}
+
+func readOnly() string {
+	_, err := os.Create("new.txt")
+	return fmt.Sprintln(read("dir/../../a.txt"), read("../secret"), read("escape"), read("link"), err)
+}
+
+func readWrite() string {
+	os.Chdir("dir")
+	wd, _ := os.Getwd()
+	ioutil.WriteFile("/a.txt", []byte("A"), 0644)
+	ioutil.WriteFile("d.txt", []byte("d"), 0644)
+	os.Remove("b.txt")
+	os.Rename("c.txt", "/e.txt")
+	f, _ := ioutil.TempFile("", "")
+	f.Close()
+	m, _ := filepath.Glob("/*.txt")
+	return fmt.Sprintln(wd, read("/a.txt"), os.Remove(f.Name()), m, list())
+}`
+
+	i := interp.New(interp.Options{FSRoot: root})
+	i.Use(stdlib.Symbols)
+	if _, err = i.Eval(src); err != nil {
+		t.Fatal(err)
+	}
+	res, err := i.Eval("readOnly()")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, want := res.String(), "a open ../secret: no such file or directory open escape: permission denied a open new.txt: read-only file system\n"; s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+
+	i = interp.New(interp.Options{FSRoot: root, FSOverlay: overlay})
+	i.Use(stdlib.Symbols)
+	if _, err = i.Eval(src); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Mkdir(filepath.Join(root, "tmp"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if res, err = i.Eval("readWrite()"); err != nil {
+		t.Fatal(err)
+	}
+	want := "/dir A <nil> [/a.txt /e.txt] / /a.txt /dir /dir/d.txt /e.txt /escape /link /tmp\n"
+	if s := res.String(); s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+	for name, data := range files {
+		if b, err := ioutil.ReadFile(filepath.Join(root, name)); err != nil || string(b) != data {
+			t.Errorf("got %s content %q, %v, want %q unchanged", name, b, err, data)
+		}
+	}
+}
+
+// fakeClock is a clock advanced explicitly.
+type fakeClock struct {
+	mutex    sync.Mutex
+	now      time.Time
+	sleepers map[chan bool]time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Sleep(d time.Duration) {
+	c.mutex.Lock()
+	done := make(chan bool)
+	c.sleepers[done] = c.now.Add(d)
+	c.mutex.Unlock()
+	<-done
+}
+
+// advance advances the clock by d, once n sleepers are waiting.
+func (c *fakeClock) advance(n int, d time.Duration) {
+	for {
+		c.mutex.Lock()
+		if len(c.sleepers) >= n {
+			break
+		}
+		c.mutex.Unlock()
+		time.Sleep(time.Millisecond)
+	}
+	defer c.mutex.Unlock()
+	c.now = c.now.Add(d)
+	for done, t := range c.sleepers {
+		if !t.After(c.now) {
+			delete(c.sleepers, done)
+			close(done)
+		}
+	}
+}
+
+func TestClock(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := &fakeClock{now: start, sleepers: map[chan bool]time.Time{}}
+	i := interp.New(interp.Options{Clock: clock})
+	i.Use(stdlib.Symbols)
+	_, err := i.Eval(`
+import "time"
+
+var start = time.Now()
+
+func wait(events chan string) string {
+	ticks := 0
+	for {
+		select {
+		case e := <-events:
+			return e + " after " + time.Since(start).String()
+		case <-time.After(time.Minute):
+			if ticks++; ticks == 3 {
+				return "timeout at " + time.Now().Format(time.Kitchen)
+			}
+		}
+	}
+}`)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := i.Eval("wait")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wait := v.Interface().(func(chan string) string)
+
+	res := make(chan string)
+	events := make(chan string)
+	go func() { res <- wait(events) }()
+	clock.advance(1, time.Minute)
+	clock.advance(1, 30*time.Second)
+	events <- "event"
+	if s, want := <-res, "event after 1m30s"; s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+
+	// The timer of the previous wait is still pending.
+	go func() { res <- wait(events) }()
+	clock.advance(2, time.Minute)
+	clock.advance(1, time.Minute)
+	clock.advance(1, time.Minute)
+	if s, want := <-res, "timeout at 12:04AM"; s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+}
+
+func TestStderr(t *testing.T) {
+	var stderr bytes.Buffer
+	i := interp.New(interp.Options{Stderr: &stderr})
+	if _, err := i.Eval(`x := 2; p := &x; print("x", x, *p == 2); println(); println(p != nil, []int{}[:0] != nil, 1e21)`); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := stderr.String(), "x2true\ntrue true 1e+21\n"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	// Panics recovered at the binary boundary without handler.
+	stderr.Reset()
+	i = interp.New(interp.Options{Stderr: &stderr, PanicToError: true})
+	eval(t, i, `func count() int { panic(2) }`)
+	if n := eval(t, i, "count").Interface().(func() int)(); n != 0 || stderr.String() != "panic: 2\n" {
+		t.Fatalf("got %d, %q, want 0, panic: 2", n, stderr.String())
+	}
+}
+
+func TestSaveState(t *testing.T) {
+	src := `
+type Point struct{ X, Y int }
+
+var (
+	count int
+	name  = "init"
+	list  []string
+	index = map[string]int{}
+	pt    Point
+	ppt   = &Point{1, 2}
+)
+`
+	i := interp.New(interp.Options{})
+	if _, err := i.Eval(src); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := i.Eval(`count = 3; name = "done"; list = []string{"x", "y"}; index["a"] = 7; pt.X = 5; ppt.Y = 9`); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := i.SaveState(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	j := interp.New(interp.Options{})
+	if _, err := j.Eval(src); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.LoadState(&buf); err != nil {
+		t.Fatal(err)
+	}
+	j.Use(stdlib.Symbols)
+	if _, err := j.Eval(`import "fmt"`); err != nil {
+		t.Fatal(err)
+	}
+	res, err := j.Eval(`fmt.Sprint(count, name, list, index, pt, *ppt)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, want := res.String(), "3done[x y] map[a:7] {5 0} {1 9}"; s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+
+	// A state can not be loaded in an interpreter without the variables.
+	if err := i.SaveState(&buf); err != nil {
+		t.Fatal(err)
+	}
+	k := interp.New(interp.Options{})
+	if _, err := k.Eval(`var count int`); err != nil {
+		t.Fatal(err)
+	}
+	if err := k.LoadState(&buf); err == nil || !strings.HasSuffix(err.Error(), ": undefined variable") {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if _, err := i.Eval(`var (f = func() {}; c chan int; v interface{})`); err != nil {
+		t.Fatal(err)
+	}
+	err = i.SaveState(&buf)
+	if want := "cannot save state, unserializable values: c (chan), f (func), v (interface)"; err == nil || err.Error() != want {
+		t.Errorf("got error %v, want %q", err, want)
+	}
+}
+
+func TestMathBits(t *testing.T) {
+	// Wrap each function of math/bits in interpreted functions of the same
+	// signature, returning the call result directly or from a variable.
+	syms := stdlib.Symbols["math/bits"]
+	var names []string
+	for name, v := range syms {
+		if v.Kind() == reflect.Func {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	b.WriteString("package main\n\nimport \"math/bits\"\n")
+	for _, name := range names {
+		typ := syms[name].Type()
+		var params, args, results, vars []string
+		for i := 0; i < typ.NumIn(); i++ {
+			params = append(params, fmt.Sprintf("a%d %s", i, typ.In(i)))
+			args = append(args, fmt.Sprintf("a%d", i))
+		}
+		for i := 0; i < typ.NumOut(); i++ {
+			results = append(results, typ.Out(i).String())
+			vars = append(vars, fmt.Sprintf("r%d", i))
+		}
+		sig := fmt.Sprintf("(%s) (%s)", strings.Join(params, ", "), strings.Join(results, ", "))
+		call := fmt.Sprintf("bits.%s(%s)", name, strings.Join(args, ", "))
+		fmt.Fprintf(&b, "\nfunc Return%s%s { return %s }\n", name, sig, call)
+		fmt.Fprintf(&b, "\nfunc Assign%s%s {\n\t%s := %s\n\treturn %[3]s\n}\n", name, sig, strings.Join(vars, ", "), call)
+	}
+	i := interp.New(interp.Options{})
+	i.Use(stdlib.Symbols)
+	if _, err := i.Eval(b.String()); err != nil {
+		t.Fatal(err)
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	for _, name := range names {
+		host := syms[name]
+		typ := host.Type()
+		var wrappers []reflect.Value
+		for _, prefix := range []string{"Return", "Assign"} {
+			w, err := i.Eval(prefix + name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			wrappers = append(wrappers, w)
+		}
+		for n := 0; n < 200; n++ {
+			in := make([]reflect.Value, typ.NumIn())
+			for j := range in {
+				if typ.In(j).Kind() == reflect.Int {
+					in[j] = reflect.ValueOf(rnd.Intn(200) - 100) // Rotation counts.
+				} else {
+					in[j] = reflect.ValueOf(rnd.Uint64() >> uint(rnd.Intn(64))).Convert(typ.In(j))
+				}
+			}
+			if strings.HasPrefix(name, "Div") || strings.HasPrefix(name, "Rem") {
+				// Avoid the division panics: the divisor must be greater than hi.
+				in[0] = reflect.Zero(typ.In(0))
+				if in[2].Uint() == 0 {
+					in[2] = reflect.ValueOf(uint64(1)).Convert(typ.In(2))
+				}
+			}
+			want := host.Call(in)
+			for _, w := range wrappers {
+				got := w.Call(in)
+				for j := range want {
+					if got[j].Interface() != want[j].Interface() {
+						t.Fatalf("%s%v: got %v, want %v", name, in, got[j], want[j])
+					}
+				}
+			}
+		}
+	}
+
+	res, err := i.Eval("bits.UintSize")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := res.Int(); s != bits.UintSize {
+		t.Errorf("got UintSize %d, want %d", s, bits.UintSize)
+	}
+}
+
+func BenchmarkMathBits(b *testing.B) {
+	i := interp.New(interp.Options{})
+	i.Use(stdlib.Symbols)
+	v, err := i.Eval(`import "math/bits"
+
+func F(n int) (s int) {
+	for j := 0; j < n; j++ {
+		x := uint64(j)
+		s += bits.OnesCount64(x) + bits.LeadingZeros64(x) + int(bits.RotateLeft64(x, -3)&1)
+	}
+	return s
+}`)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if v, err = i.Eval("F"); err != nil {
+		b.Fatal(err)
+	}
+	f := v.Interface().(func(int) int)
+	b.ResetTimer()
+	f(b.N)
+}
+
+func TestBinVarInLoop(t *testing.T) {
+	level, name := 1, "a"
+	i := interp.New(interp.Options{})
+	i.Use(stdlib.Symbols)
+	i.Use(interp.Exports{"conf": {
+		"Level": reflect.ValueOf(&level).Elem(),
+		"Name":  reflect.ValueOf(&name).Elem(),
+		"Bump":  reflect.ValueOf(func() { level++; name += "b" }),
+	}})
+	eval(t, i, `import ("conf"; "fmt")`)
+	eval(t, i, `func F() (res []string) {
+	for j := 0; j < 4; j++ {
+		switch j {
+		case 1:
+			conf.Bump()
+		case 2:
+			conf.Level, conf.Name = 10, "z"
+		}
+		res = append(res, fmt.Sprintf("%d %s %t", conf.Level*2, conf.Name+"!", conf.Level > 1))
+	}
+	return res
+}`)
+	got := fmt.Sprint(eval(t, i, "F()"))
+	if want := "[2 a! false 4 ab! true 20 z! true 20 z! true]"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	if level != 10 || name != "z" {
+		t.Errorf("got %d %q, want 10 \"z\"", level, name)
+	}
+}
+
+func BenchmarkBinSelector(b *testing.B) {
+	i := interp.New(interp.Options{})
+	i.Use(stdlib.Symbols)
+	v, err := i.Eval(`import ("math"; "net/http"; "os"; "time")
+
+func F(n int) (s float64) {
+	var d time.Duration
+	for j := 0; j < n; j++ {
+		s += math.Pi
+		d += time.Nanosecond
+		if http.StatusOK == 200 && len(os.Args) > 0 {
+			s++
+		}
+	}
+	return s + d.Seconds()
+}`)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if v, err = i.Eval("F"); err != nil {
+		b.Fatal(err)
+	}
+	f := v.Interface().(func(int) float64)
+	b.ResetTimer()
+	f(b.N)
+}
+
+func TestEvalWithStats(t *testing.T) {
+	i := interp.New(interp.Options{Stats: true})
+	i.Use(stdlib.Symbols)
+	eval(t, i, `import "sync"`)
+	eval(t, i, `func loop(n int) (s int) { for i := 0; i < n; i++ { s += i }; return }`)
+	eval(t, i, `func calls(n int) { for i := 0; i < n; i++ { loop(1) } }`)
+	eval(t, i, `var wg sync.WaitGroup`)
+	eval(t, i, `var start = make(chan bool)`)
+	eval(t, i, `func spawn(n int) { wg.Add(n); for i := 0; i < n; i++ { go func() { <-start; loop(100); wg.Done() }() } }`)
+	eval(t, i, `func finish() { close(start); wg.Wait() }`)
+
+	_, s1, err := i.EvalWithStats("loop(100)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, s2, err := i.EvalWithStats("loop(1000)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := float64(s2.Nodes()) / float64(s1.Nodes()); r < 8 || r > 12 {
+		t.Errorf("got nodes %d and %d, want a ratio of about 10", s1.Nodes(), s2.Nodes())
+	}
+	if s1.Frames() != 1 || s2.Frames() != 1 || s1.Duration() <= 0 {
+		t.Errorf("got frames %d and %d, duration %v, want 1 frame", s1.Frames(), s2.Frames(), s1.Duration())
+	}
+	_, s3, err := i.EvalWithStats("calls(100)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s3.Frames() != 101 || s3.Values() < 3*s3.Frames() {
+		t.Errorf("got frames %d and values %d, want 101 frames", s3.Frames(), s3.Values())
+	}
+
+	// Work of goroutines is accounted until they exit.
+	_, s4, err := i.EvalWithStats("spawn(3)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s4.Goroutines() != 3 || s4.MaxGoroutines() != 3 {
+		t.Errorf("got %d running and %d max goroutines, want 3", s4.Goroutines(), s4.MaxGoroutines())
+	}
+	n := s4.Nodes()
+	eval(t, i, "finish()")
+	for deadline := time.Now().Add(time.Second); s4.Goroutines() > 0 && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	if s4.Goroutines() != 0 || s4.Nodes() < n+3*s1.Nodes() {
+		t.Errorf("got %d running goroutines and %d nodes, want 0 and more than %d", s4.Goroutines(), s4.Nodes(), n+3*s1.Nodes())
+	}
+
+	// Program executions are accumulated.
+	p, err := i.Compile("loop(100)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var prev int64
+	for k := 0; k < 3; k++ {
+		if _, err := i.Execute(p); err != nil {
+			t.Fatal(err)
+		}
+		if n := p.Stats().Nodes(); n <= prev {
+			t.Errorf("got nodes %d after %d, want an increase", n, prev)
+		}
+		prev = p.Stats().Nodes()
+	}
+	if p.Stats().Frames() != 6 {
+		t.Errorf("got frames %d, want 6", p.Stats().Frames())
+	}
+
+	if _, _, err := interp.New(interp.Options{}).EvalWithStats("1"); err == nil || err.Error() != "stats not enabled" {
+		t.Errorf("got %v, want stats not enabled", err)
+	}
+}
+
+func TestEvalGoVersion(t *testing.T) {
+	const (
+		loopVars = `(func() (s string) {
+	var fs []func()
+	for i := 0; i < 3; i++ {
+		fs = append(fs, func() { s += string(rune('0' + i)) })
+	}
+	for _, r := range "ab" {
+		fs = append(fs, func() { s += string(r) })
+	}
+	for _, f := range fs {
+		f()
+	}
+	return s
+})()`
+		bodyVars = `(func() (s string) {
+	var ps []*int
+	for i := 0; i < 3; i++ {
+		j := i
+		ps = append(ps, &j)
+	}
+	for _, p := range ps {
+		s += string(rune('0' + *p))
+	}
+	return s
+})()`
+		rangeInt  = `(func() (s int) { for i := range 4 { s += i }; return })()`
+		rangeFunc = `(func() { seq := func(yield func(int) bool) {}; for i := range seq { _ = i } })()`
+		generic   = `func id[T any](x T) T { return x }`
+	)
+
+	tests := []struct {
+		version string
+		cases   []testCase
+	}{
+		{version: "go1.17", cases: []testCase{
+			{desc: "generic", src: generic, err: "1:14: type parameter requires go1.18 or later"},
+		}},
+		{version: "go1.18", cases: []testCase{
+			{desc: "generic", src: generic, err: "1:14: generic declarations are not supported"},
+		}},
+		{version: "go1.20", cases: []testCase{
+			{desc: "min", src: "min(1, 2)", err: "1:28: min requires go1.21 or later"},
+			{desc: "max", src: "max(1, 2)", err: "1:28: max requires go1.21 or later"},
+			{desc: "clear", src: "clear(map[int]int{})", err: "1:28: clear requires go1.21 or later"},
+		}},
+		{version: "go1.21", cases: []testCase{
+			{desc: "min", src: "min(1, 2)", res: "1"},
+			{desc: "max", src: "max(1, 2)", res: "2"},
+			{desc: "loopVars", src: loopVars, res: "333bb"},
+			{desc: "bodyVars", src: bodyVars, res: "012"},
+			{desc: "rangeInt", src: rangeInt, err: "1:61: cannot range over 4: requires go1.22 or later"},
+		}},
+		{version: "go1.21rc1", cases: []testCase{
+			{desc: "min", src: "min(1, 2)", res: "1"},
+			{desc: "loopVars", src: loopVars, res: "333bb"},
+		}},
+		{version: "go1.21.3", cases: []testCase{
+			{desc: "loopVars", src: loopVars, res: "333bb"},
+		}},
+		{version: "go1.22", cases: []testCase{
+			{desc: "loopVars", src: loopVars, res: "012ab"},
+			{desc: "bodyVars", src: bodyVars, res: "012"},
+			{desc: "rangeInt", src: rangeInt, res: "6"},
+			{desc: "rangeFunc", src: rangeFunc, err: "1:91: cannot range over seq: requires go1.23 or later"},
+		}},
+		{version: "go1.23", cases: []testCase{
+			{desc: "rangeFunc", src: rangeFunc, err: "1:91: cannot range over seq: range over function not supported"},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.version, func(t *testing.T) {
+			runTests(t, interp.New(interp.Options{GoVersion: test.version}), test.cases)
+		})
+	}
+
+	// The toolchain version is the default, and replaces an invalid version.
+	for _, version := range []string{"", "go1", "go1.x", "1.21", "go2.0"} {
+		runTests(t, interp.New(interp.Options{GoVersion: version}), []testCase{
+			{desc: "default", src: loopVars, res: "012ab"},
+		})
+	}
+
+	// Before go1.21, panic(nil) is recovered as nil.
+	_, err := interp.New(interp.Options{GoVersion: "go1.20"}).Eval(`panic(nil)`)
+	if p, ok := err.(interp.Panic); !ok || p.Value != nil {
+		t.Fatalf("got %#v, want a nil panic", err)
 	}
 }
 
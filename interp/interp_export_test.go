@@ -1,7 +1,9 @@
 package interp_test
 
 import (
+	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/containous/yaegi/interp"
@@ -66,3 +68,186 @@ func NewMyInt(i int) wrap.Wrap {
 	w := NewMyInt(4)
 	Hi(w)
 }
+
+// Shape is an interface with many methods, implemented by interpreted types
+// through its wrapper.
+type Shape interface {
+	M00() int
+	M01() int
+	M02() int
+	M03() int
+	M04() int
+	M05() int
+	M06() int
+	M07() int
+	M08() int
+	M09() int
+	M10() int
+	M11() int
+	M12() int
+	M13() int
+	M14() int
+	M15() int
+	M16() int
+	M17() int
+	M18() int
+	M19() int
+	M20() int
+	M21() int
+	M22() int
+	M23() int
+	M24() int
+}
+
+// Message has an unexported method, which interpreted types get from the
+// MessageState embedded in its wrapper.
+type Message interface {
+	Name() string
+	state() *MessageState
+}
+
+// MessageState counts the calls of Describe.
+type MessageState struct{ calls int }
+
+func (m *MessageState) state() *MessageState { return m }
+
+// Describe returns the name of m and the number of times it was described.
+func Describe(m Message) string {
+	m.state().calls++
+	return fmt.Sprint(m.Name(), " ", m.state().calls)
+}
+
+// Sealed has an unexported method provided by no type, so it can not be
+// implemented by interpreted types.
+type Sealed interface {
+	Kind() int
+	sealed()
+}
+
+// The following wrappers are generated by the extract command.
+
+type _github_com_containous_yaegi_interp_test_Shape struct {
+	WM00   func() int
+	WM01   func() int
+	WM02   func() int
+	WM03   func() int
+	WM04   func() int
+	WM05   func() int
+	WM06   func() int
+	WM07   func() int
+	WM08   func() int
+	WM09   func() int
+	WM10   func() int
+	WM11   func() int
+	WM12   func() int
+	WM13   func() int
+	WM14   func() int
+	WM15   func() int
+	WM16   func() int
+	WM17   func() int
+	WM18   func() int
+	WM19   func() int
+	WM20   func() int
+	WM21   func() int
+	WM22   func() int
+	WM23   func() int
+	WM24   func() int
+	IValue interface{}
+}
+
+func (W _github_com_containous_yaegi_interp_test_Shape) M00() int { return W.WM00() }
+func (W _github_com_containous_yaegi_interp_test_Shape) M01() int { return W.WM01() }
+func (W _github_com_containous_yaegi_interp_test_Shape) M02() int { return W.WM02() }
+func (W _github_com_containous_yaegi_interp_test_Shape) M03() int { return W.WM03() }
+func (W _github_com_containous_yaegi_interp_test_Shape) M04() int { return W.WM04() }
+func (W _github_com_containous_yaegi_interp_test_Shape) M05() int { return W.WM05() }
+func (W _github_com_containous_yaegi_interp_test_Shape) M06() int { return W.WM06() }
+func (W _github_com_containous_yaegi_interp_test_Shape) M07() int { return W.WM07() }
+func (W _github_com_containous_yaegi_interp_test_Shape) M08() int { return W.WM08() }
+func (W _github_com_containous_yaegi_interp_test_Shape) M09() int { return W.WM09() }
+func (W _github_com_containous_yaegi_interp_test_Shape) M10() int { return W.WM10() }
+func (W _github_com_containous_yaegi_interp_test_Shape) M11() int { return W.WM11() }
+func (W _github_com_containous_yaegi_interp_test_Shape) M12() int { return W.WM12() }
+func (W _github_com_containous_yaegi_interp_test_Shape) M13() int { return W.WM13() }
+func (W _github_com_containous_yaegi_interp_test_Shape) M14() int { return W.WM14() }
+func (W _github_com_containous_yaegi_interp_test_Shape) M15() int { return W.WM15() }
+func (W _github_com_containous_yaegi_interp_test_Shape) M16() int { return W.WM16() }
+func (W _github_com_containous_yaegi_interp_test_Shape) M17() int { return W.WM17() }
+func (W _github_com_containous_yaegi_interp_test_Shape) M18() int { return W.WM18() }
+func (W _github_com_containous_yaegi_interp_test_Shape) M19() int { return W.WM19() }
+func (W _github_com_containous_yaegi_interp_test_Shape) M20() int { return W.WM20() }
+func (W _github_com_containous_yaegi_interp_test_Shape) M21() int { return W.WM21() }
+func (W _github_com_containous_yaegi_interp_test_Shape) M22() int { return W.WM22() }
+func (W _github_com_containous_yaegi_interp_test_Shape) M23() int { return W.WM23() }
+func (W _github_com_containous_yaegi_interp_test_Shape) M24() int { return W.WM24() }
+
+type _github_com_containous_yaegi_interp_test_Message struct {
+	MessageState
+	WName  func() string
+	IValue interface{}
+}
+
+func (W _github_com_containous_yaegi_interp_test_Message) Name() string { return W.WName() }
+
+type _github_com_containous_yaegi_interp_test_Sealed struct {
+	WKind  func() int
+	IValue interface{}
+}
+
+func (W _github_com_containous_yaegi_interp_test_Sealed) Kind() int { return W.WKind() }
+
+func TestInterfaceWrapper(t *testing.T) {
+	const path = "github.com/containous/yaegi/interp_test"
+	i := interp.New(interp.Options{})
+	i.Use(interp.Exports{
+		path: {
+			"Describe": reflect.ValueOf(Describe),
+			"Message":  reflect.ValueOf((*Message)(nil)),
+			"Sealed":   reflect.ValueOf((*Sealed)(nil)),
+			"Shape":    reflect.ValueOf((*Shape)(nil)),
+
+			"_Message": reflect.ValueOf((*_github_com_containous_yaegi_interp_test_Message)(nil)),
+			"_Sealed":  reflect.ValueOf((*_github_com_containous_yaegi_interp_test_Sealed)(nil)),
+			"_Shape":   reflect.ValueOf((*_github_com_containous_yaegi_interp_test_Shape)(nil)),
+		},
+	})
+
+	src := new(strings.Builder)
+	fmt.Fprintf(src, "import shape %q\n\ntype T int\n\n", path)
+	for j := 0; j < 25; j++ {
+		fmt.Fprintf(src, "func (t T) M%02d() int { return int(t) * %d }\n", j, j)
+	}
+	fmt.Fprintln(src, "func NewShape(n int) shape.Shape { return T(n) }")
+	eval(t, i, src.String())
+	s := eval(t, i, "NewShape(2)").Interface().(Shape)
+	v := reflect.ValueOf(&s).Elem()
+	for j := 0; j < v.NumMethod(); j++ {
+		if got := v.Method(j).Call(nil)[0].Int(); got != int64(2*j) {
+			t.Errorf("M%02d: got %d, want %d", j, got, 2*j)
+		}
+	}
+
+	eval(t, i, `
+type M struct{ name string }
+
+func (m M) Name() string { return m.name }
+
+func Describe(n string) string {
+	var m shape.Message = M{n}
+	shape.Describe(m)
+	return shape.Describe(m)
+}`)
+	if got, want := eval(t, i, `Describe("msg")`).String(), "msg 2"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	_, err := i.Eval(`
+type K int
+
+func (k K) Kind() int { return int(k) }
+
+var s shape.Sealed = K(1)`)
+	if err == nil || !strings.Contains(err.Error(), "no wrapper implementing") {
+		t.Errorf("got %v, want no wrapper error", err)
+	}
+}
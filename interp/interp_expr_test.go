@@ -0,0 +1,332 @@
+package interp_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/containous/yaegi/interp"
+)
+
+// TestEvalExpression compares the interpreted evaluation of randomly generated
+// expressions to the evaluation of the same expressions by the compiler, to
+// check operators precedence, associativity and constant folding.
+func TestEvalExpression(t *testing.T) {
+	a, b, c := 12, -5, 3
+	x, y := 1.5, -2.25
+	i := interp.New(interp.Options{})
+	eval(t, i, "var a, b, c = 12, -5, 3")
+	eval(t, i, "var x, y = 1.5, -2.25")
+
+	var tests []testCase
+	for _, e := range []struct {
+		src string
+		res interface{}
+	}{
+		{"^b-a^1-7", ^b - a ^ 1 - 7},
+		{"-(c)%2&9", -(c) % 2 & 9},
+		{"(^b&^b) % 6", (^b &^ b) % 6},
+		{"3<<1 % 9+c^9 * 9 + b", 3<<1%9 + c ^ 9*9 + b},
+		{"2 * 1 - 3 * c*c%4 | (^a)", 2*1 - 3*c*c%4 | (^a)},
+		{"+b ^ 4|(5 % 3)", +b ^ 4 | (5 % 3)},
+		{"(2) &^ (+b)-(4 | c) & (c) * c", (2)&^(+b) - (4|c)&(c)*c},
+		{"8 & a", 8 & a},
+		{"(b)&c * 5 % (1)-(7) - c", (b)&c*5%(1) - (7) - c},
+		{"^5 - 6&a&(b) * (4)", ^5 - 6&a&(b)*(4)},
+		{"^c", ^c},
+		{"-4 << 0 % (2)", -4 << 0 % (2)},
+		{"a-a * (-c)&(c)*c+(a)", a - a*(-c)&(c)*c + (a)},
+		{"a ^ (-6 | a)", a ^ (-6 | a)},
+		{"a/7>>(2) ^ (a*c>>0)", a/7>>(2) ^ (a * c >> 0)},
+		{"+c-(1|5)", +c - (1 | 5)},
+		{"((7) / 7-b / 2) >> 0", ((7)/7 - b/2) >> 0},
+		{"-(+(^8))", -(+(^8))},
+		{"4 ^ 5 * c + 9-b", 4 ^ 5*c + 9 - b},
+		{"b^(-c>>1)", b ^ (-c >> 1)},
+		{"-c&c>>0", -c & c >> 0},
+		{"a>>3", a >> 3},
+		{"c << 3", c << 3},
+		{"(b)^a-a+(6)/5", (b) ^ a - a + (6)/5},
+		{"(+5 | 4)&^((a) << 2 * (a)^9)", (+5 | 4) &^ ((a)<<2*(a) ^ 9)},
+		{"a + (c) ^ b - 5", a + (c) ^ b - 5},
+		{"b-5 - 7 * a&b+3&^5 | 1", b - 5 - 7*a&b + 3&^5 | 1},
+		{"((c-(3))|(c) * 6) + (a)%5 + (-2)", ((c - (3)) | (c)*6) + (a)%5 + (-2)},
+		{"c*(c) << (2) - 9 << 2|a &^ b", c*(c)<<(2) - 9<<2 | a&^b},
+		{"c + 6 + (b) % 4", c + 6 + (b)%4},
+		{"+4>>1/8", +4 >> 1 / 8},
+		{"a>>3/(5)*(c) ^ ((8)<<0)", a>>3/(5)*(c) ^ ((8) << 0)},
+		{"c &^ (b) % 3<<0", c &^ (b) % 3 << 0},
+		{"(+2>>0)|(a) - (3)+b << 2", (+2 >> 0) | (a) - (3) + b<<2},
+		{"3|(-2)", 3 | (-2)},
+		{"c-c + (b - c)&^9/5", c - c + (b-c)&^9/5},
+		{"c-(+2)+(^3)", c - (+2) + (^3)},
+		{"+b % (6) * (9 + c)+3 ^ (9)", +b%(6)*(9+c) + 3 ^ (9)},
+		{"+(+b) * (+2 + c)", +(+b) * (+2 + c)},
+		{"7&1 / 9 + c-2", 7&1/9 + c - 2},
+		{"^(-(^3))", ^(-(^3))},
+		{"c &^ c - b * b - (+a * b+a)", c&^c - b*b - (+a*b + a)},
+		{"9*(+b) &^ a / 9", 9 * (+b) &^ a / 9},
+		{"((4) % 9 | b&^(1)) + (^b - c)", ((4)%9 | b&^(1)) + (^b - c)},
+		{"(9*a >> 1)^((a)>>1 | a - (c))", (9 * a >> 1) ^ ((a)>>1 | a - (c))},
+		{"^9", ^9},
+		{"c&^4>>2 & (7 * a)+c", c&^4>>2&(7*a) + c},
+		{"(a * 4*(8 / 9)) | a - 3-(c)>>3", (a * 4 * (8 / 9)) | a - 3 - (c)>>3},
+		{"c/9^(c) | 9<<(2)", c/9 ^ (c) | 9<<(2)},
+		{"((1-a)<<3)/7", ((1 - a) << 3) / 7},
+		{"(b+6) & b-(a) % (6)", (b+6)&b - (a)%(6)},
+		{"3 * 2-a&a^(5)", 3*2 - a&a ^ (5)},
+		{"^4+6/1", ^4 + 6/1},
+		{"4 * c + ((6) & a)+8 + b&^7/8", 4*c + ((6) & a) + 8 + b&^7/8},
+		{"c^b+c<<2 >> 0", c ^ b + c<<2>>0},
+		{"a >> 0 & (6) + (a) / 5", a>>0&(6) + (a)/5},
+		{"b&^c - c|7+(^6>>3)", b&^c - c | 7 + (^6 >> 3)},
+		{"+2 | c - b", +2 | c - b},
+		{"-(+a-9)", -(+a - 9)},
+		{"^4 &^ (9) ^ c", ^4&^(9) ^ c},
+		{"(1)>>0", (1) >> 0},
+		{"^4 + 7 + (^2*b)", ^4 + 7 + (^2 * b)},
+		{"((3) &^ (-a)) | b", ((3) &^ (-a)) | b},
+		{"6%8", 6 % 8},
+		{"(c)&2 &^ (4) + 7+(5%9 / 9)", (c)&2&^(4) + 7 + (5 % 9 / 9)},
+		{"-3", -3},
+		{"4*(^6 * c)", 4 * (^6 * c)},
+		{"c &^ 6*c - (3)^(+7 + c/8)", c&^6*c - (3) ^ (+7 + c/8)},
+		{"c^(+9-b)", c ^ (+9 - b)},
+		{"(6 + c)<<(2) + (6)", (6+c)<<(2) + (6)},
+		{"3 - 9 | (c &^ (a)) * b+(2) ^ 7", 3 - 9 | (c&^(a))*b + (2) ^ 7},
+		{"^c%(1) * (^a)", ^c % (1) * (^a)},
+		{"((c)*b) * (-a) & 6", ((c) * b) * (-a) & 6},
+		{"a|3%6%6", a | 3%6%6},
+		{"((c)/4*a)^(^7) | (b) * a", ((c) / 4 * a) ^ (^7) | (b)*a},
+		{"((7*c) + 9) - c * (a) * (a) ^ a", ((7 * c) + 9) - c*(a)*(a) ^ a},
+		{"+a%2+a", +a%2 + a},
+		{"(b >> 3)*1^c-b", (b>>3)*1 ^ c - b},
+		{"(2 &^ (2)*8)+a ^ b << 3", (2 &^ (2) * 8) + a ^ b<<3},
+		{"a / 1-(+b)", a/1 - (+b)},
+		{"+(^b)/9", +(^b) / 9},
+		{"c >> 3*3/8%(9)", c >> 3 * 3 / 8 % (9)},
+		{"4*a & ((a) &^ 8)+(b - 4/2)", 4*a&((a)&^8) + (b - 4/2)},
+		{"1-b|a * 2%9", 1 - b | a*2%9},
+		{"5 << 1", 5 << 1},
+		{"(c) + 7", (c) + 7},
+		{"7-a + 7|(+a)", 7 - a + 7 | (+a)},
+		{"(3) ^ b-(a|(1)) &^ c + (^b)", (3) ^ b - (a|(1))&^c + (^b)},
+		{"^5+(a >> 1) / 2", ^5 + (a>>1)/2},
+		{"(b)&^4 + (c) << (0)%(6)", (b)&^4 + (c)<<(0)%(6)},
+		{"(^4 + 3-c) * (-2 + 5%6)", (^4 + 3 - c) * (-2 + 5%6)},
+		{"(4&^(-c)) / 1", (4 &^ (-c)) / 1},
+		{"-(^5%4)", -(^5 % 4)},
+		{"-2|(7) + 7-6", -2 | (7) + 7 - 6},
+		{"(2 - (c)&a+a)%(6)", (2 - (c)&a + a) % (6)},
+		{"+1&^(9)+8", +1&^(9) + 8},
+		{"a << 0 - (4) + c + (2)", a<<0 - (4) + c + (2)},
+		{"(7 ^ b % 8)/7", (7 ^ b%8) / 7},
+		{"c<<3", c << 3},
+		{"c &^ 2 / (5) &^ (-c*a)", c &^ 2 / (5) &^ (-c * a)},
+		{"((c-c)>>(1)) & (a)-(c)-(+a)", ((c-c)>>(1))&(a) - (c) - (+a)},
+		{"9 * b+(c) - (1)+c%(7) * c", 9*b + (c) - (1) + c%(7)*c},
+		{"-b&a ^ a + c - (b)+c", -b&a ^ a + c - (b) + c},
+		{"9&a >> 3 | (^c) | (b) * 4", 9&a>>3 | (^c) | (b)*4},
+		{"a/6<<1 | 2-c + 4-a", a/6<<1 | 2 - c + 4 - a},
+		{"a * b & ((5) ^ 4) - (((a)-a)|6 / 6)", a*b&((5)^4) - (((a) - a) | 6/6)},
+		{"4-c", 4 - c},
+		{"7<<2|b | a & (4+b | 5+(b))", 7<<2 | b | a&(4+b|5+(b))},
+		{"(-b)^(c % 8)*(a)", (-b) ^ (c%8)*(a)},
+		{"^a&a+c * c", ^a&a + c*c},
+		{"4<<0&4 + 5 >> 0-(7-5)", 4<<0&4 + 5>>0 - (7 - 5)},
+		{"3&7/(9)", 3 & 7 / (9)},
+		{"(7 * 2/8)-(^3>>2)", (7 * 2 / 8) - (^3 >> 2)},
+		{"+7+4*7+6", +7 + 4*7 + 6},
+		{"1&(2)*6", 1 & (2) * 6},
+		{"8 & 7 | ((6) * 7)|3|(-6)", 8&7 | ((6) * 7) | 3 | (-6)},
+		{"+6 - (-5)", +6 - (-5)},
+		{"-4", -4},
+		{"(3) &^ (8) / 4*(-6-(6))", (3) &^ (8) / 4 * (-6 - (6))},
+		{"6|9 - 1 * 4&(9)^(+4)", 6 | 9 - 1*4&(9) ^ (+4)},
+		{"^8 | (4)+(((8)+7) * 1/9)", ^8 | (4) + (((8) + 7) * 1 / 9)},
+		{"(6|9-4) - (^9-4)", (6 | 9 - 4) - (^9 - 4)},
+		{"9>>(0)", 9 >> (0)},
+		{"4 + (8)&4 + 8", 4 + (8)&4 + 8},
+		{"-1 & (9 + 5) << 0", -1 & (9 + 5) << 0},
+		{"3 + 8 | (4) & (9) / 3", 3 + 8 | (4)&(9)/3},
+		{"^1 * 8<<1", ^1 * 8 << 1},
+		{"5 ^ (4)", 5 ^ (4)},
+		{"+4|(6) + (+2 / 7)", +4 | (6) + (+2 / 7)},
+		{"5-6 ^ (6 / 9)^(5) >> 0", 5 - 6 ^ (6 / 9) ^ (5)>>0},
+		{"((7) / 1)^(^7) + (9) * 4 - (7>>(1))", ((7) / 1) ^ (^7) + (9)*4 - (7 >> (1))},
+		{"^5+(1) | 8", ^5 + (1) | 8},
+		{"^1>>(0)", ^1 >> (0)},
+		{"-(^8 & 9 &^ 8)", -(^8 & 9 &^ 8)},
+		{"(((8) >> 1) * (2) * 2)*(2)&3 >> 3", (((8) >> 1) * (2) * 2) * (2) & 3 >> 3},
+		{"4-8&(^6)&((6)/4) * (-7)", 4 - 8&(^6)&((6)/4)*(-7)},
+		{"+(-7+(+7))", +(-7 + (+7))},
+		{"(5 & 1) + 5%9*(5 % 1 - (1 ^ 9))", (5 & 1) + 5%9*(5%1-(1^9))},
+		{"(1 & 8)^(-7&^6)", (1 & 8) ^ (-7 &^ 6)},
+		{"(4)>>(2)", (4) >> (2)},
+		{"^(8 ^ 9)-3", ^(8 ^ 9) - 3},
+		{"(7&^7)-((4)%7) ^ 4>>1", (7 &^ 7) - ((4) % 7) ^ 4>>1},
+		{"2|1>>(1)+(^3) >> (3)", 2 | 1>>(1) + (^3)>>(3)},
+		{"(4)+(+9 ^ 5)", (4) + (+9 ^ 5)},
+		{"(5 % 8)+(3*(8)>>(2))", (5 % 8) + (3 * (8) >> (2))},
+		{"-(+5*(8) - 2)", -(+5*(8) - 2)},
+		{"+(9)/4*(-6) ^ 1", +(9)/4*(-6) ^ 1},
+		{"5&(9)>>3", 5 & (9) >> 3},
+		{"+5 | (6)>>1", +5 | (6)>>1},
+		{"8 - 2*(-7)+9", 8 - 2*(-7) + 9},
+		{"^1 & 2 & (1&^7)", ^1 & 2 & (1 &^ 7)},
+		{"^(-(5)|(3))", ^(-(5) | (3))},
+		{"5|8*8 * 7 | 5-((4)*2)", 5 | 8*8*7 | 5 - ((4) * 2)},
+		{"8 * (2^1)&8*(3)", 8 * (2 ^ 1) & 8 * (3)},
+		{"3<<1 ^ (+1)|3", 3<<1 ^ (+1) | 3},
+		{"7&5 << 3 - (5 * (4) * 2)", 7&5<<3 - (5 * (4) * 2)},
+		{"^2 + (+5-6)", ^2 + (+5 - 6)},
+		{"5 << 0 / 8", 5 << 0 / 8},
+		{"3 / (4) * (+4) & (+7 ^ (4))", 3 / (4) * (+4) & (+7 ^ (4))},
+		{"5 / 8", 5 / 8},
+		{"(7 * 3)+3&^(2) * 8 | (^7)", (7 * 3) + 3&^(2)*8 | (^7)},
+		{"+3 * (6)+(1) * 7", +3*(6) + (1)*7},
+		{"4 - 4*(8) ^ 9 << 0", 4 - 4*(8) ^ 9<<0},
+		{"9|9 * 2+7 * 8", 9 | 9*2 + 7*8},
+		{"8 + 3", 8 + 3},
+		{"(5 * 9) + (^4)-(5)", (5 * 9) + (^4) - (5)},
+		{"(9 & (3)) - (5)*(3)-(2>>1) / 7", (9 & (3)) - (5)*(3) - (2>>1)/7},
+		{"8/4 << 1&^(1) + 5&(2) >> 1", 8/4<<1&^(1) + 5&(2)>>1},
+		{"(x + 2.0 * -4.5 - (y)) * (y) * -4.5 / (4.0)", (x + 2.0*-4.5 - (y)) * (y) * -4.5 / (4.0)},
+		{"x - (y) + (1.5 - 2.0) * (-4.5)", x - (y) + (1.5-2.0)*(-4.5)},
+		{"(-4.5 / 2 - (-4.5) * (0.25)) - (3 + (y * x))", (-4.5/2 - (-4.5)*(0.25)) - (3 + (y * x))},
+		{"+(y * x) - y", +(y * x) - y},
+		{"(x) * -y + y", (x)*-y + y},
+		{"+y", +y},
+		{"(((y) * 1.5) - x - 3) + ((y) - x + (2.0) + (x))", (((y) * 1.5) - x - 3) + ((y) - x + (2.0) + (x))},
+		{"(x * 3 / (0.5)) + y", (x * 3 / (0.5)) + y},
+		{"y + 2 + y * 0.25 * 2 - y", y + 2 + y*0.25*2 - y},
+		{"(x + (x) * y) / 0.5", (x + (x)*y) / 0.5},
+		{"-(x) * (x) * (2.0)", -(x) * (x) * (2.0)},
+		{"((x) / (0.5)) - 0.25", ((x) / (0.5)) - 0.25},
+		{"(0.25 + (x)) - y - y - y * -y", (0.25 + (x)) - y - y - y*-y},
+		{"(y - x) + (3) * (x) + x", (y - x) + (3)*(x) + x},
+		{"(2.0) / 2", (2.0) / 2},
+		{"((2.0) * y) / 0.5 * (y)", ((2.0) * y) / 0.5 * (y)},
+		{"(x + x - 0.25) / 4.0", (x + x - 0.25) / 4.0},
+		{"(2.0) * (y - 1.5) / (2)", (2.0) * (y - 1.5) / (2)},
+		{"((3 - 3) / (2)) * ((y) - -x)", ((3 - 3) / (2)) * ((y) - -x)},
+		{"x / (4.0) / 0.5 / 0.5", x / (4.0) / 0.5 / 0.5},
+		{"(y) * 3", (y) * 3},
+		{"y / (2) + +3 + (y) * (-y)", y/(2) + +3 + (y)*(-y)},
+		{"-2.0 + y + ((3) * y) + y / 4.0", -2.0 + y + ((3) * y) + y/4.0},
+		{"-4.5", -4.5},
+		{"-0.25 / 2 / (0.5)", -0.25 / 2 / (0.5)},
+		{"0.25 - -x + y - (x)", 0.25 - -x + y - (x)},
+		{"(x - (0.25)) / 2 - x", (x-(0.25))/2 - x},
+		{"(y) / 0.5 + 3 + x - y * +y", (y)/0.5 + 3 + x - y*+y},
+		{"0.25 - 2.0", 0.25 - 2.0},
+		{"(0.25) / (0.5)", (0.25) / (0.5)},
+		{"-(y / 4.0) + (0.25)", -(y / 4.0) + (0.25)},
+		{"x - x - y / (2)", x - x - y/(2)},
+		{"x / 4.0", x / 4.0},
+		{"+(-x + (1.5) + 3)", +(-x + (1.5) + 3)},
+		{"+(-4.5 * -4.5) + y", +(-4.5 * -4.5) + y},
+		{"(-4.5 * 3) - 0.25 / 2 / 2", (-4.5 * 3) - 0.25/2/2},
+		{"-4.5 / (2) + 2.0 * y - (x + (-4.5))", -4.5/(2) + 2.0*y - (x + (-4.5))},
+		{"(+2 * x) + (+x / 0.5)", (+2 * x) + (+x / 0.5)},
+		{"y - 1.5 + -4.5 / 4.0", y - 1.5 + -4.5/4.0},
+		{"y / 0.5", y / 0.5},
+		{"(0.25 / 2 + (2 - (x))) + y / (2)", (0.25/2 + (2 - (x))) + y/(2)},
+		{"(((x) / 4.0) + (+1.5)) * (x) / 4.0 / 2", (((x) / 4.0) + (+1.5)) * (x) / 4.0 / 2},
+		{"-y * x / (4.0) + (x / (2)) * 3 * (y)", -y*x/(4.0) + (x/(2))*3*(y)},
+		{"-(-4.5 - y) - y", -(-4.5 - y) - y},
+		{"-(y / 2) * (y * (x))", -(y / 2) * (y * (x))},
+		{"((0.25 / (0.5)) - 3 + 0.25) - x", ((0.25 / (0.5)) - 3 + 0.25) - x},
+		{"(-4.5) * (x) - y - ((-4.5) * (1.5) - (1.5) / 4.0)", (-4.5)*(x) - y - ((-4.5)*(1.5) - (1.5)/4.0)},
+		{"+y - x * x - y / (2)", +y - x*x - y/(2)},
+		{"(y) + 0.25", (y) + 0.25},
+		{"0.25 / 2 / 4.0 - -3 + 0.25 - y", 0.25/2/4.0 - -3 + 0.25 - y},
+		{"(3) / 4.0", (3) / 4.0},
+		{"((3) + x) / 2 * (y)", ((3) + x) / 2 * (y)},
+		{"((y) - (y) + x) + -x + 1.5 * (0.25)", ((y) - (y) + x) + -x + 1.5*(0.25)},
+		{"(x * ((y) - x)) * -x / 0.5", (x * ((y) - x)) * -x / 0.5},
+		{"(x - (x + 2.0)) - (+y / 4.0)", (x - (x + 2.0)) - (+y / 4.0)},
+		{"-3 * (x / 4.0)", -3 * (x / 4.0)},
+		{"2.0 / 2", 2.0 / 2},
+		{"+3", +3},
+		{"(-4.5 - (2) * 2.0) / (2)", (-4.5 - (2)*2.0) / (2)},
+		{"+2 / 4.0 - 1.5", +2/4.0 - 1.5},
+		{"0.25 + (0.25)", 0.25 + (0.25)},
+		{"-0.25", -0.25},
+		{"1.5 / 4.0 + -4.5 + 2.0 / (0.5) / 2", 1.5/4.0 + -4.5 + 2.0/(0.5)/2},
+		{"-4.5 - (-2.0)", -4.5 - (-2.0)},
+		{"(-0.25 - 1.5) * (0.25)", (-0.25 - 1.5) * (0.25)},
+		{"(-4.5 + -4.5) / 0.5", (-4.5 + -4.5) / 0.5},
+		{"((2) * (-(-4.5))) / 4.0", ((2) * (-(-4.5))) / 4.0},
+		{"-0.25 * 0.25 + 3 + -4.5", -0.25*0.25 + 3 + -4.5},
+		{"2.0 - 0.25 - ((2.0) + 0.25) / 4.0", 2.0 - 0.25 - ((2.0)+0.25)/4.0},
+		{"-(-4.5 - 0.25) + (2)", -(-4.5 - 0.25) + (2)},
+		{"2 - +1.5 - 1.5", 2 - +1.5 - 1.5},
+		{"0.25 + -4.5", 0.25 + -4.5},
+		{"0.25 * +(1.5) * 3", 0.25 * +(1.5) * 3},
+		{"(0.25 * (0.25) + -4.5 * 2) + 3 * (3) * ((2.0) * 1.5)", (0.25*(0.25) + -4.5*2) + 3*(3)*((2.0)*1.5)},
+		{"(1.5 * (3)) * 2 / 0.5", (1.5 * (3)) * 2 / 0.5},
+		{"2.0 - ((2) / 2)", 2.0 - ((2) / 2)},
+		{"2 / (0.5)", 2 / (0.5)},
+		{"(((-4.5) + -4.5) + (-4.5)) * 2.0 + 2.0 + (-2)", (((-4.5)+-4.5)+(-4.5))*2.0 + 2.0 + (-2)},
+		{"((3 - 0.25) * 3 / (4.0)) * 0.25", ((3 - 0.25) * 3 / (4.0)) * 0.25},
+		{"-4.5 * 2 / 2 + ((2.0) + (3)) * (2.0 / 2)", -4.5*2/2 + ((2.0)+(3))*(2.0/2)},
+		{"3 + ((2) * (3) + 2.0)", 3 + ((2)*(3) + 2.0)},
+		{"((0.25) + (1.5) / 4.0) * ((-4.5) + 2) / 4.0", ((0.25) + (1.5)/4.0) * ((-4.5) + 2) / 4.0},
+		{"(3) * (-4.5) * -4.5 - 3 - 2.0 * -4.5 / 2", (3)*(-4.5)*-4.5 - 3 - 2.0*-4.5/2},
+		{"(1.5) - 3 * (3) - (-4.5) * 2", (1.5) - 3*(3) - (-4.5)*2},
+		{"2 * 2", 2 * 2},
+		{"!((9)%4 + 1 == (b) &^ (a) * (9*a) && (c) << (2) & c << (0) < a)", !((9)%4+1 == (b)&^(a)*(9*a) && (c)<<(2)&c<<(0) < a)},
+		{"c > (6+(a)) / 4", c > (6+(a))/4},
+		{"b >= (a) / 7", b >= (a)/7},
+		{"(b&^b)%5 > a &^ b ^ c-c", (b&^b)%5 > a&^b^c-c},
+		{"8 >= (2 - b)*2-(b)", 8 >= (2-b)*2-(b)},
+		{"(a)*1 / 7 > +8", (a)*1/7 > +8},
+		{"((b) << 2)<<(2) > b", ((b)<<2)<<(2) > b},
+		{"((6)+c) & (b - (1)) != 4/6/2 || c <= 6*a & (5)>>2 || (a<<1)&^(^7) >= ((2)<<(3)) * 1-a", ((6)+c)&(b-(1)) != 4/6/2 || c <= 6*a&(5)>>2 || (a<<1)&^(^7) >= ((2)<<(3))*1-a},
+		{"(+2)*(+b) < a || ^7^8 >> (3) == a ^ a&^(^a) || b%(9)*9 ^ a <= 4-c << 3", (+2)*(+b) < a || ^7^8>>(3) == a^a&^(^a) || b%(9)*9^a <= 4-c<<3},
+		{"a * 4-a <= (^c)>>3 && -1+a > +2 % 5 && (2*(4))-b-a > b", a*4-a <= (^c)>>3 && -1+a > +2%5 && (2*(4))-b-a > b},
+		{"1 == +3 + 9 && +(-6) < c || -1/8 != (b) << 2 ^ 3 >> 2", 1 == +3+9 && +(-6) < c || -1/8 != (b)<<2^3>>2},
+		{"c <= 8 || ((c) &^ c)%7 != b || b < ^1 &^ 1 && 3 >= c + a<<(0)", c <= 8 || ((c)&^c)%7 != b || b < ^1&^1 && 3 >= c+a<<(0)},
+		{"^c > +4+a || +1^(-1) == +b / 1 && (3*9)|4&2 >= 9 || a != (c) >> (2)+(a &^ c)", ^c > +4+a || +1^(-1) == +b/1 && (3*9)|4&2 >= 9 || a != (c)>>(2)+(a&^c)},
+		{"4 * 3 &^ 6|b >= 7&b/(6) && c&6%(6) != (c / 1) ^ c - a", 4*3&^6|b >= 7&b/(6) && c&6%(6) != (c/1)^c-a},
+		{"c == -b", c == -b},
+		{"^b<<(2) != -a && ^c*a >= 5 && a >= (-a)*((b)%5) && (c+4) &^ (b) >> (1) == 8 - c", ^b<<(2) != -a && ^c*a >= 5 && a >= (-a)*((b)%5) && (c+4)&^(b)>>(1) == 8-c},
+		{"6 != b+b/6", 6 != b+b/6},
+		{"a-a%5 >= (4 - 7)>>3 || (7 - c)<<(1) <= ^b+8 && 9 / 9%4 < ^7 * a || 4 > (8 << (2))<<0", a-a%5 >= (4-7)>>3 || (7-c)<<(1) <= ^b+8 && 9/9%4 < ^7*a || 4 > (8<<(2))<<0},
+		{"!((3) ^ a * c >= (a)%2-(2)&b && 5>>0 + (b)+c <= b - (c)^a)", !((3)^a*c >= (a)%2-(2)&b && 5>>0+(b)+c <= b-(c)^a)},
+		{"a-c-(a) >= b&^a && (b%(5)) ^ 6 > ^4*b || (7<<1) | c<<(0) <= (5) & 1 / 2 && ((a) % 6) &^ 6&(b) < 9", a-c-(a) >= b&^a && (b%(5))^6 > ^4*b || (7<<1)|c<<(0) <= (5)&1/2 && ((a)%6)&^6&(b) < 9},
+		{"7 <= a && +c+c < c || !(1 &^ a + (a*(a)) == a)", 7 <= a && +c+c < c || !(1&^a+(a*(a)) == a)},
+		{"!((b^c) &^ c / 6 != b*(3)+(^b))", !((b^c)&^c/6 != b*(3)+(^b))},
+		{"^(c)%8 < ((b) * b)&^(9+a) && ((7) & 2) >> (3) <= 8*b | (^c) && 7 + ((b)/9) < (b) | (4 * 3)", ^(c)%8 < ((b)*b)&^(9+a) && ((7)&2)>>(3) <= 8*b|(^c) && 7+((b)/9) < (b)|(4*3)},
+		{"-6 * b < 8", -6*b < 8},
+		{"(8) &^ 2 / (9) > 3", (8)&^2/(9) > 3},
+		{"(a|(c)) &^ a &^ (4) >= 9 && a <= 4 && -(a) >> 3 <= c/8", (a|(c))&^a&^(4) >= 9 && a <= 4 && -(a)>>3 <= c/8},
+		{"c | 2 + (+c) < (c)%7 * 8 % 4", c|2+(+c) < (c)%7*8%4},
+		{"(c) * c - c >= ^1 - b", (c)*c-c >= ^1-b},
+		{"(8|c)+(c+(4)) != (1) * (a)-(-b) || 5 * 9+3<<2 <= (1 * b)-a << (0) && 3 < 1 - c+c&^(9)", (8|c)+(c+(4)) != (1)*(a)-(-b) || 5*9+3<<2 <= (1*b)-a<<(0) && 3 < 1-c+c&^(9)},
+		{"4-a + c != 6 * 4 * (a>>2)", 4-a+c != 6*4*(a>>2)},
+		{"7%7 != a - a*7|c || a >> 3 < ((3)&9) / 4 || 2 * (c) < c - (b) &^ 5 || 8 & 9 ^ (+c) >= b", 7%7 != a-a*7|c || a>>3 < ((3)&9)/4 || 2*(c) < c-(b)&^5 || 8&9^(+c) >= b},
+		{"!(!(c == a-(c)|((8)*(b))))", !(!(c == a-(c)|((8)*(b))))},
+		{"2 % 8 - 3 + 5 < +a", 2%8-3+5 < +a},
+		{"1-3*(^8) == -1>>0 || (c|b) + c &^ (4) > c - a - (a) && (7>>1) ^ (a) <= b^b << 3", 1-3*(^8) == -1>>0 || (c|b)+c&^(4) > c-a-(a) && (7>>1)^(a) <= b^b<<3},
+		{"6 <= +b && 4 >= ^8+(2)+a || (b|b) ^ 9 - c != a&^c >> 1", 6 <= +b && 4 >= ^8+(2)+a || (b|b)^9-c != a&^c>>1},
+		{"((c)<<1) | (a/(2)) != (a*(a)) - (a / (8))", ((c)<<1)|(a/(2)) != (a*(a))-(a/(8))},
+		{"^b&(b / 4) > ^(6)+(a)", ^b&(b/4) > ^(6)+(a)},
+		{"a*9 + c - 6 > ((b) >> 1)>>0 || (c) ^ 3-3/1 > ^8 | c | (c) || +c ^ 6 << (0) > 5/7 + (4)/1", a*9+c-6 > ((b)>>1)>>0 || (c)^3-3/1 > ^8|c|(c) || +c^6<<(0) > 5/7+(4)/1},
+		{"(a)*(3) % 8 != b/(6) + (a)*a || b | c - 8 > c || 3 &^ c+b != 6 + (-b) || 6 << (1) / 5 != c&4 % 9", (a)*(3)%8 != b/(6)+(a)*a || b|c-8 > c || 3&^c+b != 6+(-b) || 6<<(1)/5 != c&4%9},
+		{"!(6 > +4 / 4) || c != ^3+6", !(6 > +4/4) || c != ^3+6},
+		{"!((c) * c+(5 >> 1) > 5 &^ c + 1 + b && (c) - a & b > (2)-8&((a)%(8)))", !((c)*c+(5>>1) > 5&^c+1+b && (c)-a&b > (2)-8&((a)%(8)))},
+		{"a<<2 >> 1 == (6)-(a) % 8", a<<2>>1 == (6)-(a)%8},
+		{"-(^1) > 3 | a+(7 * b)", -(^1) > 3|a+(7*b)},
+		{"a &^ 2 | (b >> 3) != +a*(2)-a", a&^2|(b>>3) != +a*(2)-a},
+		{"a == a &^ ((c) + c) && ^8<<1 != (1) - 5&2 || (a*b) ^ (b) >= b && 3&9 / (6) >= (4 + a)+(+a)", a == a&^((c)+c) && ^8<<1 != (1)-5&2 || (a*b)^(b) >= b && 3&9/(6) >= (4+a)+(+a)},
+		{"!(a >> 1 < a % 9 % 5)", !(a>>1 < a%9%5)},
+		{"(b)*2 * b | c != (-b)-a&^8 && (b) >> (2) * (6 % 2) <= ^7&2^9", (b)*2*b|c != (-b)-a&^8 && (b)>>(2)*(6%2) <= ^7&2^9},
+		{"3 / (5) != 4", 3/(5) != 4},
+		{"(2|b)&^c % (6) != a ^ b+(8 &^ c)", (2|b)&^c%(6) != a^b+(8&^c)},
+		{"!(-(+b) > -c / 3 && +8 * a > a &^ (b)%(2))", !(-(+b) > -c/3 && +8*a > a&^(b)%(2))},
+	} {
+		tests = append(tests, testCase{desc: e.src, src: e.src, res: fmt.Sprint(e.res)})
+	}
+	runTests(t, i, tests)
+}
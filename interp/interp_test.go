@@ -188,3 +188,33 @@ func TestIsNatural(t *testing.T) {
 		}
 	}
 }
+
+func TestIsTypeVerbArg(t *testing.T) {
+	tests := []struct {
+		format string
+		arg    int
+		want   bool
+	}{
+		{"%T", 0, true},
+		{"%T %#v", 0, true},
+		{"%T %#v", 1, false},
+		{"%v %T", 0, false},
+		{"%v %T", 1, true},
+		{"%%T %v", 0, false},
+		{"%*d %T", 2, true},
+		{"%-*.*v %T", 3, true},
+		{"%.*T", 1, true},
+		{"%v %[1]T", 0, true},
+		{"%[2]T %v", 0, false},
+		{"%[2]T %v", 1, true},
+		{"%[2]T %v", 2, false},
+		{"%5.2T", 0, true},
+		{"%é %T", 1, true},
+		{"%", 0, false},
+	}
+	for _, test := range tests {
+		if got := isTypeVerbArg(test.format, test.arg); got != test.want {
+			t.Errorf("%q, %d: got %v, want %v", test.format, test.arg, got, test.want)
+		}
+	}
+}
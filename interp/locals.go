@@ -0,0 +1,127 @@
+package interp
+
+import (
+	"go/token"
+	"reflect"
+)
+
+// FrameInfo describes the frame of an interpreted function unwound by a
+// panic, as recorded if the PanicLocals option is set.
+type FrameInfo struct {
+	Func string         // function name, as "main.f" or "main.(*T).m"
+	Pos  token.Position // position of the function declaration
+
+	locals map[string]reflect.Value
+}
+
+// Locals returns the local variables of the function, including its receiver,
+// parameters and named results, indexed by name. The values are copied when
+// the panic unwinds the frame, before its deferred calls are run, and are
+// safe to use after Eval returns. The
+// variables of blocks not yet executed have their zero value. Of several
+// variables with the same name, only one is returned.
+func (fi FrameInfo) Locals() map[string]reflect.Value { return fi.locals }
+
+// local is a named variable in the frame of a function.
+type local struct {
+	name  string
+	index int
+	typ   *itype
+}
+
+// addLocals records the variables of the scope symbols in the frame of the
+// function def.
+func (interp *Interpreter) addLocals(def *node, syms map[string]*symbol) {
+	var locals []local
+	if v, ok := interp.locals.Load(def); ok {
+		locals = v.([]local)
+	}
+	for name, sym := range syms {
+		if sym.kind != varSym || sym.index < 0 || name == "_" {
+			continue
+		}
+		locals = append(locals, local{name: name, index: sym.index, typ: sym.typ})
+	}
+	interp.locals.Store(def, locals)
+}
+
+// frameInfo returns the description of frame f of the function def, with
+// a copy of its local variables.
+func (interp *Interpreter) frameInfo(def *node, f *frame) FrameInfo {
+	fi := FrameInfo{
+		Func:   funcName(def),
+		Pos:    interp.fset.Position(def.pos),
+		locals: map[string]reflect.Value{},
+	}
+	v, ok := interp.locals.Load(def)
+	if !ok {
+		return fi
+	}
+	for _, l := range v.([]local) {
+		if l.index < len(f.data) {
+			fi.locals[l.name] = copyLocal(f, f.data[l.index], l.typ, interp.panicLocals)
+		}
+	}
+	return fi
+}
+
+// copyLocal returns a copy of the value v of type t in frame f, with the
+// strings, arrays, slices and maps truncated to max bytes or elements, if
+// max is positive. The truncated arrays are returned as slices. Interpreted
+// functions and interface values are converted to their runtime values.
+func copyLocal(f *frame, v reflect.Value, t *itype, max int) reflect.Value {
+	switch t.cat {
+	case funcT:
+		if n, ok := v.Interface().(*node); ok && n != nil {
+			return genFunctionWrapper(n)(f)
+		}
+		return reflect.New(t.TypeOf()).Elem()
+	case interfaceT:
+		if vi, ok := v.Interface().(valueInterface); ok && vi.value.IsValid() {
+			v = vi.value
+		} else {
+			return reflect.New(t.TypeOf()).Elem()
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		if max > 0 && v.Len() > max {
+			return reflect.ValueOf(v.String()[:max]).Convert(v.Type())
+		}
+	case reflect.Array:
+		if max > 0 && v.Len() > max {
+			s := reflect.MakeSlice(reflect.SliceOf(v.Type().Elem()), max, max)
+			for i := 0; i < max; i++ {
+				s.Index(i).Set(v.Index(i))
+			}
+			return s
+		}
+	case reflect.Slice:
+		if v.IsNil() {
+			return reflect.New(v.Type()).Elem()
+		}
+		l := v.Len()
+		if max > 0 && l > max {
+			l = max
+		}
+		s := reflect.MakeSlice(v.Type(), l, l)
+		reflect.Copy(s, v)
+		return s
+	case reflect.Map:
+		if v.IsNil() {
+			return reflect.New(v.Type()).Elem()
+		}
+		m := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for i, it := 0, v.MapRange(); it.Next(); i++ {
+			if max > 0 && i == max {
+				break
+			}
+			m.SetMapIndex(it.Key(), it.Value())
+		}
+		return m
+	}
+	c := reflect.New(v.Type()).Elem()
+	c.Set(v)
+	return c
+}
@@ -0,0 +1,63 @@
+package interp
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// TypeByName returns the reflect type of the type called name, declared in
+// the interpreted package pkg. If pkg is empty, the type is looked up in the
+// current package, as by Eval. Otherwise, pkg is the import path of a source
+// package, or the name of a package evaluated with Eval.
+//
+// The returned type is the one used by the interpreter for values of the
+// named type: a value created by the host, for example with reflect.New, can
+// be passed to interpreted functions expecting the named type or a pointer
+// to it.
+func (interp *Interpreter) TypeByName(pkg, name string) (reflect.Type, error) {
+	syms, err := interp.pkgSymbols(pkg)
+	if err != nil {
+		return nil, err
+	}
+	sym := syms[name]
+	if sym == nil || sym.kind != typeSym || sym.typ == nil {
+		return nil, fmt.Errorf("type %s not found in package %q", name, pkg)
+	}
+	if !sym.typ.isComplete() {
+		return nil, fmt.Errorf("type %s is incomplete in package %q", name, pkg)
+	}
+	return sym.typ.TypeOf(), nil
+}
+
+// TypeNames returns the sorted names of the types declared in the interpreted
+// package pkg, as accepted by TypeByName.
+func (interp *Interpreter) TypeNames(pkg string) ([]string, error) {
+	syms, err := interp.pkgSymbols(pkg)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for name, sym := range syms {
+		if sym.kind == typeSym && sym.typ != nil && sym.typ.isComplete() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// pkgSymbols returns the package level symbols of the interpreted package pkg.
+func (interp *Interpreter) pkgSymbols(pkg string) (map[string]*symbol, error) {
+	interp.mutex.RLock()
+	defer interp.mutex.RUnlock()
+
+	if pkg == "" {
+		if sc, ok := interp.scopes[interp.Name]; ok {
+			return sc.sym, nil
+		}
+	} else if syms, ok := interp.srcPkg[pkg]; ok {
+		return syms, nil
+	}
+	return nil, fmt.Errorf("package %q not found", pkg)
+}
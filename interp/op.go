@@ -701,7 +701,13 @@ func quoConst(n *node) {
 	n.rval = reflect.New(t).Elem()
 	switch {
 	case isConst:
-		v := constant.BinaryOp(vConstantValue(v0), token.QUO, vConstantValue(v1))
+		c0, c1 := vConstantValue(v0), vConstantValue(v1)
+		tok := token.QUO
+		if isConstInt(n.child[0], c0) && isConstInt(n.child[1], c1) {
+			// Integer constants division truncates towards zero.
+			tok = token.QUO_ASSIGN
+		}
+		v := constant.BinaryOp(c0, tok, c1)
 		n.rval.Set(reflect.ValueOf(v))
 	case isComplex(t):
 		n.rval.SetComplex(vComplex(v0) / vComplex(v1))
@@ -1885,7 +1891,7 @@ func dec(n *node) {
 			v.SetInt(i - 1)
 			return next
 		}
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
 		v0 := genValueUint(n.child[0])
 		n.exec = func(f *frame) bltn {
 			v, i := v0(f)
@@ -1921,7 +1927,7 @@ func inc(n *node) {
 			v.SetInt(i + 1)
 			return next
 		}
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
 		v0 := genValueUint(n.child[0])
 		n.exec = func(f *frame) bltn {
 			v, i := v0(f)
@@ -1957,10 +1963,10 @@ func bitNotConst(n *node) {
 	case isConst:
 		v := constant.UnaryOp(token.XOR, vConstantValue(v0), 0)
 		n.rval.Set(reflect.ValueOf(v))
-	case isInt(t):
-		n.rval.SetInt(^v0.Int())
 	case isUint(t):
 		n.rval.SetUint(^v0.Uint())
+	case isInt(t):
+		n.rval.SetInt(^v0.Int())
 	}
 }
 
@@ -1976,10 +1982,10 @@ func negConst(n *node) {
 	case isConst:
 		v := constant.UnaryOp(token.SUB, vConstantValue(v0), 0)
 		n.rval.Set(reflect.ValueOf(v))
-	case isInt(t):
-		n.rval.SetInt(-v0.Int())
 	case isUint(t):
 		n.rval.SetUint(-v0.Uint())
+	case isInt(t):
+		n.rval.SetInt(-v0.Int())
 	case isFloat(t):
 		n.rval.SetFloat(-v0.Float())
 	case isComplex(t):
@@ -2015,10 +2021,10 @@ func posConst(n *node) {
 	case isConst:
 		v := constant.UnaryOp(token.ADD, vConstantValue(v0), 0)
 		n.rval.Set(reflect.ValueOf(v))
-	case isInt(t):
-		n.rval.SetInt(+v0.Int())
 	case isUint(t):
 		n.rval.SetUint(+v0.Uint())
+	case isInt(t):
+		n.rval.SetInt(+v0.Int())
 	case isFloat(t):
 		n.rval.SetFloat(+v0.Float())
 	case isComplex(t):
@@ -2459,7 +2465,7 @@ func equal(n *node) {
 		switch {
 		case c0.rval.IsValid():
 			i0 := c0.rval.Interface()
-			v1 := genValue(c1)
+			v1 := genValueUnwrap(c1)
 			if n.fnext != nil {
 				fnext := getExec(n.fnext)
 				n.exec = func(f *frame) bltn {
@@ -2481,7 +2487,7 @@ func equal(n *node) {
 			}
 		case c1.rval.IsValid():
 			i1 := c1.rval.Interface()
-			v0 := genValue(c0)
+			v0 := genValueUnwrap(c0)
 			if n.fnext != nil {
 				fnext := getExec(n.fnext)
 				n.exec = func(f *frame) bltn {
@@ -2502,8 +2508,8 @@ func equal(n *node) {
 				}
 			}
 		default:
-			v0 := genValue(c0)
-			v1 := genValue(c1)
+			v0 := genValueUnwrap(c0)
+			v1 := genValueUnwrap(c1)
 			if n.fnext != nil {
 				fnext := getExec(n.fnext)
 				n.exec = func(f *frame) bltn {
@@ -4118,7 +4124,7 @@ func notEqual(n *node) {
 		switch {
 		case c0.rval.IsValid():
 			i0 := c0.rval.Interface()
-			v1 := genValue(c1)
+			v1 := genValueUnwrap(c1)
 			if n.fnext != nil {
 				fnext := getExec(n.fnext)
 				n.exec = func(f *frame) bltn {
@@ -4140,7 +4146,7 @@ func notEqual(n *node) {
 			}
 		case c1.rval.IsValid():
 			i1 := c1.rval.Interface()
-			v0 := genValue(c0)
+			v0 := genValueUnwrap(c0)
 			if n.fnext != nil {
 				fnext := getExec(n.fnext)
 				n.exec = func(f *frame) bltn {
@@ -4161,8 +4167,8 @@ func notEqual(n *node) {
 				}
 			}
 		default:
-			v0 := genValue(c0)
-			v1 := genValue(c1)
+			v0 := genValueUnwrap(c0)
+			v1 := genValueUnwrap(c1)
 			if n.fnext != nil {
 				fnext := getExec(n.fnext)
 				n.exec = func(f *frame) bltn {
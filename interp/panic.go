@@ -0,0 +1,197 @@
+package interp
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// PanicError is the error returned when the PanicToError option is set, by
+// an interpreted function called from binary code which panicked, if its
+// last result is of type error. It is also the value passed to the PanicHandler
+// option for functions without error result.
+type PanicError interface {
+	error
+
+	// Recovered returns the value passed to panic.
+	Recovered() interface{}
+
+	// InterpStack returns the interpreted functions unwound by the panic,
+	// innermost first, with the position of their declaration.
+	InterpStack() []string
+}
+
+type panicError struct {
+	value interface{}
+	stack []string
+}
+
+func (e *panicError) Error() string          { return fmt.Sprintf("panic: %v", e.value) }
+func (e *panicError) Recovered() interface{} { return e.value }
+func (e *panicError) InterpStack() []string  { return e.stack }
+
+// tracedPanic is the value of a panic unwinding interpreted frames when the
+// PanicToError option is set. It records the interpreted stack and whether
+// the panic originated in binary code, in which case it is not recovered.
+type tracedPanic struct {
+	value  interface{}
+	stack  []string
+	frames []FrameInfo
+	host   bool
+}
+
+// panicValue returns the value passed to panic from the recovered value r.
+func panicValue(r interface{}) interface{} {
+	if t, ok := r.(*tracedPanic); ok {
+		r = t.value
+	}
+	if _, ok := r.(nilPanic); ok {
+		return nil
+	}
+	return r
+}
+
+// nilPanic is the value of panic(nil) in interpreted code before go1.21,
+// which recover returns as nil.
+type nilPanic struct{}
+
+// genPanicValue returns a generator of the value passed to panic by the call
+// n, as it is recovered by binary code. Interpreted errors and stringers are
+// wrapped, as when passed to an empty interface parameter of a binary function,
+// so their methods are used to print an unrecovered panic. Since go1.21,
+// panic(nil) is converted to a *runtime.PanicNilError.
+func genPanicValue(n *node) func(*frame) interface{} {
+	c := n.child[1]
+	value := genValue(c)
+
+	return func(f *frame) interface{} {
+		v, nod := value(f), c
+		if v.IsValid() {
+			if vi, ok := v.Interface().(valueInterface); ok {
+				v, nod = vi.value, vi.node
+			}
+		}
+		if v.IsValid() && v.Kind() == reflect.Interface {
+			v = v.Elem()
+		}
+		if !v.IsValid() || nod == nil {
+			if goMinorVersion(&n.interp.context) >= 21 {
+				return panicNilError()
+			}
+			return nilPanic{}
+		}
+		typ := interf
+		if nod.typ.cat != valueT && !nod.typ.implements(&itype{cat: valueT, rtype: errorType}) && isStringer(nod.typ) {
+			typ = stringerType
+		}
+		return wrapValue(nod, func(*frame) reflect.Value { return v }, typ)(f).Interface()
+	}
+}
+
+// tracePanic returns the recovered value r, completed by the interpreted
+// function whose body starts at node n, running in frame f.
+func tracePanic(n *node, f *frame, r interface{}) interface{} {
+	t, ok := r.(*tracedPanic)
+	if !ok {
+		t = &tracedPanic{value: r}
+	}
+	if n.interp.panicToError {
+		t.stack = append(t.stack, location(n))
+	}
+	if n.interp.panicLocals != 0 {
+		if def := funcDef(n); def != nil {
+			t.frames = append(t.frames, n.interp.frameInfo(def, f))
+		}
+	}
+	return t
+}
+
+// untraced returns the value passed to panic from the recovered value r,
+// which may be traced.
+func untraced(r interface{}) interface{} {
+	if t, ok := r.(*tracedPanic); ok {
+		return t.value
+	}
+	return r
+}
+
+// untracePanic propagates the panic of an interpreted function called from
+// binary code with the value passed to panic, so it can be recovered by the
+// binary code. It must be deferred.
+func untracePanic() {
+	if r := recover(); r != nil {
+		panic(untraced(r))
+	}
+}
+
+// panicFrames returns the frames recorded in the recovered value r.
+func panicFrames(r interface{}) []FrameInfo {
+	if t, ok := r.(*tracedPanic); ok {
+		return t.frames
+	}
+	return nil
+}
+
+// funcDef returns the function declaration or literal enclosing node n,
+// or nil outside of functions.
+func funcDef(n *node) *node {
+	for n != nil && n.kind != funcDecl && n.kind != funcLit {
+		n = n.anc
+	}
+	return n
+}
+
+// location returns the name and position of the interpreted function
+// enclosing node n, or the position of n outside of functions.
+func location(n *node) string {
+	def := funcDef(n)
+	if def == nil {
+		return n.interp.fset.Position(n.pos).String()
+	}
+	return funcName(def) + " at " + n.interp.fset.Position(def.pos).String()
+}
+
+// markHostPanic marks a panic raised by a binary function called from
+// interpreted code, so it is not converted at the boundary of the calling
+// interpreted function. It must be deferred.
+func markHostPanic() {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if _, ok := r.(*tracedPanic); !ok {
+		r = &tracedPanic{value: r, host: true}
+	}
+	panic(r)
+}
+
+// recoverPanic returns the results of a call of type t to an interpreted
+// function, which panicked with the recovered value r.
+func (interp *Interpreter) recoverPanic(t reflect.Type, r interface{}) []reflect.Value {
+	if isExit(r) {
+		// Let os.Exit and runtime.Goexit terminate the evaluation.
+		panic(r)
+	}
+	tp, ok := r.(*tracedPanic)
+	if !ok {
+		tp = &tracedPanic{value: r}
+	}
+	if tp.host {
+		// Let host panics propagate to host frames.
+		panic(tp.value)
+	}
+	err := &panicError{value: panicValue(tp), stack: tp.stack}
+
+	out := make([]reflect.Value, t.NumOut())
+	for i := range out {
+		out[i] = reflect.New(t.Out(i)).Elem()
+	}
+	switch l := len(out) - 1; {
+	case l >= 0 && t.Out(l) == errorType:
+		out[l].Set(reflect.ValueOf(err))
+	case interp.panicHandler != nil:
+		interp.panicHandler(err)
+	default:
+		fmt.Fprintln(interp.stderr, err)
+	}
+	return out
+}
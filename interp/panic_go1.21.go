@@ -0,0 +1,9 @@
+//go:build go1.21
+// +build go1.21
+
+package interp
+
+import "runtime"
+
+// panicNilError returns the value of panic(nil), since go1.21.
+func panicNilError() interface{} { return new(runtime.PanicNilError) }
@@ -0,0 +1,8 @@
+//go:build !go1.21
+// +build !go1.21
+
+package interp
+
+// panicNilError returns the value of panic(nil), which is not converted
+// before go1.21.
+func panicNilError() interface{} { return nilPanic{} }
@@ -0,0 +1,121 @@
+package interp
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+)
+
+// genPrint generates the print or println builtin call n, which writes its
+// arguments to the interpreter stderr, formatted as by the Go runtime. The
+// operands of println are separated by spaces and followed by a newline.
+func genPrint(n *node, ln bool) {
+	child := n.child[1:]
+	values := make([]func(*frame) reflect.Value, len(child))
+	for i, c := range child {
+		values[i] = genValue(c)
+	}
+	out := n.interp.stderr
+
+	genBuiltinDeferWrapper(n, values, nil, func(args []reflect.Value) []reflect.Value {
+		var b []byte
+		for i, v := range args {
+			if ln && i > 0 {
+				b = append(b, ' ')
+			}
+			b = appendPrint(b, v)
+		}
+		if ln {
+			b = append(b, '\n')
+		}
+		_, _ = out.Write(b)
+		return nil
+	})
+}
+
+// appendPrint appends to b the value v formatted as by the print builtin.
+func appendPrint(b []byte, v reflect.Value) []byte {
+	if !v.IsValid() {
+		return append(b, "(0x0,0x0)"...)
+	}
+	if vi, ok := v.Interface().(valueInterface); ok {
+		// An interpreted interface value.
+		if !vi.value.IsValid() {
+			return append(b, "(0x0,0x0)"...)
+		}
+		return appendInterface(b, vi.value)
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		return strconv.AppendBool(b, v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.AppendInt(b, v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.AppendUint(b, v.Uint(), 10)
+	case reflect.Float32:
+		return strconv.AppendFloat(b, v.Float(), 'g', -1, 32)
+	case reflect.Float64:
+		return strconv.AppendFloat(b, v.Float(), 'g', -1, 64)
+	case reflect.Complex64, reflect.Complex128:
+		size := 64
+		if v.Kind() == reflect.Complex64 {
+			size = 32
+		}
+		c := v.Complex()
+		b = strconv.AppendFloat(append(b, '('), real(c), 'g', -1, size)
+		if i := strconv.FormatFloat(imag(c), 'g', -1, size); i[0] == '-' || i[0] == '+' {
+			b = append(b, i...)
+		} else {
+			b = append(append(b, '+'), i...)
+		}
+		return append(b, "i)"...)
+	case reflect.String:
+		return append(b, v.String()...)
+	case reflect.Slice:
+		b = append(b, '[')
+		b = strconv.AppendInt(b, int64(v.Len()), 10)
+		b = append(b, '/')
+		b = strconv.AppendInt(b, int64(v.Cap()), 10)
+		b = append(b, ']')
+		return appendHex(b, v.Pointer())
+	case reflect.Chan, reflect.Func, reflect.Map, reflect.Ptr, reflect.UnsafePointer:
+		return appendHex(b, v.Pointer())
+	case reflect.Interface:
+		if v.IsNil() {
+			return append(b, "(0x0,0x0)"...)
+		}
+		return appendInterface(b, v.Elem())
+	}
+	return b
+}
+
+// appendInterface appends to b the interface value holding v, as the pair
+// of the addresses of its type and of its data.
+func appendInterface(b []byte, v reflect.Value) []byte {
+	b = appendHex(append(b, '('), reflect.ValueOf(v.Type()).Pointer())
+	b = append(b, ',')
+	switch v.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Map, reflect.Ptr, reflect.UnsafePointer:
+		b = appendHex(b, v.Pointer())
+	default:
+		p := reflect.New(v.Type())
+		p.Elem().Set(v)
+		b = appendHex(b, p.Pointer())
+	}
+	return append(b, ')')
+}
+
+func appendHex(b []byte, p uintptr) []byte {
+	return strconv.AppendUint(append(b, "0x"...), uint64(p), 16)
+}
+
+// stdoutSymbols returns the print functions of package fmt writing to w,
+// set by the Stdout option, instead of os.Stdout.
+func stdoutSymbols(w io.Writer) map[string]reflect.Value {
+	return map[string]reflect.Value{
+		"Print":   reflect.ValueOf(func(a ...interface{}) (int, error) { return fmt.Fprint(w, a...) }),
+		"Printf":  reflect.ValueOf(func(format string, a ...interface{}) (int, error) { return fmt.Fprintf(w, format, a...) }),
+		"Println": reflect.ValueOf(func(a ...interface{}) (int, error) { return fmt.Fprintln(w, a...) }),
+	}
+}
@@ -0,0 +1,140 @@
+package interp
+
+import (
+	"context"
+	"runtime/pprof"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// profileLabel is the runtime/pprof label key set during the execution
+// of interpreted functions, when profiling is enabled.
+const profileLabel = "yaegi_func"
+
+// profile stores the cumulative execution time of interpreted functions.
+type profile struct {
+	mutex sync.Mutex
+	names map[*node]string         // function names, indexed by definition node
+	times map[string]time.Duration // cumulative execution time, indexed by function name
+}
+
+func newProfile() *profile {
+	return &profile{names: map[*node]string{}, times: map[string]time.Duration{}}
+}
+
+// name returns the qualified name of the function defined by node def.
+func (p *profile) name(def *node) string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if s, ok := p.names[def]; ok {
+		return s
+	}
+	s := funcName(def)
+	p.names[def] = s
+	return s
+}
+
+func (p *profile) add(name string, d time.Duration) {
+	p.mutex.Lock()
+	p.times[name] += d
+	p.mutex.Unlock()
+}
+
+// runFunc executes the body of function def in frame nf. If profiling is enabled,
+// the execution is labelled in runtime/pprof profiles with the function name,
+// as a child of the ctx labels, and accounted in the interpreter profile.
+func runFunc(ctx context.Context, def *node, nf *frame) {
+	p := def.interp.profile
+	if p == nil {
+		runCfg(def.child[3].start, nf)
+		return
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	name := p.name(def)
+	start := time.Now()
+	defer func() { p.add(name, time.Since(start)) }()
+
+	pprof.Do(ctx, pprof.Labels(profileLabel, name), func(ctx context.Context) {
+		nf.labels = ctx
+		runCfg(def.child[3].start, nf)
+	})
+}
+
+// Profile returns the cumulative execution time of each interpreted function,
+// indexed by qualified function name (i.e. "main.(*T).Method").
+// It returns nil if the interpreter was not created with the Profile option.
+// As the time of a function includes the time of its callees, recursive
+// functions are accounted several times.
+func (interp *Interpreter) Profile() map[string]time.Duration {
+	if interp.profile == nil {
+		return nil
+	}
+	interp.profile.mutex.Lock()
+	defer interp.profile.mutex.Unlock()
+	res := make(map[string]time.Duration, len(interp.profile.times))
+	for k, v := range interp.profile.times {
+		res[k] = v
+	}
+	return res
+}
+
+// funcName returns the qualified name of a function definition node, in a format
+// close to the runtime one: "pkg.Func", "pkg.T.Method", "pkg.(*T).Method", or
+// "pkg.Func.func1" for function literals.
+func funcName(def *node) string {
+	if def.kind == funcLit {
+		return funcLitName(def)
+	}
+	name := pkgName(def) + "."
+	if isMethod(def) {
+		switch t := def.child[0].child[0].lastChild(); t.kind {
+		case starExpr:
+			name += "(*" + t.child[0].ident + ")."
+		case identExpr:
+			name += t.ident + "."
+		}
+	}
+	return name + def.child[1].ident
+}
+
+// funcLitName returns the name of a function literal: the name of the enclosing
+// function followed by ".func" and the rank of the literal within it.
+func funcLitName(def *node) string {
+	anc := def.anc
+	for anc != nil && anc.kind != funcDecl && anc.kind != funcLit {
+		anc = anc.anc
+	}
+	prefix := pkgName(def) + ".glob"
+	root := anc
+	if anc != nil {
+		prefix = funcName(anc)
+	} else {
+		for root = def; root.anc != nil; root = root.anc {
+		}
+	}
+	rank, found := 0, false
+	root.Walk(func(n *node) bool {
+		if found || n == root || n.kind != funcLit {
+			return !found
+		}
+		// Function literals nested in other literals are numbered within their parent.
+		rank++
+		found = n == def
+		return false
+	}, nil)
+	return prefix + ".func" + strconv.Itoa(rank)
+}
+
+// pkgName returns the name of the package in which node n is defined.
+func pkgName(n *node) string {
+	for n.anc != nil {
+		n = n.anc
+	}
+	if n.kind == fileStmt && len(n.child) > 0 && n.child[0].ident != "" {
+		return n.child[0].ident
+	}
+	return mainID
+}
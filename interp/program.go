@@ -0,0 +1,280 @@
+package interp
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Program is the compiled form of Go statements or expressions, as returned
+// by Compile. A program can be executed several times, possibly concurrently.
+type Program struct {
+	interp *Interpreter
+	root   *node
+	value  func(*frame) reflect.Value // value of the last statement or expression
+	index  int                        // index of the first frame value owned by the program
+	types  []reflect.Type             // types of the frame values owned by the program
+	vars   map[string]int             // frame indexes of the variables bound by CompileWithVars
+	stats  *Stats                     // accounting of the executions, if enabled
+}
+
+// Stats returns the cumulative accounting of the executions of p, or nil if
+// the interpreter was not created with the Stats option.
+func (p *Program) Stats() *Stats { return p.stats }
+
+// Compile compiles the statements or expressions of src, in the context of the
+// current package, into a program to be executed by Execute. Declarations at
+// top level are not allowed, so the package state is not modified, except short
+// variable declarations which define variables local to the program.
+//
+// Compile is safe for concurrent use, but not with Eval or ReEval which may
+// modify the package declarations.
+func (interp *Interpreter) Compile(src string) (*Program, error) {
+	return interp.compile(src, nil)
+}
+
+// CompileWithVars compiles src as Compile, where the names of vars are
+// variables of the given types, local to the program. They shadow the package
+// declarations of the same name, and their values are set at each execution
+// by ExecuteWithVars. The use of an undefined identifier is reported with the
+// list of the variable names.
+func (interp *Interpreter) CompileWithVars(src string, vars map[string]reflect.Type) (*Program, error) {
+	return interp.compile(src, vars)
+}
+
+// EvalExprWithVars evaluates src, as Eval, where the names of vars are variables
+// initialized with the corresponding values, without modifying the package
+// declarations. The type of a variable is the one of its value, or interface{}
+// for a nil value. To evaluate src several times, use CompileWithVars once,
+// then ExecuteWithVars.
+func (interp *Interpreter) EvalExprWithVars(src string, vars map[string]interface{}) (reflect.Value, error) {
+	types := make(map[string]reflect.Type, len(vars))
+	for name, v := range vars {
+		if t := reflect.TypeOf(v); t != nil {
+			types[name] = t
+		} else {
+			types[name] = reflect.TypeOf((*interface{})(nil)).Elem()
+		}
+	}
+	p, err := interp.compile(src, types)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return interp.ExecuteWithVars(p, vars)
+}
+
+func (interp *Interpreter) compile(src string, vars map[string]reflect.Type) (prog *Program, err error) {
+	defer func() {
+		r := recover()
+		switch {
+		case isExit(r):
+			err = exitError(r)
+		case r != nil:
+			var pc [64]uintptr // 64 frames should be enough.
+			n := runtime.Callers(1, pc[:])
+			err = Panic{Value: panicValue(r), Callers: pc[:n], Stack: debug.Stack(), frames: panicFrames(r)}
+		}
+	}()
+
+	interp.compileMutex.Lock()
+	defer interp.compileMutex.Unlock()
+
+	switch interp.firstToken(src) {
+	case token.PACKAGE:
+		return nil, errors.New("package clause not allowed in program")
+	case token.CONST, token.FUNC, token.IMPORT, token.TYPE, token.VAR:
+		return nil, errors.New("declaration not allowed in program")
+	}
+	// Parse src as statements of the REPL, in a pseudo main function.
+	pkgName, root, err := interp.ast(src, "")
+	if err != nil || root == nil {
+		return nil, err
+	}
+	if interp.exprOnly {
+		if err = interp.checkExprOnly(root); err != nil {
+			return nil, err
+		}
+	}
+	sc := interp.initScopePkg(interp.Name)
+	for _, c := range root.child {
+		switch c.kind {
+		case declStmt:
+			return nil, c.cfgErrorf("declaration not allowed in program")
+		}
+	}
+	// As in GTA, the short variable declarations at top level, outside of the
+	// blocks and statements with their own scope, are local to the program.
+	var locals []string
+	root.Walk(func(n *node) bool {
+		if err != nil || isScopeStmt(n) {
+			return false
+		}
+		switch n.kind {
+		case blockStmt:
+			return n == root
+		case defineStmt, defineXStmt:
+			for _, id := range n.child[:n.nleft] {
+				if _, ok := vars[id.ident]; ok || sc.sym[id.ident] != nil {
+					err = id.cfgErrorf("cannot redeclare %s in program", id.ident)
+					return false
+				}
+				locals = append(locals, id.ident)
+			}
+		}
+		return true
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	// Variables defined at top level are local to the program.
+	defer func() {
+		for _, name := range locals {
+			delete(sc.sym, name)
+		}
+	}()
+
+	// The program values are allocated after the ones of the global frame.
+	index := len(interp.universe.types)
+	sc.types = interp.universe.types
+
+	// Bound variables shadow the package symbols during the compilation.
+	var names []string
+	bound := make(map[string]int, len(vars))
+	shadowed := make(map[string]*symbol, len(vars))
+	for name, t := range vars {
+		typ := &itype{cat: valueT, rtype: t}
+		shadowed[name] = sc.sym[name]
+		sc.sym[name] = &symbol{kind: varSym, index: sc.add(typ), typ: typ}
+		bound[name] = sc.sym[name].index
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	defer func() {
+		for name, sym := range shadowed {
+			if sym == nil {
+				delete(sc.sym, name)
+			} else {
+				sc.sym[name] = sym
+			}
+		}
+	}()
+
+	if err = interp.gtaRetry([]*node{root}, pkgName, interp.Name); err != nil {
+		return nil, undefinedVar(sc, err, names)
+	}
+	for _, name := range locals {
+		if sym := sc.sym[name]; sym != nil {
+			sym.global = false
+		}
+	}
+	if _, err = interp.cfg(root, interp.Name); err != nil {
+		return nil, undefinedVar(sc, err, names)
+	}
+	setExec(root.start)
+	if err = genRun(root); err != nil {
+		return nil, err
+	}
+
+	// The global frame is not resized, as it would race with executions.
+	prog = &Program{
+		interp: interp,
+		root:   root,
+		value:  genValue(root),
+		index:  index,
+		types:  append([]reflect.Type(nil), interp.universe.types[index:]...),
+		vars:   bound,
+	}
+	if interp.stats {
+		prog.stats = &Stats{}
+	}
+	return prog, nil
+}
+
+// undefinedVar completes an error on an undefined identifier with the list
+// of the names of the bound variables, if any.
+func undefinedVar(sc *scope, err error, names []string) error {
+	e, ok := err.(*cfgError)
+	if !ok || len(names) == 0 || e.node.kind != identExpr {
+		return err
+	}
+	if _, _, found := sc.lookup(e.node.ident); found {
+		return err
+	}
+	return e.node.cfgErrorf("undefined: %s (available variables: %s)", e.node.ident, strings.Join(names, ", "))
+}
+
+// Execute executes the program p, and returns the value of its last
+// statement or expression, as Eval. The program runs on a private copy
+// of the global frame, where the package variables are shared, so several
+// executions of the same program can occur concurrently.
+func (interp *Interpreter) Execute(p *Program) (reflect.Value, error) {
+	return interp.ExecuteWithVars(p, nil)
+}
+
+// ExecuteWithVars executes the program p, as Execute, where the variables
+// bound by CompileWithVars are initialized with the values of vars. The
+// variables missing in vars have the zero value of their type.
+func (interp *Interpreter) ExecuteWithVars(p *Program, vars map[string]interface{}) (res reflect.Value, err error) {
+	defer func() {
+		r := recover()
+		switch {
+		case isExit(r):
+			err = exitError(r)
+		case r != nil:
+			var pc [64]uintptr // 64 frames should be enough.
+			n := runtime.Callers(1, pc[:])
+			err = Panic{Value: panicValue(r), Callers: pc[:n], Stack: debug.Stack(), frames: panicFrames(r)}
+		}
+	}()
+
+	// The values of the global frame are references to the variables storage,
+	// so a copy of the frame shares the package variables. The values owned
+	// by the program are allocated for each execution.
+	f := newFrame(nil, p.index+len(p.types), interp.runid())
+	f.setStats(p.stats)
+	interp.frame.mutex.RLock()
+	copy(f.data[:p.index], interp.frame.data)
+	interp.frame.mutex.RUnlock()
+	for i, t := range p.types {
+		f.data[p.index+i] = reflect.New(t).Elem()
+	}
+	for name, v := range vars {
+		i, ok := p.vars[name]
+		if !ok {
+			return res, fmt.Errorf("undefined variable: %s", name)
+		}
+		if v == nil {
+			continue
+		}
+		val, t := reflect.ValueOf(v), f.data[i].Type()
+		if !val.Type().AssignableTo(t) {
+			return res, fmt.Errorf("cannot use %v (type %s) as type %s in variable %s", v, val.Type(), t, name)
+		}
+		f.data[i].Set(val)
+	}
+	interp.mutex.RLock()
+	f.done = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(interp.done)}
+	interp.mutex.RUnlock()
+
+	if p.stats != nil {
+		start := time.Now()
+		defer func() { atomic.AddInt64(&p.stats.duration, int64(time.Since(start))) }()
+	}
+	runCfg(p.root.start, f)
+
+	res = p.value(f)
+	// If result is an interpreter node, wrap it in a runtime callable function
+	if res.IsValid() {
+		if n, ok := res.Interface().(*node); ok {
+			res = genFunctionWrapper(n)(f)
+		}
+	}
+	return res, err
+}
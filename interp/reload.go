@@ -0,0 +1,290 @@
+package interp
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+)
+
+// reload stores the state necessary to replace the package level declarations
+// of a previously evaluated file by the ones of a new version of the file.
+type reload struct {
+	interp   *Interpreter
+	old      map[string]*node   // AST roots of the previous evaluation, by file name
+	removed  []string           // names of files removed since the previous evaluation
+	sc       *scope             // package scope
+	sym      map[string]*symbol // package symbols prior to reload
+	methods  map[*itype][]*node // methods of package types prior to reload
+	funcs    map[string]*node   // previous function and method definitions, by qualified name
+	vars     map[string]*symbol // previous variable symbols, by name
+	types    map[string]*symbol // previous type symbols, by name
+	replaced bool               // true once the new declarations are in place
+}
+
+// ReEval evaluates the source files at paths in the current package, as Eval,
+// replacing the package level declarations resulting from a previous call of
+// ReEval on the same files: the functions, methods, variables, constants,
+// types and imports of the previous version are removed, then the ones of the
+// new version are added. The files which depend on each other must be
+// evaluated together.
+//
+// Functions and methods with an unchanged signature are updated in place, so
+// the function values previously obtained from Eval, and the callers compiled
+// previously, run the new implementation. Variables with an unchanged type
+// keep their storage and are initialized again. The redefinition of a type
+// with a different layout is rejected.
+//
+// In case of error, the previous declarations are left unchanged.
+func (interp *Interpreter) ReEval(paths ...string) (reflect.Value, error) {
+	return interp.reEval(paths, nil)
+}
+
+// reEval re-evaluates the files at paths, and removes the declarations of the
+// previously evaluated files at removed paths.
+func (interp *Interpreter) reEval(paths, removed []string) (reflect.Value, error) {
+	srcs := make([]string, len(paths))
+	for i, path := range paths {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		srcs[i] = string(b)
+	}
+	r := &reload{interp: interp, old: map[string]*node{}, removed: removed}
+	interp.mutex.RLock()
+	for _, path := range paths {
+		r.old[path] = interp.roots[path]
+	}
+	for _, path := range removed {
+		r.old[path] = interp.roots[path]
+	}
+	interp.mutex.RUnlock()
+	if len(paths) == 0 {
+		// Only remove declarations.
+		r.forget()
+		r.replace(nil)
+		return reflect.Value{}, nil
+	}
+	return interp.eval(paths, srcs, r)
+}
+
+// forget saves the package state, then removes the declarations of the
+// previous version of the files from the package scope.
+func (r *reload) forget() {
+	r.sc = r.interp.initScopePkg(r.interp.Name)
+	r.sym = make(map[string]*symbol, len(r.sc.sym))
+	r.methods = map[*itype][]*node{}
+	for k, s := range r.sc.sym {
+		r.sym[k] = s
+		if s.kind == typeSym && s.typ != nil {
+			r.methods[s.typ] = s.typ.method
+		}
+	}
+	r.funcs = map[string]*node{}
+	r.vars = map[string]*symbol{}
+	r.types = map[string]*symbol{}
+	for _, root := range r.old {
+		if root != nil {
+			r.forgetDecls(root)
+		}
+	}
+}
+
+// forgetDecls removes the package level declarations of root.
+func (r *reload) forgetDecls(root *node) {
+	baseName := filepath.Base(r.interp.fset.Position(root.pos).Filename)
+	walkDecls(root, func(n *node) {
+		switch n.kind {
+		case funcDecl:
+			if isMethod(n) {
+				// Remove the method from its receiver type, and from the value type
+				// for a pointer receiver.
+				r.funcs[funcName(n)] = n
+				if t := n.child[0].child[0].lastChild().typ; t != nil {
+					r.removeMethod(t, n)
+					if t.cat == ptrT && t.val != nil {
+						r.removeMethod(t.val, n)
+					}
+				}
+				break
+			}
+			if name := n.child[1].ident; name != "init" {
+				r.funcs[funcName(n)] = n
+				delete(r.sc.sym, name)
+			}
+		case importSpec:
+			// Only imports of binary packages are registered per source file.
+			var name string
+			if len(n.child) == 2 {
+				name = n.child[0].ident
+			} else {
+				name = identifier.FindString(n.child[0].rval.String())
+			}
+			delete(r.sc.sym, filepath.Join(name, baseName))
+		case typeSpec, typeSpecAssign:
+			name := n.child[0].ident
+			if s, ok := r.sc.sym[name]; ok && s.kind == typeSym {
+				r.types[name] = s
+				delete(r.sc.sym, name)
+			}
+		case defineStmt, defineXStmt, valueSpec:
+			l := n.nleft
+			if n.kind == valueSpec {
+				l = len(n.child) - 1
+			}
+			for _, c := range n.child[:l] {
+				if s, ok := r.sc.sym[c.ident]; ok && (s.kind == varSym || s.kind == constSym) {
+					r.vars[c.ident] = s
+					delete(r.sc.sym, c.ident)
+				}
+			}
+		}
+	})
+}
+
+// removeMethod removes the method definition n from type t. The previous
+// methods of t are saved to be restored in case of error.
+func (r *reload) removeMethod(t *itype, n *node) {
+	if _, ok := r.methods[t]; !ok {
+		r.methods[t] = t.method
+	}
+	t.method = removeNode(t.method, n)
+}
+
+// check verifies that redefined types keep the same layout, and reuses the
+// storage of redefined variables of unchanged type. It must be called after
+// the global types analysis of the new version, and prior to CFG.
+func (r *reload) check() error {
+	for name, old := range r.types {
+		s, ok := r.sc.sym[name]
+		if !ok || s.kind != typeSym {
+			continue
+		}
+		if old.typ.TypeOf() != s.typ.TypeOf() {
+			if s.typ.node != nil {
+				return s.typ.node.cfgErrorf("cannot redefine type %s: layout changed", name)
+			}
+			return errors.New("cannot redefine type " + name + ": layout changed")
+		}
+		// Keep the methods defined on the type in other files.
+		s.typ.method = append(s.typ.method, old.typ.method...)
+	}
+	for name, old := range r.vars {
+		if s, ok := r.sc.sym[name]; ok && s.kind == varSym && old.kind == varSym && s.typ.TypeOf() == old.typ.TypeOf() {
+			s.index = old.index
+		}
+	}
+	return nil
+}
+
+// replace updates in place the previous definitions of functions, methods and
+// types by the new ones, so the existing references to them use the new
+// version. It must be called after the code generation of the new version.
+func (r *reload) replace(roots []*node) {
+	defs := map[string]*node{}
+	for _, root := range roots {
+		walkDecls(root, func(n *node) {
+			if n.kind == funcDecl && n.child[1].ident != "init" {
+				defs[funcName(n)] = n
+			}
+		})
+	}
+	for name, old := range r.funcs {
+		n, ok := defs[name]
+		if !ok || n.typ.TypeOf() != old.typ.TypeOf() {
+			continue
+		}
+		*old = *n
+		for _, c := range old.child {
+			c.anc = old
+		}
+		// The previous definition node becomes the one of the new version.
+		if n.anc != nil {
+			n.anc.child[childPos(n)] = old
+		}
+		if isMethod(n) {
+			if t := n.child[0].child[0].lastChild().typ; t != nil {
+				replaceNode(t.method, n, old)
+				if t.cat == ptrT && t.val != nil {
+					replaceNode(t.val.method, n, old)
+				}
+			}
+		} else if s, ok := r.sc.sym[n.child[1].ident]; ok && s.node == n {
+			s.node = old
+		}
+	}
+	for name, old := range r.types {
+		if s, ok := r.sc.sym[name]; ok && s.kind == typeSym {
+			*old.typ = *s.typ
+			s.typ = old.typ
+		}
+	}
+	r.interp.mutex.Lock()
+	for _, name := range r.removed {
+		delete(r.interp.roots, name)
+	}
+	r.interp.mutex.Unlock()
+	r.replaced = true
+}
+
+// restore reverts the package state saved by forget, unless the new
+// declarations are already in place.
+func (r *reload) restore() {
+	if r.replaced || r.sc == nil {
+		return
+	}
+	for k := range r.sc.sym {
+		delete(r.sc.sym, k)
+	}
+	for k, s := range r.sym {
+		r.sc.sym[k] = s
+	}
+	for t, m := range r.methods {
+		t.method = m
+	}
+	r.interp.mutex.Lock()
+	for name, root := range r.old {
+		if root != nil {
+			r.interp.roots[name] = root
+		} else {
+			delete(r.interp.roots, name)
+		}
+	}
+	r.interp.mutex.Unlock()
+}
+
+// walkDecls calls f for each package level declaration or specification node
+// of root.
+func walkDecls(root *node, f func(n *node)) {
+	root.Walk(func(n *node) bool {
+		switch n.kind {
+		case fileStmt, importDecl, constDecl, varDecl, typeDecl:
+			return true
+		case blockStmt:
+			return n == root
+		}
+		f(n)
+		return false
+	}, nil)
+}
+
+// removeNode returns a copy of list without n.
+func removeNode(list []*node, n *node) []*node {
+	res := make([]*node, 0, len(list))
+	for _, m := range list {
+		if m != n {
+			res = append(res, m)
+		}
+	}
+	return res
+}
+
+// replaceNode replaces in list the occurrences of n by m.
+func replaceNode(list []*node, n, m *node) {
+	for i, o := range list {
+		if o == n {
+			list[i] = m
+		}
+	}
+}
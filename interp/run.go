@@ -3,10 +3,17 @@ package interp
 //go:generate go run ../internal/genop/genop.go
 
 import (
+	"context"
 	"fmt"
 	"go/constant"
+	"go/token"
 	"log"
+	"math"
 	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 	"unsafe"
 )
 
@@ -92,6 +99,7 @@ func (interp *Interpreter) run(n *node, cf *frame) {
 		f = interp.frame
 	} else {
 		f = newFrame(cf, len(n.types), interp.runid())
+		f.setStats(cf.stats)
 	}
 	interp.mutex.RLock()
 	f.done = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(interp.done)}
@@ -109,190 +117,329 @@ func (interp *Interpreter) run(n *node, cf *frame) {
 func runCfg(n *node, f *frame) {
 	defer func() {
 		f.mutex.Lock()
+		// The lock is also released if a deferred call panics.
+		defer f.mutex.Unlock()
 		f.recovered = recover()
-		for _, val := range f.deferred {
-			val[0].Call(val[1:])
+		if f.recovered != nil && (n.interp.panicToError || n.interp.panicLocals != 0) {
+			f.recovered = tracePanic(n, f, f.recovered)
 		}
+		deferred := f.deferred
+		f.deferred = nil
+		runDeferred(f, deferred)
 		if f.recovered != nil {
-			fmt.Println(n.cfgErrorf("panic"))
-			f.mutex.Unlock()
+			if !isExit(f.recovered) {
+				fmt.Println(n.cfgErrorf("panic"))
+			}
 			panic(f.recovered)
 		}
-		f.mutex.Unlock()
 	}()
 
-	for exec := n.exec; exec != nil && f.runid() == n.interp.runid(); {
-		exec = exec(f)
+	if s := f.stats; s != nil {
+		for exec := n.exec; exec != nil && f.runid() == n.interp.runid(); {
+			exec = exec(f)
+			s.addNode()
+		}
+	} else {
+		for exec := n.exec; exec != nil && f.runid() == n.interp.runid(); {
+			exec = exec(f)
+		}
+	}
+	if f.runid() != n.interp.runid() {
+		n.interp.interrupted(n)
 	}
 }
 
-func typeAssertStatus(n *node) {
-	c0, c1 := n.child[0], n.child[1]   // cO contains the input value, c1 the type to assert
-	value := genValue(c0)              // input value
-	value1 := genValue(n.anc.child[1]) // returned status
-	rtype := c1.typ.rtype              // type to assert
-	next := getExec(n.tnext)
-
-	switch {
-	case isInterfaceSrc(c1.typ):
-		typ := c1.typ
-		n.exec = func(f *frame) bltn {
-			v, ok := value(f).Interface().(valueInterface)
-			value1(f).SetBool(ok && v.node.typ.implements(typ))
-			return next
-		}
-	case isInterface(c1.typ):
-		n.exec = func(f *frame) bltn {
-			v := value(f)
-			ok := v.IsValid() && canAssertTypes(v.Elem().Type(), rtype)
-			value1(f).SetBool(ok)
-			return next
-		}
-	case c0.typ.cat == valueT:
-		n.exec = func(f *frame) bltn {
-			v := value(f)
-			ok := v.IsValid() && canAssertTypes(v.Elem().Type(), rtype)
-			value1(f).SetBool(ok)
-			return next
-		}
-	default:
-		n.exec = func(f *frame) bltn {
-			v, ok := value(f).Interface().(valueInterface)
-			ok = ok && v.value.IsValid() && canAssertTypes(v.value.Type(), rtype)
-			value1(f).SetBool(ok)
-			return next
+// runDeferred calls the deferred functions of frame f. As in Go, the next
+// deferred functions are still called if one of them panics, and the new
+// panic replaces the current one, unless it exits the program.
+func runDeferred(f *frame, deferred [][]reflect.Value) {
+	for _, val := range deferred {
+		if r := callDeferred(val); r != nil {
+			f.recovered = r
+			if isExit(r) {
+				return
+			}
 		}
 	}
 }
 
+// callDeferred calls the deferred function val[0] with arguments val[1:],
+// and returns the value of its panic, if any.
+func callDeferred(val []reflect.Value) (r interface{}) {
+	defer func() { r = recover() }()
+	val[0].Call(val[1:])
+	return nil
+}
+
+// nilFunc returns a function of type t which panics when called, in place
+// of a nil function value called by n.
+func nilFunc(n *node, t reflect.Type) reflect.Value {
+	return reflect.MakeFunc(t, func([]reflect.Value) []reflect.Value {
+		panic(nilDeref(n))
+	})
+}
+
 func typeAssert(n *node) {
-	c0, c1 := n.child[0], n.child[1]
+	c0 := n.child[0]
 	value := genValue(c0) // input value
 	value0 := genValue(n) // returned result
+	assert := genAssert(n, c0.typ, n.child[1].typ)
 	next := getExec(n.tnext)
 
-	switch {
-	case isInterfaceSrc(c1.typ):
-		typ := n.child[1].typ
-		typID := n.child[1].typ.id()
-		n.exec = func(f *frame) bltn {
-			v := value(f)
-			vi, ok := v.Interface().(valueInterface)
-			if !ok {
-				panic(n.cfgErrorf("interface conversion: nil is not %v", typID))
-			}
-			if !vi.node.typ.implements(typ) {
-				panic(n.cfgErrorf("interface conversion: %v is not %v", vi.node.typ.id(), typID))
-			}
-			value0(f).Set(v)
-			return next
+	n.exec = func(f *frame) bltn {
+		v := value(f)
+		r, ok := assert(f, v)
+		if !ok {
+			panic(assertError(c0.typ, n.child[1].typ, v))
 		}
-	case isInterface(c1.typ):
-		n.exec = func(f *frame) bltn {
-			v := value(f).Elem()
-			typ := value0(f).Type()
-			if !v.IsValid() {
-				panic(fmt.Sprintf("interface conversion: interface {} is nil, not %s", typ.String()))
-			}
-			if !canAssertTypes(v.Type(), typ) {
-				method := firstMissingMethod(v.Type(), typ)
-				panic(fmt.Sprintf("interface conversion: %s is not %s: missing method %s", v.Type().String(), typ.String(), method))
-			}
-			value0(f).Set(v)
-			return next
+		value0(f).Set(r)
+		return next
+	}
+}
+
+func typeAssert2(n *node) {
+	value := genValue(n.child[0]) // input value
+	setValue := genValueCommaOk(n.anc.child[0], n)
+	setStatus := genStatusCommaOk(n.anc.child[1])
+	assert := genAssert(n, n.child[0].typ, n.child[1].typ)
+	next := getExec(n.tnext)
+
+	n.exec = func(f *frame) bltn {
+		v, ok := assert(f, value(f))
+		if setValue != nil {
+			setValue(f, v)
 		}
-	case c0.typ.cat == valueT:
-		n.exec = func(f *frame) bltn {
-			v := value(f).Elem()
-			typ := value0(f).Type()
-			if !v.IsValid() {
-				panic(fmt.Sprintf("interface conversion: interface {} is nil, not %s", typ.String()))
+		if setStatus != nil {
+			setStatus(f, ok)
+		}
+		return next
+	}
+}
+
+// genAssert returns a function asserting v, the frame value of an interface
+// of type src, to the type typ, in a type assertion or a type switch clause n.
+// The function returns the asserted value, in the frame representation of
+// typ, and true, or an invalid value and false if the assertion does not hold.
+func genAssert(n *node, src, typ *itype) func(f *frame, v reflect.Value) (reflect.Value, bool) {
+	rtype := typ.TypeOf()
+
+	switch {
+	case isInterfaceSrc(typ):
+		// The dynamic value is kept, with its type, in an interpreted interface.
+		return func(f *frame, v reflect.Value) (reflect.Value, bool) {
+			nod, dv := dynamicValue(src, v)
+			if nod == nil || missingMethod(nod.typ, dv, typ) != "" {
+				return reflect.Value{}, false
+			}
+			return reflect.ValueOf(valueInterface{nod, dv}), true
+		}
+	case rtype.Kind() == reflect.Interface:
+		// A dynamic value of interpreted type is wrapped to implement the
+		// binary interface.
+		return func(f *frame, v reflect.Value) (reflect.Value, bool) {
+			nod, dv := dynamicValue(src, v)
+			if nod == nil || missingMethod(nod.typ, dv, typ) != "" {
+				return reflect.Value{}, false
+			}
+			if nod.typ.cat == valueT || rtype.NumMethod() == 0 {
+				return dv, true
+			}
+			if dv.Type().Implements(rtype) && !nod.typ.hasInterpMethods() {
+				// A binary value is not wrapped, so its dynamic type and its
+				// other methods remain visible to binary code.
+				return dv, true
+			}
+			if w := v; src.cat == valueT {
+				// Keep the wrapper of an interpreted value, if it implements typ.
+				if w.Kind() == reflect.Interface {
+					w = w.Elem()
+				}
+				if w.Type().Implements(rtype) {
+					return w, true
+				}
 			}
-			if !canAssertTypes(v.Type(), typ) {
-				method := firstMissingMethod(v.Type(), typ)
-				panic(fmt.Sprintf("interface conversion: %s is not %s: missing method %s", v.Type().String(), typ.String(), method))
+			if n.interp.getWrapper(rtype) == nil {
+				return reflect.Value{}, false
 			}
-			value0(f).Set(v)
-			return next
+			wrapped := &node{kind: basicLit, rval: dv, typ: nod.typ, interp: n.interp, pos: n.pos}
+			return genInterfaceWrapper(wrapped, rtype)(f), true
 		}
 	default:
-		n.exec = func(f *frame) bltn {
-			v := value(f).Interface().(valueInterface)
-			typ := value0(f).Type()
-			if !v.value.IsValid() {
-				panic(fmt.Sprintf("interface conversion: interface {} is nil, not %s", typ.String()))
+		// The dynamic type must be identical to the asserted type.
+		id := typ.id()
+		return func(f *frame, v reflect.Value) (reflect.Value, bool) {
+			nod, dv := dynamicValue(src, v)
+			switch {
+			case nod == nil:
+			case nod.typ.cat != valueT:
+				if nod.typ.id() == id {
+					return dv, true
+				}
+			default:
+				switch w := dv.Interface().(type) {
+				case *_error:
+					// An interpreted error, wrapped as a binary one.
+					if w.IValue != nil {
+						dv = reflect.ValueOf(w.IValue)
+					}
+				case *_stringer:
+					// An interpreted fmt.Stringer, wrapped as a binary one.
+					if w.IValue != nil {
+						dv = reflect.ValueOf(w.IValue)
+					}
+				}
+				if canAssertTypes(dv.Type(), rtype) {
+					return dv, true
+				}
 			}
-			if !canAssertTypes(v.value.Type(), typ) {
-				panic(fmt.Sprintf("interface conversion: interface {} is %s, not %s", v.value.Type().String(), typ.String()))
+			return reflect.Value{}, false
+		}
+	}
+}
+
+// dynamicValue returns a node of the dynamic type and the dynamic value of v,
+// the frame value of an interface of type t, or a nil node if v is a nil
+// interface.
+func dynamicValue(t *itype, v reflect.Value) (*node, reflect.Value) {
+	var nod *node
+	if isInterfaceSrc(t) {
+		// Traverse interface indirections to find out the concrete type.
+		for v.IsValid() {
+			vi, ok := v.Interface().(valueInterface)
+			if !ok {
+				break
 			}
-			value0(f).Set(v.value)
-			return next
+			v, nod = vi.value, vi.node
 		}
+		if nod != nil && nod.typ != nil && nod.typ.cat != nilT && !isInterfaceSrc(nod.typ) &&
+			(nod.typ.cat != valueT || nod.typ.rtype.Kind() != reflect.Interface) {
+			return nod, v
+		}
+	}
+	if v.IsValid() && v.Kind() == reflect.Interface {
+		v = v.Elem()
 	}
+	if !v.IsValid() {
+		return nil, v
+	}
+	if vi, ok := wrappedValue(v); ok {
+		return vi.node, vi.value
+	}
+	return &node{kind: basicLit, typ: &itype{cat: valueT, rtype: v.Type()}}, v
 }
 
-func typeAssert2(n *node) {
-	c0, c1 := n.child[0], n.child[1]
-	value := genValue(c0)                    // input value
-	value0 := genValue(n.anc.child[0])       // returned result
-	value1 := genValue(n.anc.child[1])       // returned status
-	setStatus := n.anc.child[1].ident != "_" // do not assign status to "_"
-	typ := c1.typ                            // type to assert or convert to
-	typID := typ.id()
-	rtype := typ.rtype // type to assert
-	next := getExec(n.tnext)
+// wrappedValue returns the interpreted value held by v, if v is a generated
+// wrapper of an interpreted value implementing a binary interface.
+func wrappedValue(v reflect.Value) (valueInterface, bool) {
+	t := v.Type()
+	if t.Kind() != reflect.Ptr || v.IsNil() {
+		return valueInterface{}, false
+	}
+	t = t.Elem()
+	if t.Kind() != reflect.Struct || !strings.HasPrefix(t.Name(), "_") {
+		return valueInterface{}, false
+	}
+	i := t.NumField() - 1
+	if i < 0 || t.Field(i).Name != "IValue" {
+		return valueInterface{}, false
+	}
+	vi, ok := v.Elem().Field(i).Interface().(valueInterface)
+	return vi, ok && vi.node != nil
+}
 
-	switch {
-	case isInterfaceSrc(typ):
-		n.exec = func(f *frame) bltn {
-			v, ok := value(f).Interface().(valueInterface)
-			if ok && v.node.typ.id() == typID {
-				value0(f).Set(value(f))
-			} else {
-				ok = false
-			}
-			if setStatus {
-				value1(f).SetBool(ok)
+// binValueInterface returns the interpreted interface value holding the
+// dynamic value of the binary value v, which is the nil interface if v is a
+// nil interface.
+func binValueInterface(v reflect.Value) reflect.Value {
+	nod, dv := dynamicValue(&itype{cat: valueT, rtype: v.Type()}, v)
+	if nod == nil {
+		return reflect.ValueOf(valueInterface{})
+	}
+	return reflect.ValueOf(valueInterface{nod, dv})
+}
+
+// missingMethod returns the name of the first method of the interface it
+// which is not a method of the dynamic type t of the value v, or an empty
+// string if t implements it.
+func missingMethod(t *itype, v reflect.Value, it *itype) string {
+	if it.cat == valueT || it.cat == errorT {
+		rt := it.TypeOf()
+		if t.cat == valueT {
+			if v.Type().Implements(rt) {
+				return ""
 			}
-			return next
+			return firstMissingMethod(v.Type(), rt)
 		}
-	case isInterface(typ):
-		n.exec = func(f *frame) bltn {
-			v := value(f).Elem()
-			ok := v.IsValid() && canAssertTypes(v.Type(), rtype)
-			if ok {
-				value0(f).Set(v)
-			}
-			if setStatus {
-				value1(f).SetBool(ok)
+		for i := 0; i < rt.NumMethod(); i++ {
+			if rt.Method(i).PkgPath != "" {
+				continue // Unexported, provided by the wrapper.
 			}
-			return next
-		}
-	case n.child[0].typ.cat == valueT:
-		n.exec = func(f *frame) bltn {
-			v := value(f).Elem()
-			ok := v.IsValid() && canAssertTypes(v.Type(), rtype)
-			if ok {
-				value0(f).Set(v)
+			name := rt.Method(i).Name
+			if m, _ := t.lookupMethod(name); m != nil {
+				continue
 			}
-			if setStatus {
-				value1(f).SetBool(ok)
+			if _, _, _, ok := t.lookupBinMethod(name); !ok {
+				return name
 			}
-			return next
 		}
-	default:
-		n.exec = func(f *frame) bltn {
-			v, ok := value(f).Interface().(valueInterface)
-			ok = ok && v.value.IsValid() && canAssertTypes(v.value.Type(), rtype)
-			if ok {
-				value0(f).Set(v.value)
-			}
-			if setStatus {
-				value1(f).SetBool(ok)
+		return ""
+	}
+	methods := it.methods()
+	names := make([]string, 0, len(methods))
+	for name := range methods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if t.cat != valueT {
+		tm := t.methods()
+		for _, name := range names {
+			if tm[name] != methods[name] {
+				return name
 			}
-			return next
 		}
+		return ""
 	}
+	for _, name := range names {
+		if m := v.MethodByName(name); !m.IsValid() || m.Type().String() != methods[name] {
+			return name
+		}
+	}
+	return ""
+}
+
+// assertError returns the runtime error of the failed assertion of v, the
+// frame value of an interface of type src, to the type typ.
+func assertError(src, typ *itype, v reflect.Value) string {
+	nod, dv := dynamicValue(src, v)
+	if isInterface(typ) {
+		if nod == nil {
+			return fmt.Sprintf("interface conversion: interface is nil, not %s", typeString(typ))
+		}
+		return fmt.Sprintf("interface conversion: %s is not %s: missing method %s", typeString(nod.typ), typeString(typ), missingMethod(nod.typ, dv, typ))
+	}
+	if nod == nil {
+		return fmt.Sprintf("interface conversion: %s is nil, not %s", typeString(src), typeString(typ))
+	}
+	return fmt.Sprintf("interface conversion: %s is %s, not %s", typeString(src), typeString(nod.typ), typeString(typ))
+}
+
+// typeString returns the string of the type t, as displayed by runtime errors.
+func typeString(t *itype) string {
+	switch {
+	case t.cat == valueT || t.cat == errorT:
+		return t.TypeOf().String()
+	case t.cat == interfaceT && t.name == "" && len(t.field) == 0:
+		return "interface {}"
+	case t.cat == interfaceT && t.name == "":
+		methods := t.methods()
+		names := make([]string, 0, len(methods))
+		for name, typ := range methods {
+			names = append(names, name+strings.TrimPrefix(typ, "func"))
+		}
+		sort.Strings(names)
+		return "interface { " + strings.Join(names, "; ") + " }"
+	}
+	return t.id()
 }
 
 func canAssertTypes(src, dest reflect.Type) bool {
@@ -321,6 +468,27 @@ func firstMissingMethod(src, dest reflect.Type) string {
 	return ""
 }
 
+// sliceToArray converts the slice s to the type typ, an array or a pointer to
+// an array which shares the storage of s.
+func sliceToArray(s reflect.Value, typ reflect.Type) reflect.Value {
+	at := typ
+	if typ.Kind() == reflect.Ptr {
+		at = typ.Elem()
+	}
+	if s.Len() < at.Len() {
+		panic(fmt.Sprintf("runtime error: cannot convert slice with length %d to array or pointer to array with length %d", s.Len(), at.Len()))
+	}
+	if typ.Kind() == reflect.Ptr {
+		if s.IsNil() {
+			return reflect.Zero(typ)
+		}
+		return reflect.NewAt(at, unsafe.Pointer(s.Pointer()))
+	}
+	a := reflect.New(at).Elem()
+	reflect.Copy(a, s)
+	return a
+}
+
 func convert(n *node) {
 	dest := genValue(n)
 	c := n.child[1]
@@ -338,13 +506,88 @@ func convert(n *node) {
 		return
 	}
 
+	if isInterfaceSrc(n.child[0].typ) || n.child[0].typ.cat == funcT {
+		var value func(*frame) reflect.Value
+		switch {
+		case n.child[0].typ.cat != funcT:
+			// The value is kept with its dynamic type in an interpreted interface.
+			value = genValueInterface(c)
+		case c.typ.cat == valueT:
+			// A binary function is called through a node.
+			value = genValueNode(c)
+		default:
+			// Func types with identical underlying types share the node representation.
+			value = genValue(c)
+		}
+		n.exec = func(f *frame) bltn {
+			dest(f).Set(value(f))
+			return next
+		}
+		return
+	}
+
+	if isStructConversion(c.typ, n.child[0].typ) {
+		// The reflect types differ: copy the fields.
+		value := genValue(c)
+		t, toInterp := c.typ, n.child[0].typ.cat != valueT
+		if toInterp {
+			t = n.child[0].typ
+		}
+		n.exec = func(f *frame) bltn {
+			v := reflect.New(typ).Elem()
+			if err := copyIdentical(f, v, value(f), t, toInterp); err != nil {
+				panic(n.cfgErrorf("%v", err))
+			}
+			dest(f).Set(v)
+			return next
+		}
+		return
+	}
+
 	var value func(*frame) reflect.Value
-	if c.typ.cat == funcT {
+	switch {
+	case c.typ.cat == funcT:
 		value = genFunctionWrapper(c)
-	} else {
+	case typ.Kind() == reflect.Interface:
+		value = genInterfaceWrapper(c, typ)
+	default:
 		value = genValue(c)
 	}
 
+	vt := c.typ.TypeOf()
+	if isInt(vt) && typ.Kind() == reflect.String {
+		// Integer to string: the UTF-8 representation of a valid code point, or "\uFFFD".
+		var get func(reflect.Value) rune
+		if isUint(vt) {
+			get = func(v reflect.Value) rune {
+				if i := v.Uint(); i <= unicode.MaxRune {
+					return rune(i)
+				}
+				return unicode.ReplacementChar
+			}
+		} else {
+			get = func(v reflect.Value) rune {
+				if i := v.Int(); int64(rune(i)) == i {
+					return rune(i)
+				}
+				return unicode.ReplacementChar
+			}
+		}
+		n.exec = func(f *frame) bltn {
+			dest(f).Set(reflect.ValueOf(string(get(value(f)))).Convert(typ))
+			return next
+		}
+		return
+	}
+	if vt.Kind() == reflect.Slice && (typ.Kind() == reflect.Array || typ.Kind() == reflect.Ptr) {
+		// Slice to array or array pointer, not handled by reflect before go1.17.
+		n.exec = func(f *frame) bltn {
+			dest(f).Set(sliceToArray(value(f), typ))
+			return next
+		}
+		return
+	}
+
 	for _, con := range n.interp.hooks.convert {
 		if c.typ.rtype == nil {
 			continue
@@ -394,13 +637,14 @@ func assign(n *node) {
 		switch {
 		case dest.typ.cat == interfaceT:
 			svalue[i] = genValueInterface(src)
-		case (dest.typ.cat == valueT || dest.typ.cat == errorT) && dest.typ.rtype.Kind() == reflect.Interface:
+		case isInterfaceBin(dest.typ):
 			svalue[i] = genInterfaceWrapper(src, dest.typ.rtype)
 		case src.typ.cat == funcT && dest.typ.cat == valueT:
 			svalue[i] = genFunctionWrapper(src)
 		case src.typ.cat == funcT && isField(dest):
 			svalue[i] = genFunctionWrapper(src)
-		case dest.typ.cat == funcT && src.typ.cat == valueT:
+		case dest.typ.cat == funcT && (src.typ.cat == valueT || src.action == aRecv):
+			// A binary function value, or received from a channel.
 			svalue[i] = genValueNode(src)
 		case src.kind == basicLit && src.val == nil:
 			t := dest.typ.TypeOf()
@@ -461,8 +705,9 @@ func assign(n *node) {
 		}
 
 		// To handle swap in multi-assign:
-		// evaluate and copy all values in assign right hand side into temporary
-		// then evaluate assign left hand side and copy temporary into it
+		// evaluate and copy all values in assign right hand side into temporary,
+		// and all the maps and keys of map entries in left hand side, then copy
+		// temporary into left hand side, from left to right.
 		n.exec = func(f *frame) bltn {
 			t := make([]reflect.Value, len(svalue))
 			for i, s := range svalue {
@@ -472,14 +717,24 @@ func assign(n *node) {
 				t[i] = reflect.New(types[i]).Elem()
 				t[i].Set(s(f))
 			}
-			for i, d := range dvalue {
+			d := make([]reflect.Value, len(dvalue))
+			k := make([]reflect.Value, len(dvalue))
+			for i, dv := range dvalue {
 				if n.child[i].ident == "_" {
 					continue
 				}
+				d[i] = dv(f)
 				if j := ivalue[i]; j != nil {
-					d(f).SetMapIndex(j(f), t[i]) // Assign a map entry
-				} else {
-					d(f).Set(t[i]) // Assign a var or array/slice entry
+					k[i] = copyValue(j(f))
+				}
+			}
+			for i, v := range d {
+				switch {
+				case !v.IsValid():
+				case k[i].IsValid():
+					v.SetMapIndex(k[i], t[i]) // Assign a map entry
+				default:
+					v.Set(t[i]) // Assign a var or array/slice entry
 				}
 			}
 			return next
@@ -487,6 +742,29 @@ func assign(n *node) {
 	}
 }
 
+// setMapEntry returns a generator for the in place operation gen on the map
+// entry n.child[0], which stores the result of the operation in the map.
+func setMapEntry(gen bltnGenerator) bltnGenerator {
+	return func(n *node) {
+		gen(n)
+		exec := n.exec
+		c := n.child[0]
+		value0 := genValue(c.child[0]) // map
+		var value1 func(*frame) reflect.Value
+		if c.child[1].typ.cat == interfaceT {
+			value1 = genValueInterface(c.child[1])
+		} else {
+			value1 = genValue(c.child[1])
+		}
+		value := genValue(c)
+		n.exec = func(f *frame) bltn {
+			next := exec(f)
+			value0(f).SetMapIndex(value1(f), value(f))
+			return next
+		}
+	}
+}
+
 func not(n *node) {
 	dest := genValue(n)
 	value := genValue(n.child[0])
@@ -530,7 +808,7 @@ func deref(n *node) {
 	if n.fnext != nil {
 		fnext := getExec(n.fnext)
 		n.exec = func(f *frame) bltn {
-			r := value(f).Elem()
+			r := elem(n, value(f))
 			if r.Bool() {
 				getFrame(f, l).data[i] = r
 				return tnext
@@ -539,58 +817,26 @@ func deref(n *node) {
 		}
 	} else {
 		n.exec = func(f *frame) bltn {
-			getFrame(f, l).data[i] = value(f).Elem()
+			getFrame(f, l).data[i] = elem(n, value(f))
 			return tnext
 		}
 	}
 }
 
-func _print(n *node) {
-	child := n.child[1:]
-	values := make([]func(*frame) reflect.Value, len(child))
-	for i, c := range child {
-		values[i] = genValue(c)
-	}
+func _print(n *node) { genPrint(n, false) }
 
-	genBuiltinDeferWrapper(n, values, nil, func(args []reflect.Value) []reflect.Value {
-		for i, value := range args {
-			if i > 0 {
-				fmt.Printf(" ")
-			}
-			fmt.Printf("%v", value)
-		}
-		return nil
-	})
-}
-
-func _println(n *node) {
-	child := n.child[1:]
-	values := make([]func(*frame) reflect.Value, len(child))
-	for i, c := range child {
-		values[i] = genValue(c)
-	}
-
-	genBuiltinDeferWrapper(n, values, nil, func(args []reflect.Value) []reflect.Value {
-		for i, value := range args {
-			if i > 0 {
-				fmt.Printf(" ")
-			}
-			fmt.Printf("%v", value)
-		}
-		fmt.Println("")
-		return nil
-	})
-}
+func _println(n *node) { genPrint(n, true) }
 
 func _recover(n *node) {
 	tnext := getExec(n.tnext)
 	dest := genValue(n)
 
 	n.exec = func(f *frame) bltn {
-		if f.anc.recovered == nil {
+		if f.anc.recovered == nil || isExit(f.anc.recovered) {
 			dest(f).Set(reflect.ValueOf(valueInterface{}))
 		} else {
-			dest(f).Set(reflect.ValueOf(valueInterface{n, reflect.ValueOf(f.anc.recovered)}))
+			r := panicValue(f.anc.recovered)
+			dest(f).Set(binValueInterface(reflect.ValueOf(&r).Elem()))
 			f.anc.recovered = nil
 		}
 		return tnext
@@ -598,7 +844,7 @@ func _recover(n *node) {
 }
 
 func _panic(n *node) {
-	value := genValue(n.child[1])
+	value := genPanicValue(n)
 
 	n.exec = func(f *frame) bltn {
 		panic(value(f))
@@ -645,6 +891,13 @@ func genBuiltinDeferWrapper(n *node, in, out []func(*frame) reflect.Value, fn fu
 }
 
 func genFunctionWrapper(n *node) func(*frame) reflect.Value {
+	return genFunctionValue(n, true)
+}
+
+// genFunctionValue returns the function value of node n, callable by reflect.
+// If boundary is true, the function may be called from binary code, and its
+// panics are recovered if the PanicToError option is set.
+func genFunctionValue(n *node, boundary bool) func(*frame) reflect.Value {
 	var def *node
 	var ok bool
 
@@ -652,28 +905,61 @@ func genFunctionWrapper(n *node) func(*frame) reflect.Value {
 		return func(f *frame) reflect.Value { return n.rval }
 	}
 	if def, ok = n.val.(*node); !ok {
-		return genValueAsFunctionWrapper(n)
+		return genValueAsFunction(n, boundary)
 	}
-	start := def.child[3].start
 	numRet := len(def.typ.ret)
+	recoverPanic := boundary && def.interp.panicToError
+	untrace := boundary && !recoverPanic && def.interp.panicLocals != 0
 	var rcvr func(*frame) reflect.Value
 
-	if n.recv != nil {
-		if n.recv.node.typ.cat != defRecvType(def).cat {
-			rcvr = genValueRecvIndirect(n)
-		} else {
-			rcvr = genValueRecv(n)
-		}
+	switch {
+	case n.recv == nil:
+	case n.recv.node == nil:
+		// Receiver bound to a method value.
+		rcvr = func(*frame) reflect.Value { return boundRecv(n.recv) }
+	case n.recv.node.typ.fieldSeq(n.recv.index).cat == ptrT && defRecvType(def).cat != ptrT:
+		// The receiver, possibly embedded, is a pointer to the value receiver
+		// of the method. The other mismatches are handled by setRecv.
+		rcvr = genValueRecvIndirect(n)
+	default:
+		rcvr = genValueRecv(n)
 	}
 	funcType := n.typ.TypeOf()
+	var closure func(*frame) reflect.Value
+	if n.kind == funcLit {
+		// The closure context is set when the function literal is evaluated.
+		closure = genValue(n)
+	}
 
 	return func(f *frame) reflect.Value {
+		var live *frame
 		if n.frame != nil { // Use closure context if defined
 			f = n.frame
-		}
-		return reflect.MakeFunc(funcType, func(in []reflect.Value) []reflect.Value {
+		} else if closure != nil {
+			if c, ok := closure(f).Interface().(*node); ok && c != nil && c.frame != nil {
+				live, f = f, c.frame
+			}
+		}
+		return reflect.MakeFunc(funcType, func(in []reflect.Value) (out []reflect.Value) {
+			if live != nil {
+				// A deferred function literal recovers the panics of the
+				// frame where it is evaluated.
+				f.recovered = live.recovered
+				defer func() { live.recovered = f.recovered }()
+			}
+			if recoverPanic {
+				defer func() {
+					if r := recover(); r != nil {
+						out = def.interp.recoverPanic(funcType, r)
+					}
+				}()
+			} else if untrace {
+				defer untracePanic()
+			}
+
 			// Allocate and init local frame. All values to be settable and addressable.
 			fr := newFrame(f, len(def.types), f.runid())
+			fr.setStats(f.stats)
 			d := fr.data
 			for i, t := range def.types {
 				d[i] = reflect.New(t).Elem()
@@ -681,12 +967,7 @@ func genFunctionWrapper(n *node) func(*frame) reflect.Value {
 
 			// Copy method receiver as first argument, if defined
 			if rcvr != nil {
-				src, dest := rcvr(f), d[numRet]
-				if src.Type().Kind() != dest.Type().Kind() {
-					dest.Set(src.Addr())
-				} else {
-					dest.Set(src)
-				}
+				setRecv(n, d[numRet], rcvr(f))
 				d = d[numRet+1:]
 			} else {
 				d = d[numRet:]
@@ -700,13 +981,23 @@ func genFunctionWrapper(n *node) func(*frame) reflect.Value {
 					d[i].Set(reflect.ValueOf(valueInterface{value: arg.Elem()}))
 				case typ.cat == funcT && arg.Kind() == reflect.Func:
 					d[i].Set(reflect.ValueOf(genFunctionNode(arg)))
+				case arg.Type() != d[i].Type() && arg.Kind() == reflect.Ptr && arg.Type().Elem().Kind() == reflect.Interface:
+					// A pointer to a recursive struct, represented as a *interface{}.
+					if !arg.IsNil() && !arg.Elem().IsNil() {
+						d[i].Set(arg.Elem().Elem())
+					}
+				case arg.Type() != d[i].Type() && arg.Kind() == reflect.Interface:
+					// A recursive struct, represented as an interface{}.
+					if !arg.IsNil() {
+						d[i].Set(arg.Elem())
+					}
 				default:
 					d[i].Set(arg)
 				}
 			}
 
 			// Interpreter code execution
-			runCfg(start, fr)
+			runFunc(context.Background(), def, fr)
 
 			result := fr.data[:numRet]
 			for i, r := range result {
@@ -718,6 +1009,12 @@ func genFunctionWrapper(n *node) func(*frame) reflect.Value {
 					result[i] = reflect.New(reflect.TypeOf((*interface{})(nil)).Elem()).Elem()
 					result[i].Set(x)
 				}
+				if out := funcType.Out(i); result[i].Type() != out && out.Kind() == reflect.Ptr && out.Elem().Kind() == reflect.Interface {
+					// A pointer to a recursive struct, represented as a *interface{}.
+					v := reflect.New(out).Elem()
+					toRecursive(v, result[i])
+					result[i] = v
+				}
 			}
 			return result
 		})
@@ -728,15 +1025,67 @@ func genFunctionNode(v reflect.Value) *node {
 	return &node{kind: funcType, action: aNop, rval: v, typ: &itype{cat: valueT, rtype: v.Type()}}
 }
 
+// errorType is the reflect type of the error interface.
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// stringerType is the reflect type of the fmt.Stringer interface.
+var stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+
+// isStringer returns true if the values of the interpreted type t implement
+// fmt.Stringer through an interpreted method, which binary code can not call
+// directly. A String method with a pointer receiver is not in the method set
+// of a non pointer type.
+func isStringer(t *itype) bool {
+	if !t.implements(&itype{cat: valueT, rtype: stringerType}) {
+		return false
+	}
+	m, index := t.lookupMethod("String")
+	if m == nil {
+		// The method is a binary one, as of a pointer to a binary type.
+		rt := t.TypeOf()
+		return rt == nil || !rt.Implements(stringerType)
+	}
+	return !isPtrRecv(m) || t.cat == ptrT || t.fieldSeq(index).cat == ptrT
+}
+
 func genInterfaceWrapper(n *node, typ reflect.Type) func(*frame) reflect.Value {
 	value := genValue(n)
+	if isInterfaceSrc(n.typ) && typ != nil && typ.Kind() == reflect.Interface && typ.NumMethod() > 0 {
+		// The wrapper depends on the dynamic type of the interpreted interface value.
+		return func(f *frame) reflect.Value {
+			vi, _ := value(f).Interface().(valueInterface)
+			if vi.node == nil {
+				return reflect.New(typ).Elem()
+			}
+			return wrapValue(vi.node, func(*frame) reflect.Value { return vi.value }, typ)(f)
+		}
+	}
+	return wrapValue(n, value, typ)
+}
+
+// wrapValue returns a function which wraps the value of node n, as returned
+// by value, in a wrapper implementing the binary interface typ.
+func wrapValue(n *node, value func(*frame) reflect.Value, typ reflect.Type) func(*frame) reflect.Value {
+	if typ != nil && typ.Kind() == reflect.Interface && typ.NumMethod() == 0 && n.typ.cat != valueT &&
+		!isBasicType(n.typ.TypeOf()) && n.typ.implements(&itype{cat: valueT, rtype: errorType}) {
+		// An interpreted error passed as an empty interface is wrapped as an
+		// error, to be handled as such by binary code, i.e. by fmt. Errors of
+		// basic kinds are passed as is, to be formatted by value with verbs
+		// other than %v and %s.
+		typ = errorType
+	}
 	if typ == nil || typ.Kind() != reflect.Interface || typ.NumMethod() == 0 || n.typ.cat == valueT {
 		return value
 	}
 	if nt := n.typ.TypeOf(); nt != nil && nt.Kind() == reflect.Interface {
 		return value
 	}
-	mn := typ.NumMethod()
+	// The exported methods of an interface are sorted first, as the method
+	// fields of the wrapper. The unexported ones are provided by the wrapper.
+	mn := 0
+	for mn < typ.NumMethod() && typ.Method(mn).PkgPath == "" {
+		mn++
+	}
 	names := make([]string, mn)
 	methods := make([]*node, mn)
 	indexes := make([][]int, mn)
@@ -749,16 +1098,51 @@ func genInterfaceWrapper(n *node, typ reflect.Type) func(*frame) reflect.Value {
 		}
 	}
 	wrap := n.interp.getWrapper(typ)
+	if typ == stringerType {
+		// Also format the value for the verbs not using the String method.
+		wrap = reflect.TypeOf(_stringer{})
+	}
+	if wrap == nil {
+		panic(n.cfgErrorf("no wrapper implementing %s", typ))
+	}
+
+	// The interface methods fields of the wrapper follow the embedded provider
+	// of the unexported methods, if any. Fields beyond are the wrapped value,
+	// in IValue, and optional methods, prefixed by "W". The generated wrappers
+	// keep the value with its interpreted type, to be unwrapped by dynamicValue.
+	first := 0
+	for first < wrap.NumField() && wrap.Field(first).Anonymous {
+		first++
+	}
+	keepType := wrap.PkgPath() != selfPath
+	ivalue := -1
+	var optional []int
+	var optMethods []*node
+	var optIndexes [][]int
+	for i := first + mn; i < wrap.NumField(); i++ {
+		switch name := wrap.Field(i).Name; {
+		case name == "IValue":
+			ivalue = i
+		case strings.HasPrefix(name, "W"):
+			if m, index := n.typ.lookupMethod(name[1:]); m != nil {
+				optional = append(optional, i)
+				optMethods = append(optMethods, m)
+				optIndexes = append(optIndexes, index)
+			}
+		}
+	}
 
 	return func(f *frame) reflect.Value {
 		v := value(f)
 		vv := v
 		switch v.Kind() {
-		case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Ptr, reflect.Slice:
+		case reflect.Interface:
 			if v.IsNil() {
 				return reflect.New(typ).Elem()
 			}
-			if v.Kind() == reflect.Ptr {
+		case reflect.Ptr:
+			// A nil pointer is wrapped in a non-nil interface, as in Go.
+			if !v.IsNil() {
 				vv = v.Elem()
 			}
 		}
@@ -766,12 +1150,12 @@ func genInterfaceWrapper(n *node, typ reflect.Type) func(*frame) reflect.Value {
 		for i, m := range methods {
 			if m == nil {
 				if r := v.MethodByName(names[i]); r.IsValid() {
-					w.Field(i).Set(r)
+					w.Field(first + i).Set(r)
 					continue
 				}
 				o := vv.FieldByIndex(indexes[i])
 				if r := o.MethodByName(names[i]); r.IsValid() {
-					w.Field(i).Set(r)
+					w.Field(first + i).Set(r)
 				} else {
 					log.Println(n.cfgErrorf("genInterfaceWrapper error, no method %s", names[i]))
 				}
@@ -779,9 +1163,22 @@ func genInterfaceWrapper(n *node, typ reflect.Type) func(*frame) reflect.Value {
 			}
 			nod := *m
 			nod.recv = &receiver{n, v, indexes[i]}
+			w.Field(first + i).Set(genFunctionWrapper(&nod)(f))
+		}
+		switch {
+		case ivalue < 0:
+		case keepType:
+			w.Field(ivalue).Set(reflect.ValueOf(valueInterface{n, v}))
+		default:
+			w.Field(ivalue).Set(v)
+		}
+		for j, i := range optional {
+			nod := *optMethods[j]
+			nod.recv = &receiver{n, v, optIndexes[j]}
 			w.Field(i).Set(genFunctionWrapper(&nod)(f))
 		}
-		return w
+		// A pointer to the wrapper is comparable, as an interface value.
+		return w.Addr()
 	}
 }
 
@@ -819,7 +1216,7 @@ func call(n *node) {
 				ind := c.findex + j
 				values = append(values, func(f *frame) reflect.Value { return f.data[ind] })
 			}
-		case isRegularCall(c):
+		case isRegularCall(c) && len(c.child[0].typ.ret) > 1:
 			// Arguments are return values of a nested function call.
 			for j := range c.child[0].typ.ret {
 				ind := c.findex + j
@@ -839,6 +1236,8 @@ func call(n *node) {
 			switch {
 			case arg.cat == interfaceT:
 				values = append(values, genValueInterface(c))
+			case isInterfaceBin(arg):
+				values = append(values, genInterfaceWrapper(c, arg.TypeOf()))
 			case isRecursiveType(c.typ, c.typ.rtype):
 				values = append(values, genValueRecursiveInterfacePtrValue(c))
 			default:
@@ -851,6 +1250,9 @@ func call(n *node) {
 	rtypes := n.child[0].typ.ret
 	rvalues := make([]func(*frame) reflect.Value, len(rtypes))
 	switch n.anc.kind {
+	case goStmt:
+		// The results of a goroutine are discarded, in its own frame storage,
+		// not shared with the caller nor with the other goroutines.
 	case defineXStmt, assignXStmt:
 		for i := range rvalues {
 			c := n.anc.child[i]
@@ -891,15 +1293,19 @@ func call(n *node) {
 	}
 
 	if n.anc.kind == deferStmt {
-		// Store function call in frame for deferred execution.
-		value = genFunctionWrapper(n.child[0])
+		// Store function call in frame for deferred execution. The deferred
+		// function is called by the interpreter, its panics are not recovered.
+		value = genFunctionValue(n.child[0], false)
 		if method {
 			// The receiver is already passed in the function wrapper, skip it.
 			values = values[1:]
 		}
 		n.exec = func(f *frame) bltn {
 			val := make([]reflect.Value, len(values)+1)
-			val[0] = value(f)
+			if val[0] = value(f); val[0].IsNil() {
+				// As in Go, the call of a nil function panics when run.
+				val[0] = nilFunc(n, val[0].Type())
+			}
 			for i, v := range values {
 				val[i+1] = v(f)
 			}
@@ -914,17 +1320,31 @@ func call(n *node) {
 		var ok bool
 		bf := value(f)
 		if def, ok = bf.Interface().(*node); ok {
+			if def == nil {
+				panic(nilDeref(n))
+			}
 			bf = def.rval
 		}
 
 		// Call bin func if defined
 		if bf.IsValid() {
-			in := make([]reflect.Value, len(values))
-			for i, v := range values {
-				in[i] = v(f)
+			if bf.IsNil() {
+				panic(nilDeref(n))
+			}
+			in := make([]reflect.Value, 0, len(values))
+			for _, v := range values {
+				if v != nil { // The receiver is already bound to the binary method.
+					in = append(in, v(f))
+				}
 			}
 			if goroutine {
-				go bf.Call(in)
+				for i, v := range in {
+					in[i] = copyValue(v)
+				}
+				go func() {
+					defer recoverExit()
+					bf.Call(in)
+				}()
 				return tnext
 			}
 			out := bf.Call(in)
@@ -945,6 +1365,7 @@ func call(n *node) {
 			anc = def.frame
 		}
 		nf := newFrame(anc, len(def.types), anc.runid())
+		nf.setStats(f.stats)
 		var vararg reflect.Value
 
 		// Init return values
@@ -961,9 +1382,12 @@ func call(n *node) {
 			nf.data[numRet+i] = reflect.New(t).Elem()
 		}
 
+		// A method value holds its receiver, set before the arguments.
+		bound := !method && def.recv != nil
+
 		// Init variadic argument vector
 		if variadic >= 0 {
-			if method {
+			if method || bound {
 				vararg = nf.data[numRet+variadic+1]
 			} else {
 				vararg = nf.data[numRet+variadic]
@@ -972,33 +1396,18 @@ func call(n *node) {
 
 		// Copy input parameters from caller
 		if dest := nf.data[numRet:]; len(dest) > 0 {
+			if bound {
+				setRecv(n, dest[0], boundRecv(def.recv))
+				dest = dest[1:]
+			}
 			for i, v := range values {
 				switch {
 				case method && i == 0:
 					// compute receiver
-					var src reflect.Value
 					if v == nil {
-						src = def.recv.val
-						if len(def.recv.index) > 0 {
-							if src.Kind() == reflect.Ptr {
-								src = src.Elem().FieldByIndex(def.recv.index)
-							} else {
-								src = src.FieldByIndex(def.recv.index)
-							}
-						}
-					} else {
-						src = v(f)
-					}
-					// Accommodate to receiver type
-					d := dest[0]
-					if ks, kd := src.Kind(), d.Kind(); ks != kd {
-						if kd == reflect.Ptr {
-							d.Set(src.Addr())
-						} else {
-							d.Set(src.Elem())
-						}
+						setRecv(n, dest[0], boundRecv(def.recv))
 					} else {
-						d.Set(src)
+						setRecv(n, dest[0], v(f))
 					}
 				case variadic >= 0 && i >= variadic:
 					if v(f).Type() == vararg.Type() {
@@ -1017,10 +1426,22 @@ func call(n *node) {
 
 		// Execute function body
 		if goroutine {
-			go runCfg(def.child[3].start, nf)
+			if s := nf.stats; s != nil {
+				s.startGoroutine()
+				go func() {
+					defer s.endGoroutine()
+					defer recoverExit()
+					runFunc(f.labels, def, nf)
+				}()
+				return tnext
+			}
+			go func() {
+				defer recoverExit()
+				runFunc(f.labels, def, nf)
+			}()
 			return tnext
 		}
-		runCfg(def.child[3].start, nf)
+		runFunc(f.labels, def, nf)
 
 		// Handle branching according to boolean result
 		if fnext != nil && !nf.data[0].Bool() {
@@ -1030,6 +1451,39 @@ func call(n *node) {
 	}
 }
 
+// boundRecv returns the receiver value bound to a method value.
+func boundRecv(r *receiver) reflect.Value {
+	src := r.val
+	if len(r.index) > 0 {
+		if src.Kind() == reflect.Ptr {
+			src = src.Elem().FieldByIndex(r.index)
+		} else {
+			src = src.FieldByIndex(r.index)
+		}
+	}
+	return src
+}
+
+// setRecv sets the receiver d of a method call to src, accommodated to
+// the receiver type. The dereference of a nil pointer is a runtime error of
+// the call node n.
+func setRecv(n *node, d, src reflect.Value) {
+	if !src.IsValid() && d.Kind() == reflect.Ptr {
+		// A nil pointer receiver of a recursive type.
+		d.Set(reflect.Zero(d.Type()))
+		return
+	}
+	if ks, kd := src.Kind(), d.Kind(); ks != kd {
+		if kd == reflect.Ptr {
+			d.Set(src.Addr())
+		} else {
+			d.Set(elem(n, src))
+		}
+	} else {
+		d.Set(src)
+	}
+}
+
 // pindex returns definition parameter index for function call.
 func pindex(i, variadic int) int {
 	if variadic < 0 || i <= variadic {
@@ -1054,33 +1508,66 @@ func getFrame(f *frame, l int) *frame {
 }
 
 // Callbin calls a function from a bin import, accessible through reflect.
+// binRcvrOffset returns 1 if the signature of the binary function called
+// by n includes the method receiver as first argument, 0 otherwise. A method
+// signature obtained from reflect.Type includes receiver as 1st arg, except
+// for interface types.
+func binRcvrOffset(n *node) int {
+	funcType := n.child[0].typ.rtype
+	if recv := n.child[0].recv; recv != nil && !isInterface(recv.node.typ) {
+		if funcType.IsVariadic() || funcType.NumIn() > len(n.child)-1 {
+			return 1
+		}
+	}
+	return 0
+}
+
 func callBin(n *node) {
+	if callBits(n) {
+		return
+	}
 	tnext := getExec(n.tnext)
 	fnext := getExec(n.fnext)
 	child := n.child[1:]
 	value := genValue(n.child[0])
 	var values []func(*frame) reflect.Value
 	funcType := n.child[0].typ.rtype
+	rcvrOffset := binRcvrOffset(n)
+	// Position of the variadic parameter in call arguments, excluding receiver.
 	variadic := -1
 	if funcType.IsVariadic() {
-		variadic = funcType.NumIn() - 1
-	}
-	// A method signature obtained from reflect.Type includes receiver as 1st arg, except for interface types.
-	rcvrOffset := 0
-	if recv := n.child[0].recv; recv != nil && !isInterface(recv.node.typ) {
-		if funcType.NumIn() > len(child) {
-			rcvrOffset = 1
-		}
+		variadic = funcType.NumIn() - 1 - rcvrOffset
 	}
 
 	// Determine if we should use `Call` or `CallSlice` on the function Value.
-	callFn := func(v reflect.Value, in []reflect.Value) []reflect.Value { return v.Call(in) }
+	callFn := func(v reflect.Value, in []reflect.Value) []reflect.Value {
+		if v.IsNil() {
+			panic(nilDeref(n))
+		}
+		return v.Call(in)
+	}
 	if n.action == aCallSlice {
-		callFn = func(v reflect.Value, in []reflect.Value) []reflect.Value { return v.CallSlice(in) }
+		callFn = func(v reflect.Value, in []reflect.Value) []reflect.Value {
+			if v.IsNil() {
+				panic(nilDeref(n))
+			}
+			return v.CallSlice(in)
+		}
+	}
+	if n.interp.panicToError {
+		call := callFn
+		callFn = func(v reflect.Value, in []reflect.Value) []reflect.Value {
+			defer markHostPanic()
+			return call(v, in)
+		}
 	}
 
 	for i, c := range child {
 		defType := funcType.In(rcvrOffset + pindex(i, variadic))
+		if variadic >= 0 && i >= variadic && n.action != aCallSlice {
+			// Variadic arguments are elements of the last parameter.
+			defType = defType.Elem()
+		}
 		switch {
 		case isBinCall(c):
 			// Handle nested function calls: pass returned values as arguments
@@ -1089,7 +1576,7 @@ func callBin(n *node) {
 				ind := c.findex + j
 				values = append(values, func(f *frame) reflect.Value { return f.data[ind] })
 			}
-		case isRegularCall(c):
+		case isRegularCall(c) && len(c.child[0].typ.ret) > 1:
 			// Handle nested function calls: pass returned values as arguments
 			for j := range c.child[0].typ.ret {
 				ind := c.findex + j
@@ -1100,7 +1587,7 @@ func callBin(n *node) {
 				// Convert literal value (untyped) to function argument type (if not an interface{})
 				var argType reflect.Type
 				if variadic >= 0 && i >= variadic {
-					argType = funcType.In(variadic).Elem()
+					argType = funcType.In(variadic + rcvrOffset).Elem()
 				} else {
 					argType = funcType.In(i + rcvrOffset)
 				}
@@ -1109,10 +1596,31 @@ func callBin(n *node) {
 					c.val = reflect.Zero(argType)
 				}
 			}
+			if variadic >= 0 && i >= variadic && defType == interf && c.typ.cat != valueT && !isInterface(c.typ) && isStringer(c.typ) {
+				// An interpreted fmt.Stringer in the ...interface{} arguments of a
+				// binary function, i.e. of package fmt, is passed with its String
+				// method. It is passed as is to the other functions, as json.Marshal.
+				values = append(values, genInterfaceWrapper(c, stringerType))
+				break
+			}
+			if variadic >= 0 && i >= variadic && defType == interf && c.typ.cat != valueT && (isInterfaceSrc(c.typ) || isFormatted(c.typ)) {
+				// Likewise, an interpreted value which fmt can not print from
+				// its reflect representation is passed with a formatter.
+				var format func(*frame) reflect.Value
+				if variadic > 0 && funcType.In(rcvrOffset+variadic-1).Kind() == reflect.String {
+					format = values[variadic-1]
+				}
+				values = append(values, genFormatter(c, format, i-variadic))
+				break
+			}
 			switch c.typ.cat {
 			case funcT:
 				values = append(values, genFunctionWrapper(c))
 			case interfaceT:
+				if defType.Kind() == reflect.Interface && defType.NumMethod() > 0 {
+					values = append(values, genInterfaceWrapper(c, defType))
+					break
+				}
 				values = append(values, genValueInterfaceValue(c))
 			case arrayT, variadicT:
 				switch c.typ.val.cat {
@@ -1121,6 +1629,13 @@ func callBin(n *node) {
 				default:
 					values = append(values, genInterfaceWrapper(c, defType))
 				}
+			case ptrT:
+				if defType.Kind() == reflect.Ptr && defType.Elem().Kind() == reflect.Interface && c.typ.val.cat == structT {
+					// A pointer to a recursive struct, represented as a *interface{}.
+					values = append(values, genValueRecursiveInterface(c, defType))
+					break
+				}
+				values = append(values, genInterfaceWrapper(c, defType))
 			default:
 				values = append(values, genInterfaceWrapper(c, defType))
 			}
@@ -1133,7 +1648,9 @@ func callBin(n *node) {
 		// Store function call in frame for deferred execution.
 		n.exec = func(f *frame) bltn {
 			val := make([]reflect.Value, l+1)
-			val[0] = value(f)
+			if val[0] = value(f); val[0].IsNil() {
+				val[0] = nilFunc(n, val[0].Type())
+			}
 			for i, v := range values {
 				val[i+1] = v(f)
 			}
@@ -1141,13 +1658,22 @@ func callBin(n *node) {
 			return tnext
 		}
 	case n.anc.kind == goStmt:
-		// Execute function in a goroutine, discard results.
+		// Execute function in a goroutine, discard results. The function
+		// value and the arguments are evaluated and copied before the
+		// goroutine starts.
 		n.exec = func(f *frame) bltn {
+			fn := value(f)
+			if fn.IsNil() {
+				panic(nilDeref(n))
+			}
 			in := make([]reflect.Value, l)
 			for i, v := range values {
-				in[i] = v(f)
+				in[i] = copyValue(v(f))
 			}
-			go callFn(value(f), in)
+			go func() {
+				defer recoverExit()
+				callFn(fn, in)
+			}()
 			return tnext
 		}
 	case fnext != nil:
@@ -1175,10 +1701,12 @@ func callBin(n *node) {
 			// The optimization of aAssign is handled in assign(), and should not
 			// be handled here.
 			rvalues := make([]func(*frame) reflect.Value, funcType.NumOut())
+			boxed := make([]bool, len(rvalues))
 			for i := range rvalues {
 				c := n.anc.child[i]
 				if c.ident != "_" {
 					rvalues[i] = genValue(c)
+					boxed[i] = isInterfaceSrc(c.typ)
 				}
 			}
 			n.exec = func(f *frame) bltn {
@@ -1188,8 +1716,13 @@ func callBin(n *node) {
 				}
 				out := callFn(value(f), in)
 				for i, v := range rvalues {
-					if v != nil {
-						v(f).Set(out[i])
+					switch {
+					case v == nil:
+					case boxed[i]:
+						v(f).Set(binValueInterface(out[i]))
+					default:
+						d := v(f)
+						d.Set(recursiveValue(d.Type(), out[i]))
 					}
 				}
 				return tnext
@@ -1198,6 +1731,7 @@ func callBin(n *node) {
 			// The function call is part of a return statement, store output results
 			// directly in the frame location of outputs of the current function.
 			b := childPos(n)
+			ret := n.anc.val.(*node).typ.ret
 			n.exec = func(f *frame) bltn {
 				in := make([]reflect.Value, l)
 				for i, v := range values {
@@ -1205,7 +1739,10 @@ func callBin(n *node) {
 				}
 				out := callFn(value(f), in)
 				for i, v := range out {
-					f.data[b+i].Set(v)
+					if isInterfaceSrc(ret[b+i]) {
+						v = binValueInterface(v)
+					}
+					f.data[b+i].Set(recursiveValue(f.data[b+i].Type(), v))
 				}
 				return tnext
 			}
@@ -1217,7 +1754,8 @@ func callBin(n *node) {
 				}
 				out := callFn(value(f), in)
 				for i := 0; i < len(out); i++ {
-					getFrame(f, n.level).data[n.findex+i].Set(out[i])
+					d := getFrame(f, n.level).data[n.findex+i]
+					d.Set(recursiveValue(d.Type(), out[i]))
 				}
 				return tnext
 			}
@@ -1233,10 +1771,23 @@ func getIndexBinMethod(n *node) {
 	value := genValue(n.child[0])
 	next := getExec(n.tnext)
 
+	if !isCallee(n) || n.anc.anc.kind == goStmt {
+		// Method value, or method called in a goroutine: the receiver is
+		// evaluated and copied at creation.
+		n.exec = func(f *frame) bltn {
+			getFrame(f, l).data[i] = copyValue(value(f)).Method(m)
+			return next
+		}
+		return
+	}
 	n.exec = func(f *frame) bltn {
+		v := value(f)
+		if v.Kind() == reflect.Interface && v.IsNil() {
+			panic(nilDeref(n))
+		}
 		// Can not use .Set() because dest type contains the receiver and source not
 		// dest(f).Set(value(f).Method(m))
-		getFrame(f, l).data[i] = value(f).Method(m)
+		getFrame(f, l).data[i] = v.Method(m)
 		return next
 	}
 }
@@ -1396,91 +1947,42 @@ func getIndexMap(n *node) {
 
 // getIndexMap2 retrieves map value from index and set status.
 func getIndexMap2(n *node) {
-	dest := genValue(n.anc.child[0])   // result
-	value0 := genValue(n.child[0])     // map
-	value2 := genValue(n.anc.child[1]) // status
+	value0 := genValue(n.child[0]) // map
+	setValue := genValueCommaOk(n.anc.child[0], n)
+	setStatus := genStatusCommaOk(n.anc.child[1])
 	next := getExec(n.tnext)
-	typ := n.anc.child[0].typ
-	doValue := n.anc.child[0].ident != "_"
-	doStatus := n.anc.child[1].ident != "_"
 
-	if !doValue && !doStatus {
+	if setValue == nil && setStatus == nil {
 		nop(n)
 		return
 	}
-	if n.child[1].rval.IsValid() { // constant map index
+	var value1 func(*frame) reflect.Value // map index
+	if n.child[1].rval.IsValid() {
+		// constant map index
 		convertConstantValue(n.child[1])
 		mi := n.child[1].rval
-		switch {
-		case !doValue:
-			n.exec = func(f *frame) bltn {
-				v := value0(f).MapIndex(mi)
-				value2(f).SetBool(v.IsValid())
-				return next
-			}
-		case typ.cat == interfaceT:
-			n.exec = func(f *frame) bltn {
-				v := value0(f).MapIndex(mi)
-				if v.IsValid() {
-					if e := v.Elem(); e.Type().AssignableTo(valueInterfaceType) {
-						dest(f).Set(e)
-					} else {
-						dest(f).Set(reflect.ValueOf(valueInterface{n, e}))
-					}
-				}
-				if doStatus {
-					value2(f).SetBool(v.IsValid())
-				}
-				return next
-			}
-		default:
-			n.exec = func(f *frame) bltn {
-				v := value0(f).MapIndex(mi)
-				if v.IsValid() {
-					dest(f).Set(v)
-				}
-				if doStatus {
-					value2(f).SetBool(v.IsValid())
-				}
-				return next
-			}
-		}
+		value1 = func(*frame) reflect.Value { return mi }
 	} else {
-		value1 := genValue(n.child[1]) // map index
-		switch {
-		case !doValue:
-			n.exec = func(f *frame) bltn {
-				v := value0(f).MapIndex(value1(f))
-				value2(f).SetBool(v.IsValid())
-				return next
-			}
-		case typ.cat == interfaceT:
-			n.exec = func(f *frame) bltn {
-				v := value0(f).MapIndex(value1(f))
-				if v.IsValid() {
-					if e := v.Elem(); e.Type().AssignableTo(valueInterfaceType) {
-						dest(f).Set(e)
-					} else {
-						dest(f).Set(reflect.ValueOf(valueInterface{n, e}))
-					}
-				}
-				if doStatus {
-					value2(f).SetBool(v.IsValid())
-				}
-				return next
-			}
-		default:
-			n.exec = func(f *frame) bltn {
-				v := value0(f).MapIndex(value1(f))
-				if v.IsValid() {
-					dest(f).Set(v)
-				}
-				if doStatus {
-					value2(f).SetBool(v.IsValid())
+		value1 = genValue(n.child[1])
+	}
+	iface := n.typ.cat == interfaceT && n.anc.child[0].typ.cat == interfaceT
+
+	n.exec = func(f *frame) bltn {
+		v := value0(f).MapIndex(value1(f))
+		if setValue != nil {
+			if iface && v.IsValid() {
+				if e := v.Elem(); e.Type().AssignableTo(valueInterfaceType) {
+					v = e
+				} else {
+					v = reflect.ValueOf(valueInterface{n, e})
 				}
-				return next
 			}
+			setValue(f, v)
 		}
+		if setStatus != nil {
+			setStatus(f, v.IsValid())
+		}
+		return next
 	}
 }
 
@@ -1503,6 +2005,37 @@ func getMethod(n *node) {
 	l := n.level
 	next := getExec(n.tnext)
 
+	if !isCallee(n) {
+		// Method value: the receiver is evaluated at creation, and bound to
+		// the method as a copy for a value receiver, or as the address of the
+		// operand for a pointer receiver.
+		var value func(*frame) reflect.Value
+		if isRecursiveType(n.recv.node.typ, n.recv.node.typ.rtype) {
+			value = genValueRecvInterfacePtr(n)
+		} else {
+			value = genValueRecv(n)
+		}
+		def := n.val.(*node)
+		rtype := defRecvType(def).TypeOf()
+		n.exec = func(f *frame) bltn {
+			r := value(f)
+			switch {
+			case r.Kind() == rtype.Kind():
+				r = copyValue(r)
+			case rtype.Kind() == reflect.Ptr:
+				r = r.Addr()
+			default:
+				r = copyValue(r.Elem())
+			}
+			nod := *def
+			nod.val = &nod
+			nod.recv = &receiver{val: r}
+			nod.frame = f.clone()
+			getFrame(f, l).data[i] = reflect.ValueOf(&nod)
+			return next
+		}
+		return
+	}
 	n.exec = func(f *frame) bltn {
 		fr := f.clone()
 		nod := *(n.val.(*node))
@@ -1517,13 +2050,55 @@ func getMethod(n *node) {
 func getMethodByName(n *node) {
 	next := getExec(n.tnext)
 	value0 := genValue(n.child[0])
+	if index, ok := n.val.([]int); ok && !isInterfaceSrc(n.child[0].typ) {
+		// The method of an interface embedded in a struct.
+		v := value0
+		value0 = func(f *frame) reflect.Value { return fieldByIndex(n, reflect.Indirect(v(f)), index) }
+	}
 	name := n.child[1].ident
 	i := n.findex
 	l := n.level
 
 	n.exec = func(f *frame) bltn {
 		val := value0(f).Interface().(valueInterface)
+		if val.node == nil {
+			// The method of a nil interface value.
+			panic(nilDeref(n))
+		}
 		m, li := val.node.typ.lookupMethod(name)
+		for m == nil {
+			// The method may be promoted from an interface embedded in the
+			// dynamic type: the method of the interface field value is used.
+			index := val.node.typ.lookupField(name)
+			k := embeddedInterface(val.node.typ, index)
+			if k == 0 {
+				break
+			}
+			vi, ok := fieldByIndex(n, reflect.Indirect(val.value), index[:k]).Interface().(valueInterface)
+			if !ok || vi.node == nil {
+				break
+			}
+			val = vi
+			m, li = val.node.typ.lookupMethod(name)
+		}
+		if m == nil {
+			// The dynamic value is of a binary type, i.e. asserted from a
+			// binary interface, or the method is promoted from an embedded
+			// binary field.
+			v := val.value.MethodByName(name)
+			if !v.IsValid() {
+				if _, index, _, ok := val.node.typ.lookupBinMethod(name); ok {
+					fv := reflect.Indirect(val.value).FieldByIndex(index)
+					if v = fv.MethodByName(name); !v.IsValid() && fv.CanAddr() {
+						// A pointer receiver method of an embedded field, i.e.
+						// sync.WaitGroup.Done, applied to the field itself.
+						v = fv.Addr().MethodByName(name)
+					}
+				}
+			}
+			getFrame(f, l).data[i] = reflect.ValueOf(genFunctionNode(v))
+			return next
+		}
 		fr := f.clone()
 		nod := *m
 		nod.val = &nod
@@ -1558,7 +2133,7 @@ func getIndexSeq(n *node) {
 			if v.Type().Kind() == reflect.Interface && n.child[0].typ.recursive {
 				v = writableDeref(v)
 			}
-			r := v.FieldByIndex(index)
+			r := fieldByIndex(n, v, index)
 			getFrame(f, l).data[i] = r
 			if r.Bool() {
 				return tnext
@@ -1571,7 +2146,7 @@ func getIndexSeq(n *node) {
 			if v.Type().Kind() == reflect.Interface && n.child[0].typ.recursive {
 				v = writableDeref(v)
 			}
-			getFrame(f, l).data[i] = v.FieldByIndex(index)
+			getFrame(f, l).data[i] = fieldByIndex(n, v, index)
 			return tnext
 		}
 	}
@@ -1591,7 +2166,13 @@ func getPtrIndexSeq(n *node) {
 	var value func(*frame) reflect.Value
 	if isRecursiveType(n.child[0].typ, n.child[0].typ.rtype) {
 		v := genValue(n.child[0])
-		value = func(f *frame) reflect.Value { return v(f).Elem().Elem() }
+		value = func(f *frame) reflect.Value {
+			// A nil pointer to a recursive type is left to elem, to report the error.
+			if r := v(f); r.IsValid() && !r.IsNil() {
+				return r.Elem().Elem()
+			}
+			return reflect.Value{}
+		}
 	} else {
 		value = genValue(n.child[0])
 	}
@@ -1601,7 +2182,7 @@ func getPtrIndexSeq(n *node) {
 	if n.fnext != nil {
 		fnext := getExec(n.fnext)
 		n.exec = func(f *frame) bltn {
-			r := value(f).Elem().FieldByIndex(index)
+			r := fieldByIndex(n, elem(n, value(f)), index)
 			getFrame(f, l).data[i] = r
 			if r.Bool() {
 				return tnext
@@ -1610,12 +2191,40 @@ func getPtrIndexSeq(n *node) {
 		}
 	} else {
 		n.exec = func(f *frame) bltn {
-			getFrame(f, l).data[i] = value(f).Elem().FieldByIndex(index)
+			getFrame(f, l).data[i] = fieldByIndex(n, elem(n, value(f)), index)
 			return tnext
 		}
 	}
 }
 
+// elem returns the value pointed to by v, where the dereference of a nil
+// pointer is a runtime error of n.
+func elem(n *node, v reflect.Value) reflect.Value {
+	if !v.IsValid() || v.IsNil() {
+		panic(nilDeref(n))
+	}
+	return v.Elem()
+}
+
+// nilDeref returns the runtime error of the dereference of a nil pointer, or
+// of the call of a nil function or of a method of a nil interface, by n.
+func nilDeref(n *node) error {
+	return n.cfgErrorf("runtime error: invalid memory address or nil pointer dereference")
+}
+
+// fieldByIndex returns the nested field of v corresponding to index, as
+// reflect.Value.FieldByIndex, where the dereference of a nil embedded pointer
+// is a runtime error of n.
+func fieldByIndex(n *node, v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			v = elem(n, v)
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
 func getIndexSeqField(n *node) {
 	value := genValue(n.child[0])
 	index := n.val.([]int)
@@ -1627,7 +2236,7 @@ func getIndexSeqField(n *node) {
 		fnext := getExec(n.fnext)
 		if n.child[0].typ.TypeOf().Kind() == reflect.Ptr {
 			n.exec = func(f *frame) bltn {
-				r := value(f).Elem().FieldByIndex(index)
+				r := fieldByIndex(n, elem(n, value(f)), index)
 				getFrame(f, l).data[i] = r
 				if r.Bool() {
 					return tnext
@@ -1636,7 +2245,7 @@ func getIndexSeqField(n *node) {
 			}
 		} else {
 			n.exec = func(f *frame) bltn {
-				r := value(f).FieldByIndex(index)
+				r := fieldByIndex(n, value(f), index)
 				getFrame(f, l).data[i] = r
 				if r.Bool() {
 					return tnext
@@ -1647,12 +2256,12 @@ func getIndexSeqField(n *node) {
 	} else {
 		if n.child[0].typ.TypeOf().Kind() == reflect.Ptr {
 			n.exec = func(f *frame) bltn {
-				getFrame(f, l).data[i] = value(f).Elem().FieldByIndex(index)
+				getFrame(f, l).data[i] = fieldByIndex(n, elem(n, value(f)), index)
 				return tnext
 			}
 		} else {
 			n.exec = func(f *frame) bltn {
-				getFrame(f, l).data[i] = value(f).FieldByIndex(index)
+				getFrame(f, l).data[i] = fieldByIndex(n, value(f), index)
 				return tnext
 			}
 		}
@@ -1670,12 +2279,12 @@ func getIndexSeqPtrMethod(n *node) {
 
 	if n.child[0].typ.TypeOf().Kind() == reflect.Ptr {
 		n.exec = func(f *frame) bltn {
-			getFrame(f, l).data[i] = value(f).Elem().FieldByIndex(fi).Addr().Method(mi)
+			getFrame(f, l).data[i] = fieldByIndex(n, elem(n, value(f)), fi).Addr().Method(mi)
 			return next
 		}
 	} else {
 		n.exec = func(f *frame) bltn {
-			getFrame(f, l).data[i] = value(f).FieldByIndex(fi).Addr().Method(mi)
+			getFrame(f, l).data[i] = fieldByIndex(n, value(f), fi).Addr().Method(mi)
 			return next
 		}
 	}
@@ -1690,19 +2299,32 @@ func getIndexSeqMethod(n *node) {
 	l := n.level
 	next := getExec(n.tnext)
 
+	// For a method value, or a method called in a goroutine, the receiver
+	// is evaluated and copied at creation.
+	recv := func(v reflect.Value) reflect.Value { return v }
+	if !isCallee(n) || n.anc.anc.kind == goStmt {
+		recv = copyValue
+	}
 	if n.child[0].typ.TypeOf().Kind() == reflect.Ptr {
 		n.exec = func(f *frame) bltn {
-			getFrame(f, l).data[i] = value(f).Elem().FieldByIndex(fi).Method(mi)
+			getFrame(f, l).data[i] = recv(fieldByIndex(n, elem(n, value(f)), fi)).Method(mi)
 			return next
 		}
 	} else {
 		n.exec = func(f *frame) bltn {
-			getFrame(f, l).data[i] = value(f).FieldByIndex(fi).Method(mi)
+			getFrame(f, l).data[i] = recv(fieldByIndex(n, value(f), fi)).Method(mi)
 			return next
 		}
 	}
 }
 
+// copyValue returns a copy of v, not affected by later assignments to v.
+func copyValue(v reflect.Value) reflect.Value {
+	c := reflect.New(v.Type()).Elem()
+	c.Set(v)
+	return c
+}
+
 func neg(n *node) {
 	dest := genValue(n)
 	value := genValue(n.child[0])
@@ -1714,6 +2336,11 @@ func neg(n *node) {
 			dest(f).SetInt(-value(f).Int())
 			return next
 		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n.exec = func(f *frame) bltn {
+			dest(f).SetUint(-value(f).Uint())
+			return next
+		}
 	case reflect.Float32, reflect.Float64:
 		n.exec = func(f *frame) bltn {
 			dest(f).SetFloat(-value(f).Float())
@@ -1814,6 +2441,32 @@ func nop(n *node) {
 	}
 }
 
+// renew allocates anew the variables of a loop, with their current values, at
+// the end of an iteration. The frame data is copied, so the closures created
+// in the iteration, which share the previous frame data, keep the variables
+// of their iteration.
+func renew(n *node) {
+	next := getExec(n.tnext)
+	vars := n.val.([]*node)
+	index := make([]int, len(vars))
+	for i, c := range vars {
+		index[i] = c.findex
+	}
+
+	n.exec = func(f *frame) bltn {
+		data := make([]reflect.Value, len(f.data))
+		copy(data, f.data)
+		for _, i := range index {
+			if v := data[i]; v.IsValid() {
+				data[i] = reflect.New(v.Type()).Elem()
+				data[i].Set(v)
+			}
+		}
+		f.data = data
+		return next
+	}
+}
+
 func branch(n *node) {
 	tnext := getExec(n.tnext)
 	fnext := getExec(n.fnext)
@@ -1852,9 +2505,12 @@ func _return(n *node) {
 			}
 			fallthrough
 		default:
-			if c.typ.untyped {
+			switch {
+			case c.typ.untyped:
 				values[i] = genValueAs(c, def.typ.ret[i].TypeOf())
-			} else {
+			case isRecursiveType(c.typ, c.typ.rtype):
+				values[i] = genValueRecursiveInterfacePtrValue(c)
+			default:
 				values[i] = genValue(c)
 			}
 		}
@@ -1864,7 +2520,7 @@ func _return(n *node) {
 	case 0:
 		n.exec = nil
 	case 1:
-		if child[0].kind == binaryExpr || isCall(child[0]) {
+		if (child[0].kind == binaryExpr || isCall(child[0])) && !child[0].rval.IsValid() && isDirectReturn(child[0], def) {
 			n.exec = nil
 		} else {
 			v := values[0]
@@ -1906,19 +2562,11 @@ func arrayLit(n *node) {
 	for i, c := range child {
 		if c.kind == keyValueExpr {
 			convertLiteralValue(c.child[1], rtype)
-			if n.typ.val.cat == interfaceT {
-				values[i] = genValueInterface(c.child[1])
-			} else {
-				values[i] = genValue(c.child[1])
-			}
+			values[i] = genDestValue(n.typ.val, c.child[1])
 			index[i] = int(vInt(c.child[0].rval))
 		} else {
 			convertLiteralValue(c, rtype)
-			if n.typ.val.cat == interfaceT {
-				values[i] = genValueInterface(c)
-			} else {
-				values[i] = genValue(c)
-			}
+			values[i] = genDestValue(n.typ.val, c)
 			index[i] = prev
 		}
 		prev = index[i] + 1
@@ -1928,6 +2576,7 @@ func arrayLit(n *node) {
 	}
 
 	typ := n.typ.frameType()
+	destInterface := destType(n).cat == interfaceT
 	n.exec = func(f *frame) bltn {
 		var a reflect.Value
 		if n.typ.sizedef {
@@ -1938,7 +2587,11 @@ func arrayLit(n *node) {
 		for i, v := range values {
 			a.Index(index[i]).Set(v(f))
 		}
-		value(f).Set(a)
+		if destInterface {
+			value(f).Set(reflect.ValueOf(valueInterface{n, a}))
+			return next
+		}
+		setComposite(value(f), a)
 		return next
 	}
 }
@@ -1956,16 +2609,40 @@ func mapLit(n *node) {
 	for i, c := range child {
 		convertLiteralValue(c.child[0], n.typ.key.TypeOf())
 		convertLiteralValue(c.child[1], n.typ.val.TypeOf())
-		if n.typ.key.cat == interfaceT {
-			keys[i] = genValueInterface(c.child[0])
-		} else {
-			keys[i] = genValue(c.child[0])
+		keys[i] = genDestValue(n.typ.key, c.child[0])
+		values[i] = genDestValue(n.typ.val, c.child[1])
+	}
+	destInterface := destType(n).cat == interfaceT
+
+	n.exec = func(f *frame) bltn {
+		m := reflect.MakeMap(typ)
+		for i, k := range keys {
+			m.SetMapIndex(k(f), values[i](f))
 		}
-		if n.typ.val.cat == interfaceT {
-			values[i] = genValueInterface(c.child[1])
-		} else {
-			values[i] = genValue(c.child[1])
+		if destInterface {
+			value(f).Set(reflect.ValueOf(valueInterface{n, m}))
+			return next
 		}
+		setComposite(value(f), m)
+		return next
+	}
+}
+
+func compositeBinMap(n *node) {
+	value := valueGenerator(n, n.findex)
+	next := getExec(n.tnext)
+	child := n.child
+	if n.nleft == 1 {
+		child = n.child[1:]
+	}
+	typ := n.typ.TypeOf()
+	keys := make([]func(*frame) reflect.Value, len(child))
+	values := make([]func(*frame) reflect.Value, len(child))
+	for i, c := range child {
+		convertLiteralValue(c.child[0], typ.Key())
+		convertLiteralValue(c.child[1], typ.Elem())
+		keys[i] = genValue(c.child[0])
+		values[i] = genValue(c.child[1])
 	}
 
 	n.exec = func(f *frame) bltn {
@@ -1973,34 +2650,51 @@ func mapLit(n *node) {
 		for i, k := range keys {
 			m.SetMapIndex(k(f), values[i](f))
 		}
-		value(f).Set(m)
+		setComposite(value(f), m)
 		return next
 	}
 }
 
-func compositeBinMap(n *node) {
+// compositeBinSlice creates and populates an array or a slice object from a binary type.
+func compositeBinSlice(n *node) {
 	value := valueGenerator(n, n.findex)
 	next := getExec(n.tnext)
 	child := n.child
 	if n.nleft == 1 {
 		child = n.child[1:]
 	}
-	typ := n.typ.TypeOf()
-	keys := make([]func(*frame) reflect.Value, len(child))
+	typ := n.typ.rtype
 	values := make([]func(*frame) reflect.Value, len(child))
+	index := make([]int, len(child))
+	var max, prev int
+
 	for i, c := range child {
-		convertLiteralValue(c.child[0], typ.Key())
-		convertLiteralValue(c.child[1], typ.Elem())
-		keys[i] = genValue(c.child[0])
-		values[i] = genValue(c.child[1])
+		if c.kind == keyValueExpr {
+			convertLiteralValue(c.child[1], typ.Elem())
+			values[i] = genValue(c.child[1])
+			index[i] = int(vInt(c.child[0].rval))
+		} else {
+			convertLiteralValue(c, typ.Elem())
+			values[i] = genValue(c)
+			index[i] = prev
+		}
+		prev = index[i] + 1
+		if prev > max {
+			max = prev
+		}
 	}
 
 	n.exec = func(f *frame) bltn {
-		m := reflect.MakeMap(typ)
-		for i, k := range keys {
-			m.SetMapIndex(k(f), values[i](f))
+		var a reflect.Value
+		if typ.Kind() == reflect.Slice {
+			a = reflect.MakeSlice(typ, max, max)
+		} else {
+			a = reflect.New(typ).Elem()
+		}
+		for i, v := range values {
+			a.Index(index[i]).Set(v(f))
 		}
-		value(f).Set(m)
+		setComposite(value(f), a)
 		return next
 	}
 }
@@ -2010,7 +2704,10 @@ func compositeBinStruct(n *node) {
 	next := getExec(n.tnext)
 	value := valueGenerator(n, n.findex)
 	typ := n.typ.rtype
-	child := n.child[1:]
+	child := n.child
+	if n.nleft == 1 {
+		child = n.child[1:]
+	}
 	values := make([]func(*frame) reflect.Value, len(child))
 	fieldIndex := make([][]int, len(child))
 	for i, c := range child {
@@ -2041,11 +2738,23 @@ func compositeBinStruct(n *node) {
 		for i, v := range values {
 			s.FieldByIndex(fieldIndex[i]).Set(v(f))
 		}
-		value(f).Set(s)
+		setComposite(value(f), s)
 		return next
 	}
 }
 
+// setComposite sets the composite literal value v to its destination d.
+// If d is a pointer, the literal is an element which elides &T, and d is set
+// to the address of a copy of v.
+func setComposite(d, v reflect.Value) {
+	if d.Kind() == reflect.Ptr && v.Kind() != reflect.Ptr {
+		p := reflect.New(v.Type())
+		p.Elem().Set(v)
+		v = p
+	}
+	d.Set(v)
+}
+
 func destType(n *node) *itype {
 	switch n.anc.kind {
 	case assignStmt, defineStmt:
@@ -2068,17 +2777,23 @@ func doCompositeLit(n *node, hasType bool) {
 	values := make([]func(*frame) reflect.Value, len(child))
 	for i, c := range child {
 		convertLiteralValue(c, n.typ.field[i].typ.TypeOf())
-		if c.typ.cat == funcT {
+		switch ftyp := n.typ.field[i].typ; {
+		case hasMethodsSrc(ftyp):
+			values[i] = genValueInterface(c)
+		case c.typ.cat == funcT:
 			values[i] = genFunctionWrapper(c)
-		} else {
+		case isInterfaceBin(ftyp):
+			values[i] = genInterfaceWrapper(c, ftyp.TypeOf())
+		default:
 			values[i] = genValue(c)
 		}
 	}
 
 	i := n.findex
 	l := n.level
+	typ := n.typ.TypeOf()
 	n.exec = func(f *frame) bltn {
-		a := reflect.New(n.typ.TypeOf()).Elem()
+		a := reflect.New(typ).Elem()
 		for i, v := range values {
 			a.Field(i).Set(v(f))
 		}
@@ -2094,6 +2809,11 @@ func doCompositeLit(n *node, hasType bool) {
 	}
 }
 
+// hasMethodsSrc returns true if t is an interpreted interface with methods,
+// of which the values are stored with their dynamic type, so the methods can
+// be called.
+func hasMethodsSrc(t *itype) bool { return isInterfaceSrc(t) && len(t.methods()) > 0 }
+
 func compositeLit(n *node)       { doCompositeLit(n, true) }
 func compositeLitNotype(n *node) { doCompositeLit(n, false) }
 
@@ -2114,6 +2834,8 @@ func doCompositeSparse(n *node, hasType bool) {
 		field := n.typ.fieldIndex(c.child[0].ident)
 		convertLiteralValue(c1, n.typ.field[field].typ.TypeOf())
 		switch {
+		case hasMethodsSrc(n.typ.field[field].typ):
+			values[field] = genValueInterface(c1)
 		case c1.typ.cat == funcT:
 			values[field] = genFunctionWrapper(c1)
 		case c1.typ.cat == interfaceT:
@@ -2122,6 +2844,8 @@ func doCompositeSparse(n *node, hasType bool) {
 			values[field] = genValueInterfaceArray(c1)
 		case isRecursiveType(n.typ.field[field].typ, n.typ.field[field].typ.rtype):
 			values[field] = genValueRecursiveInterface(c1, n.typ.field[field].typ.rtype)
+		case isInterfaceBin(n.typ.field[field].typ):
+			values[field] = genInterfaceWrapper(c1, n.typ.field[field].typ.TypeOf())
 		default:
 			values[field] = genValue(c1)
 		}
@@ -2134,7 +2858,8 @@ func doCompositeSparse(n *node, hasType bool) {
 		d := value(f)
 		switch {
 		case d.Type().Kind() == reflect.Ptr:
-			d.Set(a.Addr())
+			// a is reused by each execution, the address of a copy is set.
+			setComposite(d, a)
 		case destInterface:
 			d.Set(reflect.ValueOf(valueInterface{n, a}))
 		default:
@@ -2149,8 +2874,6 @@ func compositeSparseNotype(n *node) { doCompositeSparse(n, false) }
 
 func empty(n *node) {}
 
-var rat = reflect.ValueOf((*[]rune)(nil)).Type().Elem() // runes array type
-
 func _range(n *node) {
 	index0 := n.child[0].findex // array index location in frame
 	index2 := index0 - 1        // shallow array for range, always just behind index0
@@ -2161,11 +2884,7 @@ func _range(n *node) {
 	if len(n.child) == 4 {
 		an := n.child[2]
 		index1 := n.child[1].findex // array value location in frame
-		if isString(an.typ.TypeOf()) {
-			value = genValueAs(an, rat) // range on string iterates over runes
-		} else {
-			value = genValueRangeArray(an)
-		}
+		value = genValueRangeArray(an)
 		n.exec = func(f *frame) bltn {
 			a := f.data[index2]
 			v0 := f.data[index0]
@@ -2178,12 +2897,7 @@ func _range(n *node) {
 			return tnext
 		}
 	} else {
-		an := n.child[1]
-		if isString(an.typ.TypeOf()) {
-			value = genValueAs(an, rat) // range on string iterates over runes
-		} else {
-			value = genValueRangeArray(an)
-		}
+		value = genValueRangeArray(n.child[1])
 		n.exec = func(f *frame) bltn {
 			v0 := f.data[index0]
 			v0.SetInt(v0.Int() + 1)
@@ -2203,11 +2917,110 @@ func _range(n *node) {
 	}
 }
 
+// rangeInt iterates over the values from 0 to the integer range expression,
+// evaluated once. The iteration variable is set from a hidden counter, so it
+// can be modified in the loop body.
+func rangeInt(n *node) {
+	index0 := n.child[0].findex // iteration value location in frame
+	index2 := index0 - 1        // next value for range, always just behind index0
+	index3 := index0 - 2        // range limit, always just behind index2
+	fnext := getExec(n.fnext)
+	tnext := getExec(n.tnext)
+	value := genValue(n.child[1])
+
+	var init func(*frame)
+	if isUint(n.child[0].typ.TypeOf()) {
+		n.exec = func(f *frame) bltn {
+			i := f.data[index2].Uint()
+			if i >= f.data[index3].Uint() {
+				return fnext
+			}
+			f.data[index0].SetUint(i)
+			f.data[index2].SetUint(i + 1)
+			return tnext
+		}
+		init = func(f *frame) {
+			f.data[index3].SetUint(vUint(value(f)))
+			f.data[index2].SetUint(0)
+		}
+	} else {
+		n.exec = func(f *frame) bltn {
+			i := f.data[index2].Int()
+			if i >= f.data[index3].Int() {
+				return fnext
+			}
+			f.data[index0].SetInt(i)
+			f.data[index2].SetInt(i + 1)
+			return tnext
+		}
+		init = func(f *frame) {
+			f.data[index3].SetInt(vInt(value(f)))
+			f.data[index2].SetInt(0)
+		}
+	}
+
+	// Init sequence
+	next := n.exec
+	n.child[0].exec = func(f *frame) bltn {
+		init(f)
+		return next
+	}
+}
+
+// rangeString iterates over the runes of a string, decoded as UTF-8. The index
+// is the byte offset of the rune, and an invalid byte is decoded as the rune
+// utf8.RuneError of width 1.
+func rangeString(n *node) {
+	index0 := n.child[0].findex // string index location in frame
+	index2 := index0 - 1        // next byte index for range, always just behind index0
+	index3 := index0 - 2        // string copy for range, always just behind index2
+	fnext := getExec(n.fnext)
+	tnext := getExec(n.tnext)
+
+	var value func(*frame) reflect.Value
+	if len(n.child) == 4 {
+		index1 := n.child[1].findex  // rune value location in frame
+		value = genValue(n.child[2]) // string
+		n.exec = func(f *frame) bltn {
+			s, i := f.data[index3].String(), int(f.data[index2].Int())
+			if i >= len(s) {
+				return fnext
+			}
+			r, w := utf8.DecodeRuneInString(s[i:])
+			f.data[index0].SetInt(int64(i))
+			f.data[index1].SetInt(int64(r))
+			f.data[index2].SetInt(int64(i + w))
+			return tnext
+		}
+	} else {
+		value = genValue(n.child[1]) // string
+		n.exec = func(f *frame) bltn {
+			s, i := f.data[index3].String(), int(f.data[index2].Int())
+			if i >= len(s) {
+				return fnext
+			}
+			_, w := utf8.DecodeRuneInString(s[i:])
+			f.data[index0].SetInt(int64(i))
+			f.data[index2].SetInt(int64(i + w))
+			return tnext
+		}
+	}
+
+	// Init sequence
+	next := n.exec
+	n.child[0].exec = func(f *frame) bltn {
+		f.data[index3].SetString(value(f).String())
+		f.data[index2].SetInt(0)
+		return next
+	}
+}
+
 func rangeChan(n *node) {
 	i := n.child[0].findex        // element index location in frame
 	value := genValue(n.child[1]) // chan
 	fnext := getExec(n.fnext)
 	tnext := getExec(n.tnext)
+	isFunc := n.child[0].typ.cat == funcT
 
 	n.exec = func(f *frame) bltn {
 		chosen, v, ok := reflect.Select([]reflect.SelectCase{f.done, {Dir: reflect.SelectRecv, Chan: value(f)}})
@@ -2217,6 +3030,10 @@ func rangeChan(n *node) {
 		if !ok {
 			return fnext
 		}
+		if isFunc {
+			// The received function value is held by a node, as genValueNode.
+			v = reflect.ValueOf(&node{rval: v})
+		}
 		f.data[i].Set(v)
 		return tnext
 	}
@@ -2284,87 +3101,51 @@ func _case(n *node) {
 	case n.anc.anc.kind == typeSwitch:
 		fnext := getExec(n.fnext)
 		sn := n.anc.anc // switch node
-		types := make([]*itype, len(n.child)-1)
-		for i := range types {
-			types[i] = n.child[i].typ
+		src := sn.child[1].lastChild().child[0]
+		srcValue := genValue(src)
+		asserts := make([]func(*frame, reflect.Value) (reflect.Value, bool), len(n.child)-1)
+		for i := range asserts {
+			if typ := n.child[i].typ; typ.cat != nilT {
+				asserts[i] = genAssert(n, src.typ, typ)
+				continue
+			}
+			// Match a nil interface value.
+			asserts[i] = func(f *frame, v reflect.Value) (reflect.Value, bool) {
+				nod, _ := dynamicValue(src.typ, v)
+				return reflect.Value{}, nod == nil
+			}
 		}
-		srcValue := genValue(sn.child[1].lastChild().child[0])
+		var destValue func(*frame) reflect.Value
 		if len(sn.child[1].child) == 2 {
 			// assign in switch guard
-			destValue := genValue(n.lastChild().child[0])
-			switch len(types) {
-			case 0:
+			destValue = genValue(n.lastChild().child[0])
+		}
+		n.exec = func(f *frame) bltn {
+			v := srcValue(f)
+			if len(asserts) == 0 {
 				// default clause: assign var to interface value
-				n.exec = func(f *frame) bltn {
-					destValue(f).Set(srcValue(f))
-					return tnext
-				}
-			case 1:
-				// match against 1 type: assign var to concrete value
-				typ := types[0]
-				n.exec = func(f *frame) bltn {
-					v := srcValue(f)
-					if !v.IsValid() {
-						// match zero value against nil
-						if typ.cat == nilT {
-							return tnext
-						}
-						return fnext
-					}
-					if t := v.Type(); t.Kind() == reflect.Interface {
-						if typ.cat == nilT && v.IsNil() {
-							return tnext
-						}
-						if typ.TypeOf().String() == t.String() {
-							destValue(f).Set(v.Elem())
-							return tnext
-						}
-						return fnext
-					}
-					vi := v.Interface().(valueInterface)
-					if vi.node == nil {
-						if typ.cat == nilT {
-							return tnext
-						}
-						return fnext
-					}
-					if vi.node.typ.id() == typ.id() {
-						destValue(f).Set(vi.value)
-						return tnext
-					}
-					return fnext
-				}
-			default:
-				// match against multiple types: assign var to interface value
-				n.exec = func(f *frame) bltn {
-					val := srcValue(f)
-					if v := srcValue(f).Interface().(valueInterface).node; v != nil {
-						for _, typ := range types {
-							if v.typ.id() == typ.id() {
-								destValue(f).Set(val)
-								return tnext
-							}
-						}
-					}
-					return fnext
+				if destValue != nil {
+					destValue(f).Set(v)
 				}
+				return tnext
 			}
-		} else {
-			// no assign in switch guard
-			if len(n.child) <= 1 {
-				n.exec = func(f *frame) bltn { return tnext }
-			} else {
-				n.exec = func(f *frame) bltn {
-					if v := srcValue(f).Interface().(valueInterface).node; v != nil {
-						for _, typ := range types {
-							if v.typ.id() == typ.id() {
-								return tnext
-							}
-						}
-					}
-					return fnext
+			for _, assert := range asserts {
+				r, ok := assert(f, v)
+				if !ok {
+					continue
+				}
+				switch {
+				case destValue == nil:
+				case len(asserts) > 1:
+					// match against multiple types: assign var to interface value
+					destValue(f).Set(v)
+				case r.IsValid():
+					// match against 1 type: assign var to asserted value
+					destValue(f).Set(r)
 				}
+				return tnext
 			}
+			return fnext
 		}
 
 	case len(n.child) <= 1: // default clause
@@ -2375,12 +3156,19 @@ func _case(n *node) {
 		l := len(n.anc.anc.child)
 		value := genValue(n.anc.anc.child[l-2])
 		values := make([]func(*frame) reflect.Value, len(n.child)-1)
+		starts := make([]*node, len(values))
 		for i := range values {
 			values[i] = genValue(n.child[i])
+			starts[i] = n.child[i].start
+			setExec(starts[i])
 		}
 		n.exec = func(f *frame) bltn {
 			v0 := value(f)
-			for _, v := range values {
+			for i, v := range values {
+				// Case expressions are evaluated in order, until a match.
+				for exec := starts[i].exec; exec != nil; {
+					exec = exec(f)
+				}
 				v1 := v(f)
 				if !v0.Type().AssignableTo(v1.Type()) {
 					v0 = v0.Convert(v1.Type())
@@ -2415,7 +3203,12 @@ func appendSlice(n *node) {
 }
 
 func _append(n *node) {
-	if c1, c2 := n.child[1], n.child[2]; len(n.child) == 3 && c2.typ.cat == arrayT && c2.typ.val.id() == n.typ.val.id() ||
+	// The element type of a slice of a binary type is only known by reflection.
+	elem := n.typ.val
+	if n.typ.cat == valueT {
+		elem = &itype{cat: valueT, rtype: n.typ.rtype.Elem()}
+	}
+	if c1, c2 := n.child[1], n.child[2]; len(n.child) == 3 && c2.typ.cat == arrayT && c2.typ.val.id() == elem.id() ||
 		isByteArray(c1.typ.TypeOf()) && isString(c2.typ.TypeOf()) {
 		appendSlice(n)
 		return
@@ -2430,12 +3223,12 @@ func _append(n *node) {
 		values := make([]func(*frame) reflect.Value, l)
 		for i, arg := range args {
 			switch {
-			case n.typ.val.cat == interfaceT:
+			case elem.cat == interfaceT:
 				values[i] = genValueInterface(arg)
-			case isRecursiveType(n.typ.val, n.typ.val.rtype):
-				values[i] = genValueRecursiveInterface(arg, n.typ.val.rtype)
+			case isRecursiveType(elem, elem.rtype):
+				values[i] = genValueRecursiveInterface(arg, elem.rtype)
 			case arg.typ.untyped:
-				values[i] = genValueAs(arg, n.child[1].typ.TypeOf().Elem())
+				values[i] = genValueAs(arg, n.child[1].typ.frameType().Elem())
 			default:
 				values[i] = genValue(arg)
 			}
@@ -2452,12 +3245,12 @@ func _append(n *node) {
 	} else {
 		var value0 func(*frame) reflect.Value
 		switch {
-		case n.typ.val.cat == interfaceT:
+		case elem.cat == interfaceT:
 			value0 = genValueInterface(n.child[2])
-		case isRecursiveType(n.typ.val, n.typ.val.rtype):
-			value0 = genValueRecursiveInterface(n.child[2], n.typ.val.rtype)
+		case isRecursiveType(elem, elem.rtype):
+			value0 = genValueRecursiveInterface(n.child[2], elem.rtype)
 		case n.child[2].typ.untyped:
-			value0 = genValueAs(n.child[2], n.child[1].typ.TypeOf().Elem())
+			value0 = genValueAs(n.child[2], n.child[1].typ.frameType().Elem())
 		default:
 			value0 = genValue(n.child[2])
 		}
@@ -2546,6 +3339,25 @@ func _real(n *node) {
 	}
 }
 
+func _clear(n *node) {
+	in := []func(*frame) reflect.Value{genValue(n.child[1])}
+
+	genBuiltinDeferWrapper(n, in, nil, func(args []reflect.Value) []reflect.Value {
+		switch v := args[0]; v.Kind() {
+		case reflect.Map:
+			for _, k := range v.MapKeys() {
+				v.SetMapIndex(k, reflect.Value{})
+			}
+		case reflect.Slice:
+			z := reflect.Zero(v.Type().Elem())
+			for i := 0; i < v.Len(); i++ {
+				v.Index(i).Set(z)
+			}
+		}
+		return nil
+	})
+}
+
 func _delete(n *node) {
 	value0 := genValue(n.child[1]) // map
 	value1 := genValue(n.child[2]) // key
@@ -2569,6 +3381,46 @@ func _len(n *node) {
 	}
 }
 
+func _max(n *node) { genMinMax(n, true) }
+
+func _min(n *node) { genMinMax(n, false) }
+
+// genMinMax generates the min or max builtin call n. A float NaN argument
+// gives a NaN result.
+func genMinMax(n *node, max bool) {
+	typ := n.typ.TypeOf()
+	dest := genValueOutput(n, typ)
+	values := make([]func(*frame) reflect.Value, len(n.child)-1)
+	for i, c := range n.child[1:] {
+		convertLiteralValue(c, typ)
+		values[i] = genValue(c)
+	}
+	var less func(a, b reflect.Value) bool
+	switch {
+	case isString(typ):
+		less = func(a, b reflect.Value) bool { return a.String() < b.String() }
+	case isFloat(typ):
+		less = func(a, b reflect.Value) bool { return a.Float() < b.Float() }
+	case isUint(typ):
+		less = func(a, b reflect.Value) bool { return a.Uint() < b.Uint() }
+	default:
+		less = func(a, b reflect.Value) bool { return a.Int() < b.Int() }
+	}
+	isNaN := func(v reflect.Value) bool { return isFloat(typ) && math.IsNaN(v.Float()) }
+	next := getExec(n.tnext)
+
+	n.exec = func(f *frame) bltn {
+		r := values[0](f)
+		for _, value := range values[1:] {
+			if v := value(f); isNaN(v) || max && less(r, v) || !max && less(v, r) {
+				r = v
+			}
+		}
+		dest(f).Set(r.Convert(typ))
+		return next
+	}
+}
+
 func _new(n *node) {
 	next := getExec(n.tnext)
 	typ := n.child[1].typ.TypeOf()
@@ -2743,19 +3595,28 @@ func recv(n *node) {
 }
 
 func recv2(n *node) {
-	vchan := genValue(n.child[0])    // chan
-	vres := genValue(n.anc.child[0]) // result
-	vok := genValue(n.anc.child[1])  // status
+	vchan := genValue(n.child[0]) // chan
+	setValue := genValueCommaOk(n.anc.child[0], n)
+	setStatus := genStatusCommaOk(n.anc.child[1])
 	tnext := getExec(n.tnext)
 
+	// set assigns the received value and status to the destinations.
+	set := func(f *frame, v reflect.Value, ok bool) {
+		if setValue != nil {
+			setValue(f, v)
+		}
+		if setStatus != nil {
+			setStatus(f, ok)
+		}
+	}
+
 	if n.interp.cancelChan {
 		// Cancellable channel read
 		n.exec = func(f *frame) bltn {
-			ch, result, status := vchan(f), vres(f), vok(f)
+			ch := vchan(f)
 			//  Fast: channel read doesn't block
 			if v, ok := ch.TryRecv(); ok {
-				result.Set(v)
-				status.SetBool(true)
+				set(f, v, true)
 				return tnext
 			}
 			// Slow: channel is blocked, allow cancel
@@ -2763,21 +3624,110 @@ func recv2(n *node) {
 			if chosen == 0 {
 				return nil
 			}
-			result.Set(v)
-			status.SetBool(ok)
+			set(f, v, ok)
 			return tnext
 		}
 	} else {
 		// Blocking channel read (less overhead)
 		n.exec = func(f *frame) bltn {
 			v, ok := vchan(f).Recv()
-			vres(f).Set(v)
-			vok(f).SetBool(ok)
+			set(f, v, ok)
 			return tnext
 		}
 	}
 }
 
+// copyIdentical copies src to dst, of identical types per the Go specification
+// but of different reflect types, one of them being represented by the
+// interpreter type t. If toInterp is true, dst holds the interpreter
+// representation, where interface values are wrapped in valueInterface,
+// otherwise the interpreted values are wrapped to implement the binary
+// interfaces.
+func copyIdentical(f *frame, dst, src reflect.Value, t *itype, toInterp bool) error {
+	for t.cat == aliasT {
+		t = t.val
+	}
+	if t.cat != interfaceT && src.Type() == dst.Type() {
+		dst.Set(src)
+		return nil
+	}
+
+	switch t.cat {
+	case interfaceT:
+		if toInterp {
+			dst.Set(binValueInterface(src))
+			return nil
+		}
+		if src.IsNil() {
+			return nil
+		}
+		v := src.Elem()
+		if vi, ok := v.Interface().(valueInterface); ok {
+			if vi.node == nil {
+				return nil
+			}
+			v = wrapValue(vi.node, func(*frame) reflect.Value { return vi.value }, dst.Type())(f)
+		}
+		if !v.IsValid() {
+			return nil
+		}
+		if !v.Type().AssignableTo(dst.Type()) {
+			return fmt.Errorf("cannot use %s as %s value", v.Type(), dst.Type())
+		}
+		dst.Set(v)
+	case structT:
+		for i, field := range t.field {
+			if err := copyIdentical(f, dst.Field(i), src.Field(i), field.typ, toInterp); err != nil {
+				return err
+			}
+		}
+	case arrayT, variadicT:
+		if src.Kind() == reflect.Slice {
+			if src.IsNil() {
+				return nil
+			}
+			dst.Set(reflect.MakeSlice(dst.Type(), src.Len(), src.Len()))
+		}
+		for i := 0; i < src.Len(); i++ {
+			if err := copyIdentical(f, dst.Index(i), src.Index(i), t.val, toInterp); err != nil {
+				return err
+			}
+		}
+	case mapT:
+		if src.IsNil() {
+			return nil
+		}
+		dt := dst.Type()
+		dst.Set(reflect.MakeMapWithSize(dt, src.Len()))
+		for iter := src.MapRange(); iter.Next(); {
+			k, e := reflect.New(dt.Key()).Elem(), reflect.New(dt.Elem()).Elem()
+			if err := copyIdentical(f, k, iter.Key(), t.key, toInterp); err != nil {
+				return err
+			}
+			if err := copyIdentical(f, e, iter.Value(), t.val, toInterp); err != nil {
+				return err
+			}
+			dst.SetMapIndex(k, e)
+		}
+	case ptrT:
+		if src.IsNil() {
+			return nil
+		}
+		// The pointed values have different types, they can not be shared.
+		p := reflect.New(dst.Type().Elem())
+		if err := copyIdentical(f, p.Elem(), src.Elem(), t.val, toInterp); err != nil {
+			return err
+		}
+		dst.Set(p)
+	default:
+		if !src.Type().ConvertibleTo(dst.Type()) {
+			return fmt.Errorf("cannot convert %s to %s", src.Type(), dst.Type())
+		}
+		dst.Set(src.Convert(dst.Type()))
+	}
+	return nil
+}
+
 func convertLiteralValue(n *node, t reflect.Type) {
 	switch {
 	case n.typ.cat == nilT:
@@ -2795,6 +3745,13 @@ func convertLiteralValue(n *node, t reflect.Type) {
 	}
 }
 
+// isConstInt returns true if the constant value c of node n is an integer,
+// and n is not of a floating point or complex type.
+func isConstInt(n *node, c constant.Value) bool {
+	t := n.typ.rtype
+	return c.Kind() == constant.Int && !isFloat(t) && !isComplex(t)
+}
+
 func convertConstantValue(n *node) {
 	if !n.rval.IsValid() {
 		return
@@ -2813,6 +3770,7 @@ func convertConstantValue(n *node) {
 	case reflect.String:
 		v = reflect.ValueOf(constant.StringVal(c)).Convert(typ)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		c := constant.ToInt(c)
 		i, _ := constant.Int64Val(c)
 		l := constant.BitLen(c)
 		if l > bitlen[kind] {
@@ -2820,6 +3778,7 @@ func convertConstantValue(n *node) {
 		}
 		v = reflect.ValueOf(i).Convert(typ)
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		c := constant.ToInt(c)
 		i, _ := constant.Uint64Val(c)
 		l := constant.BitLen(c)
 		if l > bitlen[kind] {
@@ -2827,10 +3786,10 @@ func convertConstantValue(n *node) {
 		}
 		v = reflect.ValueOf(i).Convert(typ)
 	case reflect.Float32:
-		f, _ := constant.Float32Val(c)
+		f, _ := constant.Float32Val(constant.ToFloat(c))
 		v = reflect.ValueOf(f).Convert(typ)
 	case reflect.Float64:
-		f, _ := constant.Float64Val(c)
+		f, _ := constant.Float64Val(constant.ToFloat(c))
 		v = reflect.ValueOf(f).Convert(typ)
 	case reflect.Complex64:
 		r, _ := constant.Float32Val(constant.Real(c))
@@ -2870,7 +3829,7 @@ func send(n *node) {
 	next := getExec(n.tnext)
 	value0 := genValue(n.child[0]) // channel
 	convertLiteralValue(n.child[1], n.child[0].typ.val.TypeOf())
-	value1 := genValue(n.child[1]) // value to send
+	value1 := genSendValue(n.child[0], n.child[1]) // value to send
 
 	if n.interp.cancelChan {
 		// Cancellable send
@@ -2896,12 +3855,24 @@ func send(n *node) {
 	}
 }
 
+// genSendValue returns a generator of the value of n sent on the channel ch.
+// An interpreted function is sent as a function value callable by reflect,
+// as the channel element type is the binary function type.
+func genSendValue(ch, n *node) func(*frame) reflect.Value {
+	elem := chanElement(ch.typ)
+	if elem.cat == funcT && n.typ.cat == funcT {
+		return genFunctionWrapper(n)
+	}
+	return genDestValue(elem, n)
+}
+
 func clauseChanDir(n *node) (*node, *node, *node, reflect.SelectDir) {
 	dir := reflect.SelectDefault
 	var nod, assigned, ok *node
 	var stop bool
 
-	n.Walk(func(m *node) bool {
+	// Only the communication of the clause is walked, not its body.
+	n.child[0].Walk(func(m *node) bool {
 		switch m.action {
 		case aRecv:
 			dir = reflect.SelectRecv
@@ -2944,12 +3915,20 @@ func _select(n *node) {
 			clause[i] = func(*frame) bltn { return next }
 		} else {
 			switch c0 := n.child[i].child[0]; {
-			case len(n.child[i].child) > 1:
-				// The comm clause contains a channel operation and a clause body.
-				clause[i] = getExec(n.child[i].child[1].start)
+			case n.child[i].kind == commClause && (len(n.child[i].child) > 1 || c0.action == aAssign || c0.action == aAssignX):
+				// The comm clause contains a channel operation and a clause body,
+				// possibly empty after an assigned receive.
+				clause[i] = next
+				if len(n.child[i].child) > 1 {
+					clause[i] = getExec(n.child[i].child[1].start)
+				}
 				chans[i], assigned[i], ok[i], cases[i].Dir = clauseChanDir(n.child[i])
 				chanValues[i] = genValue(chans[i])
-				if assigned[i] != nil {
+				switch {
+				case assigned[i] == nil:
+				case cases[i].Dir == reflect.SelectSend:
+					assignedValues[i] = genSendValue(chans[i], assigned[i])
+				default:
 					assignedValues[i] = genValue(assigned[i])
 				}
 				if ok[i] != nil {
@@ -2959,11 +3938,13 @@ func _select(n *node) {
 				// The comm clause has an empty body clause after channel receive.
 				chanValues[i] = genValue(c0.child[0].child[0])
 				cases[i].Dir = reflect.SelectRecv
+				clause[i] = next
 			case c0.kind == sendStmt:
 				// The comm clause as an empty body clause after channel send.
 				chanValues[i] = genValue(c0.child[0])
 				cases[i].Dir = reflect.SelectSend
-				assignedValues[i] = genValue(c0.child[1])
+				assignedValues[i] = genSendValue(c0.child[0], c0.child[1])
+				clause[i] = next
 			default:
 				// The comm clause has a default clause.
 				clause[i] = getExec(c0.start)
@@ -3068,7 +4049,7 @@ func slice0(n *node) {
 
 func isNil(n *node) {
 	var value func(*frame) reflect.Value
-	c0 := n.child[0]
+	c0 := nilOperand(n)
 	if c0.typ.cat == funcT {
 		value = genValueAsFunctionWrapper(c0)
 	} else {
@@ -3081,9 +4062,7 @@ func isNil(n *node) {
 		fnext := getExec(n.fnext)
 		if c0.typ.cat == interfaceT {
 			n.exec = func(f *frame) bltn {
-				vi := value(f).Interface().(valueInterface)
-				if (vi == valueInterface{} ||
-					vi.node.kind == basicLit && vi.node.typ.cat == nilT) {
+				if isNilInterface(value(f)) {
 					dest(f).SetBool(true)
 					return tnext
 				}
@@ -3103,7 +4082,7 @@ func isNil(n *node) {
 	} else {
 		if c0.typ.cat == interfaceT {
 			n.exec = func(f *frame) bltn {
-				dest(f).SetBool(value(f).Interface().(valueInterface) == valueInterface{})
+				dest(f).SetBool(isNilInterface(value(f)))
 				return tnext
 			}
 		} else {
@@ -3117,7 +4096,7 @@ func isNil(n *node) {
 
 func isNotNil(n *node) {
 	var value func(*frame) reflect.Value
-	c0 := n.child[0]
+	c0 := nilOperand(n)
 	if c0.typ.cat == funcT {
 		value = genValueAsFunctionWrapper(c0)
 	} else {
@@ -3130,9 +4109,7 @@ func isNotNil(n *node) {
 		fnext := getExec(n.fnext)
 		if c0.typ.cat == interfaceT {
 			n.exec = func(f *frame) bltn {
-				vi := value(f).Interface().(valueInterface)
-				if (vi == valueInterface{} ||
-					vi.node.kind == basicLit && vi.node.typ.cat == nilT) {
+				if isNilInterface(value(f)) {
 					dest(f).SetBool(false)
 					return fnext
 				}
@@ -3152,7 +4129,7 @@ func isNotNil(n *node) {
 	} else {
 		if c0.typ.cat == interfaceT {
 			n.exec = func(f *frame) bltn {
-				dest(f).SetBool(!(value(f).Interface().(valueInterface) == valueInterface{}))
+				dest(f).SetBool(!isNilInterface(value(f)))
 				return tnext
 			}
 		} else {
@@ -3164,23 +4141,143 @@ func isNotNil(n *node) {
 	}
 }
 
+// nilOperand returns the operand of comparison n which is compared to nil.
+func nilOperand(n *node) *node {
+	if c0 := n.child[0]; c0.typ.cat != nilT {
+		return c0
+	}
+	return n.child[1]
+}
+
+// isNilInterface returns true if v holds a nil interpreter interface value.
+// The zero value of an interface field in a struct is a nil interface{}
+// rather than an empty valueInterface.
+func isNilInterface(v reflect.Value) bool {
+	vi, _ := v.Interface().(valueInterface)
+	return vi == valueInterface{} || vi.node.kind == basicLit && vi.node.typ.cat == nilT
+}
+
 func complexConst(n *node) {
-	if v0, v1 := n.child[1].rval, n.child[2].rval; v0.IsValid() && v1.IsValid() {
-		n.rval = reflect.ValueOf(complex(vFloat(v0), vFloat(v1)))
-		n.gen = nop
+	v0, v1 := n.child[1].rval, n.child[2].rval
+	if !v0.IsValid() || !v1.IsValid() {
+		return
 	}
+	c0, ok0 := n.child[1].constValue()
+	c1, ok1 := n.child[2].constValue()
+	if ok0 && ok1 && n.typ.untyped {
+		// Untyped constant arguments give an exact untyped complex constant.
+		n.rval = reflect.ValueOf(constant.BinaryOp(constant.ToFloat(c0), token.ADD, constant.MakeImag(constant.ToFloat(c1))))
+	} else if c := complex(vFloat(v0), vFloat(v1)); n.typ.cat == complex64T {
+		n.rval = reflect.ValueOf(complex64(c))
+	} else {
+		n.rval = reflect.ValueOf(c)
+	}
+	n.gen = nop
 }
 
 func imagConst(n *node) {
-	if v := n.child[1].rval; v.IsValid() {
-		n.rval = reflect.ValueOf(imag(v.Complex()))
+	v := n.child[1].rval
+	if !v.IsValid() {
+		return
+	}
+	if c, ok := n.child[1].constValue(); ok && n.typ.untyped {
+		n.rval = reflect.ValueOf(constant.Imag(constant.ToComplex(c)))
+	} else {
+		n.rval = floatValue(imag(vComplex(v)), n.typ)
+	}
+	n.gen = nop
+}
+
+// lenConst computes the length of a constant string, or of an array
+// expression without function calls or channel receives.
+func lenConst(n *node) {
+	c := n.child[1]
+	if v, ok := c.constValue(); ok && v.Kind() == constant.String {
+		n.rval = reflect.ValueOf(constant.MakeInt64(int64(len(constant.StringVal(v)))))
+		n.gen = nop
+		return
+	}
+	if c.rval.IsValid() && c.rval.Kind() == reflect.String {
+		n.rval = reflect.ValueOf(constant.MakeInt64(int64(c.rval.Len())))
 		n.gen = nop
+		return
+	}
+	t := c.typ
+	if t != nil && t.cat == ptrT {
+		t = t.val
+	}
+	if t == nil || t.cat != arrayT || !t.sizedef || t.incomplete {
+		return
+	}
+	eval := false
+	c.Walk(func(n *node) bool {
+		eval = eval || n.kind == callExpr || n.action == aRecv
+		return !eval
+	}, nil)
+	if eval {
+		return
+	}
+	n.rval = reflect.ValueOf(constant.MakeInt64(int64(t.size)))
+	n.gen = nop
+}
+
+func maxConst(n *node) { minMaxConst(n, token.GTR) }
+
+func minConst(n *node) { minMaxConst(n, token.LSS) }
+
+// minMaxConst computes the min or max of constant arguments, selecting the
+// argument for which op is true.
+func minMaxConst(n *node, op token.Token) {
+	var r constant.Value
+	for _, c := range n.child[1:] {
+		v, ok := c.constValue()
+		if !ok {
+			return
+		}
+		if r == nil || constant.Compare(v, op, r) {
+			r = v
+		}
+	}
+	n.rval = reflect.ValueOf(r)
+	n.gen = nop
+}
+
+var compareToken = map[action]token.Token{
+	aEqual:        token.EQL,
+	aNotEqual:     token.NEQ,
+	aGreater:      token.GTR,
+	aGreaterEqual: token.GEQ,
+	aLower:        token.LSS,
+	aLowerEqual:   token.LEQ,
+}
+
+// compareConst computes the comparison of untyped constant operands, which
+// may not be representable by any Go type and cannot be compared at execution.
+func compareConst(n *node) {
+	v0, v1 := n.child[0].rval, n.child[1].rval
+	if !isConstantValue(v0.Type()) || !isConstantValue(v1.Type()) {
+		return
 	}
+	n.rval = reflect.ValueOf(constant.Compare(vConstantValue(v0), compareToken[n.action], vConstantValue(v1)))
 }
 
 func realConst(n *node) {
-	if v := n.child[1].rval; v.IsValid() {
-		n.rval = reflect.ValueOf(real(v.Complex()))
-		n.gen = nop
+	v := n.child[1].rval
+	if !v.IsValid() {
+		return
+	}
+	if c, ok := n.child[1].constValue(); ok && n.typ.untyped {
+		n.rval = reflect.ValueOf(constant.Real(constant.ToComplex(c)))
+	} else {
+		n.rval = floatValue(real(vComplex(v)), n.typ)
+	}
+	n.gen = nop
+}
+
+// floatValue returns the float f as a value of the float type t.
+func floatValue(f float64, t *itype) reflect.Value {
+	if t.cat == float32T {
+		return reflect.ValueOf(float32(f))
 	}
+	return reflect.ValueOf(f)
 }
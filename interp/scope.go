@@ -48,7 +48,6 @@ type symbol struct {
 	typ     *itype        // Type of value
 	node    *node         // Node value if index is negative
 	from    []*node       // list of nodes jumping to node if kind is label, or nil
-	recv    *receiver     // receiver node value, if sym refers to a method
 	index   int           // index of value in frame or -1
 	rval    reflect.Value // default value (used for constants)
 	builtin bltnGenerator // Builtin function or nil
@@ -110,6 +109,9 @@ func (s *scope) pop() *scope {
 		// propagate size and types, as scopes at same level share the same frame
 		s.anc.types = s.types
 	}
+	if s.def != nil && s.def.interp.panicLocals != 0 {
+		s.def.interp.addLocals(s.def, s.sym)
+	}
 	return s.anc
 }
 
@@ -130,6 +132,21 @@ func (s *scope) lookup(ident string) (*symbol, int, bool) {
 	return nil, 0, false
 }
 
+// lookupBlock searches for a symbol declared in the current block only. The
+// block of a function body also contains the receiver, parameters and results
+// of the function.
+func (s *scope) lookupBlock(ident string) (*symbol, bool) {
+	if sym, ok := s.sym[ident]; ok {
+		return sym, true
+	}
+	if a := s.anc; a != nil && a.level == s.level && a.anc != nil && a.anc.level < a.level {
+		// The ancestor scope is the one of the function, s is its body.
+		sym, ok := a.sym[ident]
+		return sym, ok
+	}
+	return nil, false
+}
+
 func (s *scope) rangeChanType(n *node) *itype {
 	if sym, _, found := s.lookup(n.child[1].ident); found {
 		if t := sym.typ; len(n.child) == 3 && t != nil && (t.cat == chanT || t.cat == chanRecvT) {
@@ -0,0 +1,31 @@
+package interp
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// Sources returns the syntax trees of the source files parsed by the
+// interpreter, indexed by file path, as used for their evaluation, so that
+// additional analyses can be performed on them, with positions relative to
+// FileSet. For each path, the last parsed file is returned. The code
+// evaluated by Eval has the path of the interpreter Name, and the programs
+// compiled by Compile an empty path. For an empty path, as in REPL mode,
+// declarations and statements are enclosed in a pseudo package and main
+// function. The files of the packages used with UseCompiled are not included.
+// The effect of modifying the returned trees is undefined.
+func (interp *Interpreter) Sources() map[string]*ast.File {
+	interp.mutex.RLock()
+	defer interp.mutex.RUnlock()
+
+	files := make(map[string]*ast.File, len(interp.files))
+	for name, f := range interp.files {
+		files[name] = f
+	}
+	return files
+}
+
+// FileSet returns the file set of the source files parsed by the interpreter,
+// which resolves the positions in Sources and in the errors of the
+// interpreter.
+func (interp *Interpreter) FileSet() *token.FileSet { return interp.fset }
@@ -8,25 +8,32 @@ import (
 	"strings"
 )
 
-func (interp *Interpreter) importSrc(rPath, path string) (string, error) {
+// importSrc parses, compiles and runs the source package at path. The test
+// files of the package are included if skipTest is false.
+func (interp *Interpreter) importSrc(rPath, path string, skipTest bool) (_ string, err error) {
 	var dir string
-	var err error
 
 	if interp.srcPkg[path] != nil {
 		return interp.pkgNames[path], nil
 	}
+	interp.mutex.RLock()
+	compiled := interp.compiled[path]
+	interp.mutex.RUnlock()
 
 	// For relative import paths in the form "./xxx" or "../xxx", the initial
 	// base path is the directory of the interpreter input file, or "." if no file
 	// was provided.
 	// In all other cases, absolute import paths are resolved from the GOPATH
 	// and the nested "vendor" directories.
-	if isPathRelative(path) {
+	switch {
+	case compiled != nil:
+		// The files of a compiled package are already parsed.
+	case isPathRelative(path):
 		if rPath == mainID {
 			rPath = "."
 		}
 		dir = filepath.Join(filepath.Dir(interp.Name), rPath, path)
-	} else {
+	default:
 		root, err := interp.rootFromSourceLocation(rPath)
 		if err != nil {
 			return "", err
@@ -40,10 +47,22 @@ func (interp *Interpreter) importSrc(rPath, path string) (string, error) {
 		return "", fmt.Errorf("import cycle not allowed\n\timports %s", path)
 	}
 	interp.rdir[path] = true
+	defer func() {
+		if err != nil && interp.srcPkg[path] == nil {
+			// Forget the incomplete package, so the import can be retried,
+			// for example once the missing binary packages are used.
+			interp.mutex.Lock()
+			delete(interp.rdir, path)
+			delete(interp.scopes, path)
+			interp.mutex.Unlock()
+		}
+	}()
 
-	files, err := ioutil.ReadDir(dir)
-	if err != nil {
-		return "", err
+	var files []os.FileInfo
+	if compiled == nil {
+		if files, err = ioutil.ReadDir(dir); err != nil {
+			return "", err
+		}
 	}
 
 	var initNodes []*node
@@ -54,24 +73,40 @@ func (interp *Interpreter) importSrc(rPath, path string) (string, error) {
 	var pkgName string
 
 	// Parse source files.
-	for _, file := range files {
-		name := file.Name()
-		if skipFile(&interp.context, name) {
-			continue
-		}
-
-		name = filepath.Join(dir, name)
-		var buf []byte
-		if buf, err = ioutil.ReadFile(name); err != nil {
-			return "", err
-		}
+	nfiles := len(files)
+	if compiled != nil {
+		nfiles = len(compiled.files)
+	}
+	for i := 0; i < nfiles; i++ {
+		var name, pname string
+		if compiled != nil {
+			cf := compiled.files[i]
+			name = compiled.fset.Position(cf.file.Pos()).Filename
+			if pname, root, err = interp.astCompiled(compiled, cf); err != nil {
+				return "", err
+			}
+		} else {
+			name = files[i].Name()
+			fname := name
+			if !skipTest && strings.HasSuffix(name, "_test.go") {
+				// Test files are subject to the same build constraints as other files.
+				fname = strings.TrimSuffix(name, "_test.go") + ".go"
+			}
+			if skipFile(&interp.context, fname) {
+				continue
+			}
 
-		var pname string
-		if pname, root, err = interp.ast(string(buf), name); err != nil {
-			return "", err
+			name = filepath.Join(dir, name)
+			var buf []byte
+			if buf, err = ioutil.ReadFile(name); err != nil {
+				return "", err
+			}
+			if pname, root, err = interp.ast(string(buf), name); err != nil {
+				return "", err
+			}
 		}
-		if root == nil {
-			continue
+		if root == nil || !skipTest && pname != pkgName && strings.HasSuffix(pname, "_test") {
+			continue // External test packages are not supported.
 		}
 
 		if interp.astDot {
@@ -87,6 +122,7 @@ func (interp *Interpreter) importSrc(rPath, path string) (string, error) {
 			return "", fmt.Errorf("found packages %s and %s in %s", pkgName, pname, dir)
 		}
 		rootNodes = append(rootNodes, root)
+		interp.roots[name] = root
 
 		subRPath := effectivePkg(rPath, path)
 		var list []*node
@@ -104,14 +140,27 @@ func (interp *Interpreter) importSrc(rPath, path string) (string, error) {
 		}
 	}
 
-	// Generate control flow graphs
+	// Generate control flow graphs, reporting errors of all files.
+	var errs ErrorList
 	for _, root := range rootNodes {
-		var nodes []*node
-		if nodes, err = interp.cfg(root, path); err != nil {
-			return "", err
+		nodes, err := interp.cfg(root, path)
+		if err != nil {
+			if l, ok := err.(ErrorList); ok {
+				errs = append(errs, l...)
+			} else {
+				errs = append(errs, err)
+			}
+			if len(errs) >= maxErrors {
+				errs = errs[:maxErrors]
+				break
+			}
+			continue
 		}
 		initNodes = append(initNodes, nodes...)
 	}
+	if len(errs) > 0 {
+		return "", errs
+	}
 
 	// Register source package in the interpreter. The package contains only
 	// the global symbols in the package scope.
@@ -124,6 +173,10 @@ func (interp *Interpreter) importSrc(rPath, path string) (string, error) {
 	interp.frame.mutex.Unlock()
 	interp.mutex.Unlock()
 
+	if interp.noRun {
+		return pkgName, nil
+	}
+
 	// Once all package sources have been parsed, execute entry points then init functions
 	for _, n := range rootNodes {
 		if err = genRun(n); err != nil {
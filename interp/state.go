@@ -0,0 +1,151 @@
+package interp
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// stateVersion is the version of the encoding of SaveState.
+const stateVersion = 1
+
+// stateHeader starts the encoding of SaveState.
+type stateHeader struct {
+	Version int
+}
+
+// SaveState writes to w the values of the package level variables of the
+// interpreted packages, so they can be restored by LoadState in another
+// interpreter which has evaluated the same sources. The values must be plain
+// data: booleans, numbers, strings, and arrays, slices, maps, pointers and
+// structs of those. Values of interface, function or channel types can not be
+// saved, and are reported in the returned error with their variable names.
+//
+// The variables of package main are named by their identifier, and those of
+// imported source packages are prefixed by the package import path.
+func (interp *Interpreter) SaveState(w io.Writer) error {
+	vars := interp.globals()
+	var invalid []string
+	for name, v := range vars {
+		if what := unserializable(v.Type()); what != "" {
+			invalid = append(invalid, fmt.Sprintf("%s (%s)", name, what))
+		}
+	}
+	if len(invalid) > 0 {
+		sort.Strings(invalid)
+		return fmt.Errorf("cannot save state, unserializable values: %s", strings.Join(invalid, ", "))
+	}
+
+	state := make(map[string][]byte, len(vars))
+	for name, v := range vars {
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).EncodeValue(v); err != nil {
+			return fmt.Errorf("cannot save %s: %v", name, err)
+		}
+		state[name] = buf.Bytes()
+	}
+	enc := gob.NewEncoder(w)
+	if err := enc.Encode(stateHeader{Version: stateVersion}); err != nil {
+		return err
+	}
+	return enc.Encode(state)
+}
+
+// LoadState sets the package level variables of the interpreted packages to
+// the values written by SaveState. All the saved variables must be declared,
+// with the same types. The variables which were not saved are left unchanged.
+func (interp *Interpreter) LoadState(r io.Reader) error {
+	dec := gob.NewDecoder(r)
+	var h stateHeader
+	if err := dec.Decode(&h); err != nil {
+		return fmt.Errorf("cannot load state: %v", err)
+	}
+	if h.Version != stateVersion {
+		return fmt.Errorf("cannot load state: unsupported version %d", h.Version)
+	}
+	var state map[string][]byte
+	if err := dec.Decode(&state); err != nil {
+		return fmt.Errorf("cannot load state: %v", err)
+	}
+
+	// All values are decoded before any variable is modified.
+	vars := interp.globals()
+	values := make(map[string]reflect.Value, len(state))
+	for name, b := range state {
+		v, ok := vars[name]
+		if !ok {
+			return fmt.Errorf("cannot load %s: undefined variable", name)
+		}
+		nv := reflect.New(v.Type())
+		if err := gob.NewDecoder(bytes.NewReader(b)).DecodeValue(nv); err != nil {
+			return fmt.Errorf("cannot load %s: %v", name, err)
+		}
+		values[name] = nv.Elem()
+	}
+
+	interp.frame.mutex.Lock()
+	defer interp.frame.mutex.Unlock()
+	for name, v := range values {
+		vars[name].Set(v)
+	}
+	return nil
+}
+
+// globals returns the package level variables of the interpreted packages,
+// indexed by qualified name.
+func (interp *Interpreter) globals() map[string]reflect.Value {
+	interp.mutex.RLock()
+	defer interp.mutex.RUnlock()
+	interp.frame.mutex.RLock()
+	defer interp.frame.mutex.RUnlock()
+
+	vars := map[string]reflect.Value{}
+	for pkgID, sc := range interp.scopes {
+		for name, sym := range sc.sym {
+			if sym.kind != varSym || !sym.global || name == "_" || sym.index < 0 || sym.index >= len(interp.frame.data) {
+				continue
+			}
+			if pkgID != interp.Name {
+				name = pkgID + "." + name
+			}
+			vars[name] = interp.frame.data[sym.index]
+		}
+	}
+	return vars
+}
+
+// unserializable returns a description of the part of type t which can not
+// be saved by SaveState, or an empty string.
+func unserializable(t reflect.Type) string { return unserializableType(t, map[reflect.Type]bool{}) }
+
+func unserializableType(t reflect.Type, seen map[reflect.Type]bool) string {
+	if seen[t] {
+		return "" // A recursive type.
+	}
+	seen[t] = true
+	switch t.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.UnsafePointer:
+		return t.Kind().String()
+	case reflect.Array, reflect.Ptr, reflect.Slice:
+		return unserializableType(t.Elem(), seen)
+	case reflect.Map:
+		if s := unserializableType(t.Key(), seen); s != "" {
+			return s
+		}
+		return unserializableType(t.Elem(), seen)
+	case reflect.Struct:
+		if t == valueInterfaceType {
+			return "interface"
+		}
+		for i := 0; i < t.NumField(); i++ {
+			if s := unserializableType(t.Field(i).Type, seen); s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,92 @@
+package interp
+
+import (
+	"errors"
+	"reflect"
+	"sync/atomic"
+	"time"
+)
+
+// Stats accounts for the work done by an evaluation or by the executions of a
+// program, when the Stats option is set. The goroutines started by interpreted
+// code are accounted for until they exit, so the counters may still increase
+// after the evaluation returns. Stats is safe for concurrent use.
+type Stats struct {
+	nodes         int64 // executed nodes
+	frames        int64 // allocated frames
+	values        int64 // allocated frame values
+	goroutines    int64 // running interpreted goroutines
+	maxGoroutines int64 // peak of running interpreted goroutines
+	duration      int64 // wall time, in nanoseconds
+}
+
+// Nodes returns the number of executed CFG nodes, a measure of the CPU work
+// done by interpreted code, excluding the binary functions it calls.
+func (s *Stats) Nodes() int64 { return atomic.LoadInt64(&s.nodes) }
+
+// Frames returns the number of frames allocated by function calls.
+func (s *Stats) Frames() int64 { return atomic.LoadInt64(&s.frames) }
+
+// Values returns the number of values allocated in frames, for function
+// parameters, results and local variables.
+func (s *Stats) Values() int64 { return atomic.LoadInt64(&s.values) }
+
+// Goroutines returns the number of interpreted goroutines still running.
+func (s *Stats) Goroutines() int64 { return atomic.LoadInt64(&s.goroutines) }
+
+// MaxGoroutines returns the peak number of interpreted goroutines running
+// concurrently.
+func (s *Stats) MaxGoroutines() int64 { return atomic.LoadInt64(&s.maxGoroutines) }
+
+// Duration returns the wall time of the evaluation, or the cumulative wall
+// time of the program executions, excluding the goroutines still running.
+func (s *Stats) Duration() time.Duration { return time.Duration(atomic.LoadInt64(&s.duration)) }
+
+// addNode accounts for the execution of a node.
+func (s *Stats) addNode() { atomic.AddInt64(&s.nodes, 1) }
+
+// addFrame accounts for the allocation of a frame of n values.
+func (s *Stats) addFrame(n int) {
+	atomic.AddInt64(&s.frames, 1)
+	atomic.AddInt64(&s.values, int64(n))
+}
+
+// startGoroutine accounts for the start of an interpreted goroutine.
+func (s *Stats) startGoroutine() {
+	n := atomic.AddInt64(&s.goroutines, 1)
+	for {
+		max := atomic.LoadInt64(&s.maxGoroutines)
+		if n <= max || atomic.CompareAndSwapInt64(&s.maxGoroutines, max, n) {
+			return
+		}
+	}
+}
+
+// endGoroutine accounts for the exit of an interpreted goroutine.
+func (s *Stats) endGoroutine() { atomic.AddInt64(&s.goroutines, -1) }
+
+// setStats sets the accounting of frame f, and accounts for its allocation.
+func (f *frame) setStats(s *Stats) {
+	if s == nil {
+		return
+	}
+	f.stats = s
+	s.addFrame(len(f.data))
+}
+
+// EvalWithStats evaluates Go code represented as a string, as Eval, and
+// returns the accounting of the work done. It requires the Stats option.
+func (interp *Interpreter) EvalWithStats(src string) (reflect.Value, *Stats, error) {
+	if !interp.stats {
+		return reflect.Value{}, nil, errors.New("stats not enabled")
+	}
+	// The top level code runs in the global frame.
+	s := &Stats{}
+	interp.frame.stats = s
+	defer func() { interp.frame.stats = nil }()
+
+	start := time.Now()
+	res, err := interp.Eval(src)
+	atomic.AddInt64(&s.duration, int64(time.Since(start)))
+	return res, s, err
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var (
+	name  = flag.String("name", "world", "name to greet")
+	count = flag.Int("n", 1, "number of greetings")
+	trace []string
+)
+
+func init() { trace = append(trace, "init1") }
+
+func init() { trace = append(trace, "init2") }
+
+func main() {
+	flag.Parse()
+	fmt.Println(strings.Join(trace, " "))
+	fmt.Println("program:", os.Args[0])
+	for i := 0; i < *count; i++ {
+		fmt.Printf("hello %s\n", *name)
+	}
+	if flag.NArg() > 0 {
+		fmt.Println("args:", flag.Args())
+	}
+	if *name == "exit" {
+		os.Exit(3)
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"go/constant"
 	"path/filepath"
 	"reflect"
+	"sort"
 	"strconv"
+	"strings"
 )
 
 // tcat defines interpreter type categories.
@@ -133,6 +135,23 @@ var (
 	untypedComplex = &itype{cat: complex128T, name: "complex128", untyped: true}
 )
 
+// untypedConstType returns the untyped type of the constant c, or nil.
+func untypedConstType(c constant.Value) *itype {
+	switch c.Kind() {
+	case constant.Bool:
+		return untypedBool
+	case constant.String:
+		return untypedString
+	case constant.Int:
+		return untypedInt
+	case constant.Float:
+		return untypedFloat
+	case constant.Complex:
+		return untypedComplex
+	}
+	return nil
+}
+
 // nodeType returns a type definition for the corresponding AST subtree.
 func nodeType(interp *Interpreter, sc *scope, n *node) (*itype, error) {
 	if n.typ != nil && !n.typ.incomplete {
@@ -166,39 +185,34 @@ func nodeType(interp *Interpreter, sc *scope, n *node) (*itype, error) {
 	case arrayType:
 		t.cat = arrayT
 		if len(n.child) > 1 {
-			v := n.child[0].rval
-			switch {
-			case v.IsValid():
-				// constant size
-				if isConstantValue(v.Type()) {
-					c := v.Interface().(constant.Value)
-					t.size = constToInt(c)
-				} else {
-					t.size = int(v.Int())
-				}
-			case n.child[0].kind == ellipsisExpr:
-				// [...]T expression
-				t.size = arrayTypeLen(n.anc)
-			default:
-				if sym, _, ok := sc.lookup(n.child[0].ident); ok {
-					// Resolve symbol to get size value
-					if sym.typ != nil && sym.typ.cat == intT {
-						if v, ok := sym.rval.Interface().(int); ok {
-							t.size = v
-						} else if c, ok := sym.rval.Interface().(constant.Value); ok {
-							t.size = constToInt(c)
-						} else {
-							t.incomplete = true
-						}
-					} else {
-						t.incomplete = true
+			if n.child[0].kind == ellipsisExpr {
+				// [...]T expression, the length is given by the composite literal indexes.
+				for _, c := range n.anc.child[1:] {
+					if c.kind != keyValueExpr {
+						continue
 					}
-				} else {
-					// Evaluate constant array size expression
-					if _, err = interp.cfg(n.child[0], sc.pkgID); err != nil {
+					c.typ = t // as propagated from the composite literal, to compute the index
+					if _, ok, err := interp.constExpr(sc, c.child[0]); err != nil {
 						return nil, err
+					} else if !ok {
+						t.incomplete = true
 					}
+				}
+				t.size = arrayTypeLen(n.anc)
+			} else {
+				c, ok, err := interp.constExpr(sc, n.child[0])
+				switch {
+				case err != nil:
+					return nil, err
+				case !ok:
+					// Come back when the constants are known.
 					t.incomplete = true
+				case c == nil:
+					return nil, n.child[0].cfgErrorf("array length must be constant")
+				default:
+					if t.size, err = arrayLen(n.child[0], c); err != nil {
+						return nil, err
+					}
 				}
 			}
 			if t.val, err = nodeType(interp, sc, n.child[1]); err != nil {
@@ -226,19 +240,11 @@ func nodeType(interp *Interpreter, sc *scope, n *node) (*itype, error) {
 		case string:
 			t = untypedString
 		case constant.Value:
-			switch v.Kind() {
-			case constant.Bool:
-				t = untypedBool
-			case constant.String:
-				t = untypedString
-			case constant.Int:
-				t = untypedInt
-			case constant.Float:
-				t = untypedFloat
-			case constant.Complex:
-				t = untypedComplex
-			default:
+			switch t = untypedConstType(v); {
+			case t == nil:
 				err = n.cfgErrorf("missing support for type %v", n.rval)
+			case t == untypedInt && strings.HasPrefix(n.ident, "'"):
+				t = untypedRune
 			}
 		default:
 			err = n.cfgErrorf("missing support for type %T: %v", v, n.rval)
@@ -252,10 +258,20 @@ func nodeType(interp *Interpreter, sc *scope, n *node) (*itype, error) {
 		if t, err = nodeType(interp, sc, n.child[0]); err != nil {
 			return nil, err
 		}
+		if t.incomplete {
+			// The operand depends on a declaration not yet analyzed.
+			break
+		}
 		// For operators other than shift, get the type from the 2nd operand if the first is untyped.
 		if t.untyped && !isShiftNode(n) {
 			var t1 *itype
-			t1, err = nodeType(interp, sc, n.child[1])
+			if t1, err = nodeType(interp, sc, n.child[1]); err != nil {
+				return nil, err
+			}
+			if t1.incomplete {
+				t = t1
+				break
+			}
 			if !(t1.untyped && isInt(t1.TypeOf()) && isFloat(t.TypeOf())) {
 				t = t1
 			}
@@ -270,13 +286,17 @@ func nodeType(interp *Interpreter, sc *scope, n *node) (*itype, error) {
 		case a.kind == returnStmt:
 			dt = sc.def.typ.ret[childPos(n)]
 		}
+		if isInterfaceBin(dt) {
+			// The result is converted to the binary interface when assigned.
+			dt = t
+		}
 		if isInterface(dt) {
 			dt.val = t
 		}
 		t = dt
 
 	case callExpr:
-		if interp.isBuiltinCall(n) {
+		if interp.isBuiltinCall(n, sc) {
 			// Builtin types are special and may depend from their input arguments.
 			t.cat = builtinT
 			switch n.child[0].ident {
@@ -297,7 +317,7 @@ func nodeType(interp *Interpreter, sc *scope, n *node) (*itype, error) {
 					case isFloat64(t0) && isFloat64(t1):
 						t = sc.getType("complex128")
 					case nt0.untyped && isNumber(t0) && nt1.untyped && isNumber(t1):
-						t = &itype{cat: valueT, rtype: complexType, scope: sc}
+						t = untypedComplex
 					case nt0.untyped && isFloat32(t1) || nt1.untyped && isFloat32(t0):
 						t = sc.getType("complex64")
 					case nt0.untyped && isFloat64(t1) || nt1.untyped && isFloat64(t0):
@@ -305,9 +325,6 @@ func nodeType(interp *Interpreter, sc *scope, n *node) (*itype, error) {
 					default:
 						err = n.cfgErrorf("invalid types %s and %s", t0.Kind(), t1.Kind())
 					}
-					if nt0.untyped && nt1.untyped {
-						t.untyped = true
-					}
 				}
 			case "real", "imag":
 				if t, err = nodeType(interp, sc, n.child[1]); err != nil {
@@ -320,17 +337,60 @@ func nodeType(interp *Interpreter, sc *scope, n *node) (*itype, error) {
 					case k == reflect.Complex128:
 						t = sc.getType("float64")
 					case t.untyped && isNumber(t.TypeOf()):
-						t = &itype{cat: valueT, rtype: floatType, untyped: true, scope: sc}
+						t = untypedFloat
 					default:
 						err = n.cfgErrorf("invalid complex type %s", k)
 					}
 				}
 			case "cap", "copy", "len":
 				t = sc.getType("int")
+			case "clear":
+				if len(n.child) != 2 {
+					err = n.cfgErrorf("wrong number of arguments for clear() (expected 1, found %d)", len(n.child)-1)
+					break
+				}
+				var ct *itype
+				if ct, err = nodeType(interp, sc, n.child[1]); err != nil {
+					return nil, err
+				}
+				if !ct.incomplete {
+					if k := ct.TypeOf().Kind(); k != reflect.Map && k != reflect.Slice {
+						err = n.child[1].cfgErrorf("invalid argument: %s is not a map or slice", ct.id())
+					}
+				}
+			case "max", "min":
+				t, err = minMaxType(interp, sc, n)
+			case "print", "println":
+				// The operands are printed by the runtime, which supports only the
+				// basic, reference and interface types.
+				for _, c := range n.child[1:] {
+					var ct *itype
+					if ct, err = nodeType(interp, sc, c); err != nil || ct.incomplete {
+						break
+					}
+					if ct.isNil() {
+						err = c.cfgErrorf("use of untyped nil in argument to built-in %s", n.child[0].ident)
+						break
+					}
+					if k := ct.TypeOf().Kind(); k == reflect.Array || k == reflect.Struct {
+						err = c.cfgErrorf("illegal types for operand: %s\n\t%s", n.child[0].ident, ct.id())
+						break
+					}
+				}
 			case "append", "make":
 				t, err = nodeType(interp, sc, n.child[1])
 			case "new":
-				t, err = nodeType(interp, sc, n.child[1])
+				if len(n.child) != 2 {
+					err = n.cfgErrorf("wrong number of arguments for new() (expected 1, found %d)", len(n.child)-1)
+					break
+				}
+				if t, err = nodeType(interp, sc, n.child[1]); err != nil {
+					break
+				}
+				if !t.incomplete && !n.child[1].isType(sc) {
+					err = n.child[1].cfgErrorf("%s is not a type", exprString(n.child[1]))
+					break
+				}
 				t = &itype{cat: ptrT, val: t, incomplete: t.incomplete, scope: sc}
 			case "recover":
 				t = sc.getType("interface{}")
@@ -422,6 +482,9 @@ func nodeType(interp *Interpreter, sc *scope, n *node) (*itype, error) {
 		}
 
 	case identExpr:
+		if n.ident == "_" && !isBlankDest(n) {
+			return nil, n.cfgErrorf("cannot use _ as value")
+		}
 		sym, _, found := sc.lookup(n.ident)
 		if !found {
 			// retry with the filename, in case ident is a package name.
@@ -437,6 +500,13 @@ func nodeType(interp *Interpreter, sc *scope, n *node) (*itype, error) {
 		}
 		t = sym.typ
 		if t.incomplete && t.node != n {
+			if ok, byValue := selfReference(n, t.node); ok {
+				// Only recursive struct types can be represented with reflect.
+				if byValue {
+					return nil, n.cfgErrorf("invalid recursive type %s", n.ident)
+				}
+				return nil, n.cfgErrorf("unsupported recursive type %s", n.ident)
+			}
 			m := t.method
 			if t, err = nodeType(interp, sc, t.node); err != nil {
 				return nil, err
@@ -470,24 +540,53 @@ func nodeType(interp *Interpreter, sc *scope, n *node) (*itype, error) {
 				sym.typ = t
 			}
 		}
+		var decl []*node // declaration of each field, for error reporting
+		explicit := map[string]bool{}
 		for _, field := range n.child[0].child {
 			if len(field.child) == 1 {
 				typ, err := nodeType(interp, sc, field.child[0])
 				if err != nil {
 					return nil, err
 				}
+				if rt := binInterfaceType(typ); rt != nil {
+					// The methods of an embedded binary interface are added as
+					// methods of the interface, to be found by selectors.
+					for i := 0; i < rt.NumMethod(); i++ {
+						m := rt.Method(i)
+						t.field = append(t.field, structField{name: m.Name, typ: binFuncType(m.Type, sc)})
+						decl = append(decl, field)
+					}
+					continue
+				}
 				t.field = append(t.field, structField{name: fieldName(field.child[0]), embed: true, typ: typ})
+				decl = append(decl, field)
 				incomplete = incomplete || typ.incomplete
 			} else {
+				name := field.child[0].ident
+				if name == "_" {
+					return nil, field.cfgErrorf("methods must have a unique non-blank name")
+				}
+				if explicit[name] {
+					return nil, field.cfgErrorf("duplicate method %s", name)
+				}
+				explicit[name] = true
 				typ, err := nodeType(interp, sc, field.child[1])
 				if err != nil {
 					return nil, err
 				}
-				t.field = append(t.field, structField{name: field.child[0].ident, typ: typ})
-				incomplete = incomplete || typ.incomplete
+				t.field = append(t.field, structField{name: name, typ: typ})
+				decl = append(decl, field)
+				// The method signature may refer to a type being declared, not
+				// yet marked incomplete, as a struct holding the interface.
+				incomplete = incomplete || !typ.isComplete()
 			}
 		}
 		t.incomplete = incomplete
+		if !incomplete {
+			if t.field, err = mergeMethods(t.field, decl); err != nil {
+				return nil, err
+			}
+		}
 
 	case landExpr, lorExpr:
 		t.cat = boolT
@@ -546,6 +645,8 @@ func nodeType(interp *Interpreter, sc *scope, n *node) (*itype, error) {
 				t.rtype = v.Type()
 				if isBinType(v) { // a bin type is encoded as a pointer on nil value
 					t.rtype = t.rtype.Elem()
+				} else if c, ok := v.Interface().(constant.Value); ok && untypedConstType(c) != nil {
+					t = untypedConstType(c)
 				}
 			} else {
 				err = n.cfgErrorf("undefined selector %s.%s", lt.path, name)
@@ -559,7 +660,9 @@ func nodeType(interp *Interpreter, sc *scope, n *node) (*itype, error) {
 				err = n.cfgErrorf("undefined selector %s.%s", lt.path, name)
 			}
 		default:
-			if m, _ := lt.lookupMethod(name); m != nil {
+			if s := lt.lookupSelector(name); len(s) > 1 {
+				err = n.cfgErrorf("ambiguous selector %s: %s", name, selectionPaths(s))
+			} else if m, _ := lt.lookupMethod(name); m != nil {
 				t, err = nodeType(interp, sc, m.child[2])
 			} else if bm, _, _, ok := lt.lookupBinMethod(name); ok {
 				t = &itype{cat: valueT, rtype: bm.Type, isBinMethod: true, scope: sc}
@@ -639,12 +742,76 @@ func nodeType(interp *Interpreter, sc *scope, n *node) (*itype, error) {
 	return t, err
 }
 
-func (interp *Interpreter) isBuiltinCall(n *node) bool {
-	if n.kind != callExpr {
+func (interp *Interpreter) isBuiltinCall(n *node, sc *scope) bool {
+	if n.kind != callExpr || n.child[0].kind != identExpr {
 		return false
 	}
-	s := interp.universe.sym[n.child[0].ident]
-	return s != nil && s.kind == bltnSym
+	// The builtin may be shadowed by a declaration in an inner scope.
+	s, _, ok := sc.lookup(n.child[0].ident)
+	return ok && s.kind == bltnSym
+}
+
+// minMaxType returns the type of a min or max builtin call n: the type of its
+// typed arguments, which must be identical, or the untyped type of its
+// constant arguments. The arguments must be ordered.
+func minMaxType(interp *Interpreter, sc *scope, n *node) (*itype, error) {
+	name := n.child[0].ident
+	if len(n.child) < 2 {
+		return nil, n.cfgErrorf("not enough arguments for %s() (expected 1, found 0)", name)
+	}
+	var typed, untyped *itype
+	for _, c := range n.child[1:] {
+		t, err := nodeType(interp, sc, c)
+		if err != nil {
+			return nil, err
+		}
+		if t.incomplete {
+			return t, nil
+		}
+		rt := t.TypeOf()
+		if !isNumber(rt) && !isString(rt) || isComplex(rt) {
+			return nil, c.cfgErrorf("invalid argument: %s cannot be ordered", t.id())
+		}
+		prev := typed
+		if prev == nil {
+			prev = untyped
+		}
+		if prev != nil && isString(prev.TypeOf()) != isString(rt) || !t.untyped && typed != nil && typed.id() != t.id() {
+			return nil, c.cfgErrorf("invalid argument: mismatched types %s and %s in %s", prev.id(), t.id(), name)
+		}
+		switch {
+		case !t.untyped:
+			typed = t
+		case untyped == nil || untypedRank(t) > untypedRank(untyped):
+			untyped = t
+		}
+	}
+	if typed != nil {
+		return typed, nil
+	}
+	return untyped, nil
+}
+
+// untypedRank returns the rank of an untyped numeric type: the untyped
+// result of an operation on untyped operands has the highest rank.
+func untypedRank(t *itype) int {
+	switch t.cat {
+	case int32T:
+		return 1
+	case float64T:
+		return 2
+	}
+	return 0
+}
+
+// isNil returns true if n is the predeclared nil identifier, which may be
+// shadowed by a declaration in an inner scope.
+func (interp *Interpreter) isNil(n *node, sc *scope) bool {
+	if n.kind != identExpr || n.ident != nilIdent {
+		return false
+	}
+	s, _, ok := sc.lookup(nilIdent)
+	return ok && s == interp.universe.sym[nilIdent]
 }
 
 // struct name returns the name of a struct type.
@@ -656,6 +823,64 @@ func typeName(n *node) string {
 }
 
 // fieldName returns an implicit struct field name according to node kind.
+// binInterfaceType returns the reflect type of t if t is a binary interface, or nil.
+func binInterfaceType(t *itype) reflect.Type {
+	switch {
+	case t.cat == errorT:
+		return errorType
+	case t.cat == valueT && t.rtype.Kind() == reflect.Interface:
+		return t.rtype
+	}
+	return nil
+}
+
+// binFuncType returns the interpreter func type of the binary func type rt.
+func binFuncType(rt reflect.Type, sc *scope) *itype {
+	t := &itype{cat: funcT, scope: sc}
+	for i := 0; i < rt.NumIn(); i++ {
+		if rt.IsVariadic() && i == rt.NumIn()-1 {
+			t.arg = append(t.arg, &itype{cat: variadicT, val: &itype{cat: valueT, rtype: rt.In(i).Elem(), scope: sc}, scope: sc})
+			break
+		}
+		t.arg = append(t.arg, &itype{cat: valueT, rtype: rt.In(i), scope: sc})
+	}
+	for i := 0; i < rt.NumOut(); i++ {
+		t.ret = append(t.ret, &itype{cat: valueT, rtype: rt.Out(i), scope: sc})
+	}
+	return t
+}
+
+// mergeMethods returns the interface fields, where methods identical to
+// one already provided by a previous field are removed. Methods with the same
+// name but different signatures are reported as duplicate.
+func mergeMethods(fields []structField, decl []*node) ([]structField, error) {
+	sigs := map[string]string{}
+	res := make([]structField, 0, len(fields))
+	for i, f := range fields {
+		ms := methodSet{f.name: ""}
+		if f.embed {
+			ms = f.typ.methods()
+		} else {
+			ms[f.name] = f.typ.TypeOf().String()
+		}
+		dup := false
+		for name, sig := range ms {
+			if s, ok := sigs[name]; ok {
+				if s != sig {
+					return nil, decl[i].cfgErrorf("duplicate method %s", name)
+				}
+				dup = true
+			}
+			sigs[name] = sig
+		}
+		if dup && !f.embed {
+			continue
+		}
+		res = append(res, f)
+	}
+	return res, nil
+}
+
 func fieldName(n *node) string {
 	switch n.kind {
 	case selectorExpr:
@@ -722,6 +947,27 @@ func (t *itype) finalize() (*itype, error) {
 	return t, err
 }
 
+// selfReference returns true if the type name n is part of def, the type
+// expression of its own definition, and if def then contains itself by value,
+// i.e. not through a pointer, slice, map, channel, function or interface.
+func selfReference(n, def *node) (found, byValue bool) {
+	byValue = true
+	for a := n.anc; a != nil; a = a.anc {
+		switch a.kind {
+		case starExpr, mapType, chanType, chanTypeRecv, chanTypeSend, funcType, interfaceType:
+			byValue = false
+		case arrayType:
+			if len(a.child) == 1 {
+				byValue = false
+			}
+		}
+		if a == def {
+			return true, byValue
+		}
+	}
+	return false, false
+}
+
 // ReferTo returns true if the type contains a reference to a
 // full type name. It allows to asses a type recursive status.
 func (t *itype) referTo(name string, seen map[*itype]bool) bool {
@@ -758,6 +1004,39 @@ func (t *itype) referTo(name string, seen map[*itype]bool) bool {
 	return false
 }
 
+// HasValueCycle returns true if the type contains itself by value, i.e. not
+// through a pointer, slice, map, channel, function or interface, which makes
+// it an invalid recursive type.
+func (t *itype) hasValueCycle() bool {
+	if t.name == "" {
+		return false
+	}
+	return t.valueReferTo(t.path+"/"+t.name, map[*itype]bool{}, true)
+}
+
+func (t *itype) valueReferTo(name string, seen map[*itype]bool, root bool) bool {
+	if !root && t.path+"/"+t.name == name {
+		return true
+	}
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+	switch t.cat {
+	case aliasT:
+		return t.val.valueReferTo(name, seen, false)
+	case arrayT:
+		return t.sizedef && t.val.valueReferTo(name, seen, false)
+	case structT:
+		for _, f := range t.field {
+			if f.typ.valueReferTo(name, seen, false) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (t *itype) numOut() int {
 	switch t.cat {
 	case funcT:
@@ -841,6 +1120,10 @@ func (t *itype) comparable() bool {
 }
 
 func (t *itype) assignableTo(o *itype) bool {
+	if t.isBinMethod {
+		// A binary method value has the type of the method without receiver.
+		t = &itype{cat: valueT, rtype: t.methodCallType()}
+	}
 	if t.equals(o) {
 		return true
 	}
@@ -851,6 +1134,10 @@ func (t *itype) assignableTo(o *itype) bool {
 	if t.isNil() && o.hasNil() || o.isNil() && t.hasNil() {
 		return true
 	}
+	if t.isDefined() && o.isDefined() && !isInterface(o) {
+		// Distinct defined types are never assignable, even with identical underlying types.
+		return false
+	}
 	return t.TypeOf().AssignableTo(o.TypeOf())
 }
 
@@ -895,6 +1182,17 @@ func (m methodSet) equals(n methodSet) bool {
 // Methods returns a map of method type strings, indexed by method names.
 func (t *itype) methods() methodSet {
 	res := make(methodSet)
+	t.addMethods(res, map[*itype]bool{})
+	return res
+}
+
+// addMethods adds the methods of t to res, visiting the embedded types once,
+// as they may be recursive.
+func (t *itype) addMethods(res methodSet, visited map[*itype]bool) {
+	if visited[t] {
+		return
+	}
+	visited[t] = true
 	switch t.cat {
 	case interfaceT:
 		// Get methods from recursive analysis of interface fields.
@@ -902,25 +1200,42 @@ func (t *itype) methods() methodSet {
 			if f.typ.cat == funcT {
 				res[f.name] = f.typ.TypeOf().String()
 			} else {
-				for k, v := range f.typ.methods() {
-					res[k] = v
-				}
+				f.typ.addMethods(res, visited)
 			}
 		}
 	case valueT, errorT:
 		// Get method from corresponding reflect.Type.
 		for i := t.rtype.NumMethod() - 1; i >= 0; i-- {
 			m := t.rtype.Method(i)
+			if m.PkgPath != "" {
+				// The unexported methods of an interface can not be implemented
+				// by interpreted types, but by the wrapper of the interface.
+				continue
+			}
+			if t.rtype.Kind() != reflect.Interface {
+				// Remove the receiver from the method type.
+				res[m.Name] = (&itype{rtype: m.Type}).methodCallType().String()
+				continue
+			}
 			res[m.Name] = m.Type.String()
 		}
 	case ptrT:
-		for k, v := range t.val.methods() {
-			res[k] = v
+		if t.val.cat == valueT {
+			// The method set of a pointer to a binary type includes the methods
+			// with a pointer receiver.
+			rt := reflect.PtrTo(t.val.rtype)
+			for i := rt.NumMethod() - 1; i >= 0; i-- {
+				m := rt.Method(i)
+				res[m.Name] = (&itype{rtype: m.Type}).methodCallType().String()
+			}
+			break
 		}
+		t.val.addMethods(res, visited)
 	case structT:
+		// Methods are promoted from embedded fields only.
 		for _, f := range t.field {
-			for k, v := range f.typ.methods() {
-				res[k] = v
+			if f.embed {
+				f.typ.addMethods(res, visited)
 			}
 		}
 	}
@@ -928,7 +1243,6 @@ func (t *itype) methods() methodSet {
 	for _, m := range t.method {
 		res[m.ident] = m.typ.TypeOf().String()
 	}
-	return res
 }
 
 // id returns a unique type identificator string.
@@ -943,7 +1257,11 @@ func (t *itype) id() (res string) {
 	case nilT:
 		res = "nil"
 	case arrayT:
-		res = "[" + strconv.Itoa(t.size) + "]" + t.val.id()
+		if t.sizedef {
+			res = "[" + strconv.Itoa(t.size) + "]" + t.val.id()
+		} else {
+			res = "[]" + t.val.id()
+		}
 	case chanT:
 		res = "chan " + t.val.id()
 	case chanSendT:
@@ -961,11 +1279,14 @@ func (t *itype) id() (res string) {
 		}
 		res += ")"
 	case interfaceT:
-		res = "interface{"
-		for _, t := range t.field {
-			res += t.name + " " + t.typ.id() + ";"
-		}
-		res += "}"
+		// Methods are sorted, as the identity of interfaces does not depend on
+		// the order of declaration.
+		methods := make([]string, len(t.field))
+		for i, t := range t.field {
+			methods[i] = t.name + " " + t.typ.id() + ";"
+		}
+		sort.Strings(methods)
+		res = "interface{" + strings.Join(methods, "") + "}"
 	case mapT:
 		res = "map[" + t.key.id() + "]" + t.val.id()
 	case ptrT:
@@ -973,10 +1294,20 @@ func (t *itype) id() (res string) {
 	case structT:
 		res = "struct{"
 		for _, t := range t.field {
-			res += t.name + " " + t.typ.id() + ";"
+			res += t.name + " " + t.typ.id()
+			if t.tag != "" {
+				res += " " + strconv.Quote(t.tag)
+			}
+			res += ";"
 		}
 		res += "}"
+	case variadicT:
+		res = "..." + t.val.id()
 	case valueT:
+		if t.rtype.Name() == "" {
+			// Unnamed binary type, i.e. []byte.
+			return t.rtype.String()
+		}
 		res = ""
 		if t.rtype.PkgPath() != "" {
 			res += t.rtype.PkgPath() + "."
@@ -1009,6 +1340,9 @@ func (t *itype) zero() (v reflect.Value, err error) {
 
 // fieldIndex returns the field index from name in a struct, or -1 if not found.
 func (t *itype) fieldIndex(name string) int {
+	if name == "_" {
+		return -1 // blank fields can not be referred to
+	}
 	switch t.cat {
 	case aliasT, ptrT:
 		return t.val.fieldIndex(name)
@@ -1038,43 +1372,141 @@ func (t *itype) lookupField(name string) []int {
 	switch t.cat {
 	case aliasT, ptrT:
 		return t.val.lookupField(name)
-	}
-	if fi := t.fieldIndex(name); fi >= 0 {
-		return []int{fi}
-	}
-
-	for i, f := range t.field {
-		switch f.typ.cat {
-		case ptrT, structT, interfaceT, aliasT:
-			if index2 := f.typ.lookupField(name); len(index2) > 0 {
-				return append([]int{i}, index2...)
+	case interfaceT:
+		if fi := t.fieldIndex(name); fi >= 0 {
+			return []int{fi}
+		}
+		for i, f := range t.field {
+			if f.typ.cat == interfaceT {
+				if index2 := f.typ.lookupField(name); len(index2) > 0 {
+					return append([]int{i}, index2...)
+				}
 			}
 		}
+		return nil
+	}
+	if s := t.lookupSelector(name); len(s) == 1 && s[0].kind == fieldSel {
+		return s[0].index
 	}
-
 	return nil
 }
 
 // lookupBinField returns a structfield and a path to access an embedded binary field in a struct object.
 func (t *itype) lookupBinField(name string) (s reflect.StructField, index []int, ok bool) {
-	if t.cat == ptrT {
-		return t.val.lookupBinField(name)
-	}
 	if !isStruct(t) {
 		return
 	}
-	s, ok = t.TypeOf().FieldByName(name)
-	if !ok {
-		for i, f := range t.field {
-			if f.embed {
-				if s2, index2, ok2 := f.typ.lookupBinField(name); ok2 {
-					index = append([]int{i}, index2...)
-					return s2, index, ok2
+	if sel := t.lookupSelector(name); len(sel) == 1 && sel[0].kind == binFieldSel {
+		return sel[0].field, sel[0].index, true
+	}
+	return
+}
+
+// selKind is the kind of a selection.
+type selKind uint
+
+const (
+	fieldSel     selKind = iota // field of an interpreted struct
+	methodSel                   // method of an interpreted type
+	binFieldSel                 // field of a binary struct
+	binMethodSel                // method of a binary type
+)
+
+// selection is a field or method selected by name in a type, possibly
+// promoted from embedded fields.
+type selection struct {
+	kind   selKind
+	path   string              // names of the embedded fields and name, i.e. "B.A.X"
+	index  []int               // field indexes of a field, or of the binary struct or receiver holding it
+	method *node               // method of an interpreted type
+	field  reflect.StructField // field of a binary struct
+	bin    reflect.Method      // method of a binary type
+	isPtr  bool                // binary method with a pointer receiver
+	owner  *itype              // interpreted type declaring the field or method
+}
+
+// lookupSelector returns the fields and methods called name in t, at the
+// shallowest depth of embedding where it is found, as per the Go selector
+// rules. The selector is ambiguous if more than one is returned.
+func (t *itype) lookupSelector(name string) []selection {
+	type embedded struct {
+		typ   *itype
+		path  string
+		index []int
+	}
+	found := map[int][]selection{} // selections by depth
+	seen := map[*itype]bool{}
+	add := func(depth int, s selection) { found[depth] = append(found[depth], s) }
+	level := []embedded{{typ: t}}
+	for depth := 0; len(level) > 0 || len(found) > 0; depth++ {
+		var next []embedded
+		visited := map[*itype]bool{}
+		for _, e := range level {
+			typ := e.typ
+			if typ.cat == ptrT {
+				typ = typ.val
+			}
+			if seen[typ] {
+				// Already visited at a shallower depth.
+				continue
+			}
+			visited[typ] = true
+			index := func(i ...int) []int { return append(append([]int{}, e.index...), i...) }
+
+			if typ.cat == valueT {
+				rt := typ.rtype
+				if m, ok := rt.MethodByName(name); ok {
+					add(depth, selection{kind: binMethodSel, path: e.path + name, index: e.index, bin: m})
+				} else if m, ok := reflect.PtrTo(rt).MethodByName(name); ok && rt.Kind() != reflect.Ptr && rt.Kind() != reflect.Interface {
+					add(depth, selection{kind: binMethodSel, path: e.path + name, index: e.index, bin: m, isPtr: true})
+				}
+				if rt.Kind() == reflect.Ptr {
+					rt = rt.Elem()
+				}
+				if rt.Kind() == reflect.Struct {
+					if f, ok := rt.FieldByName(name); ok {
+						add(depth+len(f.Index)-1, selection{kind: binFieldSel, path: e.path + name, index: index(), field: f})
+					}
+				}
+				continue
+			}
+
+			if m := typ.getMethod(name); m != nil {
+				add(depth, selection{kind: methodSel, path: e.path + name, index: e.index, method: m, owner: typ})
+			}
+			st := typ
+			for st.cat == aliasT {
+				st = st.val
+			}
+			switch st.cat {
+			case interfaceT:
+				// The methods of an embedded interface are handled as fields.
+				if depth > 0 {
+					if fi := st.fieldIndex(name); fi >= 0 {
+						add(depth, selection{kind: fieldSel, path: e.path + name, index: index(fi), owner: st})
+					}
+				}
+			case structT:
+				for i, f := range st.field {
+					if f.name == name {
+						add(depth, selection{kind: fieldSel, path: e.path + name, index: index(i), owner: st})
+					}
+					if f.embed {
+						next = append(next, embedded{typ: f.typ, path: e.path + f.name + ".", index: index(i)})
+					}
 				}
 			}
 		}
+		if s := found[depth]; len(s) > 0 {
+			return s
+		}
+		delete(found, depth)
+		for typ := range visited {
+			seen[typ] = true
+		}
+		level = next
 	}
-	return s, index, ok
+	return nil
 }
 
 // MethodCallType returns a method function type without the receiver defined.
@@ -1103,48 +1535,47 @@ func (t *itype) getMethod(name string) *node {
 	return nil
 }
 
+// selectionPaths returns the paths of the selections s, for error messages.
+func selectionPaths(s []selection) string {
+	paths := make([]string, len(s))
+	for i, sel := range s {
+		paths[i] = sel.path
+	}
+	return strings.Join(paths, " and ")
+}
+
 // LookupMethod returns a pointer to method definition associated to type t
 // and the list of indices to access the right struct field, in case of an embedded method.
 func (t *itype) lookupMethod(name string) (*node, []int) {
-	if t.cat == ptrT {
-		return t.val.lookupMethod(name)
-	}
-	var index []int
-	m := t.getMethod(name)
-	if m == nil {
-		for i, f := range t.field {
-			if f.embed {
-				if n, index2 := f.typ.lookupMethod(name); n != nil {
-					index = append([]int{i}, index2...)
-					return n, index
-				}
-			}
-		}
+	if s := t.lookupSelector(name); len(s) == 1 && s[0].kind == methodSel {
+		return s[0].method, s[0].index
 	}
-	return m, index
+	return nil, nil
 }
 
 // LookupBinMethod returns a method and a path to access a field in a struct object (the receiver).
 func (t *itype) lookupBinMethod(name string) (m reflect.Method, index []int, isPtr bool, ok bool) {
-	if t.cat == ptrT {
-		return t.val.lookupBinMethod(name)
+	if s := t.lookupSelector(name); len(s) == 1 && s[0].kind == binMethodSel {
+		return s[0].bin, s[0].index, s[0].isPtr, true
 	}
-	m, ok = t.TypeOf().MethodByName(name)
-	if !ok {
-		m, ok = reflect.PtrTo(t.TypeOf()).MethodByName(name)
-		isPtr = ok
-	}
-	if !ok {
-		for i, f := range t.field {
-			if f.embed {
-				if m2, index2, isPtr2, ok2 := f.typ.lookupBinMethod(name); ok2 {
-					index = append([]int{i}, index2...)
-					return m2, index, isPtr2, ok2
-				}
-			}
+	return m, index, isPtr, ok
+}
+
+// hasInterpMethods returns true if t has methods defined in the interpreter,
+// including the methods promoted from its embedded fields.
+func (t *itype) hasInterpMethods() bool {
+	for name := range t.methods() {
+		if m, _ := t.lookupMethod(name); m != nil {
+			return true
 		}
 	}
-	return m, index, isPtr, ok
+	return false
+}
+
+// hides returns true if the unexported field or method name, declared in
+// type t, can not be referred to from the package pkgID.
+func (t *itype) hides(name, pkgID string) bool {
+	return t != nil && !canExport(name) && t.scope != nil && t.scope.pkgID != pkgID
 }
 
 func exportName(s string) string {
@@ -1252,8 +1683,12 @@ func (t *itype) refType(defined map[string]*itype, wrapRecursive bool) reflect.T
 			defined[name] = t
 		}
 		var fields []reflect.StructField
-		for _, f := range t.field {
+		for i, f := range t.field {
 			field := reflect.StructField{Name: exportName(f.name), Type: f.typ.refType(defined, wrapRecursive), Tag: reflect.StructTag(f.tag)}
+			if f.name == "_" {
+				// Blank fields take space, but reflect requires distinct names.
+				field.Name += strconv.Itoa(i)
+			}
 			fields = append(fields, field)
 		}
 		if recursive && wrapRecursive {
@@ -1317,6 +1752,10 @@ func (t *itype) defaultType() *itype {
 
 func (t *itype) isNil() bool { return t.cat == nilT }
 
+// isDefined returns true if t is a typed predeclared or defined type, which
+// is only identical to itself.
+func (t *itype) isDefined() bool { return t.name != "" && !t.untyped }
+
 func (t *itype) hasNil() bool {
 	switch t.TypeOf().Kind() {
 	case reflect.UnsafePointer:
@@ -1365,7 +1804,85 @@ func hasRecursiveStruct(t *itype, defined map[string]*itype) bool {
 	return false
 }
 
+// constExpr computes the value of the constant expression n in scope sc,
+// using the CFG constant folding. The value is nil if n is not constant.
+// It returns false if n depends on constants which are not yet known.
+func (interp *Interpreter) constExpr(sc *scope, n *node) (constant.Value, bool, error) {
+	if !n.rval.IsValid() {
+		if !isConstReady(sc, n) {
+			return nil, false, nil
+		}
+		if _, err := interp.cfgScope(n, sc); err != nil {
+			return nil, false, err
+		}
+	}
+	v := n.rval
+	if !v.IsValid() {
+		return nil, true, nil
+	}
+	if c, ok := n.constValue(); ok {
+		return c, true, nil
+	}
+	switch {
+	case isUint(v.Type()):
+		return constant.MakeUint64(v.Uint()), true, nil
+	case isInt(v.Type()):
+		return constant.MakeInt64(v.Int()), true, nil
+	case isFloat(v.Type()):
+		return constant.MakeFloat64(v.Float()), true, nil
+	case isString(v.Type()):
+		return constant.MakeString(v.String()), true, nil
+	case isBoolean(v.Type()):
+		return constant.MakeBool(v.Bool()), true, nil
+	}
+	return constant.MakeUnknown(), true, nil
+}
+
+// isConstReady returns true if all the symbols used in expression n are
+// defined, and the constants have a known value.
+func isConstReady(sc *scope, n *node) bool {
+	baseName := filepath.Base(n.interp.fset.Position(n.pos).Filename)
+	ready := true
+	n.Walk(func(c *node) bool {
+		if !ready {
+			return false
+		}
+		switch c.kind {
+		case selectorExpr:
+			// Only the package or the receiver is resolved in scope.
+			c = c.child[0]
+			if c.kind != identExpr {
+				return true
+			}
+		case identExpr:
+		default:
+			return true
+		}
+		sym, _, ok := sc.lookup(c.ident)
+		if !ok {
+			sym, _, ok = sc.lookup(filepath.Join(c.ident, baseName))
+		}
+		ready = ok && (sym.kind != constSym || sym.rval.IsValid())
+		return false
+	}, nil)
+	return ready
+}
+
+// arrayLen returns the array length from the constant c of length expression n.
+func arrayLen(n *node, c constant.Value) (int, error) {
+	i := constant.ToInt(c)
+	if i.Kind() != constant.Int {
+		return 0, n.cfgErrorf("array length %s must be integer", c)
+	}
+	if constant.Sign(i) < 0 || constant.BitLen(i) > 63 {
+		return 0, n.cfgErrorf("invalid array length %s", c)
+	}
+	l, _ := constant.Int64Val(i)
+	return int(l), nil
+}
+
 func constToInt(c constant.Value) int {
+	c = constant.ToInt(c)
 	if constant.BitLen(c) > 64 {
 		panic(fmt.Sprintf("constant %s overflows int64", c.ExactString()))
 	}
@@ -1404,12 +1921,31 @@ func chanElement(t *itype) *itype {
 	return nil
 }
 
+// mapElement returns the map element type.
+func mapElement(t *itype) *itype {
+	switch t.cat {
+	case aliasT:
+		return mapElement(t.val)
+	case mapT:
+		return t.val
+	case valueT:
+		return &itype{cat: valueT, rtype: t.rtype.Elem(), node: t.node, scope: t.scope}
+	}
+	return nil
+}
+
 func isBool(t *itype) bool { return t.TypeOf().Kind() == reflect.Bool }
 func isChan(t *itype) bool { return t.TypeOf().Kind() == reflect.Chan }
 func isFunc(t *itype) bool { return t.TypeOf().Kind() == reflect.Func }
 func isMap(t *itype) bool  { return t.TypeOf().Kind() == reflect.Map }
 func isPtr(t *itype) bool  { return t.TypeOf().Kind() == reflect.Ptr }
 
+// isPtrPtr returns true if t is a pointer to a pointer type.
+func isPtrPtr(t *itype) bool {
+	rt := t.TypeOf()
+	return rt != nil && rt.Kind() == reflect.Ptr && rt.Elem().Kind() == reflect.Ptr
+}
+
 func isSendChan(t *itype) bool {
 	rt := t.TypeOf()
 	return rt.Kind() == reflect.Chan && rt.ChanDir() == reflect.SendDir
@@ -1424,6 +1960,11 @@ func isInterfaceSrc(t *itype) bool {
 	return t.cat == interfaceT || (t.cat == aliasT && isInterfaceSrc(t.val))
 }
 
+// isInterfaceBin returns true if t is a binary interface, including error.
+func isInterfaceBin(t *itype) bool {
+	return (t.cat == valueT || t.cat == errorT) && t.TypeOf().Kind() == reflect.Interface
+}
+
 func isInterface(t *itype) bool {
 	return isInterfaceSrc(t) || t.TypeOf() != nil && t.TypeOf().Kind() == reflect.Interface
 }
@@ -1493,6 +2034,18 @@ func isFloat(t reflect.Type) bool {
 	return false
 }
 
+// isUnsafeConvertible returns true if from and to are an unsafe.Pointer and a
+// pointer or uintptr, which reflect does not report as convertible.
+func isUnsafeConvertible(from, to reflect.Type) bool {
+	switch {
+	case from.Kind() == reflect.UnsafePointer:
+		return to.Kind() == reflect.Ptr || to.Kind() == reflect.Uintptr
+	case to.Kind() == reflect.UnsafePointer:
+		return from.Kind() == reflect.Ptr || from.Kind() == reflect.Uintptr
+	}
+	return false
+}
+
 func isByteArray(t reflect.Type) bool {
 	if t == nil {
 		return false
@@ -1506,6 +2059,7 @@ func isFloat64(t reflect.Type) bool { return t != nil && t.Kind() == reflect.Flo
 func isNumber(t reflect.Type) bool {
 	return isInt(t) || isFloat(t) || isComplex(t) || isConstantValue(t)
 }
+func isBasicType(t reflect.Type) bool     { return isNumber(t) || isBoolean(t) || isString(t) }
 func isBoolean(t reflect.Type) bool       { return t != nil && t.Kind() == reflect.Bool }
 func isString(t reflect.Type) bool        { return t != nil && t.Kind() == reflect.String }
 func isConstantValue(t reflect.Type) bool { return t != nil && t.Implements(constVal) }
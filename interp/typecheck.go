@@ -3,8 +3,11 @@ package interp
 import (
 	"errors"
 	"go/constant"
+	"go/token"
 	"math"
 	"reflect"
+	"strings"
+	"unicode"
 )
 
 type opPredicates map[action]func(reflect.Type) bool
@@ -32,11 +35,16 @@ func (check typecheck) op(p opPredicates, a action, n, c *node, t reflect.Type)
 //
 // This is done per pair of assignments.
 func (check typecheck) assignExpr(n, dest, src *node) error {
+	if err := check.assignDest(dest); err != nil {
+		return err
+	}
 	if n.action == aAssign {
 		isConst := n.anc.kind == constDecl
 		if !isConst {
 			// var operations must be typed
 			dest.typ = dest.typ.defaultType()
+		} else if !src.rval.IsValid() {
+			return src.cfgErrorf("%s (value of type %s) is not constant", exprString(src), src.typ.id())
 		}
 
 		if src.typ.untyped {
@@ -63,6 +71,92 @@ func (check typecheck) assignExpr(n, dest, src *node) error {
 	return check.binaryExpr(n)
 }
 
+// assignCommaOk type checks the destinations of the comma-ok assignment n,
+// of a value of type typ, and of an untyped boolean status.
+func (check typecheck) assignCommaOk(n *node, typ, boolType *itype) error {
+	dest0, dest1, src := n.child[0], n.child[1], n.lastChild()
+	if dest0.ident != "_" && !typ.assignableTo(dest0.typ) {
+		return src.cfgErrorf("cannot use type %s as type %s in assignment", typ.id(), dest0.typ.id())
+	}
+	if dest1.ident != "_" && !isBool(dest1.typ) && !boolType.assignableTo(dest1.typ) {
+		return src.cfgErrorf("cannot use untyped bool as type %s in assignment", dest1.typ.id())
+	}
+	return nil
+}
+
+// assignDest type checks that dest can be the destination of an assignment.
+// A field of a struct stored in a map is not addressable, unlike the
+// field of a struct pointed to by a map element.
+func (check typecheck) assignDest(dest *node) error {
+	if dest.kind == indexExpr && isString(dest.child[0].typ.TypeOf()) {
+		return dest.cfgErrorf("cannot assign to %s (strings are immutable)", exprString(dest))
+	}
+	for c := dest; c.kind == selectorExpr; c = c.child[0] {
+		c0 := c.child[0]
+		switch {
+		case isMapEntry(c0) && !isPtr(c0.typ) && c != dest:
+			return dest.cfgErrorf("cannot assign to %s (neither addressable nor a map index expression)", exprString(dest))
+		case isMapEntry(c0) && !isPtr(c0.typ):
+			return dest.cfgErrorf("cannot assign to struct field %s in map", exprString(dest))
+		case c0.kind != selectorExpr || isPtr(c0.typ):
+			return nil
+		}
+	}
+	return nil
+}
+
+// incDecStmt type checks an increment or decrement statement n, of which the
+// operand must be a numeric variable or map entry.
+func (check typecheck) incDecStmt(n *node) error {
+	c0 := n.child[0]
+	if !isNumber(c0.typ.TypeOf()) {
+		return n.cfgErrorf("invalid operation: %s%v (non-numeric type %s)", exprString(c0), n.action, c0.typ.id())
+	}
+	if err := check.assignDest(c0); err != nil {
+		return err
+	}
+	if !isAddressable(c0) && !isMapEntry(c0) {
+		return n.cfgErrorf("cannot assign to %s (neither addressable nor a map index expression)", exprString(c0))
+	}
+	return nil
+}
+
+// exprString returns the source form of expression n, for error messages.
+func exprString(n *node) string {
+	switch n.kind {
+	case identExpr, basicLit:
+		return n.ident
+	case selectorExpr:
+		return exprString(n.child[0]) + "." + exprString(n.child[1])
+	case indexExpr:
+		return exprString(n.child[0]) + "[" + exprString(n.child[1]) + "]"
+	case starExpr:
+		return "*" + exprString(n.child[0])
+	case parenExpr:
+		return "(" + exprString(n.child[0]) + ")"
+	case binaryExpr:
+		return exprString(n.child[0]) + " " + n.action.String() + " " + exprString(n.child[1])
+	case callExpr:
+		args := make([]string, len(n.child)-1)
+		for i, c := range n.child[1:] {
+			args[i] = exprString(c)
+		}
+		return exprString(n.child[0]) + "(" + strings.Join(args, ", ") + ")"
+	case compositeLitExpr:
+		var s string
+		if n.nleft > 0 && n.typ.name != "" {
+			s = n.typ.name
+		} else if n.nleft > 0 {
+			s = n.typ.id()
+		}
+		if len(n.child) > n.nleft {
+			return s + "{…}"
+		}
+		return s + "{}"
+	}
+	return n.kind.String()
+}
+
 // addressExpr type checks a unary address expression.
 func (check typecheck) addressExpr(n *node) error {
 	c0 := n.child[0]
@@ -90,6 +184,201 @@ func (check typecheck) addressExpr(n *node) error {
 	return nil
 }
 
+// indexExpr type checks the constant index of an index expression n, which
+// must be in range when indexing an array or a constant string.
+func (check typecheck) indexExpr(n *node) error {
+	c0, c1 := n.child[0], n.child[1]
+	if !c1.rval.IsValid() || isMap(c0.typ) {
+		return nil
+	}
+	i, ok := constIndex(c1)
+	if !ok {
+		return nil
+	}
+	if i < 0 {
+		return c1.cfgErrorf("invalid argument: index %d (constant of type int) must not be negative", i)
+	}
+	l := -1
+	switch t := c0.typ.TypeOf(); {
+	case t.Kind() == reflect.Array:
+		l = t.Len()
+	case c0.rval.IsValid() && isString(t):
+		if v, ok := c0.constValue(); ok {
+			l = len(constant.StringVal(v))
+		} else {
+			l = c0.rval.Len()
+		}
+	}
+	if l >= 0 && i >= l {
+		return c1.cfgErrorf("invalid argument: index %d out of bounds [0:%d]", i, l)
+	}
+	return nil
+}
+
+// arrayLitExpr type checks the indexes of an array or slice literal elements.
+// The length l is the array length, or -1 for a slice.
+func (check typecheck) arrayLitExpr(child []*node, l int) error {
+	visited := make(map[int]bool, len(child))
+	index := 0
+	for _, c := range child {
+		n := c
+		if c.kind == keyValueExpr {
+			n = c.child[0]
+			i, ok := constIndex(n)
+			if !ok || i < 0 {
+				return n.cfgErrorf("index must be non-negative integer constant")
+			}
+			index = i
+		}
+		if l >= 0 && index >= l {
+			return n.cfgErrorf("index %d out of bounds [0:%d]", index, l)
+		}
+		if visited[index] {
+			return n.cfgErrorf("duplicate index %d in array or slice literal", index)
+		}
+		visited[index] = true
+		index++
+	}
+	return nil
+}
+
+// compositeLitValues converts the untyped constant values of the composite
+// literal elements child to the element type typ.
+func (check typecheck) compositeLitValues(child []*node, typ *itype) error {
+	if isInterface(typ) {
+		return nil
+	}
+	for _, c := range child {
+		if c.kind == keyValueExpr {
+			c = c.child[1]
+		}
+		if !c.rval.IsValid() {
+			continue
+		}
+		if err := check.convertUntyped(c, typ); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// structLitExpr type checks the elements of the struct literal n, which must
+// either all be keyed by distinct field names, or all be unkeyed and provide
+// a value for each field in order. The unexported fields of binary struct
+// types, or of interpreted ones declared outside of the package pkgID, can
+// not be initialized.
+func (check typecheck) structLitExpr(n *node, pkgID string) error {
+	child := n.child[n.nleft:]
+	if len(child) == 0 {
+		return nil
+	}
+
+	var (
+		names  []string
+		types  []*itype
+		hidden []bool
+	)
+	switch n.typ.cat {
+	case structT:
+		for _, f := range n.typ.field {
+			names = append(names, f.name)
+			types = append(types, f.typ)
+			hidden = append(hidden, n.typ.hides(f.name, pkgID))
+		}
+	case valueT:
+		for rt, i := n.typ.rtype, 0; i < rt.NumField(); i++ {
+			f := rt.Field(i)
+			names = append(names, f.Name)
+			types = append(types, &itype{cat: valueT, rtype: f.Type})
+			hidden = append(hidden, f.PkgPath != "")
+		}
+	}
+	tid := n.typ.id()
+
+	keyed := child[0].kind == keyValueExpr
+	set := map[string]bool{}
+	for i, c := range child {
+		if (c.kind == keyValueExpr) != keyed {
+			return c.cfgErrorf("mixture of field:value and value elements in struct literal")
+		}
+		v := c
+		if keyed {
+			name := c.child[0].ident
+			i = -1
+			for j, s := range names {
+				if s == name && name != "_" {
+					i = j
+				}
+			}
+			if i < 0 {
+				return c.child[0].cfgErrorf("unknown field %s in struct literal of type %s", name, tid)
+			}
+			if hidden[i] {
+				return c.child[0].cfgErrorf("cannot refer to unexported field %s in struct literal of type %s", name, tid)
+			}
+			if set[name] {
+				return c.child[0].cfgErrorf("duplicate field name %s in struct literal", name)
+			}
+			set[name] = true
+			v = c.child[1]
+		} else {
+			if i >= len(names) {
+				return c.cfgErrorf("too many values in struct literal of type %s", tid)
+			}
+			if hidden[i] {
+				return c.cfgErrorf("implicit assignment to unexported field %s in struct literal of type %s", names[i], tid)
+			}
+		}
+		if err := check.fieldValue(v, types[i]); err != nil {
+			return err
+		}
+	}
+	if !keyed && len(child) < len(names) {
+		return n.cfgErrorf("too few values in struct literal of type %s", tid)
+	}
+	return nil
+}
+
+// fieldValue checks that the value n of a struct literal is assignable to
+// the field type typ, converting it if it is an untyped constant.
+func (check typecheck) fieldValue(n *node, typ *itype) error {
+	if n.typ == nil {
+		return nil
+	}
+	tid := n.typ.id()
+	if n.typ.untyped {
+		tid = "untyped " + tid
+		t := typ
+		if t.isNil() || isInterface(t) {
+			t = n.typ.defaultType()
+		}
+		if err := check.convertUntyped(n, t); err != nil {
+			return n.cfgErrorf("cannot use %s (type %s) as type %s in struct literal", exprString(n), tid, typ.id())
+		}
+	}
+	if !n.typ.assignableTo(typ) {
+		return n.cfgErrorf("cannot use %s (type %s) as type %s in struct literal", exprString(n), tid, typ.id())
+	}
+	return nil
+}
+
+// constIndex returns the value of the constant integer index n.
+func constIndex(n *node) (int, bool) {
+	v := n.rval
+	if !v.IsValid() {
+		return 0, false
+	}
+	if c, ok := n.constValue(); ok {
+		c = constant.ToInt(c)
+		i, exact := constant.Int64Val(c)
+		return int(i), c.Kind() == constant.Int && exact
+	}
+	if !isInt(v.Type()) {
+		return 0, false
+	}
+	return int(vInt(v)), true
+}
+
 var unaryOpPredicates = opPredicates{
 	aPos:    isNumber,
 	aNeg:    isNumber,
@@ -166,7 +455,45 @@ func (check typecheck) comparison(n *node) error {
 		if typ.isNil() {
 			typ = c1.typ
 		}
-		return n.cfgErrorf("invalid operation: operator %v not defined on %s", n.action, typ.id(), ".")
+		if typ.hasNil() && !isInterface(typ) && (n.action == aEqual || n.action == aNotEqual) {
+			return n.cfgErrorf("invalid operation: operator %v not defined on %s (can only be compared to nil)", n.action, typ.id())
+		}
+		return n.cfgErrorf("invalid operation: operator %v not defined on %s", n.action, typ.id())
+	}
+	return nil
+}
+
+// switchCases type checks that the constant case values of the expression
+// switch n are all different, once converted to the type of the switch tag.
+func (check typecheck) switchCases(n *node) error {
+	type caseKey struct {
+		id  string
+		val interface{}
+	}
+	tag := n.child[len(n.child)-2]
+	used := map[caseKey]*node{}
+	for _, clause := range n.lastChild().child {
+		if len(clause.child) < 2 {
+			continue // default clause
+		}
+		for _, c := range clause.child[:len(clause.child)-1] {
+			if !c.rval.IsValid() || c.typ == nil || !isConstType(c.typ) {
+				continue // Not a constant case.
+			}
+			typ := tag.typ
+			if isInterface(typ) {
+				typ = c.typ
+			}
+			v, err := check.convertConst(c.rval, typ.TypeOf())
+			if err != nil || !v.IsValid() || !v.CanInterface() {
+				continue
+			}
+			k := caseKey{typ.id(), v.Interface()}
+			if prev, ok := used[k]; ok {
+				return c.cfgErrorf("duplicate case %s in expression switch\n\tprevious case at %v", exprString(c), n.interp.fset.Position(prev.pos))
+			}
+			used[k] = c
+		}
 	}
 	return nil
 }
@@ -199,8 +526,16 @@ func (check typecheck) binaryExpr(n *node) error {
 		return check.shift(n)
 	}
 
-	_ = check.convertUntyped(c0, c1.typ)
-	_ = check.convertUntyped(c1, c0.typ)
+	if c0.typ.isNil() && c1.typ.isNil() {
+		return n.cfgErrorf("invalid operation: operator %v not defined on nil", n.action)
+	}
+
+	if err := check.binaryOperand(c0, c1.typ); err != nil {
+		return err
+	}
+	if err := check.binaryOperand(c1, c0.typ); err != nil {
+		return err
+	}
 
 	if isComparisonAction(a) {
 		return check.comparison(n)
@@ -224,6 +559,21 @@ func (check typecheck) binaryExpr(n *node) error {
 	return nil
 }
 
+// binaryOperand converts the untyped operand n to the type of the other
+// operand. Only a numeric constant overflowing a numeric type of the same
+// kind is an error, other mismatches are reported by the caller.
+func (check typecheck) binaryOperand(n *node, typ *itype) error {
+	err := check.convertUntyped(n, typ)
+	if err == nil || typ.untyped {
+		return nil
+	}
+	nt, t := n.typ.TypeOf(), typ.TypeOf()
+	if !isNumber(nt) || !isNumber(t) || isInt(t) && !isInt(nt) {
+		return nil
+	}
+	return err
+}
+
 var errCantConvert = errors.New("cannot convert")
 
 func (check typecheck) convertUntyped(n *node, typ *itype) error {
@@ -257,6 +607,9 @@ func (check typecheck) convertUntyped(n *node, typ *itype) error {
 		n.typ = typ
 		return nil
 	case isNumber(ttyp) || isString(ttyp) || isBoolean(ttyp):
+		if isString(ntyp) && !isString(ttyp) || isBoolean(ntyp) && !isBoolean(ttyp) {
+			return convErr
+		}
 		ityp = typ
 		rtyp = ttyp
 	case isInterface(typ):
@@ -294,6 +647,315 @@ func (check typecheck) convertUntyped(n *node, typ *itype) error {
 	return nil
 }
 
+// binArguments type checks the untyped constant arguments of the call n to
+// a binary function, and converts them to the parameter types.
+func (check typecheck) binArguments(n *node) error {
+	typ, args := n.child[0].typ.rtype, n.child[1:]
+	rcvrOffset := binRcvrOffset(n)
+	variadic := -1
+	if typ.IsVariadic() {
+		variadic = typ.NumIn() - 1 - rcvrOffset
+	}
+	for i, c := range args {
+		var t reflect.Type
+		switch {
+		case variadic >= 0 && i >= variadic:
+			if t = typ.In(variadic + rcvrOffset); n.action != aCallSlice {
+				t = t.Elem()
+			}
+		case i+rcvrOffset < typ.NumIn():
+			t = typ.In(i + rcvrOffset)
+		default:
+			return nil
+		}
+		if err := check.convertUntyped(c, &itype{cat: valueT, rtype: t}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// conversion type checks the conversion expression n, as defined by the
+// conversion rules of the Go specification. A constant value converted to
+// a constant type is converted in place.
+func (check typecheck) conversion(n *node) error {
+	c0, c1 := n.child[0], n.child[1]
+	if c1.isNil() {
+		switch c0.typ.TypeOf().Kind() {
+		case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Ptr, reflect.Slice, reflect.UnsafePointer:
+			return nil
+		}
+		return n.cfgErrorf("cannot convert nil to type %s", c0.typ.id())
+	}
+	if isInterface(c0.typ) {
+		if !c1.typ.implements(c0.typ) {
+			return n.cfgErrorf("type %v does not implement interface %v", c1.typ.id(), c0.typ.id())
+		}
+		return nil
+	}
+
+	from, to := c1.typ.TypeOf(), c0.typ.TypeOf()
+	if from == nil || to == nil {
+		return nil
+	}
+	if c, ok := c1.constValue(); ok && isConstType(c0.typ) {
+		return check.constConversion(n, c)
+	}
+	if isInterface(c1.typ) {
+		return n.cfgErrorf("cannot convert type %s to type %s: need type assertion", c1.typ.id(), c0.typ.id())
+	}
+	if from.ConvertibleTo(to) {
+		// Identical underlying types ignoring tags, numeric types, strings
+		// and slices of bytes or runes, integers to strings, and pointers
+		// to identical underlying base types.
+		return nil
+	}
+	if isUnsafeConvertible(from, to) {
+		// Pointers and uintptr values to and from unsafe.Pointer.
+		return nil
+	}
+	if isStructConversion(c1.typ, c0.typ) {
+		return nil
+	}
+	// Before go1.17, slices to arrays or array pointers are not handled by reflect.
+	if from.Kind() == reflect.Slice {
+		switch {
+		case to.Kind() == reflect.Array && from.Elem() == to.Elem():
+			return nil
+		case to.Kind() == reflect.Ptr && to.Elem().Kind() == reflect.Array && from.Elem() == to.Elem().Elem():
+			return nil
+		}
+	}
+	return n.cfgErrorf("cannot convert type %s to type %s", c1.typ.id(), c0.typ.id())
+}
+
+// isStructConversion returns true if one of the types from and to is an
+// interpreter type and the other a binary one, with identical underlying
+// struct types ignoring tags. Their reflect types may differ, as for
+// embedded or interface fields, so such a conversion copies the fields.
+func isStructConversion(from, to *itype) bool {
+	switch {
+	case from.cat == valueT && to.cat != valueT:
+		return identicalStruct(to, from.rtype)
+	case to.cat == valueT && from.cat != valueT:
+		return identicalStruct(from, to.rtype)
+	}
+	return false
+}
+
+// identicalStruct returns true if the underlying type of the interpreter
+// type t is a struct type identical to the underlying type of the binary
+// type rt, ignoring tags.
+func identicalStruct(t *itype, rt reflect.Type) bool {
+	for t.cat == aliasT {
+		t = t.val
+	}
+	if t.cat != structT || rt.Kind() != reflect.Struct || len(t.field) != rt.NumField() {
+		return false
+	}
+	for i, f := range t.field {
+		rf := rt.Field(i)
+		// Non-exported field names from different packages are always different.
+		if f.name != rf.Name || !canExport(f.name) || f.embed != rf.Anonymous || !identicalBinType(f.typ, rf.Type) {
+			return false
+		}
+	}
+	return true
+}
+
+// identicalBinType returns true if the interpreter type t is identical to
+// the binary type rt, ignoring struct tags. A type defined in the
+// interpreter is never identical to a binary type.
+func identicalBinType(t *itype, rt reflect.Type) bool {
+	switch t.cat {
+	case valueT:
+		return t.rtype == rt
+	case aliasT:
+		return false
+	case arrayT, variadicT, chanT, chanRecvT, chanSendT, funcT, interfaceT, mapT, ptrT, structT:
+		if t.name != "" || rt.Name() != "" {
+			return false
+		}
+	default:
+		return t.TypeOf() == rt
+	}
+
+	switch t.cat {
+	case arrayT, variadicT:
+		if t.sizedef {
+			return rt.Kind() == reflect.Array && rt.Len() == t.size && identicalBinType(t.val, rt.Elem())
+		}
+		return rt.Kind() == reflect.Slice && identicalBinType(t.val, rt.Elem())
+	case chanT, chanRecvT, chanSendT:
+		dir := reflect.BothDir
+		switch t.cat {
+		case chanRecvT:
+			dir = reflect.RecvDir
+		case chanSendT:
+			dir = reflect.SendDir
+		}
+		return rt.Kind() == reflect.Chan && rt.ChanDir() == dir && identicalBinType(t.val, rt.Elem())
+	case funcT:
+		if rt.Kind() != reflect.Func || len(t.arg) != rt.NumIn() || len(t.ret) != rt.NumOut() {
+			return false
+		}
+		for i, a := range t.arg {
+			if a.cat == variadicT != (rt.IsVariadic() && i == rt.NumIn()-1) || !identicalBinType(a, rt.In(i)) {
+				return false
+			}
+		}
+		for i, r := range t.ret {
+			if !identicalBinType(r, rt.Out(i)) {
+				return false
+			}
+		}
+		return true
+	case interfaceT:
+		if rt.Kind() != reflect.Interface {
+			return false
+		}
+		m := t.methods()
+		for name := range m {
+			if !canExport(name) {
+				return false
+			}
+		}
+		return m.equals((&itype{cat: valueT, rtype: rt}).methods())
+	case mapT:
+		return rt.Kind() == reflect.Map && identicalBinType(t.key, rt.Key()) && identicalBinType(t.val, rt.Elem())
+	case ptrT:
+		return rt.Kind() == reflect.Ptr && identicalBinType(t.val, rt.Elem())
+	case structT:
+		return identicalStruct(t, rt)
+	}
+	return false
+}
+
+// constConversion type checks the conversion n of the constant c to a constant
+// type, and replaces the value of the converted operand by the result.
+func (check typecheck) constConversion(n *node, c constant.Value) error {
+	c0, c1 := n.child[0], n.child[1]
+	t := c0.typ.TypeOf()
+	switch {
+	case isString(t) && c.Kind() == constant.Int:
+		// Integer to string: the UTF-8 representation of a valid code point, or
+		// "\uFFFD", as the compiler does for out of range values.
+		r := unicode.ReplacementChar
+		if i, ok := constant.Int64Val(c); ok && int64(rune(i)) == i {
+			r = rune(i)
+		}
+		c1.rval = reflect.ValueOf(constant.MakeString(string(r)))
+		return nil
+	case representableConst(c, t):
+	case isNumber(t) && isNumber(c1.typ.TypeOf()):
+		if isInt(t) && constant.ToInt(c).Kind() != constant.Int {
+			return n.cfgErrorf("constant %s truncated to integer", c)
+		}
+		return n.cfgErrorf("constant %s overflows %s", c, c0.typ.id())
+	default:
+		return n.cfgErrorf("cannot convert type %s to type %s", c1.typ.id(), c0.typ.id())
+	}
+
+	// Normalize the constant kind, converted later by convertConstantValue.
+	switch {
+	case isInt(t):
+		c = constant.ToInt(c)
+	case isFloat(t):
+		c = constant.ToFloat(c)
+	case isComplex(t):
+		c = constant.ToComplex(c)
+	}
+	c1.rval = reflect.ValueOf(c)
+	return nil
+}
+
+// constResult type checks the constant integer or float result of the
+// operation n, which must be representable by the type of n, unless untyped.
+func (check typecheck) constResult(n *node) error {
+	t := n.typ.TypeOf()
+	if n.typ.untyped || !isInt(t) && !isFloat(t) {
+		return nil
+	}
+	c, ok := n.constValue()
+	if !ok {
+		// Operands are typed constants, compute the exact result.
+		if c, ok = exactConst(n); !ok {
+			return nil
+		}
+	}
+	if !representableConst(c, t) {
+		return n.cfgErrorf("constant %s overflows %s", constString(c), n.typ.id())
+	}
+	return nil
+}
+
+// exactConst returns the exact result of the integer or float operation n
+// on typed constant operands, for operations which may overflow.
+func exactConst(n *node) (constant.Value, bool) {
+	x := numConst(n.child[0].rval)
+	if x == nil {
+		return nil, false
+	}
+	if n.action == aNeg {
+		return constant.UnaryOp(token.SUB, x, 0), true
+	}
+	if len(n.child) < 2 {
+		return nil, false
+	}
+	y := numConst(n.child[1].rval)
+	if y == nil {
+		return nil, false
+	}
+	var tok token.Token
+	switch n.action {
+	case aAdd:
+		tok = token.ADD
+	case aSub:
+		tok = token.SUB
+	case aMul:
+		tok = token.MUL
+	case aQuo:
+		if constant.Sign(y) == 0 {
+			return nil, false
+		}
+		tok = token.QUO
+		if x.Kind() == constant.Int && y.Kind() == constant.Int {
+			tok = token.QUO_ASSIGN // Integer division.
+		}
+	case aShl:
+		s, ok := constant.Uint64Val(y)
+		return constant.Shift(x, token.SHL, uint(s)), ok
+	default:
+		return nil, false
+	}
+	return constant.BinaryOp(x, tok, y), true
+}
+
+// numConst returns the constant value of the integer or float v, or nil.
+func numConst(v reflect.Value) constant.Value {
+	switch {
+	case !v.IsValid():
+	case isConstantValue(v.Type()):
+		return v.Interface().(constant.Value)
+	case isUint(v.Type()):
+		return constant.MakeUint64(v.Uint())
+	case isInt(v.Type()):
+		return constant.MakeInt64(v.Int())
+	case isFloat(v.Type()):
+		return constant.MakeFloat64(v.Float())
+	}
+	return nil
+}
+
+// constString returns the exact representation of an integer constant,
+// and the short one of other constants.
+func constString(c constant.Value) string {
+	if c.Kind() == constant.Int {
+		return c.ExactString()
+	}
+	return c.String()
+}
+
 func (check typecheck) representable(n *node, t reflect.Type) error {
 	if !n.rval.IsValid() {
 		// TODO(nick): This should be an error as the const is in the frame which is undesirable.
@@ -318,7 +980,7 @@ func (check typecheck) representable(n *node, t reflect.Type) error {
 			if !isInt(typ) && isInt(t) {
 				return n.cfgErrorf("%s truncated to %s", c.ExactString(), t.Kind().String())
 			}
-			return n.cfgErrorf("%s overflows %s", c.ExactString(), t.Kind().String())
+			return n.cfgErrorf("%s overflows %s", constString(c), t.Kind().String())
 		}
 		return n.cfgErrorf("cannot convert %s to %s", c.ExactString(), t.Kind().String())
 	}
@@ -391,9 +1053,13 @@ func representableConst(c constant.Value, t reflect.Type) bool {
 		}
 		switch t.Kind() {
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			if _, ok := constant.Int64Val(x); !ok {
+			i, ok := constant.Int64Val(x)
+			if !ok {
 				return false
 			}
+			if l := uint(bitlen[t.Kind()]); l < 64 {
+				return -1<<(l-1) <= i && i < 1<<(l-1)
+			}
 		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
 			if _, ok := constant.Uint64Val(x); !ok {
 				return false
@@ -0,0 +1,18 @@
+//go:build go1.18
+// +build go1.18
+
+package interp
+
+import "go/ast"
+
+// typeParams returns the type parameters of a function type or a type
+// specification, or nil, since go1.18.
+func typeParams(nod ast.Node) *ast.FieldList {
+	switch a := nod.(type) {
+	case *ast.FuncType:
+		return a.TypeParams
+	case *ast.TypeSpec:
+		return a.TypeParams
+	}
+	return nil
+}
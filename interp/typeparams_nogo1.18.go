@@ -0,0 +1,9 @@
+//go:build !go1.18
+// +build !go1.18
+
+package interp
+
+import "go/ast"
+
+// typeParams returns nil, as type parameters are not parsed before go1.18.
+func typeParams(nod ast.Node) *ast.FieldList { return nil }
@@ -49,9 +49,9 @@ func genValueRecv(n *node) func(*frame) reflect.Value {
 	return func(f *frame) reflect.Value {
 		r := v(f)
 		if r.Kind() == reflect.Ptr {
-			r = r.Elem()
+			r = elem(n, r)
 		}
-		return r.FieldByIndex(fi)
+		return fieldByIndex(n, r, fi)
 	}
 }
 
@@ -60,12 +60,15 @@ func genValueRecvInterfacePtr(n *node) func(*frame) reflect.Value {
 	fi := n.recv.index
 
 	return func(f *frame) reflect.Value {
-		r := v(f)
-		r = r.Elem().Elem()
+		r := recursiveStruct(v(f))
 
 		if len(fi) == 0 {
+			// An invalid value is a nil pointer receiver.
 			return r
 		}
+		if !r.IsValid() {
+			elem(n, r)
+		}
 
 		if r.Kind() == reflect.Ptr {
 			r = r.Elem()
@@ -74,7 +77,26 @@ func genValueRecvInterfacePtr(n *node) func(*frame) reflect.Value {
 	}
 }
 
+// genDestValue returns a generator of the value of n, boxed if necessary in
+// the interface typ of its destination. A nil pointer is boxed in a non-nil
+// interface.
+func genDestValue(typ *itype, n *node) func(*frame) reflect.Value {
+	switch {
+	case typ.cat == interfaceT:
+		return genValueInterface(n)
+	case isInterfaceBin(typ):
+		return genInterfaceWrapper(n, typ.TypeOf())
+	}
+	return genValue(n)
+}
+
 func genValueAsFunctionWrapper(n *node) func(*frame) reflect.Value {
+	return genValueAsFunction(n, true)
+}
+
+// genValueAsFunction returns the function value held by node n, as
+// genFunctionValue.
+func genValueAsFunction(n *node, boundary bool) func(*frame) reflect.Value {
 	value := genValue(n)
 	typ := n.typ.TypeOf()
 
@@ -83,11 +105,20 @@ func genValueAsFunctionWrapper(n *node) func(*frame) reflect.Value {
 		if v.IsNil() {
 			return reflect.New(typ).Elem()
 		}
-		return genFunctionWrapper(v.Interface().(*node))(f)
+		vn := v.Interface().(*node)
+		if vn.rval.IsValid() && vn.rval.Kind() == reflect.Func {
+			// A binary function held by a node is used directly.
+			return vn.rval.Convert(typ)
+		}
+		return genFunctionValue(vn, boundary)(f)
 	}
 }
 
 func genValueAs(n *node, t reflect.Type) func(*frame) reflect.Value {
+	if n.isNil() {
+		z := reflect.New(t).Elem()
+		return func(f *frame) reflect.Value { return z }
+	}
 	v := genValue(n)
 	return func(f *frame) reflect.Value {
 		return v(f).Convert(t)
@@ -142,6 +173,29 @@ func genValue(n *node) func(*frame) reflect.Value {
 	}
 }
 
+// genValueUnwrap returns a generator of the value of n where a binary interface
+// wrapping an interpreted value, as an error, is replaced by the wrapped value,
+// to compare it with interpreted values.
+func genValueUnwrap(n *node) func(*frame) reflect.Value {
+	value := genValue(n)
+	if t := n.typ.TypeOf(); t == nil || t.Kind() != reflect.Interface || isInterfaceSrc(n.typ) {
+		return value
+	}
+	return func(f *frame) reflect.Value { return unwrapError(value(f)) }
+}
+
+// unwrapError returns the interpreted value wrapped in v, if v holds an
+// interpreted error passed to binary code, or v otherwise.
+func unwrapError(v reflect.Value) reflect.Value {
+	if !v.IsValid() || !v.CanInterface() {
+		return v
+	}
+	if w, ok := v.Interface().(*_error); ok && w != nil && w.IValue != nil {
+		return reflect.ValueOf(w.IValue)
+	}
+	return v
+}
+
 func genValueArray(n *node) func(*frame) reflect.Value {
 	value := genValue(n)
 	// dereference array pointer, to support array operations on array pointer
@@ -216,10 +270,85 @@ func genValueInterface(n *node) func(*frame) reflect.Value {
 			v = vi.value
 			nod = vi.node
 		}
+		if v.IsValid() && v.Kind() == reflect.Interface && v.IsNil() {
+			// A nil interface{}, i.e. the zero value of an interface field.
+			return reflect.ValueOf(valueInterface{})
+		}
+		if k := v.Kind(); v.CanAddr() && (k == reflect.Array || k == reflect.Struct) {
+			// The interface holds a copy of a variable, not a reference to it.
+			v = copyValue(v)
+		}
 		return reflect.ValueOf(valueInterface{nod, v})
 	}
 }
 
+// genValueCommaOk returns a function assigning to dest, the first destination
+// of a comma-ok expression src, a value v, or the zero value of the type of src
+// if v is invalid. A value of concrete type is wrapped if dest is an interpreted
+// interface.
+// It returns nil if dest is the blank identifier.
+func genValueCommaOk(dest, src *node) func(*frame, reflect.Value) {
+	if dest.ident == "_" {
+		return nil
+	}
+	set := genAssignDest(dest)
+	if dest.typ.cat == interfaceT && src.typ.cat != interfaceT {
+		zero := reflect.Zero(src.typ.TypeOf())
+		return func(f *frame, v reflect.Value) {
+			if !v.IsValid() {
+				v = zero
+			}
+			set(f, reflect.ValueOf(valueInterface{src, v}))
+		}
+	}
+	return set
+}
+
+// genStatusCommaOk returns a function assigning to dest, the second destination
+// of a comma-ok expression, the untyped boolean status, or nil if dest is the
+// blank identifier.
+func genStatusCommaOk(dest *node) func(*frame, bool) {
+	if dest.ident == "_" {
+		return nil
+	}
+	set := genAssignDest(dest)
+	if dest.typ.cat == interfaceT {
+		nod := &node{kind: basicLit, typ: dest.interp.universe.sym["bool"].typ}
+		return func(f *frame, ok bool) { set(f, reflect.ValueOf(valueInterface{nod, reflect.ValueOf(ok)})) }
+	}
+	typ := dest.typ.TypeOf()
+	return func(f *frame, ok bool) { set(f, reflect.ValueOf(ok).Convert(typ)) }
+}
+
+// genAssignDest returns a function assigning to dest, a variable or a map
+// entry, a value v, or the zero value if v is invalid.
+func genAssignDest(dest *node) func(*frame, reflect.Value) {
+	if isMapEntry(dest) {
+		value := genValue(dest.child[0]) // map
+		var key func(*frame) reflect.Value
+		if dest.child[1].typ.cat == interfaceT {
+			key = genValueInterface(dest.child[1])
+		} else {
+			key = genValue(dest.child[1])
+		}
+		return func(f *frame, v reflect.Value) {
+			m := value(f)
+			if !v.IsValid() {
+				v = reflect.Zero(m.Type().Elem())
+			}
+			m.SetMapIndex(key(f), v)
+		}
+	}
+	value := genValue(dest)
+	return func(f *frame, v reflect.Value) {
+		d := value(f)
+		if !v.IsValid() {
+			v = reflect.Zero(d.Type())
+		}
+		d.Set(v)
+	}
+}
+
 func zeroInterfaceValue() reflect.Value {
 	n := &node{kind: basicLit, typ: &itype{cat: nilT, untyped: true}}
 	v := reflect.New(interf).Elem()
@@ -251,6 +380,9 @@ func genValueInterfaceValue(n *node) func(*frame) reflect.Value {
 		v := value(f)
 		if v.Interface().(valueInterface).node == nil {
 			// Uninitialized interface value, set it to a correct zero value.
+			if !v.CanSet() {
+				return zeroInterfaceValue().Interface().(valueInterface).value
+			}
 			v.Set(zeroInterfaceValue())
 			v = value(f)
 		}
@@ -288,7 +420,7 @@ func toRecursive(dest, src reflect.Value) {
 		for _, kv := range src.MapKeys() {
 			vv := reflect.New(dest.Type().Elem()).Elem()
 			toRecursive(vv, src.MapIndex(kv))
-			vv.SetMapIndex(kv, vv)
+			v.SetMapIndex(kv, vv)
 		}
 		dest.Set(v)
 	case reflect.Slice:
@@ -299,6 +431,10 @@ func toRecursive(dest, src reflect.Value) {
 		}
 		dest.Set(v)
 	case reflect.Ptr:
+		if src.Kind() == reflect.Ptr && src.IsNil() {
+			// Keep the destination a nil pointer.
+			return
+		}
 		v := reflect.New(dest.Type().Elem()).Elem()
 		s := src
 		if s.Elem().Kind() != reflect.Struct { // In the case of *interface{}, we want *struct{}
@@ -306,11 +442,37 @@ func toRecursive(dest, src reflect.Value) {
 		}
 		toRecursive(v, s)
 		dest.Set(v.Addr())
+	case reflect.Interface:
+		if src.Kind() == reflect.Interface {
+			src = src.Elem()
+		}
+		if src.Kind() == reflect.Struct {
+			// Store a copy of the struct, as the one held by an interface
+			// may be modified in place, see writableDeref.
+			v := reflect.New(src.Type()).Elem()
+			v.Set(src)
+			src = v
+		}
+		if src.IsValid() {
+			dest.Set(src)
+		}
 	default:
 		dest.Set(src)
 	}
 }
 
+// recursiveValue returns v, or the struct held by v if v is the interface{}
+// representing a recursive struct of type t, such as the result of a function.
+func recursiveValue(t reflect.Type, v reflect.Value) reflect.Value {
+	if t.Kind() != reflect.Struct || v.Kind() != reflect.Interface {
+		return v
+	}
+	if v.IsNil() {
+		return reflect.Zero(t)
+	}
+	return v.Elem()
+}
+
 func genValueRecursiveInterfacePtrValue(n *node) func(*frame) reflect.Value {
 	value := genValue(n)
 
@@ -319,8 +481,21 @@ func genValueRecursiveInterfacePtrValue(n *node) func(*frame) reflect.Value {
 		if v.IsZero() {
 			return v
 		}
-		return v.Elem().Elem()
+		return recursiveStruct(v)
+	}
+}
+
+// recursiveStruct returns the value of a recursive struct, held in v by a
+// *interface{}, by an interface{}, or directly, as the element of a slice, map
+// or array field. A nil pointer or interface gives an invalid value.
+func recursiveStruct(v reflect.Value) reflect.Value {
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
 	}
+	return v
 }
 
 func vInt(v reflect.Value) (i int64) {
@@ -0,0 +1,109 @@
+package interp
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// watchInterval is the period of polling of source files by Watch.
+const watchInterval = 200 * time.Millisecond
+
+// fileState is the modification time and size of a file.
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// Watch evaluates the Go source files of directory dir in the current package
+// of interpreter i, then re-evaluates them with ReEval each time they change,
+// until ctx is done. All the files are replaced together, as they may depend
+// on each other, and the declarations of removed files are removed. A main
+// function, if defined, is run again after each evaluation, and must return
+// for the watch to continue.
+//
+// Files are polled for changes of modification time or size. A burst of writes
+// produces a single evaluation, once the files stay unchanged for a polling
+// period. If onReload is not nil, it is called after each evaluation with its
+// result, nil or an error, which doesn't stop the watch.
+func Watch(ctx context.Context, i *Interpreter, dir string, onReload func(error)) {
+	report := func(err error) {
+		if onReload != nil {
+			onReload(err)
+		}
+	}
+
+	var last, prev map[string]fileState // states at last evaluation and last poll
+	var evaluated map[string]fileState  // states at last successful evaluation
+	var lastErr string
+	loaded := false
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	for {
+		cur, err := i.sourceStates(dir)
+		switch {
+		case err != nil:
+			// Report directory errors once.
+			if err.Error() != lastErr {
+				lastErr = err.Error()
+				report(err)
+			}
+		case !loaded || !equalStates(cur, last) && equalStates(cur, prev):
+			lastErr = ""
+			var paths, removed []string
+			for name := range cur {
+				paths = append(paths, name)
+			}
+			for name := range evaluated {
+				if _, ok := cur[name]; !ok {
+					removed = append(removed, name)
+				}
+			}
+			sort.Strings(paths)
+			if _, err = i.reEval(paths, removed); err == nil {
+				evaluated = cur
+			}
+			last, loaded = cur, true
+			report(err)
+		}
+		prev = cur
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sourceStates returns the states of the Go source files of dir which satisfy
+// the build constraints, indexed by path. Test files are excluded.
+func (interp *Interpreter) sourceStates(dir string) (map[string]fileState, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	states := map[string]fileState{}
+	for _, file := range files {
+		if file.IsDir() || skipFile(&interp.context, file.Name()) {
+			continue
+		}
+		states[filepath.Join(dir, file.Name())] = fileState{file.ModTime(), file.Size()}
+	}
+	return states, nil
+}
+
+func equalStates(a, b map[string]fileState) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || !w.modTime.Equal(v.modTime) || w.size != v.size {
+			return false
+		}
+	}
+	return true
+}
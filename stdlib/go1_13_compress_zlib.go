@@ -41,6 +41,7 @@ func init() {
 // _compress_zlib_Resetter is an interface wrapper for Resetter type
 type _compress_zlib_Resetter struct {
 	WReset func(r io.Reader, dict []byte) error
+	IValue interface{}
 }
 
 func (W _compress_zlib_Resetter) Reset(r io.Reader, dict []byte) error { return W.WReset(r, dict) }
@@ -37,6 +37,7 @@ type _context_Context struct {
 	WDone     func() <-chan struct{}
 	WErr      func() error
 	WValue    func(key interface{}) interface{}
+	IValue    interface{}
 }
 
 func (W _context_Context) Deadline() (deadline time.Time, ok bool) { return W.WDeadline() }
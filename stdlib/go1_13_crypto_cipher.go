@@ -44,6 +44,7 @@ type _crypto_cipher_AEAD struct {
 	WOpen      func(dst []byte, nonce []byte, ciphertext []byte, additionalData []byte) ([]byte, error)
 	WOverhead  func() int
 	WSeal      func(dst []byte, nonce []byte, plaintext []byte, additionalData []byte) []byte
+	IValue     interface{}
 }
 
 func (W _crypto_cipher_AEAD) NonceSize() int { return W.WNonceSize() }
@@ -60,6 +61,7 @@ type _crypto_cipher_Block struct {
 	WBlockSize func() int
 	WDecrypt   func(dst []byte, src []byte)
 	WEncrypt   func(dst []byte, src []byte)
+	IValue     interface{}
 }
 
 func (W _crypto_cipher_Block) BlockSize() int                 { return W.WBlockSize() }
@@ -70,6 +72,7 @@ func (W _crypto_cipher_Block) Encrypt(dst []byte, src []byte) { W.WEncrypt(dst,
 type _crypto_cipher_BlockMode struct {
 	WBlockSize   func() int
 	WCryptBlocks func(dst []byte, src []byte)
+	IValue       interface{}
 }
 
 func (W _crypto_cipher_BlockMode) BlockSize() int                     { return W.WBlockSize() }
@@ -78,6 +81,7 @@ func (W _crypto_cipher_BlockMode) CryptBlocks(dst []byte, src []byte) { W.WCrypt
 // _crypto_cipher_Stream is an interface wrapper for Stream type
 type _crypto_cipher_Stream struct {
 	WXORKeyStream func(dst []byte, src []byte)
+	IValue        interface{}
 }
 
 func (W _crypto_cipher_Stream) XORKeyStream(dst []byte, src []byte) { W.WXORKeyStream(dst, src) }
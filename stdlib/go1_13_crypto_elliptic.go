@@ -38,6 +38,7 @@ type _crypto_elliptic_Curve struct {
 	WParams         func() *elliptic.CurveParams
 	WScalarBaseMult func(k []byte) (x *big.Int, y *big.Int)
 	WScalarMult     func(x1 *big.Int, y1 *big.Int, k []byte) (x *big.Int, y *big.Int)
+	IValue          interface{}
 }
 
 func (W _crypto_elliptic_Curve) Add(x1 *big.Int, y1 *big.Int, x2 *big.Int, y2 *big.Int) (x *big.Int, y *big.Int) {
@@ -62,6 +62,7 @@ func init() {
 type _database_sql_Result struct {
 	WLastInsertId func() (int64, error)
 	WRowsAffected func() (int64, error)
+	IValue        interface{}
 }
 
 func (W _database_sql_Result) LastInsertId() (int64, error) { return W.WLastInsertId() }
@@ -69,7 +70,8 @@ func (W _database_sql_Result) RowsAffected() (int64, error) { return W.WRowsAffe
 
 // _database_sql_Scanner is an interface wrapper for Scanner type
 type _database_sql_Scanner struct {
-	WScan func(src interface{}) error
+	WScan  func(src interface{}) error
+	IValue interface{}
 }
 
 func (W _database_sql_Scanner) Scan(src interface{}) error { return W.WScan(src) }
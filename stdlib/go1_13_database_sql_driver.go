@@ -97,6 +97,7 @@ func init() {
 // _database_sql_driver_ColumnConverter is an interface wrapper for ColumnConverter type
 type _database_sql_driver_ColumnConverter struct {
 	WColumnConverter func(idx int) driver.ValueConverter
+	IValue           interface{}
 }
 
 func (W _database_sql_driver_ColumnConverter) ColumnConverter(idx int) driver.ValueConverter {
@@ -108,6 +109,7 @@ type _database_sql_driver_Conn struct {
 	WBegin   func() (driver.Tx, error)
 	WClose   func() error
 	WPrepare func(query string) (driver.Stmt, error)
+	IValue   interface{}
 }
 
 func (W _database_sql_driver_Conn) Begin() (driver.Tx, error) { return W.WBegin() }
@@ -119,6 +121,7 @@ func (W _database_sql_driver_Conn) Prepare(query string) (driver.Stmt, error) {
 // _database_sql_driver_ConnBeginTx is an interface wrapper for ConnBeginTx type
 type _database_sql_driver_ConnBeginTx struct {
 	WBeginTx func(ctx context.Context, opts driver.TxOptions) (driver.Tx, error)
+	IValue   interface{}
 }
 
 func (W _database_sql_driver_ConnBeginTx) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
@@ -128,6 +131,7 @@ func (W _database_sql_driver_ConnBeginTx) BeginTx(ctx context.Context, opts driv
 // _database_sql_driver_ConnPrepareContext is an interface wrapper for ConnPrepareContext type
 type _database_sql_driver_ConnPrepareContext struct {
 	WPrepareContext func(ctx context.Context, query string) (driver.Stmt, error)
+	IValue          interface{}
 }
 
 func (W _database_sql_driver_ConnPrepareContext) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
@@ -138,6 +142,7 @@ func (W _database_sql_driver_ConnPrepareContext) PrepareContext(ctx context.Cont
 type _database_sql_driver_Connector struct {
 	WConnect func(a0 context.Context) (driver.Conn, error)
 	WDriver  func() driver.Driver
+	IValue   interface{}
 }
 
 func (W _database_sql_driver_Connector) Connect(a0 context.Context) (driver.Conn, error) {
@@ -147,7 +152,8 @@ func (W _database_sql_driver_Connector) Driver() driver.Driver { return W.WDrive
 
 // _database_sql_driver_Driver is an interface wrapper for Driver type
 type _database_sql_driver_Driver struct {
-	WOpen func(name string) (driver.Conn, error)
+	WOpen  func(name string) (driver.Conn, error)
+	IValue interface{}
 }
 
 func (W _database_sql_driver_Driver) Open(name string) (driver.Conn, error) { return W.WOpen(name) }
@@ -155,6 +161,7 @@ func (W _database_sql_driver_Driver) Open(name string) (driver.Conn, error) { re
 // _database_sql_driver_DriverContext is an interface wrapper for DriverContext type
 type _database_sql_driver_DriverContext struct {
 	WOpenConnector func(name string) (driver.Connector, error)
+	IValue         interface{}
 }
 
 func (W _database_sql_driver_DriverContext) OpenConnector(name string) (driver.Connector, error) {
@@ -163,7 +170,8 @@ func (W _database_sql_driver_DriverContext) OpenConnector(name string) (driver.C
 
 // _database_sql_driver_Execer is an interface wrapper for Execer type
 type _database_sql_driver_Execer struct {
-	WExec func(query string, args []driver.Value) (driver.Result, error)
+	WExec  func(query string, args []driver.Value) (driver.Result, error)
+	IValue interface{}
 }
 
 func (W _database_sql_driver_Execer) Exec(query string, args []driver.Value) (driver.Result, error) {
@@ -173,6 +181,7 @@ func (W _database_sql_driver_Execer) Exec(query string, args []driver.Value) (dr
 // _database_sql_driver_ExecerContext is an interface wrapper for ExecerContext type
 type _database_sql_driver_ExecerContext struct {
 	WExecContext func(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error)
+	IValue       interface{}
 }
 
 func (W _database_sql_driver_ExecerContext) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
@@ -182,6 +191,7 @@ func (W _database_sql_driver_ExecerContext) ExecContext(ctx context.Context, que
 // _database_sql_driver_NamedValueChecker is an interface wrapper for NamedValueChecker type
 type _database_sql_driver_NamedValueChecker struct {
 	WCheckNamedValue func(a0 *driver.NamedValue) error
+	IValue           interface{}
 }
 
 func (W _database_sql_driver_NamedValueChecker) CheckNamedValue(a0 *driver.NamedValue) error {
@@ -190,7 +200,8 @@ func (W _database_sql_driver_NamedValueChecker) CheckNamedValue(a0 *driver.Named
 
 // _database_sql_driver_Pinger is an interface wrapper for Pinger type
 type _database_sql_driver_Pinger struct {
-	WPing func(ctx context.Context) error
+	WPing  func(ctx context.Context) error
+	IValue interface{}
 }
 
 func (W _database_sql_driver_Pinger) Ping(ctx context.Context) error { return W.WPing(ctx) }
@@ -198,6 +209,7 @@ func (W _database_sql_driver_Pinger) Ping(ctx context.Context) error { return W.
 // _database_sql_driver_Queryer is an interface wrapper for Queryer type
 type _database_sql_driver_Queryer struct {
 	WQuery func(query string, args []driver.Value) (driver.Rows, error)
+	IValue interface{}
 }
 
 func (W _database_sql_driver_Queryer) Query(query string, args []driver.Value) (driver.Rows, error) {
@@ -207,6 +219,7 @@ func (W _database_sql_driver_Queryer) Query(query string, args []driver.Value) (
 // _database_sql_driver_QueryerContext is an interface wrapper for QueryerContext type
 type _database_sql_driver_QueryerContext struct {
 	WQueryContext func(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error)
+	IValue        interface{}
 }
 
 func (W _database_sql_driver_QueryerContext) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
@@ -217,6 +230,7 @@ func (W _database_sql_driver_QueryerContext) QueryContext(ctx context.Context, q
 type _database_sql_driver_Result struct {
 	WLastInsertId func() (int64, error)
 	WRowsAffected func() (int64, error)
+	IValue        interface{}
 }
 
 func (W _database_sql_driver_Result) LastInsertId() (int64, error) { return W.WLastInsertId() }
@@ -227,6 +241,7 @@ type _database_sql_driver_Rows struct {
 	WClose   func() error
 	WColumns func() []string
 	WNext    func(dest []driver.Value) error
+	IValue   interface{}
 }
 
 func (W _database_sql_driver_Rows) Close() error                   { return W.WClose() }
@@ -239,6 +254,7 @@ type _database_sql_driver_RowsColumnTypeDatabaseTypeName struct {
 	WColumnTypeDatabaseTypeName func(index int) string
 	WColumns                    func() []string
 	WNext                       func(dest []driver.Value) error
+	IValue                      interface{}
 }
 
 func (W _database_sql_driver_RowsColumnTypeDatabaseTypeName) Close() error { return W.WClose() }
@@ -256,6 +272,7 @@ type _database_sql_driver_RowsColumnTypeLength struct {
 	WColumnTypeLength func(index int) (length int64, ok bool)
 	WColumns          func() []string
 	WNext             func(dest []driver.Value) error
+	IValue            interface{}
 }
 
 func (W _database_sql_driver_RowsColumnTypeLength) Close() error { return W.WClose() }
@@ -273,6 +290,7 @@ type _database_sql_driver_RowsColumnTypeNullable struct {
 	WColumnTypeNullable func(index int) (nullable bool, ok bool)
 	WColumns            func() []string
 	WNext               func(dest []driver.Value) error
+	IValue              interface{}
 }
 
 func (W _database_sql_driver_RowsColumnTypeNullable) Close() error { return W.WClose() }
@@ -290,6 +308,7 @@ type _database_sql_driver_RowsColumnTypePrecisionScale struct {
 	WColumnTypePrecisionScale func(index int) (precision int64, scale int64, ok bool)
 	WColumns                  func() []string
 	WNext                     func(dest []driver.Value) error
+	IValue                    interface{}
 }
 
 func (W _database_sql_driver_RowsColumnTypePrecisionScale) Close() error { return W.WClose() }
@@ -307,6 +326,7 @@ type _database_sql_driver_RowsColumnTypeScanType struct {
 	WColumnTypeScanType func(index int) reflect.Type
 	WColumns            func() []string
 	WNext               func(dest []driver.Value) error
+	IValue              interface{}
 }
 
 func (W _database_sql_driver_RowsColumnTypeScanType) Close() error { return W.WClose() }
@@ -325,6 +345,7 @@ type _database_sql_driver_RowsNextResultSet struct {
 	WHasNextResultSet func() bool
 	WNext             func(dest []driver.Value) error
 	WNextResultSet    func() error
+	IValue            interface{}
 }
 
 func (W _database_sql_driver_RowsNextResultSet) Close() error      { return W.WClose() }
@@ -340,6 +361,7 @@ func (W _database_sql_driver_RowsNextResultSet) NextResultSet() error { return W
 // _database_sql_driver_SessionResetter is an interface wrapper for SessionResetter type
 type _database_sql_driver_SessionResetter struct {
 	WResetSession func(ctx context.Context) error
+	IValue        interface{}
 }
 
 func (W _database_sql_driver_SessionResetter) ResetSession(ctx context.Context) error {
@@ -352,6 +374,7 @@ type _database_sql_driver_Stmt struct {
 	WExec     func(args []driver.Value) (driver.Result, error)
 	WNumInput func() int
 	WQuery    func(args []driver.Value) (driver.Rows, error)
+	IValue    interface{}
 }
 
 func (W _database_sql_driver_Stmt) Close() error { return W.WClose() }
@@ -366,6 +389,7 @@ func (W _database_sql_driver_Stmt) Query(args []driver.Value) (driver.Rows, erro
 // _database_sql_driver_StmtExecContext is an interface wrapper for StmtExecContext type
 type _database_sql_driver_StmtExecContext struct {
 	WExecContext func(ctx context.Context, args []driver.NamedValue) (driver.Result, error)
+	IValue       interface{}
 }
 
 func (W _database_sql_driver_StmtExecContext) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
@@ -375,6 +399,7 @@ func (W _database_sql_driver_StmtExecContext) ExecContext(ctx context.Context, a
 // _database_sql_driver_StmtQueryContext is an interface wrapper for StmtQueryContext type
 type _database_sql_driver_StmtQueryContext struct {
 	WQueryContext func(ctx context.Context, args []driver.NamedValue) (driver.Rows, error)
+	IValue        interface{}
 }
 
 func (W _database_sql_driver_StmtQueryContext) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
@@ -385,6 +410,7 @@ func (W _database_sql_driver_StmtQueryContext) QueryContext(ctx context.Context,
 type _database_sql_driver_Tx struct {
 	WCommit   func() error
 	WRollback func() error
+	IValue    interface{}
 }
 
 func (W _database_sql_driver_Tx) Commit() error   { return W.WCommit() }
@@ -392,11 +418,13 @@ func (W _database_sql_driver_Tx) Rollback() error { return W.WRollback() }
 
 // _database_sql_driver_Value is an interface wrapper for Value type
 type _database_sql_driver_Value struct {
+	IValue interface{}
 }
 
 // _database_sql_driver_ValueConverter is an interface wrapper for ValueConverter type
 type _database_sql_driver_ValueConverter struct {
 	WConvertValue func(v interface{}) (driver.Value, error)
+	IValue        interface{}
 }
 
 func (W _database_sql_driver_ValueConverter) ConvertValue(v interface{}) (driver.Value, error) {
@@ -406,6 +434,7 @@ func (W _database_sql_driver_ValueConverter) ConvertValue(v interface{}) (driver
 // _database_sql_driver_Valuer is an interface wrapper for Valuer type
 type _database_sql_driver_Valuer struct {
 	WValue func() (driver.Value, error)
+	IValue interface{}
 }
 
 func (W _database_sql_driver_Valuer) Value() (driver.Value, error) { return W.WValue() }
@@ -28,6 +28,7 @@ func init() {
 // _encoding_BinaryMarshaler is an interface wrapper for BinaryMarshaler type
 type _encoding_BinaryMarshaler struct {
 	WMarshalBinary func() (data []byte, err error)
+	IValue         interface{}
 }
 
 func (W _encoding_BinaryMarshaler) MarshalBinary() (data []byte, err error) { return W.WMarshalBinary() }
@@ -35,6 +36,7 @@ func (W _encoding_BinaryMarshaler) MarshalBinary() (data []byte, err error) { re
 // _encoding_BinaryUnmarshaler is an interface wrapper for BinaryUnmarshaler type
 type _encoding_BinaryUnmarshaler struct {
 	WUnmarshalBinary func(data []byte) error
+	IValue           interface{}
 }
 
 func (W _encoding_BinaryUnmarshaler) UnmarshalBinary(data []byte) error {
@@ -44,6 +46,7 @@ func (W _encoding_BinaryUnmarshaler) UnmarshalBinary(data []byte) error {
 // _encoding_TextMarshaler is an interface wrapper for TextMarshaler type
 type _encoding_TextMarshaler struct {
 	WMarshalText func() (text []byte, err error)
+	IValue       interface{}
 }
 
 func (W _encoding_TextMarshaler) MarshalText() (text []byte, err error) { return W.WMarshalText() }
@@ -51,6 +54,7 @@ func (W _encoding_TextMarshaler) MarshalText() (text []byte, err error) { return
 // _encoding_TextUnmarshaler is an interface wrapper for TextUnmarshaler type
 type _encoding_TextUnmarshaler struct {
 	WUnmarshalText func(text []byte) error
+	IValue         interface{}
 }
 
 func (W _encoding_TextUnmarshaler) UnmarshalText(text []byte) error { return W.WUnmarshalText(text) }
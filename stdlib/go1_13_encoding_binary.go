@@ -46,6 +46,7 @@ type _encoding_binary_ByteOrder struct {
 	WUint16    func(a0 []byte) uint16
 	WUint32    func(a0 []byte) uint32
 	WUint64    func(a0 []byte) uint64
+	IValue     interface{}
 }
 
 func (W _encoding_binary_ByteOrder) PutUint16(a0 []byte, a1 uint16) { W.WPutUint16(a0, a1) }
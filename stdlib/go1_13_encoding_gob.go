@@ -33,6 +33,7 @@ func init() {
 // _encoding_gob_GobDecoder is an interface wrapper for GobDecoder type
 type _encoding_gob_GobDecoder struct {
 	WGobDecode func(a0 []byte) error
+	IValue     interface{}
 }
 
 func (W _encoding_gob_GobDecoder) GobDecode(a0 []byte) error { return W.WGobDecode(a0) }
@@ -40,6 +41,7 @@ func (W _encoding_gob_GobDecoder) GobDecode(a0 []byte) error { return W.WGobDeco
 // _encoding_gob_GobEncoder is an interface wrapper for GobEncoder type
 type _encoding_gob_GobEncoder struct {
 	WGobEncode func() ([]byte, error)
+	IValue     interface{}
 }
 
 func (W _encoding_gob_GobEncoder) GobEncode() ([]byte, error) { return W.WGobEncode() }
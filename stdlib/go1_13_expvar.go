@@ -38,6 +38,7 @@ func init() {
 // _expvar_Var is an interface wrapper for Var type
 type _expvar_Var struct {
 	WString func() string
+	IValue  interface{}
 }
 
 func (W _expvar_Var) String() string { return W.WString() }
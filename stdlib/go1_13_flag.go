@@ -67,6 +67,7 @@ type _flag_Getter struct {
 	WGet    func() interface{}
 	WSet    func(a0 string) error
 	WString func() string
+	IValue  interface{}
 }
 
 func (W _flag_Getter) Get() interface{}    { return W.WGet() }
@@ -77,6 +78,7 @@ func (W _flag_Getter) String() string      { return W.WString() }
 type _flag_Value struct {
 	WSet    func(a0 string) error
 	WString func() string
+	IValue  interface{}
 }
 
 func (W _flag_Value) Set(a0 string) error { return W.WSet(a0) }
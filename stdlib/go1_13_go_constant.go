@@ -64,6 +64,7 @@ type _go_constant_Value struct {
 	WExactString func() string
 	WKind        func() constant.Kind
 	WString      func() string
+	IValue       interface{}
 }
 
 func (W _go_constant_Value) ExactString() string { return W.WExactString() }
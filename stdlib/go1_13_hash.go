@@ -30,6 +30,7 @@ type _hash_Hash struct {
 	WSize      func() int
 	WSum       func(b []byte) []byte
 	WWrite     func(p []byte) (n int, err error)
+	IValue     interface{}
 }
 
 func (W _hash_Hash) BlockSize() int                    { return W.WBlockSize() }
@@ -46,6 +47,7 @@ type _hash_Hash32 struct {
 	WSum       func(b []byte) []byte
 	WSum32     func() uint32
 	WWrite     func(p []byte) (n int, err error)
+	IValue     interface{}
 }
 
 func (W _hash_Hash32) BlockSize() int                    { return W.WBlockSize() }
@@ -63,6 +65,7 @@ type _hash_Hash64 struct {
 	WSum       func(b []byte) []byte
 	WSum64     func() uint64
 	WWrite     func(p []byte) (n int, err error)
+	IValue     interface{}
 }
 
 func (W _hash_Hash64) BlockSize() int                    { return W.WBlockSize() }
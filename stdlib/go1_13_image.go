@@ -77,6 +77,7 @@ type _image_Image struct {
 	WAt         func(x int, y int) color.Color
 	WBounds     func() image.Rectangle
 	WColorModel func() color.Model
+	IValue      interface{}
 }
 
 func (W _image_Image) At(x int, y int) color.Color { return W.WAt(x, y) }
@@ -89,6 +90,7 @@ type _image_PalettedImage struct {
 	WBounds       func() image.Rectangle
 	WColorIndexAt func(x int, y int) uint8
 	WColorModel   func() color.Model
+	IValue        interface{}
 }
 
 func (W _image_PalettedImage) At(x int, y int) color.Color     { return W.WAt(x, y) }
@@ -57,7 +57,8 @@ func init() {
 
 // _image_color_Color is an interface wrapper for Color type
 type _image_color_Color struct {
-	WRGBA func() (r uint32, g uint32, b uint32, a uint32)
+	WRGBA  func() (r uint32, g uint32, b uint32, a uint32)
+	IValue interface{}
 }
 
 func (W _image_color_Color) RGBA() (r uint32, g uint32, b uint32, a uint32) { return W.WRGBA() }
@@ -65,6 +66,7 @@ func (W _image_color_Color) RGBA() (r uint32, g uint32, b uint32, a uint32) { re
 // _image_color_Model is an interface wrapper for Model type
 type _image_color_Model struct {
 	WConvert func(c color.Color) color.Color
+	IValue   interface{}
 }
 
 func (W _image_color_Model) Convert(c color.Color) color.Color { return W.WConvert(c) }
@@ -35,7 +35,8 @@ func init() {
 
 // _image_draw_Drawer is an interface wrapper for Drawer type
 type _image_draw_Drawer struct {
-	WDraw func(dst draw.Image, r image.Rectangle, src image.Image, sp image.Point)
+	WDraw  func(dst draw.Image, r image.Rectangle, src image.Image, sp image.Point)
+	IValue interface{}
 }
 
 func (W _image_draw_Drawer) Draw(dst draw.Image, r image.Rectangle, src image.Image, sp image.Point) {
@@ -48,6 +49,7 @@ type _image_draw_Image struct {
 	WBounds     func() image.Rectangle
 	WColorModel func() color.Model
 	WSet        func(x int, y int, c color.Color)
+	IValue      interface{}
 }
 
 func (W _image_draw_Image) At(x int, y int) color.Color     { return W.WAt(x, y) }
@@ -58,6 +60,7 @@ func (W _image_draw_Image) Set(x int, y int, c color.Color) { W.WSet(x, y, c) }
 // _image_draw_Quantizer is an interface wrapper for Quantizer type
 type _image_draw_Quantizer struct {
 	WQuantize func(p color.Palette, m image.Image) color.Palette
+	IValue    interface{}
 }
 
 func (W _image_draw_Quantizer) Quantize(p color.Palette, m image.Image) color.Palette {
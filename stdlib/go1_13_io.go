@@ -91,6 +91,7 @@ func init() {
 // _io_ByteReader is an interface wrapper for ByteReader type
 type _io_ByteReader struct {
 	WReadByte func() (byte, error)
+	IValue    interface{}
 }
 
 func (W _io_ByteReader) ReadByte() (byte, error) { return W.WReadByte() }
@@ -99,6 +100,7 @@ func (W _io_ByteReader) ReadByte() (byte, error) { return W.WReadByte() }
 type _io_ByteScanner struct {
 	WReadByte   func() (byte, error)
 	WUnreadByte func() error
+	IValue      interface{}
 }
 
 func (W _io_ByteScanner) ReadByte() (byte, error) { return W.WReadByte() }
@@ -107,6 +109,7 @@ func (W _io_ByteScanner) UnreadByte() error       { return W.WUnreadByte() }
 // _io_ByteWriter is an interface wrapper for ByteWriter type
 type _io_ByteWriter struct {
 	WWriteByte func(c byte) error
+	IValue     interface{}
 }
 
 func (W _io_ByteWriter) WriteByte(c byte) error { return W.WWriteByte(c) }
@@ -114,6 +117,7 @@ func (W _io_ByteWriter) WriteByte(c byte) error { return W.WWriteByte(c) }
 // _io_Closer is an interface wrapper for Closer type
 type _io_Closer struct {
 	WClose func() error
+	IValue interface{}
 }
 
 func (W _io_Closer) Close() error { return W.WClose() }
@@ -122,6 +126,7 @@ func (W _io_Closer) Close() error { return W.WClose() }
 type _io_ReadCloser struct {
 	WClose func() error
 	WRead  func(p []byte) (n int, err error)
+	IValue interface{}
 }
 
 func (W _io_ReadCloser) Close() error                     { return W.WClose() }
@@ -129,8 +134,9 @@ func (W _io_ReadCloser) Read(p []byte) (n int, err error) { return W.WRead(p) }
 
 // _io_ReadSeeker is an interface wrapper for ReadSeeker type
 type _io_ReadSeeker struct {
-	WRead func(p []byte) (n int, err error)
-	WSeek func(offset int64, whence int) (int64, error)
+	WRead  func(p []byte) (n int, err error)
+	WSeek  func(offset int64, whence int) (int64, error)
+	IValue interface{}
 }
 
 func (W _io_ReadSeeker) Read(p []byte) (n int, err error) { return W.WRead(p) }
@@ -143,6 +149,7 @@ type _io_ReadWriteCloser struct {
 	WClose func() error
 	WRead  func(p []byte) (n int, err error)
 	WWrite func(p []byte) (n int, err error)
+	IValue interface{}
 }
 
 func (W _io_ReadWriteCloser) Close() error                      { return W.WClose() }
@@ -154,6 +161,7 @@ type _io_ReadWriteSeeker struct {
 	WRead  func(p []byte) (n int, err error)
 	WSeek  func(offset int64, whence int) (int64, error)
 	WWrite func(p []byte) (n int, err error)
+	IValue interface{}
 }
 
 func (W _io_ReadWriteSeeker) Read(p []byte) (n int, err error) { return W.WRead(p) }
@@ -166,6 +174,7 @@ func (W _io_ReadWriteSeeker) Write(p []byte) (n int, err error) { return W.WWrit
 type _io_ReadWriter struct {
 	WRead  func(p []byte) (n int, err error)
 	WWrite func(p []byte) (n int, err error)
+	IValue interface{}
 }
 
 func (W _io_ReadWriter) Read(p []byte) (n int, err error)  { return W.WRead(p) }
@@ -173,7 +182,8 @@ func (W _io_ReadWriter) Write(p []byte) (n int, err error) { return W.WWrite(p)
 
 // _io_Reader is an interface wrapper for Reader type
 type _io_Reader struct {
-	WRead func(p []byte) (n int, err error)
+	WRead  func(p []byte) (n int, err error)
+	IValue interface{}
 }
 
 func (W _io_Reader) Read(p []byte) (n int, err error) { return W.WRead(p) }
@@ -181,6 +191,7 @@ func (W _io_Reader) Read(p []byte) (n int, err error) { return W.WRead(p) }
 // _io_ReaderAt is an interface wrapper for ReaderAt type
 type _io_ReaderAt struct {
 	WReadAt func(p []byte, off int64) (n int, err error)
+	IValue  interface{}
 }
 
 func (W _io_ReaderAt) ReadAt(p []byte, off int64) (n int, err error) { return W.WReadAt(p, off) }
@@ -188,6 +199,7 @@ func (W _io_ReaderAt) ReadAt(p []byte, off int64) (n int, err error) { return W.
 // _io_ReaderFrom is an interface wrapper for ReaderFrom type
 type _io_ReaderFrom struct {
 	WReadFrom func(r io.Reader) (n int64, err error)
+	IValue    interface{}
 }
 
 func (W _io_ReaderFrom) ReadFrom(r io.Reader) (n int64, err error) { return W.WReadFrom(r) }
@@ -195,6 +207,7 @@ func (W _io_ReaderFrom) ReadFrom(r io.Reader) (n int64, err error) { return W.WR
 // _io_RuneReader is an interface wrapper for RuneReader type
 type _io_RuneReader struct {
 	WReadRune func() (r rune, size int, err error)
+	IValue    interface{}
 }
 
 func (W _io_RuneReader) ReadRune() (r rune, size int, err error) { return W.WReadRune() }
@@ -203,6 +216,7 @@ func (W _io_RuneReader) ReadRune() (r rune, size int, err error) { return W.WRea
 type _io_RuneScanner struct {
 	WReadRune   func() (r rune, size int, err error)
 	WUnreadRune func() error
+	IValue      interface{}
 }
 
 func (W _io_RuneScanner) ReadRune() (r rune, size int, err error) { return W.WReadRune() }
@@ -210,7 +224,8 @@ func (W _io_RuneScanner) UnreadRune() error                       { return W.WUn
 
 // _io_Seeker is an interface wrapper for Seeker type
 type _io_Seeker struct {
-	WSeek func(offset int64, whence int) (int64, error)
+	WSeek  func(offset int64, whence int) (int64, error)
+	IValue interface{}
 }
 
 func (W _io_Seeker) Seek(offset int64, whence int) (int64, error) { return W.WSeek(offset, whence) }
@@ -218,6 +233,7 @@ func (W _io_Seeker) Seek(offset int64, whence int) (int64, error) { return W.WSe
 // _io_StringWriter is an interface wrapper for StringWriter type
 type _io_StringWriter struct {
 	WWriteString func(s string) (n int, err error)
+	IValue       interface{}
 }
 
 func (W _io_StringWriter) WriteString(s string) (n int, err error) { return W.WWriteString(s) }
@@ -226,6 +242,7 @@ func (W _io_StringWriter) WriteString(s string) (n int, err error) { return W.WW
 type _io_WriteCloser struct {
 	WClose func() error
 	WWrite func(p []byte) (n int, err error)
+	IValue interface{}
 }
 
 func (W _io_WriteCloser) Close() error                      { return W.WClose() }
@@ -235,6 +252,7 @@ func (W _io_WriteCloser) Write(p []byte) (n int, err error) { return W.WWrite(p)
 type _io_WriteSeeker struct {
 	WSeek  func(offset int64, whence int) (int64, error)
 	WWrite func(p []byte) (n int, err error)
+	IValue interface{}
 }
 
 func (W _io_WriteSeeker) Seek(offset int64, whence int) (int64, error) {
@@ -245,6 +263,7 @@ func (W _io_WriteSeeker) Write(p []byte) (n int, err error) { return W.WWrite(p)
 // _io_Writer is an interface wrapper for Writer type
 type _io_Writer struct {
 	WWrite func(p []byte) (n int, err error)
+	IValue interface{}
 }
 
 func (W _io_Writer) Write(p []byte) (n int, err error) { return W.WWrite(p) }
@@ -252,6 +271,7 @@ func (W _io_Writer) Write(p []byte) (n int, err error) { return W.WWrite(p) }
 // _io_WriterAt is an interface wrapper for WriterAt type
 type _io_WriterAt struct {
 	WWriteAt func(p []byte, off int64) (n int, err error)
+	IValue   interface{}
 }
 
 func (W _io_WriterAt) WriteAt(p []byte, off int64) (n int, err error) { return W.WWriteAt(p, off) }
@@ -259,6 +279,7 @@ func (W _io_WriterAt) WriteAt(p []byte, off int64) (n int, err error) { return W
 // _io_WriterTo is an interface wrapper for WriterTo type
 type _io_WriterTo struct {
 	WWriteTo func(w io.Writer) (n int64, err error)
+	IValue   interface{}
 }
 
 func (W _io_WriterTo) WriteTo(w io.Writer) (n int64, err error) { return W.WWriteTo(w) }
@@ -48,6 +48,7 @@ func init() {
 type _math_rand_Source struct {
 	WInt63 func() int64
 	WSeed  func(seed int64)
+	IValue interface{}
 }
 
 func (W _math_rand_Source) Int63() int64    { return W.WInt63() }
@@ -58,6 +59,7 @@ type _math_rand_Source64 struct {
 	WInt63  func() int64
 	WSeed   func(seed int64)
 	WUint64 func() uint64
+	IValue  interface{}
 }
 
 func (W _math_rand_Source64) Int63() int64    { return W.WInt63() }
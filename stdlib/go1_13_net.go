@@ -128,6 +128,7 @@ func init() {
 type _net_Addr struct {
 	WNetwork func() string
 	WString  func() string
+	IValue   interface{}
 }
 
 func (W _net_Addr) Network() string { return W.WNetwork() }
@@ -143,6 +144,7 @@ type _net_Conn struct {
 	WSetReadDeadline  func(t time.Time) error
 	WSetWriteDeadline func(t time.Time) error
 	WWrite            func(b []byte) (n int, err error)
+	IValue            interface{}
 }
 
 func (W _net_Conn) Close() error                       { return W.WClose() }
@@ -159,6 +161,7 @@ type _net_Error struct {
 	WError     func() string
 	WTemporary func() bool
 	WTimeout   func() bool
+	IValue     interface{}
 }
 
 func (W _net_Error) Error() string   { return W.WError() }
@@ -170,6 +173,7 @@ type _net_Listener struct {
 	WAccept func() (net.Conn, error)
 	WAddr   func() net.Addr
 	WClose  func() error
+	IValue  interface{}
 }
 
 func (W _net_Listener) Accept() (net.Conn, error) { return W.WAccept() }
@@ -185,6 +189,7 @@ type _net_PacketConn struct {
 	WSetReadDeadline  func(t time.Time) error
 	WSetWriteDeadline func(t time.Time) error
 	WWriteTo          func(p []byte, addr net.Addr) (n int, err error)
+	IValue            interface{}
 }
 
 func (W _net_PacketConn) Close() error                                        { return W.WClose() }
@@ -137,6 +137,7 @@ type _os_FileInfo struct {
 	WName    func() string
 	WSize    func() int64
 	WSys     func() interface{}
+	IValue   interface{}
 }
 
 func (W _os_FileInfo) IsDir() bool        { return W.WIsDir() }
@@ -150,6 +151,7 @@ func (W _os_FileInfo) Sys() interface{}   { return W.WSys() }
 type _os_Signal struct {
 	WSignal func()
 	WString func() string
+	IValue  interface{}
 }
 
 func (W _os_Signal) Signal()        { W.WSignal() }
@@ -33,6 +33,7 @@ func init() {
 type _sync_Locker struct {
 	WLock   func()
 	WUnlock func()
+	IValue  interface{}
 }
 
 func (W _sync_Locker) Lock()   { W.WLock() }
@@ -67,10 +67,12 @@ func init() {
 
 // _text_template_parse_Node is an interface wrapper for Node type
 type _text_template_parse_Node struct {
+	parse.ActionNode
 	WCopy     func() parse.Node
 	WPosition func() parse.Pos
 	WString   func() string
 	WType     func() parse.NodeType
+	IValue    interface{}
 }
 
 func (W _text_template_parse_Node) Copy() parse.Node     { return W.WCopy() }
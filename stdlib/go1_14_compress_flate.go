@@ -44,6 +44,7 @@ func init() {
 type _compress_flate_Reader struct {
 	WRead     func(p []byte) (n int, err error)
 	WReadByte func() (byte, error)
+	IValue    interface{}
 }
 
 func (W _compress_flate_Reader) Read(p []byte) (n int, err error) { return W.WRead(p) }
@@ -52,6 +53,7 @@ func (W _compress_flate_Reader) ReadByte() (byte, error)          { return W.WRe
 // _compress_flate_Resetter is an interface wrapper for Resetter type
 type _compress_flate_Resetter struct {
 	WReset func(r io.Reader, dict []byte) error
+	IValue interface{}
 }
 
 func (W _compress_flate_Resetter) Reset(r io.Reader, dict []byte) error { return W.WReset(r, dict) }
@@ -57,6 +57,7 @@ func init() {
 type _crypto_Decrypter struct {
 	WDecrypt func(rand io.Reader, msg []byte, opts crypto.DecrypterOpts) (plaintext []byte, err error)
 	WPublic  func() crypto.PublicKey
+	IValue   interface{}
 }
 
 func (W _crypto_Decrypter) Decrypt(rand io.Reader, msg []byte, opts crypto.DecrypterOpts) (plaintext []byte, err error) {
@@ -66,20 +67,24 @@ func (W _crypto_Decrypter) Public() crypto.PublicKey { return W.WPublic() }
 
 // _crypto_DecrypterOpts is an interface wrapper for DecrypterOpts type
 type _crypto_DecrypterOpts struct {
+	IValue interface{}
 }
 
 // _crypto_PrivateKey is an interface wrapper for PrivateKey type
 type _crypto_PrivateKey struct {
+	IValue interface{}
 }
 
 // _crypto_PublicKey is an interface wrapper for PublicKey type
 type _crypto_PublicKey struct {
+	IValue interface{}
 }
 
 // _crypto_Signer is an interface wrapper for Signer type
 type _crypto_Signer struct {
 	WPublic func() crypto.PublicKey
 	WSign   func(rand io.Reader, digest []byte, opts crypto.SignerOpts) (signature []byte, err error)
+	IValue  interface{}
 }
 
 func (W _crypto_Signer) Public() crypto.PublicKey { return W.WPublic() }
@@ -90,6 +95,7 @@ func (W _crypto_Signer) Sign(rand io.Reader, digest []byte, opts crypto.SignerOp
 // _crypto_SignerOpts is an interface wrapper for SignerOpts type
 type _crypto_SignerOpts struct {
 	WHashFunc func() crypto.Hash
+	IValue    interface{}
 }
 
 func (W _crypto_SignerOpts) HashFunc() crypto.Hash { return W.WHashFunc() }
@@ -274,6 +274,7 @@ type _debug_dwarf_Type struct {
 	WCommon func() *dwarf.CommonType
 	WSize   func() int64
 	WString func() string
+	IValue  interface{}
 }
 
 func (W _debug_dwarf_Type) Common() *dwarf.CommonType { return W.WCommon() }
@@ -50,17 +50,20 @@ func init() {
 // _encoding_json_Marshaler is an interface wrapper for Marshaler type
 type _encoding_json_Marshaler struct {
 	WMarshalJSON func() ([]byte, error)
+	IValue       interface{}
 }
 
 func (W _encoding_json_Marshaler) MarshalJSON() ([]byte, error) { return W.WMarshalJSON() }
 
 // _encoding_json_Token is an interface wrapper for Token type
 type _encoding_json_Token struct {
+	IValue interface{}
 }
 
 // _encoding_json_Unmarshaler is an interface wrapper for Unmarshaler type
 type _encoding_json_Unmarshaler struct {
 	WUnmarshalJSON func(a0 []byte) error
+	IValue         interface{}
 }
 
 func (W _encoding_json_Unmarshaler) UnmarshalJSON(a0 []byte) error { return W.WUnmarshalJSON(a0) }
@@ -60,6 +60,7 @@ func init() {
 // _encoding_xml_Marshaler is an interface wrapper for Marshaler type
 type _encoding_xml_Marshaler struct {
 	WMarshalXML func(e *xml.Encoder, start xml.StartElement) error
+	IValue      interface{}
 }
 
 func (W _encoding_xml_Marshaler) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
@@ -69,6 +70,7 @@ func (W _encoding_xml_Marshaler) MarshalXML(e *xml.Encoder, start xml.StartEleme
 // _encoding_xml_MarshalerAttr is an interface wrapper for MarshalerAttr type
 type _encoding_xml_MarshalerAttr struct {
 	WMarshalXMLAttr func(name xml.Name) (xml.Attr, error)
+	IValue          interface{}
 }
 
 func (W _encoding_xml_MarshalerAttr) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
@@ -77,11 +79,13 @@ func (W _encoding_xml_MarshalerAttr) MarshalXMLAttr(name xml.Name) (xml.Attr, er
 
 // _encoding_xml_Token is an interface wrapper for Token type
 type _encoding_xml_Token struct {
+	IValue interface{}
 }
 
 // _encoding_xml_TokenReader is an interface wrapper for TokenReader type
 type _encoding_xml_TokenReader struct {
 	WToken func() (xml.Token, error)
+	IValue interface{}
 }
 
 func (W _encoding_xml_TokenReader) Token() (xml.Token, error) { return W.WToken() }
@@ -89,6 +93,7 @@ func (W _encoding_xml_TokenReader) Token() (xml.Token, error) { return W.WToken(
 // _encoding_xml_Unmarshaler is an interface wrapper for Unmarshaler type
 type _encoding_xml_Unmarshaler struct {
 	WUnmarshalXML func(d *xml.Decoder, start xml.StartElement) error
+	IValue        interface{}
 }
 
 func (W _encoding_xml_Unmarshaler) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
@@ -98,6 +103,7 @@ func (W _encoding_xml_Unmarshaler) UnmarshalXML(d *xml.Decoder, start xml.StartE
 // _encoding_xml_UnmarshalerAttr is an interface wrapper for UnmarshalerAttr type
 type _encoding_xml_UnmarshalerAttr struct {
 	WUnmarshalXMLAttr func(attr xml.Attr) error
+	IValue            interface{}
 }
 
 func (W _encoding_xml_UnmarshalerAttr) UnmarshalXMLAttr(attr xml.Attr) error {
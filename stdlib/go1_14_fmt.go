@@ -53,6 +53,7 @@ func init() {
 // _fmt_Formatter is an interface wrapper for Formatter type
 type _fmt_Formatter struct {
 	WFormat func(f fmt.State, c rune)
+	IValue  interface{}
 }
 
 func (W _fmt_Formatter) Format(f fmt.State, c rune) { W.WFormat(f, c) }
@@ -60,6 +61,7 @@ func (W _fmt_Formatter) Format(f fmt.State, c rune) { W.WFormat(f, c) }
 // _fmt_GoStringer is an interface wrapper for GoStringer type
 type _fmt_GoStringer struct {
 	WGoString func() string
+	IValue    interface{}
 }
 
 func (W _fmt_GoStringer) GoString() string { return W.WGoString() }
@@ -72,6 +74,7 @@ type _fmt_ScanState struct {
 	WToken      func(skipSpace bool, f func(rune) bool) (token []byte, err error)
 	WUnreadRune func() error
 	WWidth      func() (wid int, ok bool)
+	IValue      interface{}
 }
 
 func (W _fmt_ScanState) Read(buf []byte) (n int, err error)      { return W.WRead(buf) }
@@ -85,7 +88,8 @@ func (W _fmt_ScanState) Width() (wid int, ok bool) { return W.WWidth() }
 
 // _fmt_Scanner is an interface wrapper for Scanner type
 type _fmt_Scanner struct {
-	WScan func(state fmt.ScanState, verb rune) error
+	WScan  func(state fmt.ScanState, verb rune) error
+	IValue interface{}
 }
 
 func (W _fmt_Scanner) Scan(state fmt.ScanState, verb rune) error { return W.WScan(state, verb) }
@@ -96,6 +100,7 @@ type _fmt_State struct {
 	WPrecision func() (prec int, ok bool)
 	WWidth     func() (wid int, ok bool)
 	WWrite     func(b []byte) (n int, err error)
+	IValue     interface{}
 }
 
 func (W _fmt_State) Flag(c int) bool                   { return W.WFlag(c) }
@@ -106,6 +111,7 @@ func (W _fmt_State) Write(b []byte) (n int, err error) { return W.WWrite(b) }
 // _fmt_Stringer is an interface wrapper for Stringer type
 type _fmt_Stringer struct {
 	WString func() string
+	IValue  interface{}
 }
 
 func (W _fmt_Stringer) String() string { return W.WString() }
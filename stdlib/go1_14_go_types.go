@@ -163,6 +163,7 @@ func init() {
 // _go_types_Importer is an interface wrapper for Importer type
 type _go_types_Importer struct {
 	WImport func(path string) (*types.Package, error)
+	IValue  interface{}
 }
 
 func (W _go_types_Importer) Import(path string) (*types.Package, error) { return W.WImport(path) }
@@ -171,6 +172,7 @@ func (W _go_types_Importer) Import(path string) (*types.Package, error) { return
 type _go_types_ImporterFrom struct {
 	WImport     func(path string) (*types.Package, error)
 	WImportFrom func(path string, dir string, mode types.ImportMode) (*types.Package, error)
+	IValue      interface{}
 }
 
 func (W _go_types_ImporterFrom) Import(path string) (*types.Package, error) { return W.WImport(path) }
@@ -180,6 +182,7 @@ func (W _go_types_ImporterFrom) ImportFrom(path string, dir string, mode types.I
 
 // _go_types_Object is an interface wrapper for Object type
 type _go_types_Object struct {
+	types.Builtin
 	WExported func() bool
 	WId       func() string
 	WName     func() string
@@ -188,6 +191,7 @@ type _go_types_Object struct {
 	WPos      func() token.Pos
 	WString   func() string
 	WType     func() types.Type
+	IValue    interface{}
 }
 
 func (W _go_types_Object) Exported() bool       { return W.WExported() }
@@ -204,6 +208,7 @@ type _go_types_Sizes struct {
 	WAlignof   func(T types.Type) int64
 	WOffsetsof func(fields []*types.Var) []int64
 	WSizeof    func(T types.Type) int64
+	IValue     interface{}
 }
 
 func (W _go_types_Sizes) Alignof(T types.Type) int64            { return W.WAlignof(T) }
@@ -214,6 +219,7 @@ func (W _go_types_Sizes) Sizeof(T types.Type) int64             { return W.WSize
 type _go_types_Type struct {
 	WString     func() string
 	WUnderlying func() types.Type
+	IValue      interface{}
 }
 
 func (W _go_types_Type) String() string         { return W.WString() }
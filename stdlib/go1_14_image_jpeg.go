@@ -34,6 +34,7 @@ func init() {
 type _image_jpeg_Reader struct {
 	WRead     func(p []byte) (n int, err error)
 	WReadByte func() (byte, error)
+	IValue    interface{}
 }
 
 func (W _image_jpeg_Reader) Read(p []byte) (n int, err error) { return W.WRead(p) }
@@ -35,6 +35,7 @@ type _mime_multipart_File struct {
 	WRead   func(p []byte) (n int, err error)
 	WReadAt func(p []byte, off int64) (n int, err error)
 	WSeek   func(offset int64, whence int) (int64, error)
+	IValue  interface{}
 }
 
 func (W _mime_multipart_File) Close() error                     { return W.WClose() }
@@ -208,6 +208,7 @@ func init() {
 // _net_http_CloseNotifier is an interface wrapper for CloseNotifier type
 type _net_http_CloseNotifier struct {
 	WCloseNotify func() <-chan bool
+	IValue       interface{}
 }
 
 func (W _net_http_CloseNotifier) CloseNotify() <-chan bool { return W.WCloseNotify() }
@@ -216,6 +217,7 @@ func (W _net_http_CloseNotifier) CloseNotify() <-chan bool { return W.WCloseNoti
 type _net_http_CookieJar struct {
 	WCookies    func(u *url.URL) []*http.Cookie
 	WSetCookies func(u *url.URL, cookies []*http.Cookie)
+	IValue      interface{}
 }
 
 func (W _net_http_CookieJar) Cookies(u *url.URL) []*http.Cookie { return W.WCookies(u) }
@@ -230,6 +232,7 @@ type _net_http_File struct {
 	WReaddir func(count int) ([]os.FileInfo, error)
 	WSeek    func(offset int64, whence int) (int64, error)
 	WStat    func() (os.FileInfo, error)
+	IValue   interface{}
 }
 
 func (W _net_http_File) Close() error                                 { return W.WClose() }
@@ -240,7 +243,8 @@ func (W _net_http_File) Stat() (os.FileInfo, error)                   { return W
 
 // _net_http_FileSystem is an interface wrapper for FileSystem type
 type _net_http_FileSystem struct {
-	WOpen func(name string) (http.File, error)
+	WOpen  func(name string) (http.File, error)
+	IValue interface{}
 }
 
 func (W _net_http_FileSystem) Open(name string) (http.File, error) { return W.WOpen(name) }
@@ -248,6 +252,7 @@ func (W _net_http_FileSystem) Open(name string) (http.File, error) { return W.WO
 // _net_http_Flusher is an interface wrapper for Flusher type
 type _net_http_Flusher struct {
 	WFlush func()
+	IValue interface{}
 }
 
 func (W _net_http_Flusher) Flush() { W.WFlush() }
@@ -255,6 +260,7 @@ func (W _net_http_Flusher) Flush() { W.WFlush() }
 // _net_http_Handler is an interface wrapper for Handler type
 type _net_http_Handler struct {
 	WServeHTTP func(a0 http.ResponseWriter, a1 *http.Request)
+	IValue     interface{}
 }
 
 func (W _net_http_Handler) ServeHTTP(a0 http.ResponseWriter, a1 *http.Request) { W.WServeHTTP(a0, a1) }
@@ -262,13 +268,15 @@ func (W _net_http_Handler) ServeHTTP(a0 http.ResponseWriter, a1 *http.Request) {
 // _net_http_Hijacker is an interface wrapper for Hijacker type
 type _net_http_Hijacker struct {
 	WHijack func() (net.Conn, *bufio.ReadWriter, error)
+	IValue  interface{}
 }
 
 func (W _net_http_Hijacker) Hijack() (net.Conn, *bufio.ReadWriter, error) { return W.WHijack() }
 
 // _net_http_Pusher is an interface wrapper for Pusher type
 type _net_http_Pusher struct {
-	WPush func(target string, opts *http.PushOptions) error
+	WPush  func(target string, opts *http.PushOptions) error
+	IValue interface{}
 }
 
 func (W _net_http_Pusher) Push(target string, opts *http.PushOptions) error {
@@ -280,6 +288,7 @@ type _net_http_ResponseWriter struct {
 	WHeader      func() http.Header
 	WWrite       func(a0 []byte) (int, error)
 	WWriteHeader func(statusCode int)
+	IValue       interface{}
 }
 
 func (W _net_http_ResponseWriter) Header() http.Header          { return W.WHeader() }
@@ -289,6 +298,7 @@ func (W _net_http_ResponseWriter) WriteHeader(statusCode int)   { W.WWriteHeader
 // _net_http_RoundTripper is an interface wrapper for RoundTripper type
 type _net_http_RoundTripper struct {
 	WRoundTrip func(a0 *http.Request) (*http.Response, error)
+	IValue     interface{}
 }
 
 func (W _net_http_RoundTripper) RoundTrip(a0 *http.Request) (*http.Response, error) {
@@ -28,6 +28,7 @@ func init() {
 type _net_http_cookiejar_PublicSuffixList struct {
 	WPublicSuffix func(domain string) string
 	WString       func() string
+	IValue        interface{}
 }
 
 func (W _net_http_cookiejar_PublicSuffixList) PublicSuffix(domain string) string {
@@ -52,6 +52,7 @@ type _net_rpc_ClientCodec struct {
 	WReadResponseBody   func(a0 interface{}) error
 	WReadResponseHeader func(a0 *rpc.Response) error
 	WWriteRequest       func(a0 *rpc.Request, a1 interface{}) error
+	IValue              interface{}
 }
 
 func (W _net_rpc_ClientCodec) Close() error                          { return W.WClose() }
@@ -69,6 +70,7 @@ type _net_rpc_ServerCodec struct {
 	WReadRequestBody   func(a0 interface{}) error
 	WReadRequestHeader func(a0 *rpc.Request) error
 	WWriteResponse     func(a0 *rpc.Response, a1 interface{}) error
+	IValue             interface{}
 }
 
 func (W _net_rpc_ServerCodec) Close() error                         { return W.WClose() }
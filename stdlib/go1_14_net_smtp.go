@@ -32,6 +32,7 @@ func init() {
 type _net_smtp_Auth struct {
 	WNext  func(fromServer []byte, more bool) (toServer []byte, err error)
 	WStart func(server *smtp.ServerInfo) (proto string, toServer []byte, err error)
+	IValue interface{}
 }
 
 func (W _net_smtp_Auth) Next(fromServer []byte, more bool) (toServer []byte, err error) {
@@ -120,6 +120,7 @@ type _reflect_Type struct {
 	WPkgPath         func() string
 	WSize            func() uintptr
 	WString          func() string
+	IValue           interface{}
 }
 
 func (W _reflect_Type) Align() int                                   { return W.WAlign() }
@@ -70,6 +70,7 @@ func init() {
 type _runtime_Error struct {
 	WError        func() string
 	WRuntimeError func()
+	IValue        interface{}
 }
 
 func (W _runtime_Error) Error() string { return W.WError() }
@@ -2238,6 +2238,7 @@ func init() {
 // _syscall_Conn is an interface wrapper for Conn type
 type _syscall_Conn struct {
 	WSyscallConn func() (syscall.RawConn, error)
+	IValue       interface{}
 }
 
 func (W _syscall_Conn) SyscallConn() (syscall.RawConn, error) { return W.WSyscallConn() }
@@ -2247,6 +2248,7 @@ type _syscall_RawConn struct {
 	WControl func(f func(fd uintptr)) error
 	WRead    func(f func(fd uintptr) (done bool)) error
 	WWrite   func(f func(fd uintptr) (done bool)) error
+	IValue   interface{}
 }
 
 func (W _syscall_RawConn) Control(f func(fd uintptr)) error           { return W.WControl(f) }
@@ -2255,4 +2257,6 @@ func (W _syscall_RawConn) Write(f func(fd uintptr) (done bool)) error { return W
 
 // _syscall_Sockaddr is an interface wrapper for Sockaddr type
 type _syscall_Sockaddr struct {
+	syscall.SockaddrInet4
+	IValue interface{}
 }